@@ -19,10 +19,12 @@ import (
 )
 
 var (
+	_ DDLNode = &AlterDatabaseStmt{}
 	_ DDLNode = &AlterTableStmt{}
 	_ DDLNode = &CreateDatabaseStmt{}
 	_ DDLNode = &CreateIndexStmt{}
 	_ DDLNode = &CreateTableStmt{}
+	_ DDLNode = &CreateViewStmt{}
 	_ DDLNode = &DropDatabaseStmt{}
 	_ DDLNode = &DropIndexStmt{}
 	_ DDLNode = &DropTableStmt{}
@@ -79,6 +81,25 @@ func (n *CreateDatabaseStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// AlterDatabaseStmt is a statement to change the characteristics of a database.
+// See https://dev.mysql.com/doc/refman/5.7/en/alter-database.html
+type AlterDatabaseStmt struct {
+	ddlNode
+
+	Name    string
+	Options []*DatabaseOption
+}
+
+// Accept implements Node Accept interface.
+func (n *AlterDatabaseStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*AlterDatabaseStmt)
+	return v.Leave(n)
+}
+
 // DropDatabaseStmt is a statement to drop a database and all tables in the database.
 // See https://dev.mysql.com/doc/refman/5.7/en/drop-database.html
 type DropDatabaseStmt struct {
@@ -242,6 +263,7 @@ const (
 	ColumnOptionOnUpdate // For Timestamp and Datetime only.
 	ColumnOptionFulltext
 	ColumnOptionComment
+	ColumnOptionCheck
 )
 
 // ColumnOption is used for parsing column constraint info from SQL.
@@ -249,7 +271,7 @@ type ColumnOption struct {
 	node
 
 	Tp ColumnOptionType
-	// The value For Default or On Update.
+	// The value For Default or On Update, or the expression for Check.
 	Expr ExprNode
 }
 
@@ -308,6 +330,7 @@ const (
 	ConstraintUniqIndex
 	ConstraintForeignKey
 	ConstraintFulltext
+	ConstraintCheck
 )
 
 // Constraint is constraint for table definition.
@@ -325,6 +348,9 @@ type Constraint struct {
 
 	// Index Options
 	Option *IndexOption
+
+	// Used for check constraint.
+	Expr ExprNode
 }
 
 // Accept implements Node Accept interface.
@@ -355,6 +381,13 @@ func (n *Constraint) Accept(v Visitor) (Node, bool) {
 		}
 		n.Option = node.(*IndexOption)
 	}
+	if n.Expr != nil {
+		node, ok := n.Expr.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Expr = node.(ExprNode)
+	}
 	return v.Leave(n)
 }
 
@@ -394,6 +427,8 @@ func (n *ColumnDef) Accept(v Visitor) (Node, bool) {
 type CreateTableStmt struct {
 	ddlNode
 
+	// IsTemporary is true for CREATE TEMPORARY TABLE, the table is only visible to the session.
+	IsTemporary bool
 	IfNotExists bool
 	Table       *TableName
 	Cols        []*ColumnDef
@@ -435,6 +470,10 @@ func (n *CreateTableStmt) Accept(v Visitor) (Node, bool) {
 type DropTableStmt struct {
 	ddlNode
 
+	// IsTemporary is true for DROP TEMPORARY TABLE, only the temporary tables are dropped.
+	IsTemporary bool
+	// IsView is true for DROP VIEW, only the views are dropped.
+	IsView   bool
 	IfExists bool
 	Tables   []*TableName
 }
@@ -456,6 +495,38 @@ func (n *DropTableStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// CreateViewStmt is a statement to create a view.
+// See https://dev.mysql.com/doc/refman/5.7/en/create-view.html
+type CreateViewStmt struct {
+	ddlNode
+
+	OrReplace bool
+	ViewName  *TableName
+	Cols      []model.CIStr
+	// Select is a SelectStmt or a UnionStmt, its text is stored as the definition of the view.
+	Select StmtNode
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateViewStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateViewStmt)
+	node, ok := n.ViewName.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.ViewName = node.(*TableName)
+	node, ok = n.Select.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Select = node.(StmtNode)
+	return v.Leave(n)
+}
+
 // CreateIndexStmt is a statement to create an index.
 // See https://dev.mysql.com/doc/refman/5.7/en/create-index.html
 type CreateIndexStmt struct {
@@ -68,6 +68,8 @@ type Join struct {
 	Tp JoinType
 	// On represents join on condition.
 	On *OnCondition
+	// Using represents join using clause.
+	Using []*ColumnName
 }
 
 // Accept implements Node Accept interface.
@@ -373,6 +375,8 @@ func (n *ByItem) Accept(v Visitor) (Node, bool) {
 type GroupByClause struct {
 	node
 	Items []*ByItem
+	// Rollup is true for GROUP BY ... WITH ROLLUP, which adds super-aggregate rows.
+	Rollup bool
 }
 
 // Accept implements Node Accept interface.
@@ -461,6 +465,29 @@ type SelectStmt struct {
 	Limit *Limit
 	// Lock is the lock type
 	LockTp SelectLockType
+	// SelectIntoOpt is the INTO OUTFILE clause, the result is written to the file instead of returned to the client.
+	SelectIntoOpt *SelectIntoOption
+	// TableHints are the table level optimizer hints in the "/*+ ... */" comment following the SELECT keyword.
+	TableHints []*TableOptimizerHint
+}
+
+// TableOptimizerHint represents a table level optimizer hint, like "USE_INDEX(t, idx)".
+// See https://dev.mysql.com/doc/refman/5.7/en/optimizer-hints.html
+type TableOptimizerHint struct {
+	// HintName is the name of the hint, like USE_INDEX.
+	HintName model.CIStr
+	// Table is the name or the alias of the table which the hint applies to.
+	Table model.CIStr
+	// Indexes are the names of the indexes in the hint.
+	Indexes []model.CIStr
+}
+
+// SelectIntoOption represents the INTO OUTFILE clause of select statement.
+// See https://dev.mysql.com/doc/refman/5.7/en/select-into.html
+type SelectIntoOption struct {
+	FileName   string
+	FieldsInfo *FieldsClause
+	LinesInfo  *LinesClause
 }
 
 // Accept implements Node Accept interface.
@@ -640,11 +667,13 @@ const (
 type LoadDataStmt struct {
 	dmlNode
 
-	IsLocal    bool
-	Path       string
-	Table      *TableName
-	FieldsInfo *FieldsClause
-	LinesInfo  *LinesClause
+	IsLocal     bool
+	Path        string
+	Table       *TableName
+	Columns     []*ColumnName
+	FieldsInfo  *FieldsClause
+	LinesInfo   *LinesClause
+	IgnoreLines uint64
 }
 
 // Accept implements Node Accept interface.
@@ -664,14 +693,14 @@ func (n *LoadDataStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
-// FieldsClause represents fields references clause in load data statement.
+// FieldsClause represents fields references clause in load data statement and select into outfile statement.
 type FieldsClause struct {
 	Terminated string
 	Enclosed   byte
 	Escaped    byte
 }
 
-// LinesClause represents lines references clause in load data statement.
+// LinesClause represents lines references clause in load data statement and select into outfile statement.
 type LinesClause struct {
 	Starting   string
 	Terminated string
@@ -911,6 +940,8 @@ const (
 	ShowProcessList
 	ShowCreateDatabase
 	ShowEvents
+	ShowErrors
+	ShowCreateView
 )
 
 // ShowStmt is a statement to provide information about databases, tables, columns and so on.
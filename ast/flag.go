@@ -126,6 +126,8 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 		} else {
 			x.SetFlag(FlagHasVariable | x.Value.GetFlag())
 		}
+	case *WindowFuncExpr:
+		f.windowFunc(x)
 	}
 
 	return in, true
@@ -197,6 +199,19 @@ func (f *flagSetter) aggregateFunc(x *AggregateFuncExpr) {
 	x.SetFlag(flag)
 }
 
+func (f *flagSetter) windowFunc(x *WindowFuncExpr) {
+	flag := FlagHasWindowFunc
+	for _, val := range x.Spec.PartitionBy {
+		flag |= val.GetFlag()
+	}
+	if x.Spec.OrderBy != nil {
+		for _, item := range x.Spec.OrderBy.Items {
+			flag |= item.Expr.GetFlag()
+		}
+	}
+	x.SetFlag(flag)
+}
+
 // MergeChildrenFlags sets flag to parent by children.
 func MergeChildrenFlags(parent ExprNode, children ...ExprNode) {
 	var flag uint64
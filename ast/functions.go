@@ -29,6 +29,7 @@ var (
 	_ FuncNode = &AggregateFuncExpr{}
 	_ FuncNode = &FuncCallExpr{}
 	_ FuncNode = &FuncCastExpr{}
+	_ FuncNode = &WindowFuncExpr{}
 )
 
 // List scalar function names.
@@ -79,6 +80,7 @@ const (
 	Abs     = "abs"
 	Ceil    = "ceil"
 	Ceiling = "ceiling"
+	Floor   = "floor"
 	Ln      = "ln"
 	Log     = "log"
 	Log2    = "log2"
@@ -96,6 +98,7 @@ const (
 	Curtime          = "curtime"
 	Date             = "date"
 	DateArith        = "date_arith"
+	DateDiff         = "datediff"
 	DateFormat       = "date_format"
 	Day              = "day"
 	DayName          = "dayname"
@@ -161,6 +164,10 @@ const (
 	User         = "user"
 	Version      = "version"
 
+	// json functions
+	JSONExtract = "json_extract"
+	JSONUnquote = "json_unquote"
+
 	// control functions
 	If     = "if"
 	Ifnull = "ifnull"
@@ -287,6 +294,14 @@ const (
 	AggFuncMin = "min"
 	// AggFuncGroupConcat is the name of group_concat function.
 	AggFuncGroupConcat = "group_concat"
+	// AggFuncStddevPop is the name of stddev_pop function, std and stddev are its synonyms.
+	AggFuncStddevPop = "stddev_pop"
+	// AggFuncStddevSamp is the name of stddev_samp function.
+	AggFuncStddevSamp = "stddev_samp"
+	// AggFuncVarPop is the name of var_pop function, variance is its synonym.
+	AggFuncVarPop = "var_pop"
+	// AggFuncVarSamp is the name of var_samp function.
+	AggFuncVarSamp = "var_samp"
 )
 
 // AggregateFuncExpr represents aggregate function expression.
@@ -300,6 +315,10 @@ type AggregateFuncExpr struct {
 	// For example, column c1 values are "1", "2", "2",  "sum(c1)" is "5",
 	// but "sum(distinct c1)" is "3".
 	Distinct bool
+	// Order is the ORDER BY clause of group_concat, it is nil if not specified.
+	Order *OrderByClause
+	// Separator is the separator of group_concat, it is "," if not specified.
+	Separator string
 
 	CurrentGroup []byte
 	// contextPerGroupMap is used to store aggregate evaluation context.
@@ -321,6 +340,17 @@ func (n *AggregateFuncExpr) Accept(v Visitor) (Node, bool) {
 		}
 		n.Args[i] = node.(ExprNode)
 	}
+	if n.Order != nil {
+		// Only the item expressions are visited, the ORDER BY clause of group_concat is
+		// evaluated in the scope of its arguments rather than the statement's ORDER BY.
+		for _, item := range n.Order.Items {
+			node, ok := item.Expr.Accept(v)
+			if !ok {
+				return n, false
+			}
+			item.Expr = node.(ExprNode)
+		}
+	}
 	return v.Leave(n)
 }
 
@@ -479,13 +509,11 @@ func (n *AggregateFuncExpr) updateGroupConcat(sc *variable.StatementContext) err
 	if ctx.Buffer == nil {
 		ctx.Buffer = &bytes.Buffer{}
 	} else {
-		// now use comma separator
-		ctx.Buffer.WriteString(",")
+		ctx.Buffer.WriteString(n.Separator)
 	}
 	for _, val := range vals {
 		ctx.Buffer.WriteString(fmt.Sprintf("%v", val))
 	}
-	// TODO: if total length is greater than global var group_concat_max_len, truncate it.
 	return nil
 }
 
@@ -544,6 +572,65 @@ type AggEvaluateContext struct {
 	DistinctChecker *distinct.Checker
 	Count           int64
 	Value           types.Datum
-	Buffer          *bytes.Buffer // Buffer is used for group_concat.
-	GotFirstRow     bool          // It will check if the agg has met the first row key.
+	Buffer          *bytes.Buffer   // Buffer is used for group_concat.
+	SortRows        [][]types.Datum // SortRows is used for group_concat with ORDER BY, each row is the value followed by the keys.
+	GotFirstRow     bool            // It will check if the agg has met the first row key.
+	Shift           float64         // Shift is the first value of the variance functions, the others are accumulated relative to it.
+	ShiftedSum      float64         // ShiftedSum is the sum of the differences from Shift of the variance functions.
+	ShiftedSquares  float64         // ShiftedSquares is the sum of the squared differences from Shift of the variance functions.
+}
+
+const (
+	// WindowFuncRowNumber is the name of row_number function.
+	WindowFuncRowNumber = "row_number"
+	// WindowFuncRank is the name of rank function.
+	WindowFuncRank = "rank"
+	// WindowFuncDenseRank is the name of dense_rank function.
+	WindowFuncDenseRank = "dense_rank"
+)
+
+// WindowSpec is the OVER clause of a window function.
+type WindowSpec struct {
+	// PartitionBy is the PARTITION BY list, the rows are numbered within each partition.
+	PartitionBy []ExprNode
+	// OrderBy is the ORDER BY clause in the window, it is nil if not specified.
+	OrderBy *OrderByClause
+}
+
+// WindowFuncExpr represents a window function expression.
+// Unlike an aggregate function, it computes a value for every row without collapsing the rows.
+type WindowFuncExpr struct {
+	funcNode
+	// F is the function name.
+	F string
+	// Spec is the window the function is computed over.
+	Spec WindowSpec
+}
+
+// Accept implements Node Accept interface.
+func (n *WindowFuncExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*WindowFuncExpr)
+	for i, val := range n.Spec.PartitionBy {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Spec.PartitionBy[i] = node.(ExprNode)
+	}
+	if n.Spec.OrderBy != nil {
+		// Like group_concat, only the item expressions are visited, the ORDER BY clause in the window
+		// doesn't refer to the select fields.
+		for _, item := range n.Spec.OrderBy.Items {
+			node, ok := item.Expr.Accept(v)
+			if !ok {
+				return n, false
+			}
+			item.Expr = node.(ExprNode)
+		}
+	}
+	return v.Leave(n)
 }
@@ -34,12 +34,15 @@ var (
 	_ StmtNode = &ExplainStmt{}
 	_ StmtNode = &GrantStmt{}
 	_ StmtNode = &PrepareStmt{}
+	_ StmtNode = &ReleaseSavepointStmt{}
 	_ StmtNode = &RollbackStmt{}
+	_ StmtNode = &SavepointStmt{}
 	_ StmtNode = &SetPwdStmt{}
 	_ StmtNode = &SetStmt{}
 	_ StmtNode = &UseStmt{}
 	_ StmtNode = &AnalyzeTableStmt{}
 	_ StmtNode = &FlushTableStmt{}
+	_ StmtNode = &KillStmt{}
 
 	_ Node = &PrivElem{}
 	_ Node = &VariableAssignment{}
@@ -73,9 +76,18 @@ type AuthOption struct {
 type ExplainStmt struct {
 	stmtNode
 
-	Stmt StmtNode
+	Stmt   StmtNode
+	Format string
 }
 
+// Explain formats.
+const (
+	// ExplainFormatTraditional prints one row for each operator of the plan.
+	ExplainFormatTraditional = "traditional"
+	// ExplainFormatJSON prints each operator of the plan in JSON.
+	ExplainFormatJSON = "json"
+)
+
 // Accept implements Node Accept interface.
 func (n *ExplainStmt) Accept(v Visitor) (Node, bool) {
 	newNode, skipChildren := v.Enter(n)
@@ -213,10 +225,13 @@ func (n *CommitStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
-// RollbackStmt is a statement to roll back the current transaction.
+// RollbackStmt is a statement to roll back the current transaction,
+// or to the savepoint if SavepointName is not empty.
 // See https://dev.mysql.com/doc/refman/5.7/en/commit.html
 type RollbackStmt struct {
 	stmtNode
+
+	SavepointName string
 }
 
 // Accept implements Node Accept interface.
@@ -229,6 +244,42 @@ func (n *RollbackStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SavepointStmt is a statement to set a named savepoint in the current transaction.
+// See https://dev.mysql.com/doc/refman/5.7/en/savepoint.html
+type SavepointStmt struct {
+	stmtNode
+
+	Name string
+}
+
+// Accept implements Node Accept interface.
+func (n *SavepointStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SavepointStmt)
+	return v.Leave(n)
+}
+
+// ReleaseSavepointStmt is a statement to remove a named savepoint from the current transaction.
+// See https://dev.mysql.com/doc/refman/5.7/en/savepoint.html
+type ReleaseSavepointStmt struct {
+	stmtNode
+
+	Name string
+}
+
+// Accept implements Node Accept interface.
+func (n *ReleaseSavepointStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ReleaseSavepointStmt)
+	return v.Leave(n)
+}
+
 // UseStmt is a statement to use the DBName database as the current database.
 // See https://dev.mysql.com/doc/refman/5.7/en/use.html
 type UseStmt struct {
@@ -302,6 +353,27 @@ func (n *FlushTableStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// KillStmt is a statement to kill a query or connection.
+type KillStmt struct {
+	stmtNode
+
+	// Query indicates whether terminate a single query on this connection or the whole connection.
+	// If Query is true, terminates the statement the connection is currently executing, but leaves the connection itself intact.
+	// If Query is false, terminates the connection associated with the given ConnectionID, after terminating any statement the connection is executing.
+	Query        bool
+	ConnectionID uint64
+}
+
+// Accept implements Node Accept interface.
+func (n *KillStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*KillStmt)
+	return v.Leave(n)
+}
+
 // SetStmt is the statement to set variables.
 type SetStmt struct {
 	stmtNode
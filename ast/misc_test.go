@@ -70,6 +70,7 @@ func (ts *testMiscSuite) TestMiscVisitorCover(c *C) {
 			},
 		}),
 		(&FlushTableStmt{}),
+		(&KillStmt{}),
 		(&PrivElem{}),
 		(&VariableAssignment{Value: &ValueExpr{}}),
 	}
@@ -50,6 +50,9 @@ const (
 		Execute_priv		ENUM('N','Y') NOT NULL  DEFAULT 'N',
 		Index_priv		ENUM('N','Y') NOT NULL  DEFAULT 'N',
 		Create_user_priv	ENUM('N','Y') NOT NULL  DEFAULT 'N',
+		Process_priv		ENUM('N','Y') NOT NULL  DEFAULT 'N',
+		Super_priv		ENUM('N','Y') NOT NULL  DEFAULT 'N',
+		File_priv		ENUM('N','Y') NOT NULL  DEFAULT 'N',
 		PRIMARY KEY (Host, User));`
 	// CreateDBPrivTable is the SQL statement creates DB scope privilege table in system db.
 	CreateDBPrivTable = `CREATE TABLE if not exists mysql.db (
@@ -143,6 +146,9 @@ const (
 	// Const for TiDB server version 2.
 	version2 = 2
 	version3 = 3
+	version4 = 4
+	version5 = 5
+	version6 = 6
 )
 
 func checkBootstrapped(s Session) (bool, error) {
@@ -212,6 +218,15 @@ func upgrade(s Session) {
 	if ver < version3 {
 		upgradeToVer3(s)
 	}
+	if ver < version4 {
+		upgradeToVer4(s)
+	}
+	if ver < version5 {
+		upgradeToVer5(s)
+	}
+	if ver < version6 {
+		upgradeToVer6(s)
+	}
 
 	updateBootstrapVer(s)
 	_, err = s.Execute("COMMIT")
@@ -260,6 +275,35 @@ func upgradeToVer3(s Session) {
 	mustExecute(s, sql)
 }
 
+// Update to version 4.
+func upgradeToVer4(s Session) {
+	// Version 4 adds the Process_priv column to mysql.user, the users who can create users are granted it.
+	addPrivilegeColumn(s, "Process_priv", "Create_user_priv='Y'")
+}
+
+// Update to version 5.
+func upgradeToVer5(s Session) {
+	// Version 5 adds the Super_priv column to mysql.user, the users who can create users are granted it.
+	addPrivilegeColumn(s, "Super_priv", "Create_user_priv='Y'")
+}
+
+// Update to version 6.
+func upgradeToVer6(s Session) {
+	// Version 6 adds the File_priv column to mysql.user, only root is granted it.
+	addPrivilegeColumn(s, "File_priv", "User='root'")
+}
+
+// addPrivilegeColumn adds the privilege column to mysql.user, and grants the privilege to the users matching where.
+func addPrivilegeColumn(s Session, column, where string) {
+	sql := fmt.Sprintf("ALTER TABLE %s.%s ADD COLUMN %s ENUM('N','Y') NOT NULL DEFAULT 'N'", mysql.SystemDB, mysql.UserTable, column)
+	_, err := s.Execute(sql)
+	if err != nil && !infoschema.ErrColumnExists.Equal(err) {
+		log.Fatal(err)
+	}
+	sql = fmt.Sprintf("UPDATE %s.%s SET %s='Y' WHERE %s", mysql.SystemDB, mysql.UserTable, column, where)
+	mustExecute(s, sql)
+}
+
 // Update boostrap version variable in mysql.TiDB table.
 func updateBootstrapVer(s Session) {
 	// Update bootstrap version.
@@ -307,7 +351,7 @@ func doDMLWorks(s Session) {
 
 	// Insert a default user with empty password.
 	mustExecute(s, `INSERT INTO mysql.user VALUES
-		("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
 
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.SysVars))
@@ -52,16 +52,24 @@ var (
 	errCantDropColWithIndex    = terror.ClassDDL.New(codeCantDropColWithIndex, "can't drop column with index")
 	errUnsupportedAddColumn    = terror.ClassDDL.New(codeUnsupportedAddColumn, "unsupported add column")
 	errUnsupportedModifyColumn = terror.ClassDDL.New(codeUnsupportedModifyColumn, "unsupported modify column")
+	errUnsupportedAddCheck     = terror.ClassDDL.New(codeUnsupportedAddCheck, "unsupported add check constraint")
 	errUnsupportedPKHandle     = terror.ClassDDL.New(codeUnsupportedDropPKHandle,
 		"unsupported drop integer primary key")
 
 	errBlobKeyWithoutLength  = terror.ClassDDL.New(codeBlobKeyWithoutLength, "index for BLOB/TEXT column must specificate a key length")
 	errIncorrectPrefixKey    = terror.ClassDDL.New(codeIncorrectPrefixKey, "Incorrect prefix key; the used key part isn't a string, the used length is longer than the key part, or the storage engine doesn't support unique prefix keys")
+	errJSONUsedAsKey         = terror.ClassDDL.New(codeJSONUsedAsKey, "JSON column '%s' cannot be used in key specification.")
 	errTooLongKey            = terror.ClassDDL.New(codeTooLongKey, fmt.Sprintf("Specified key was too long; max key length is %d bytes", maxPrefixLength))
 	errKeyColumnDoesNotExits = terror.ClassDDL.New(codeKeyColumnDoesNotExits, "this key column doesn't exist in table")
 	errDupKeyName            = terror.ClassDDL.New(codeDupKeyName, "duplicate key name")
 	errWrongDBName           = terror.ClassDDL.New(codeWrongDBName, "Incorrect database name '%s'")
 	errWrongTableName        = terror.ClassDDL.New(codeWrongTableName, "Incorrect table name '%s'")
+	errTooLongTableComment   = terror.ClassDDL.New(codeTooLongTableComment, "Comment for table '%s' is too long (max = %d)")
+	errTooLongFieldComment   = terror.ClassDDL.New(codeTooLongFieldComment, "Comment for field '%s' is too long (max = %d)")
+
+	errCheckRefersUnknownColumn = terror.ClassDDL.New(codeCheckRefersUnknownColumn, "Check constraint '%s' refers to non-existing column '%s'.")
+	errDupCheckName             = terror.ClassDDL.New(codeDupCheckName, "Duplicate check constraint name '%s'.")
+	errDependentByCheck         = terror.ClassDDL.New(codeDependentByCheck, "Check constraint '%s' uses column '%s', hence column cannot be dropped or renamed.")
 
 	// ErrInvalidDBState returns for invalid database state.
 	ErrInvalidDBState = terror.ClassDDL.New(codeInvalidDBState, "invalid database state")
@@ -84,15 +92,28 @@ var (
 	ErrInvalidOnUpdate = terror.ClassDDL.New(codeInvalidOnUpdate, "invalid ON UPDATE clause for the column")
 	// ErrTooLongIdent returns for too long name of database/table/column.
 	ErrTooLongIdent = terror.ClassDDL.New(codeTooLongIdent, "Identifier name too long")
+	// ErrUnknownCharacterSet returns for an unsupported charset.
+	ErrUnknownCharacterSet = terror.ClassDDL.New(codeUnknownCharacterSet, "Unknown character set: '%s'")
+	// ErrUnknownCollation returns for an unsupported collation.
+	ErrUnknownCollation = terror.ClassDDL.New(codeUnknownCollation, "Unknown collation: '%s'")
+	// ErrCollationCharsetMismatch returns for a collation that doesn't belong to the charset.
+	ErrCollationCharsetMismatch = terror.ClassDDL.New(codeCollationCharsetMismatch, "COLLATION '%s' is not valid for CHARACTER SET '%s'")
 )
 
 // DDL is responsible for updating schema in data store and maintaining in-memory InfoSchema cache.
 type DDL interface {
 	CreateSchema(ctx context.Context, name model.CIStr, charsetInfo *ast.CharsetOpt) error
 	DropSchema(ctx context.Context, schema model.CIStr) error
+	AlterSchema(ctx context.Context, schema model.CIStr, charsetInfo *ast.CharsetOpt) error
 	CreateTable(ctx context.Context, ident ast.Ident, cols []*ast.ColumnDef,
 		constrs []*ast.Constraint, options []*ast.TableOption) error
+	// BuildTableInfo builds the table info of a new table without creating it, it's used by the temporary tables.
+	BuildTableInfo(ctx context.Context, ident ast.Ident, cols []*ast.ColumnDef,
+		constrs []*ast.Constraint, options []*ast.TableOption) (*model.TableInfo, error)
 	DropTable(ctx context.Context, tableIdent ast.Ident) (err error)
+	// CreateView creates a view with the columns of its select statement, the view with the same name
+	// is replaced if orReplace is true.
+	CreateView(ctx context.Context, ident ast.Ident, cols []*model.ColumnInfo, view *model.ViewInfo, orReplace bool) error
 	CreateIndex(ctx context.Context, tableIdent ast.Ident, unique bool, indexName model.CIStr,
 		columnNames []*ast.IndexColName) error
 	DropIndex(ctx context.Context, tableIdent ast.Ident, indexName model.CIStr) error
@@ -387,35 +408,54 @@ const (
 	codeUnsupportedAddColumn    = 202
 	codeUnsupportedModifyColumn = 203
 	codeUnsupportedDropPKHandle = 204
-
-	codeBadNull               = 1048
-	codeTooLongIdent          = 1059
-	codeDupKeyName            = 1061
-	codeTooLongKey            = 1071
-	codeKeyColumnDoesNotExits = 1072
-	codeIncorrectPrefixKey    = 1089
-	codeCantRemoveAllFields   = 1090
-	codeCantDropFieldOrKey    = 1091
-	codeWrongDBName           = 1102
-	codeWrongTableName        = 1103
-	codeBlobKeyWithoutLength  = 1170
-	codeInvalidOnUpdate       = 1294
+	codeUnsupportedAddCheck     = 205
+
+	codeBadNull                  = 1048
+	codeTooLongIdent             = 1059
+	codeDupKeyName               = 1061
+	codeTooLongKey               = 1071
+	codeKeyColumnDoesNotExits    = 1072
+	codeIncorrectPrefixKey       = 1089
+	codeCantRemoveAllFields      = 1090
+	codeCantDropFieldOrKey       = 1091
+	codeWrongDBName              = 1102
+	codeWrongTableName           = 1103
+	codeUnknownCharacterSet      = 1115
+	codeBlobKeyWithoutLength     = 1170
+	codeCollationCharsetMismatch = 1253
+	codeUnknownCollation         = 1273
+	codeInvalidOnUpdate          = 1294
+	codeTooLongTableComment      = 1628
+	codeTooLongFieldComment      = 1629
+	codeJSONUsedAsKey            = 3152
+	codeCheckRefersUnknownColumn = 3820
+	codeDupCheckName             = 3822
+	codeDependentByCheck         = 3959
 )
 
 func init() {
 	ddlMySQLErrCodes := map[terror.ErrCode]uint16{
-		codeBadNull:               mysql.ErrBadNull,
-		codeCantRemoveAllFields:   mysql.ErrCantRemoveAllFields,
-		codeCantDropFieldOrKey:    mysql.ErrCantDropFieldOrKey,
-		codeInvalidOnUpdate:       mysql.ErrInvalidOnUpdate,
-		codeBlobKeyWithoutLength:  mysql.ErrBlobKeyWithoutLength,
-		codeIncorrectPrefixKey:    mysql.ErrWrongSubKey,
-		codeTooLongIdent:          mysql.ErrTooLongIdent,
-		codeTooLongKey:            mysql.ErrTooLongKey,
-		codeKeyColumnDoesNotExits: mysql.ErrKeyColumnDoesNotExits,
-		codeDupKeyName:            mysql.ErrDupKeyName,
-		codeWrongDBName:           mysql.ErrWrongDBName,
-		codeWrongTableName:        mysql.ErrWrongTableName,
+		codeBadNull:                  mysql.ErrBadNull,
+		codeCantRemoveAllFields:      mysql.ErrCantRemoveAllFields,
+		codeCantDropFieldOrKey:       mysql.ErrCantDropFieldOrKey,
+		codeInvalidOnUpdate:          mysql.ErrInvalidOnUpdate,
+		codeBlobKeyWithoutLength:     mysql.ErrBlobKeyWithoutLength,
+		codeIncorrectPrefixKey:       mysql.ErrWrongSubKey,
+		codeTooLongIdent:             mysql.ErrTooLongIdent,
+		codeTooLongKey:               mysql.ErrTooLongKey,
+		codeKeyColumnDoesNotExits:    mysql.ErrKeyColumnDoesNotExits,
+		codeDupKeyName:               mysql.ErrDupKeyName,
+		codeWrongDBName:              mysql.ErrWrongDBName,
+		codeWrongTableName:           mysql.ErrWrongTableName,
+		codeUnknownCharacterSet:      mysql.ErrUnknownCharacterSet,
+		codeUnknownCollation:         mysql.ErrUnknownCollation,
+		codeCollationCharsetMismatch: mysql.ErrCollationCharsetMismatch,
+		codeTooLongTableComment:      mysql.ErrTooLongTableComment,
+		codeTooLongFieldComment:      mysql.ErrTooLongFieldComment,
+		codeJSONUsedAsKey:            mysql.ErrJSONUsedAsKey,
+		codeCheckRefersUnknownColumn: mysql.ErrCheckConstraintRefersUnknownColumn,
+		codeDupCheckName:             mysql.ErrCheckConstraintDupName,
+		codeDependentByCheck:         mysql.ErrDependentByCheckConstraint,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassDDL] = ddlMySQLErrCodes
 }
@@ -20,6 +20,7 @@ package ddl
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/ast"
@@ -53,8 +54,10 @@ func (d *ddl) CreateSchema(ctx context.Context, schema model.CIStr, charsetInfo
 		Name: schema,
 	}
 	if charsetInfo != nil {
-		dbInfo.Charset = charsetInfo.Chs
-		dbInfo.Collate = charsetInfo.Col
+		dbInfo.Charset, dbInfo.Collate, err = ResolveCharsetCollate(charsetInfo.Chs, charsetInfo.Col)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	} else {
 		dbInfo.Charset, dbInfo.Collate = getDefaultCharsetAndCollate()
 	}
@@ -89,6 +92,30 @@ func (d *ddl) DropSchema(ctx context.Context, schema model.CIStr) (err error) {
 	return errors.Trace(err)
 }
 
+func (d *ddl) AlterSchema(ctx context.Context, schema model.CIStr, charsetInfo *ast.CharsetOpt) (err error) {
+	is := d.GetInformationSchema()
+	dbInfo, ok := is.SchemaByName(schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenByArgs(schema.O)
+	}
+
+	toCharset, toCollate, err := ResolveCharsetCollate(charsetInfo.Chs, charsetInfo.Col)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   dbInfo.ID,
+		Type:       model.ActionModifySchemaCharsetAndCollate,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{toCharset, toCollate},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func checkTooLongSchema(schema model.CIStr) error {
 	if len(schema.L) > mysql.MaxDatabaseNameLength {
 		return ErrTooLongIdent.Gen("too long schema %s", schema)
@@ -104,12 +131,51 @@ func checkTooLongTable(table model.CIStr) error {
 }
 
 func getDefaultCharsetAndCollate() (string, string) {
-	// TODO: TableDefaultCharset-->DatabaseDefaultCharset-->SystemDefaultCharset.
 	// TODO: Change TableOption parser to parse collate.
 	// This is a tmp solution.
 	return "utf8", "utf8_unicode_ci"
 }
 
+// ResolveCharsetCollate checks the charset and collation specified in a statement
+// and fills the missing one with the default of the other.
+func ResolveCharsetCollate(chs, col string) (string, string, error) {
+	chs, col = strings.ToLower(chs), strings.ToLower(col)
+	if chs != "" && chs != charset.CharsetBin && !charset.ValidCharsetAndCollation(chs, "") {
+		return "", "", ErrUnknownCharacterSet.GenByArgs(chs)
+	}
+	if col == "" {
+		if chs == "" {
+			chs, col = getDefaultCharsetAndCollate()
+			return chs, col, nil
+		}
+		defCol, err := charset.GetDefaultCollation(chs)
+		return chs, defCol, errors.Trace(err)
+	}
+	for _, c := range charset.GetCollations() {
+		if c.Name != col {
+			continue
+		}
+		if chs == "" {
+			return c.CharsetName, col, nil
+		}
+		if c.CharsetName != chs {
+			return "", "", ErrCollationCharsetMismatch.GenByArgs(col, chs)
+		}
+		return chs, col, nil
+	}
+	return "", "", ErrUnknownCollation.GenByArgs(col)
+}
+
+// getTableCharsetAndCollate returns the default charset and collation of the columns of a table,
+// chs and col are the ones of the table, the table inherits the ones of the database if both are empty.
+func getTableCharsetAndCollate(chs, col string, schema *model.DBInfo) (string, string, error) {
+	if chs == "" && col == "" {
+		chs, col = schema.Charset, schema.Collate
+	}
+	chs, col, err := ResolveCharsetCollate(chs, col)
+	return chs, col, errors.Trace(err)
+}
+
 func setColumnFlagWithConstraint(colMap map[string]*table.Column, v *ast.Constraint) {
 	switch v.Tp {
 	case ast.ConstraintPrimaryKey:
@@ -155,11 +221,11 @@ func setColumnFlagWithConstraint(colMap map[string]*table.Column, v *ast.Constra
 }
 
 func buildColumnsAndConstraints(ctx context.Context, colDefs []*ast.ColumnDef,
-	constraints []*ast.Constraint) ([]*table.Column, []*ast.Constraint, error) {
+	constraints []*ast.Constraint, tblCharset, tblCollate string) ([]*table.Column, []*ast.Constraint, error) {
 	var cols []*table.Column
 	colMap := map[string]*table.Column{}
 	for i, colDef := range colDefs {
-		col, cts, err := buildColumnAndConstraint(ctx, i, colDef)
+		col, cts, err := buildColumnAndConstraint(ctx, i, colDef, tblCharset, tblCollate)
 		if err != nil {
 			return nil, nil, errors.Trace(err)
 		}
@@ -175,28 +241,46 @@ func buildColumnsAndConstraints(ctx context.Context, colDefs []*ast.ColumnDef,
 	return cols, constraints, nil
 }
 
-func setCharsetCollationFlenDecimal(tp *types.FieldType) {
-	if len(tp.Charset) == 0 {
+// setCharsetCollationFlenDecimal fills the unspecified attributes of a column type, a string column
+// without a charset and collation uses tblCharset and tblCollate, which are the defaults of the table.
+func setCharsetCollationFlenDecimal(tp *types.FieldType, tblCharset, tblCollate string) error {
+	if len(tp.Charset) == 0 && len(tp.Collate) == 0 {
 		switch tp.Tp {
 		case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
-			tp.Charset, tp.Collate = getDefaultCharsetAndCollate()
+			tp.Charset, tp.Collate = tblCharset, tblCollate
 		default:
 			tp.Charset = charset.CharsetBin
 			tp.Collate = charset.CharsetBin
 		}
+	} else {
+		var err error
+		tp.Charset, tp.Collate, err = ResolveCharsetCollate(tp.Charset, tp.Collate)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	// If flen is not assigned, assigned it by type.
 	if tp.Flen == types.UnspecifiedLength {
 		tp.Flen = mysql.GetDefaultFieldLength(tp.Tp)
+		switch tp.Tp {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+			if mysql.HasUnsignedFlag(tp.Flag) {
+				// An unsigned integer has no sign to display.
+				tp.Flen--
+			}
+		}
 	}
 	if tp.Decimal == types.UnspecifiedLength {
 		tp.Decimal = mysql.GetDefaultDecimal(tp.Tp)
 	}
+	return nil
 }
 
 func buildColumnAndConstraint(ctx context.Context, offset int,
-	colDef *ast.ColumnDef) (*table.Column, []*ast.Constraint, error) {
-	setCharsetCollationFlenDecimal(colDef.Tp)
+	colDef *ast.ColumnDef, tblCharset, tblCollate string) (*table.Column, []*ast.Constraint, error) {
+	if err := setCharsetCollationFlenDecimal(colDef.Tp, tblCharset, tblCollate); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	col, cts, err := columnDefToCol(ctx, offset, colDef)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -284,8 +368,15 @@ func columnDefToCol(ctx context.Context, offset int, colDef *ast.ColumnDef) (*ta
 				if err != nil {
 					return nil, nil, errors.Trace(err)
 				}
+				if utf8.RuneCountInString(col.Comment) > maxColumnCommentLength {
+					return nil, nil, errTooLongFieldComment.GenByArgs(col.Name.O, maxColumnCommentLength)
+				}
 			case ast.ColumnOptionFulltext:
 				// Do nothing.
+			case ast.ColumnOptionCheck:
+				// A column check constraint is the same as a table check constraint.
+				constraint := &ast.Constraint{Tp: ast.ConstraintCheck, Expr: v.Expr}
+				constraints = append(constraints, constraint)
 			}
 		}
 	}
@@ -451,7 +542,11 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 	fkNames := map[string]bool{}
 
 	// Check not empty constraint name whether is duplicated.
+	// The names of check constraints are checked and set in buildTableInfo.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			err := checkDuplicateConstraint(fkNames, constr.Name, true)
 			if err != nil {
@@ -467,6 +562,9 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 
 	// Set empty constraint names.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			setEmptyConstraintName(fkNames, constr, true)
 		} else {
@@ -498,6 +596,7 @@ func (d *ddl) buildTableInfo(tableName model.CIStr, cols []*table.Column, constr
 			}
 			var fk model.FKInfo
 			fk.Name = model.NewCIStr(constr.Name)
+			fk.RefSchema = constr.Refer.Table.Schema
 			fk.RefTable = constr.Refer.Table.Name
 			fk.State = model.StatePublic
 			for _, key := range constr.Keys {
@@ -518,6 +617,14 @@ func (d *ddl) buildTableInfo(tableName model.CIStr, cols []*table.Column, constr
 			tbInfo.ForeignKeys = append(tbInfo.ForeignKeys, &fk)
 			continue
 		}
+		if constr.Tp == ast.ConstraintCheck {
+			check, err := buildCheckInfo(tbInfo, cols, constr)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			tbInfo.Checks = append(tbInfo.Checks, check)
+			continue
+		}
 		if constr.Tp == ast.ConstraintPrimaryKey {
 			if len(constr.Keys) == 1 {
 				key := constr.Keys[0]
@@ -575,6 +682,85 @@ func (d *ddl) buildTableInfo(tableName model.CIStr, cols []*table.Column, constr
 	return
 }
 
+// checkColumnNameCollector collects the names of the columns used in an expression.
+type checkColumnNameCollector struct {
+	names []model.CIStr
+}
+
+// Enter implements ast.Visitor interface.
+func (c *checkColumnNameCollector) Enter(inNode ast.Node) (ast.Node, bool) {
+	if v, ok := inNode.(*ast.ColumnNameExpr); ok {
+		c.names = append(c.names, v.Name.Name)
+	}
+	return inNode, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *checkColumnNameCollector) Leave(inNode ast.Node) (ast.Node, bool) {
+	return inNode, true
+}
+
+// buildCheckInfo builds the check constraint, the constraint without a name is named
+// as "<table>_chk_<n>" like MySQL.
+func buildCheckInfo(tbInfo *model.TableInfo, cols []*table.Column, constr *ast.Constraint) (*model.CheckInfo, error) {
+	name := constr.Name
+	if name == "" {
+		for i := 1; ; i++ {
+			name = fmt.Sprintf("%s_chk_%d", tbInfo.Name.O, i)
+			if findCheck(tbInfo.Checks, name) == nil {
+				break
+			}
+		}
+	} else if findCheck(tbInfo.Checks, name) != nil {
+		return nil, errDupCheckName.GenByArgs(name)
+	}
+	collector := &checkColumnNameCollector{}
+	constr.Expr.Accept(collector)
+	check := &model.CheckInfo{
+		Name:  model.NewCIStr(name),
+		Expr:  constr.Expr.Text(),
+		State: model.StatePublic,
+	}
+	for _, colName := range collector.names {
+		if table.FindCol(cols, colName.O) == nil {
+			return nil, errCheckRefersUnknownColumn.GenByArgs(name, colName.O)
+		}
+		if !containsCIStr(check.Cols, colName) {
+			check.Cols = append(check.Cols, colName)
+		}
+	}
+	return check, nil
+}
+
+func findCheck(checks []*model.CheckInfo, name string) *model.CheckInfo {
+	for _, check := range checks {
+		if check.Name.L == strings.ToLower(name) {
+			return check
+		}
+	}
+	return nil
+}
+
+func containsCIStr(names []model.CIStr, name model.CIStr) bool {
+	for _, n := range names {
+		if n.L == name.L {
+			return true
+		}
+	}
+	return false
+}
+
+// checkColumnWithCheck returns an error if the column is used by a check constraint, the
+// column can't be dropped or renamed then.
+func checkColumnWithCheck(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, check := range tblInfo.Checks {
+		if containsCIStr(check.Cols, colName) {
+			return errDependentByCheck.GenByArgs(check.Name.O, colName.O)
+		}
+	}
+	return nil
+}
+
 func (d *ddl) CreateTable(ctx context.Context, ident ast.Ident, colDefs []*ast.ColumnDef,
 	constraints []*ast.Constraint, options []*ast.TableOption) (err error) {
 	is := d.GetInformationSchema()
@@ -585,29 +771,14 @@ func (d *ddl) CreateTable(ctx context.Context, ident ast.Ident, colDefs []*ast.C
 	if is.TableExists(ident.Schema, ident.Name) {
 		return errors.Trace(infoschema.ErrTableExists.GenByArgs(ident))
 	}
-	if err = checkTooLongTable(ident.Name); err != nil {
-		return errors.Trace(err)
-	}
-	if err = checkDuplicateColumn(colDefs); err != nil {
-		return errors.Trace(err)
-	}
-	if err = checkTooLongColumn(colDefs); err != nil {
-		return errors.Trace(err)
-	}
-
-	cols, newConstraints, err := buildColumnsAndConstraints(ctx, colDefs, constraints)
+	tbInfo, err := d.BuildTableInfo(ctx, ident, colDefs, constraints, options)
 	if err != nil {
 		return errors.Trace(err)
 	}
-
-	err = checkConstraintNames(newConstraints)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	tbInfo, err := d.buildTableInfo(ident.Name, cols, newConstraints)
-	if err != nil {
-		return errors.Trace(err)
+	for _, fk := range tbInfo.ForeignKeys {
+		if err = checkReferencedIndex(is, ident.Schema, tbInfo, fk); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	job := &model.Job{
@@ -617,8 +788,6 @@ func (d *ddl) CreateTable(ctx context.Context, ident ast.Ident, colDefs []*ast.C
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{tbInfo},
 	}
-
-	handleTableOptions(options, tbInfo, schema.ID)
 	err = d.doDDLJob(ctx, job)
 	if err == nil {
 		if tbInfo.AutoIncID > 1 {
@@ -631,6 +800,60 @@ func (d *ddl) CreateTable(ctx context.Context, ident ast.Ident, colDefs []*ast.C
 	return errors.Trace(err)
 }
 
+func (d *ddl) CreateView(ctx context.Context, ident ast.Ident, cols []*model.ColumnInfo, view *model.ViewInfo, orReplace bool) error {
+	is := d.GetInformationSchema()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenByArgs(ident.Schema)
+	}
+	if err := checkTooLongTable(ident.Name); err != nil {
+		return errors.Trace(err)
+	}
+	if view.Charset == "" {
+		view.Charset, view.Collate = getDefaultCharsetAndCollate()
+	}
+
+	tbInfo := &model.TableInfo{
+		Name:    ident.Name,
+		Charset: view.Charset,
+		Collate: view.Collate,
+		View:    view,
+	}
+	old, err := is.TableByName(ident.Schema, ident.Name)
+	if err == nil {
+		if !orReplace {
+			return infoschema.ErrTableExists.GenByArgs(ident)
+		}
+		if !old.Meta().IsView() {
+			return infoschema.ErrWrongObject.GenByArgs(ident.Schema, ident.Name, "VIEW")
+		}
+		// The replaced view keeps its table ID.
+		tbInfo.ID = old.Meta().ID
+	} else {
+		tbInfo.ID, err = d.genGlobalID()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for i, col := range cols {
+		col.ID = allocateColumnID(tbInfo)
+		col.Offset = i
+		col.State = model.StatePublic
+		tbInfo.Columns = append(tbInfo.Columns, col)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		Type:       model.ActionCreateView,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo, orReplace},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 // If create table with auto_increment option, we should rebase tableAutoIncID value.
 func (d *ddl) handleAutoIncID(tbInfo *model.TableInfo, schemaID int64) error {
 	alloc := autoid.NewAllocator(d.store, schemaID)
@@ -648,6 +871,70 @@ func (d *ddl) handleAutoIncID(tbInfo *model.TableInfo, schemaID int64) error {
 	return nil
 }
 
+// The comments longer than the limits are rejected like MySQL in strict mode, the lengths are
+// counted in characters.
+const (
+	maxColumnCommentLength = 1024
+	maxTableCommentLength  = 2048
+)
+
+// BuildTableInfo checks the definition of a new table and builds its table info with a new table ID,
+// the table isn't created.
+func (d *ddl) BuildTableInfo(ctx context.Context, ident ast.Ident, colDefs []*ast.ColumnDef,
+	constraints []*ast.Constraint, options []*ast.TableOption) (*model.TableInfo, error) {
+	schema, ok := d.GetInformationSchema().SchemaByName(ident.Schema)
+	if !ok {
+		return nil, infoschema.ErrDatabaseNotExists.GenByArgs(ident.Schema)
+	}
+	if err := checkTooLongTable(ident.Name); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := checkDuplicateColumn(colDefs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := checkTooLongColumn(colDefs); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var tblCharset, tblCollate string
+	for _, op := range options {
+		switch op.Tp {
+		case ast.TableOptionCharset:
+			tblCharset = op.StrValue
+		case ast.TableOptionCollate:
+			tblCollate = op.StrValue
+		}
+	}
+	tblCharset, tblCollate, err := getTableCharsetAndCollate(tblCharset, tblCollate, schema)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cols, newConstraints, err := buildColumnsAndConstraints(ctx, colDefs, constraints, tblCharset, tblCollate)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = checkConstraintNames(newConstraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	tbInfo, err := d.buildTableInfo(ident.Name, cols, newConstraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	handleTableOptions(options, tbInfo, schema.ID)
+	if utf8.RuneCountInString(tbInfo.Comment) > maxTableCommentLength {
+		return nil, errTooLongTableComment.GenByArgs(tbInfo.Name.O, maxTableCommentLength)
+	}
+	if tbInfo.Charset == "" && tbInfo.Collate == "" {
+		// The table inherits the default charset and collation of the database.
+		tbInfo.Charset, tbInfo.Collate = schema.Charset, schema.Collate
+	}
+	return tbInfo, nil
+}
+
 // Add create table options into TableInfo.
 func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo, schemaID int64) {
 	for _, op := range options {
@@ -659,7 +946,7 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo, sch
 		case ast.TableOptionCharset:
 			tbInfo.Charset = op.StrValue
 		case ast.TableOptionCollate:
-			tbInfo.Charset = op.StrValue
+			tbInfo.Collate = op.StrValue
 		}
 	}
 }
@@ -687,6 +974,8 @@ func (d *ddl) AlterTable(ctx context.Context, ident ast.Ident, specs []*ast.Alte
 				err = d.CreateIndex(ctx, ident, true, model.NewCIStr(constr.Name), spec.Constraint.Keys)
 			case ast.ConstraintForeignKey:
 				err = d.CreateForeignKey(ctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, spec.Constraint.Refer)
+			case ast.ConstraintCheck:
+				err = errUnsupportedAddCheck
 			default:
 				// Nothing to do now.
 			}
@@ -711,7 +1000,8 @@ func (d *ddl) AlterTable(ctx context.Context, ident ast.Ident, specs []*ast.Alte
 func checkColumnConstraint(constraints []*ast.ColumnOption) error {
 	for _, constraint := range constraints {
 		switch constraint.Tp {
-		case ast.ColumnOptionAutoIncrement, ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniq, ast.ColumnOptionUniqKey:
+		case ast.ColumnOptionAutoIncrement, ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniq, ast.ColumnOptionUniqKey,
+			ast.ColumnOptionCheck:
 			return errUnsupportedAddColumn.Gen("unsupported add column constraint - %v", constraint.Tp)
 		}
 	}
@@ -751,7 +1041,11 @@ func (d *ddl) AddColumn(ctx context.Context, ti ast.Ident, spec *ast.AlterTableS
 	// Ingore table constraints now, maybe return error later.
 	// We use length(t.Cols()) as the default offset firstly, we will change the
 	// column's offset later.
-	col, _, err = buildColumnAndConstraint(ctx, len(t.Cols()), spec.NewColumn)
+	tblCharset, tblCollate, err := getTableCharsetAndCollate(t.Meta().Charset, t.Meta().Collate, schema)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	col, _, err = buildColumnAndConstraint(ctx, len(t.Cols()), spec.NewColumn, tblCharset, tblCollate)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -806,6 +1100,9 @@ func (d *ddl) DropColumn(ctx context.Context, ti ast.Ident, colName model.CIStr)
 	if col.IsPKHandleColumn(tblInfo) {
 		return errUnsupportedPKHandle
 	}
+	if err = checkColumnWithCheck(tblInfo, colName); err != nil {
+		return errors.Trace(err)
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -880,7 +1177,13 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, ident ast.Ident, origi
 		// Make sure the column definition is simple field type.
 		return nil, errUnsupportedModifyColumn
 	}
-	setCharsetCollationFlenDecimal(spec.NewColumn.Tp)
+	tblCharset, tblCollate, err := getTableCharsetAndCollate(t.Meta().Charset, t.Meta().Collate, schema)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = setCharsetCollationFlenDecimal(spec.NewColumn.Tp, tblCharset, tblCollate); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if !modifiable(&col.FieldType, spec.NewColumn.Tp) {
 		return nil, errUnsupportedModifyColumn
 	}
@@ -888,6 +1191,11 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, ident ast.Ident, origi
 	newCol := *col
 	newCol.FieldType = *spec.NewColumn.Tp
 	newCol.Name = spec.NewColumn.Name.Name
+	if newCol.Name.L != originalColName.L {
+		if err = checkColumnWithCheck(t.Meta(), originalColName); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	job := &model.Job{
 		SchemaID:   schema.ID,
 		TableID:    t.Meta().ID,
@@ -1047,6 +1355,7 @@ func (d *ddl) CreateIndex(ctx context.Context, ti ast.Ident, unique bool, indexN
 func buildFKInfo(fkName model.CIStr, keys []*ast.IndexColName, refer *ast.ReferenceDef) (*model.FKInfo, error) {
 	var fkInfo model.FKInfo
 	fkInfo.Name = fkName
+	fkInfo.RefSchema = refer.Table.Schema
 	fkInfo.RefTable = refer.Table.Name
 
 	fkInfo.Cols = make([]model.CIStr, len(keys))
@@ -1066,6 +1375,47 @@ func buildFKInfo(fkName model.CIStr, keys []*ast.IndexColName, refer *ast.Refere
 
 }
 
+// checkReferencedIndex checks that the columns referenced by fk of the table tbInfo in schema are the integer
+// primary key or the leading columns of an index of the referenced table, so the foreign key checks can find
+// the parent rows without scanning the table. The check is skipped if the referenced table doesn't exist.
+func checkReferencedIndex(is infoschema.InfoSchema, schema model.CIStr, tbInfo *model.TableInfo, fk *model.FKInfo) error {
+	refSchema := schema
+	if fk.RefSchema.L != "" {
+		refSchema = fk.RefSchema
+	}
+	refInfo := tbInfo
+	if refSchema.L != schema.L || fk.RefTable.L != tbInfo.Name.L {
+		t, err := is.TableByName(refSchema, fk.RefTable)
+		if err != nil {
+			return nil
+		}
+		refInfo = t.Meta()
+	}
+	if refInfo.PKIsHandle && len(fk.RefCols) == 1 {
+		for _, col := range refInfo.Columns {
+			if col.Name.L == fk.RefCols[0].L && mysql.HasPriKeyFlag(col.Flag) {
+				return nil
+			}
+		}
+	}
+	for _, idx := range refInfo.Indices {
+		if len(idx.Columns) < len(fk.RefCols) {
+			continue
+		}
+		matched := true
+		for i, name := range fk.RefCols {
+			if idx.Columns[i].Name.L != name.L || idx.Columns[i].Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return nil
+		}
+	}
+	return infoschema.ErrCannotAddForeign
+}
+
 func (d *ddl) CreateForeignKey(ctx context.Context, ti ast.Ident, fkName model.CIStr, keys []*ast.IndexColName, refer *ast.ReferenceDef) error {
 	is := d.infoHandle.Get()
 	schema, ok := is.SchemaByName(ti.Schema)
@@ -1082,6 +1432,9 @@ func (d *ddl) CreateForeignKey(ctx context.Context, ti ast.Ident, fkName model.C
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkReferencedIndex(is, ti.Schema, t.Meta(), fkInfo); err != nil {
+		return errors.Trace(err)
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -118,6 +118,14 @@ func (s *testDBSuite) TestMySQLErrorCode(c *C) {
 	s.testErrorCode(c, sql, tmysql.ErrKeyColumnDoesNotExits)
 	sql = "create table test_error_code1 (c1 int, c2 int, c3 int, primary key(c_not_exist))"
 	s.testErrorCode(c, sql, tmysql.ErrKeyColumnDoesNotExits)
+	sql = fmt.Sprintf("create table test_error_code1 (c1 int comment '%s')", strings.Repeat("中", 1025))
+	s.testErrorCode(c, sql, tmysql.ErrTooLongFieldComment)
+	sql = fmt.Sprintf("create table test_error_code1 (c1 int) comment '%s'", strings.Repeat("中", 2049))
+	s.testErrorCode(c, sql, tmysql.ErrTooLongTableComment)
+	sql = "create table test_error_code1 (c1 int, check (c_not_exist > 0))"
+	s.testErrorCode(c, sql, tmysql.ErrCheckConstraintRefersUnknownColumn)
+	sql = "create table test_error_code1 (c1 int, constraint chk check (c1 > 0), constraint chk check (c1 < 10))"
+	s.testErrorCode(c, sql, tmysql.ErrCheckConstraintDupName)
 	// add column
 	sql = "alter table test_error_code_succ add column c1 int"
 	s.testErrorCode(c, sql, tmysql.ErrDupFieldName)
@@ -137,6 +145,11 @@ func (s *testDBSuite) TestMySQLErrorCode(c *C) {
 	s.testErrorCode(c, sql, tmysql.ErrCantDropFieldOrKey)
 	sql = "alter table test_error_code_succ drop column c3"
 	s.testErrorCode(c, sql, int(tmysql.ErrUnknown))
+	s.tk.MustExec("create table test_error_code_check (c1 int, c2 int check (c2 > 0))")
+	sql = "alter table test_error_code_check drop column c2"
+	s.testErrorCode(c, sql, tmysql.ErrDependentByCheckConstraint)
+	sql = "alter table test_error_code_check change c2 c3 int"
+	s.testErrorCode(c, sql, tmysql.ErrDependentByCheckConstraint)
 	// modify column
 	sql = "alter table test_error_code_succ modify testx.test_error_code_succ.c1 bigint"
 	s.testErrorCode(c, sql, tmysql.ErrWrongDBName)
@@ -435,7 +448,7 @@ LOOP:
 		}
 	}
 
-	rows := s.mustQuery(c, "explain select c1 from t1 where c3 >= 0")
+	rows := s.mustQuery(c, "explain format = json select c1 from t1 where c3 >= 0")
 	c.Assert(strings.Contains(fmt.Sprintf("%v", rows), "c3_index"), IsFalse)
 
 	// check in index, must no index in kv
@@ -516,6 +529,26 @@ func (s *testDBSuite) TestColumn(c *C) {
 	s.testAddColumn(c)
 	s.testDropColumn(c)
 	s.testChangeColumn(c)
+	s.testAddColumnPosition(c)
+}
+
+func (s *testDBSuite) testAddColumnPosition(c *C) {
+	s.tk.MustExec("drop table if exists t_pos")
+	s.tk.MustExec("create table t_pos (a int, b int, index ib (b))")
+	s.tk.MustExec("insert into t_pos values (1, 2)")
+	// The existing rows get the default value of the new column.
+	s.tk.MustExec("alter table t_pos add column c int default 3 first")
+	s.tk.MustExec("alter table t_pos add column d int default 4 after a")
+	s.tk.MustQuery("select * from t_pos").Check(testkit.Rows("3 1 4 2"))
+	s.tk.MustExec("alter table t_pos drop column c")
+	s.tk.MustQuery("select * from t_pos").Check(testkit.Rows("1 4 2"))
+	// The column covered by an index can't be dropped.
+	_, err := s.tk.Exec("alter table t_pos drop column b")
+	c.Assert(err, NotNil)
+	s.tk.MustExec("alter table t_pos drop index ib")
+	s.tk.MustExec("alter table t_pos drop column b")
+	s.tk.MustQuery("select * from t_pos").Check(testkit.Rows("1 4"))
+	s.tk.MustExec("drop table t_pos")
 }
 
 func sessionExec(c *C, s kv.Storage, sql string) {
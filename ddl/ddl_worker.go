@@ -309,7 +309,8 @@ func (d *ddl) handleDDLJobQueue() error {
 		if job.State == model.JobRunning || job.State == model.JobDone {
 			switch job.Type {
 			case model.ActionCreateSchema, model.ActionDropSchema, model.ActionCreateTable,
-				model.ActionTruncateTable, model.ActionDropTable:
+				model.ActionTruncateTable, model.ActionDropTable, model.ActionModifySchemaCharsetAndCollate,
+				model.ActionCreateView:
 				// Do not need to wait for those DDL, because those DDL do not need to modify data,
 				// So there is no data inconsistent issue.
 			default:
@@ -348,10 +349,14 @@ func (d *ddl) runDDLJob(t *meta.Meta, job *model.Job) {
 		err = d.onCreateSchema(t, job)
 	case model.ActionDropSchema:
 		err = d.onDropSchema(t, job)
+	case model.ActionModifySchemaCharsetAndCollate:
+		err = d.onModifySchemaCharsetAndCollate(t, job)
 	case model.ActionCreateTable:
 		err = d.onCreateTable(t, job)
 	case model.ActionDropTable:
 		err = d.onDropTable(t, job)
+	case model.ActionCreateView:
+		err = d.onCreateView(t, job)
 	case model.ActionAddColumn:
 		err = d.onAddColumn(t, job)
 	case model.ActionDropColumn:
@@ -44,6 +44,10 @@ func buildIndexInfo(tblInfo *model.TableInfo, unique bool, indexName model.CIStr
 				ic.Column.Name)
 		}
 
+		if col.FieldType.Tp == mysql.TypeJSON {
+			return nil, errJSONUsedAsKey.GenByArgs(col.Name.O)
+		}
+
 		// Length must be specified for BLOB and TEXT column indexes.
 		if types.IsTypeBlob(col.FieldType.Tp) && ic.Length == types.UnspecifiedLength {
 			return nil, errors.Trace(errBlobKeyWithoutLength)
@@ -127,6 +127,40 @@ func (d *ddl) onDropSchema(t *meta.Meta, job *model.Job) error {
 	return errors.Trace(err)
 }
 
+func (d *ddl) onModifySchemaCharsetAndCollate(t *meta.Meta, job *model.Job) error {
+	var toCharset, toCollate string
+	if err := job.DecodeArgs(&toCharset, &toCollate); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobCancelled
+		return errors.Trace(err)
+	}
+
+	dbInfo, err := t.GetDatabase(job.SchemaID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if dbInfo == nil {
+		job.State = model.JobCancelled
+		return errors.Trace(infoschema.ErrDatabaseNotExists)
+	}
+
+	dbInfo.Charset = toCharset
+	dbInfo.Collate = toCollate
+	if err = t.UpdateDatabase(dbInfo); err != nil {
+		return errors.Trace(err)
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Finish this job.
+	job.State = model.JobDone
+	job.SchemaState = model.StatePublic
+	addDBHistoryInfo(job, ver, dbInfo)
+	return nil
+}
+
 func getIDs(tables []*model.TableInfo) []int64 {
 	ids := make([]int64, 0, len(tables))
 	for _, t := range tables {
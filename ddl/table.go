@@ -77,6 +77,59 @@ func (d *ddl) onCreateTable(t *meta.Meta, job *model.Job) error {
 	}
 }
 
+func (d *ddl) onCreateView(t *meta.Meta, job *model.Job) error {
+	schemaID := job.SchemaID
+	tbInfo := &model.TableInfo{}
+	var orReplace bool
+	if err := job.DecodeArgs(tbInfo, &orReplace); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobCancelled
+		return errors.Trace(err)
+	}
+
+	// Check this view's database.
+	tables, err := t.ListTables(schemaID)
+	if terror.ErrorEqual(err, meta.ErrDBNotExists) {
+		job.State = model.JobCancelled
+		return errors.Trace(infoschema.ErrDatabaseNotExists)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Check the view, it can only replace the view which has the same ID.
+	var replaced bool
+	for _, tbl := range tables {
+		if tbl.Name.L == tbInfo.Name.L {
+			if !orReplace || tbl.ID != tbInfo.ID {
+				job.State = model.JobCancelled
+				return errors.Trace(infoschema.ErrTableExists.GenByArgs(tbl.Name))
+			}
+			replaced = true
+		}
+	}
+
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// none -> public
+	tbInfo.State = model.StatePublic
+	if replaced {
+		err = t.UpdateTable(schemaID, tbInfo)
+	} else {
+		err = t.CreateTable(schemaID, tbInfo)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Finish this job.
+	job.SchemaState = model.StatePublic
+	job.State = model.JobDone
+	addTableHistoryInfo(job, ver, tbInfo)
+	return nil
+}
+
 func (d *ddl) onDropTable(t *meta.Meta, job *model.Job) error {
 	schemaID := job.SchemaID
 	tableID := job.TableID
@@ -225,6 +278,14 @@ func (d *ddl) onTruncateTable(t *meta.Meta, job *model.Job) error {
 		job.State = model.JobCancelled
 		return errors.Trace(err)
 	}
+	// The new table starts with an empty auto ID, rebase it to the start value of the table definition.
+	if tblInfo.AutoIncID > 1 {
+		_, err = t.GenAutoTableID(schemaID, newTableID, tblInfo.AutoIncID-1)
+		if err != nil {
+			job.State = model.JobCancelled
+			return errors.Trace(err)
+		}
+	}
 
 	ver, err := updateSchemaVersion(t, job)
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/codec"
@@ -71,6 +72,8 @@ type selectResult struct {
 	fields     []*types.FieldType
 	resp       kv.Response
 	ignoreData bool
+	// sc is used to collect the warnings returned by coprocessor.
+	sc *variable.StatementContext
 
 	results chan resultWithErr
 	closed  chan struct{}
@@ -102,6 +105,7 @@ func (r *selectResult) fetch() {
 			reader:     reader,
 			aggregate:  r.aggregate,
 			ignoreData: r.ignoreData,
+			sc:         r.sc,
 			done:       make(chan error, 1),
 		}
 		go pr.fetch()
@@ -148,6 +152,7 @@ type partialResult struct {
 	cursor     int
 	dataOffset int64
 	ignoreData bool
+	sc         *variable.StatementContext
 
 	done    chan error
 	fetched bool
@@ -174,6 +179,11 @@ func (pr *partialResult) fetch() {
 		pr.done <- errInvalidResp.Gen("[%d %s]", pr.resp.Error.GetCode(), pr.resp.Error.GetMsg())
 		return
 	}
+	if pr.sc != nil {
+		for _, w := range pr.resp.Warnings {
+			pr.sc.AppendWarning(terror.ClassXEval.New(terror.ErrCode(w.GetCode()), w.GetMsg()))
+		}
+	}
 
 	pr.done <- nil
 }
@@ -264,10 +274,11 @@ func (pr *partialResult) Close() error {
 }
 
 // Select do a select request, returns SelectResult.
+// sc: The statement context which the warnings returned by coprocessor are appended to.
 // conncurrency: The max concurrency for underlying coprocessor request.
 // keepOrder: If the result should returned in key order. For example if we need keep data in order by
 //            scan index, we should set keepOrder to true.
-func Select(client kv.Client, req *tipb.SelectRequest, keyRanges []kv.KeyRange, concurrency int, keepOrder bool) (SelectResult, error) {
+func Select(client kv.Client, sc *variable.StatementContext, req *tipb.SelectRequest, keyRanges []kv.KeyRange, concurrency int, keepOrder bool) (SelectResult, error) {
 	var err error
 	startTs := time.Now()
 	defer func() {
@@ -294,6 +305,7 @@ func Select(client kv.Client, req *tipb.SelectRequest, keyRanges []kv.KeyRange,
 	}
 	result := &selectResult{
 		resp:    resp,
+		sc:      sc,
 		results: make(chan resultWithErr, 5),
 		closed:  make(chan struct{}),
 	}
@@ -46,6 +46,23 @@ const (
 	FlagTruncateAsWarning uint64 = 1 << 1
 )
 
+// ToPBWarnings converts the warnings of the statement context to tipb errors, so they can be returned
+// to TiDB in the select response.
+func ToPBWarnings(warnings []error) []*tipb.Error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	pbWarnings := make([]*tipb.Error, 0, len(warnings))
+	for _, w := range warnings {
+		pw := &tipb.Error{Code: 1, Msg: w.Error()}
+		if terr, ok := errors.Cause(w).(*terror.Error); ok {
+			pw.Code = int32(terr.Code())
+		}
+		pbWarnings = append(pbWarnings, pw)
+	}
+	return pbWarnings
+}
+
 // Evaluator evaluates tipb.Expr.
 type Evaluator struct {
 	Row        map[int64]types.Datum // column values.
@@ -53,7 +53,9 @@ func ComputeArithmetic(sc *variable.StatementContext, op tipb.ExprType, left typ
 	case tipb.ExprType_Plus:
 		return types.ComputePlus(a, b)
 	case tipb.ExprType_Div:
-		return types.ComputeDiv(sc, a, b)
+		// The coprocessor doesn't know the div_precision_increment of the session, the default is used.
+		// Divisions are only pushed down if the session uses the default.
+		return types.ComputeDiv(sc, a, b, types.DivFracIncr)
 	case tipb.ExprType_Minus:
 		return types.ComputeMinus(a, b)
 	case tipb.ExprType_Mul:
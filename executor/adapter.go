@@ -51,9 +51,14 @@ func (a *recordSet) Fields() ([]*ast.ResultField, error) {
 
 func (a *recordSet) Next() (*ast.Row, error) {
 	row, err := a.executor.Next()
-	if err != nil || row == nil {
+	if err != nil {
+		// The error is reported by SHOW ERRORS and SHOW WARNINGS like the errors returned by Exec.
+		a.ctx.GetSessionVars().StmtCtx.AppendError(err)
 		return nil, errors.Trace(err)
 	}
+	if row == nil {
+		return nil, nil
+	}
 	return &ast.Row{Data: row.Data}, nil
 }
 
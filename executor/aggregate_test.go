@@ -101,6 +101,19 @@ func (s *testSuite) TestAggregation(c *C) {
 	result.Check(testkit.Rows("1", "2", "2"))
 	result = tk.MustQuery("select sum(c) from t group by d")
 	result.Check(testkit.Rows("2", "4", "5"))
+	result = tk.MustQuery("select count(*), count(c), sum(c), avg(c), min(c), max(c) from t group by d")
+	result.Check(testkit.Rows("3 2 2 1.0000 1 1", "2 2 4 2.0000 1 3", "2 2 5 2.5000 1 4"))
+	result = tk.MustQuery("select count(*), count(c), sum(c), avg(c), min(c), max(c) from t")
+	result.Check(testkit.Rows("7 6 11 1.8333 1 4"))
+	// Aggregation without group by always returns one row.
+	result = tk.MustQuery("select count(*), count(c), sum(c), avg(c), min(c), max(c) from t where d > 5")
+	result.Check(testkit.Rows("0 0 <nil> <nil> <nil> <nil>"))
+	result = tk.MustQuery("select count(*) from t where d > 5 group by d")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select d, count(*) from t group by d having count(*) > 2 or d > 2")
+	result.Check(testkit.Rows("1 3", "3 2"))
+	_, err1 := tk.Exec("select d from t group by d having c > 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err1), IsTrue)
 	result = tk.MustQuery("select sum(c), sum(c+1), sum(c), sum(c+1) from t group by d")
 	result.Check(testkit.Rows("2 4 2 4", "4 6 4 6", "5 7 5 7"))
 	result = tk.MustQuery("select count(distinct c,d), count(c,d) from t")
@@ -255,6 +268,23 @@ func (s *testSuite) TestAggregation(c *C) {
 	result.Check(testkit.Rows("4", "2", "5"))
 	result = tk.MustQuery("select min(distinct b) from (select * from t1) t group by a")
 	result.Check(testkit.Rows("1", "2", "3"))
+	// Rows with a NULL argument are not counted, multiple arguments are deduplicated as a whole.
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1(g int, a int, b int, index(g))")
+	tk.MustExec("insert into t1 values (1, 1, 1), (1, 1, 1), (1, 1, 2), (1, null, 2), (1, 2, null), (2, 3, 3), (2, 3, 3)")
+	result = tk.MustQuery("select g, count(distinct a), count(distinct a, b), sum(distinct a), avg(distinct a) from t1 group by g order by g")
+	result.Check(testkit.Rows("1 2 2 3 1.5000", "2 1 1 3 3.0000"))
+	result = tk.MustQuery("select count(distinct a), count(distinct a, b), sum(distinct b) from t1 use index (g) group by g")
+	result.Check(testkit.Rows("2 2 3", "1 1 3"))
+	result = tk.MustQuery("select count(distinct a, b), count(distinct b, a), sum(distinct b) from t1")
+	result.Check(testkit.Rows("3 3 6"))
+	result = tk.MustQuery("select count(distinct a), sum(distinct a) from t1 where g = 3")
+	result.Check(testkit.Rows("0 <nil>"))
+	tk.MustExec("begin")
+	tk.MustExec("insert into t1 values (2, 4, 3), (2, null, null)")
+	result = tk.MustQuery("select g, count(distinct a), count(distinct a, b) from t1 group by g order by g")
+	result.Check(testkit.Rows("1 2 2", "2 2 2"))
+	tk.MustExec("rollback")
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (id int primary key, ds date)")
 	tk.MustExec("insert into t (id, ds) values (1, \"1991-09-05\"),(2,\"1991-09-05\"), (3, \"1991-09-06\"),(0,\"1991-09-06\")")
@@ -270,7 +300,139 @@ func (s *testSuite) TestAggregation(c *C) {
 	result.Check(testkit.Rows("<nil>", "<nil>"))
 
 	result = tk.MustQuery("select count(*) from information_schema.columns")
-	result.Check(testkit.Rows("529"))
+	result.Check(testkit.Rows("547"))
+}
+
+func (s *testSuite) TestGroupConcat(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a int, s varchar(10))")
+	tk.MustExec("insert t values (1, 3, 'x'), (1, 1, 'y'), (2, 2, null), (1, null, 'z'), (1, 1, 'w')")
+
+	// NULL values are skipped, and the result is NULL if all the values are NULL.
+	result := tk.MustQuery("select id, group_concat(a), group_concat(s) from t group by id order by id")
+	result.Check(testkit.Rows("1 3,1,1 x,y,z,w", "2 2 <nil>"))
+	result = tk.MustQuery("select group_concat(a, s) from t")
+	result.Check(testkit.Rows("3x,1y,1w"))
+	result = tk.MustQuery("select group_concat(a) from t where id = 3")
+	result.Check(testkit.Rows("<nil>"))
+
+	// ORDER BY and SEPARATOR.
+	result = tk.MustQuery("select id, group_concat(a order by a desc separator ';') from t group by id order by id")
+	result.Check(testkit.Rows("1 3;1;1", "2 2"))
+	result = tk.MustQuery("select group_concat(distinct a order by a separator '') from t")
+	result.Check(testkit.Rows("123"))
+	result = tk.MustQuery("select group_concat(s order by a, s desc) from t")
+	result.Check(testkit.Rows("z,y,w,x"))
+	result = tk.MustQuery("select group_concat(a), group_concat(a separator '-'), group_concat(a order by a) from t where id = 1")
+	result.Check(testkit.Rows("3,1,1 3-1-1 1,1,3"))
+
+	// The rows of a union are ordered together, the function isn't pushed down to each part.
+	tk.MustExec("drop table if exists u1, u2")
+	tk.MustExec("create table u1 (a int)")
+	tk.MustExec("create table u2 (a int)")
+	tk.MustExec("insert u1 values (1), (3), (5)")
+	tk.MustExec("insert u2 values (2), (4), (3)")
+	result = tk.MustQuery("select group_concat(a order by a desc separator '-') from (select a from u1 union all select a from u2) x")
+	result.Check(testkit.Rows("5-4-3-3-2-1"))
+	result = tk.MustQuery("select a, group_concat(a order by a desc separator '-') from (select a from u1 union all select a from u2) x group by a order by a")
+	result.Check(testkit.Rows("1 1", "2 2", "3 3-3", "4 4", "5 5"))
+	result = tk.MustQuery("select group_concat(b order by b desc) from (select a + 1 as b from u1) x")
+	result.Check(testkit.Rows("6,4,2"))
+
+	// The result is truncated to group_concat_max_len with a warning.
+	tk.MustExec("set @@group_concat_max_len = 3")
+	result = tk.MustQuery("select group_concat(s) from t")
+	result.Check(testkit.Rows("x,y"))
+	warnings := tk.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(warnings[0].Error(), Matches, ".*Row 1 was cut by GROUP_CONCAT.*")
+	result = tk.MustQuery("select id, group_concat(s order by s) from t group by id order by id")
+	result.Check(testkit.Rows("1 w,x", "2 <nil>"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	result = tk.MustQuery("select group_concat(a order by a) from t where id = 2")
+	result.Check(testkit.Rows("2"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+
+	// A multi-byte character is never split.
+	tk.MustExec("set @@group_concat_max_len = 4")
+	result = tk.MustQuery("select group_concat(s separator '') from (select '中' s union all select '文') as t")
+	result.Check(testkit.Rows("中"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+}
+
+func (s *testSuite) TestVariance(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a int, b double)")
+	tk.MustExec("insert t values (1, 2, 1e9 + 4), (1, 4, 1e9 + 7), (1, 4, 1e9 + 13), (1, 4, 1e9 + 16), (1, 5, null), (1, 5, null), (1, 7, null), (1, 9, null), (1, null, null)")
+	tk.MustExec("insert t values (2, 3, 1), (3, null, null)")
+
+	// NULL values are skipped.
+	result := tk.MustQuery("select var_pop(a), variance(a), var_samp(a), stddev_pop(a), std(a), stddev(a) from t where id = 1")
+	result.Check(testkit.Rows("4 4 4.571428571428571 2 2 2"))
+	// The values are accumulated in a single pass without losing the precision of the large values.
+	result = tk.MustQuery("select var_pop(b), var_samp(b) from t where id = 1")
+	result.Check(testkit.Rows("22.5 30"))
+
+	// The result is NULL for an empty group, and the sample variance is NULL for a single value.
+	result = tk.MustQuery("select id, var_pop(a), var_samp(a), stddev_samp(a) from t group by id order by id")
+	result.Check(testkit.Rows("1 4 4.571428571428571 2.138089935299395", "2 0 <nil> <nil>", "3 <nil> <nil> <nil>"))
+	result = tk.MustQuery("select var_pop(a), stddev_pop(a), var_samp(a) from t where id = 4")
+	result.Check(testkit.Rows("<nil> <nil> <nil>"))
+
+	// The variance of a union is calculated from all the rows, it is not pushed down to each part.
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert t1 values (1), (2)")
+	tk.MustExec("insert t2 values (10), (20)")
+	result = tk.MustQuery("select var_pop(a), var_samp(a) from (select a from t1 union all select a from t2) x")
+	result.Check(testkit.Rows("58.1875 77.58333333333333"))
+	result = tk.MustQuery("select stddev_pop(a), count(a), sum(a) from (select a from t1 union all select a from t2) x")
+	result.Check(testkit.Rows("7.628073151196179 4 33"))
+}
+
+func (s *testSuite) TestAggregationWithRollup(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int not null, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 10), (1, 2, 20), (2, 1, 30), (2, NULL, 40), (1, 1, 5)")
+
+	// The super-aggregate rows follow the groups they roll up, a NULL group-by value sorts first.
+	result := tk.MustQuery("select a, b, sum(c), count(*) from t group by a, b with rollup")
+	result.Check(testkit.Rows("1 1 15 2", "1 2 20 1", "1 <nil> 35 3", "2 <nil> 40 1", "2 1 30 1", "2 <nil> 70 2", "<nil> <nil> 105 5"))
+	result = tk.MustQuery("select a, count(distinct b), max(c) from t group by a with rollup")
+	result.Check(testkit.Rows("1 2 20", "2 1 40", "<nil> 2 40"))
+	result = tk.MustQuery("select a + 1, sum(c) from t group by a with rollup")
+	result.Check(testkit.Rows("2 35", "3 70", "<nil> 105"))
+	result = tk.MustQuery("select a, sum(c) from t where c > 100 group by a with rollup")
+	result.Check(testkit.Rows())
+
+	// HAVING and ORDER BY are applied after the super-aggregate rows are added.
+	result = tk.MustQuery("select a, sum(c) from t group by a with rollup having a is null")
+	result.Check(testkit.Rows("<nil> 105"))
+	result = tk.MustQuery("select a, sum(c) from t group by a with rollup having sum(c) > 40")
+	result.Check(testkit.Rows("2 70", "<nil> 105"))
+	result = tk.MustQuery("select * from (select a, sum(c) s from t group by a with rollup) k where a is null")
+	result.Check(testkit.Rows("<nil> 105"))
+	result = tk.MustQuery("select a, sum(c) s from t group by a with rollup order by s desc")
+	result.Check(testkit.Rows("<nil> 105", "2 70", "1 35"))
 }
 
 func (s *testSuite) TestStreamAgg(c *C) {
@@ -284,6 +446,7 @@ func (s *testSuite) TestStreamAgg(c *C) {
 	cntAgg := expression.NewAggFunction(ast.AggFuncCount, []expression.Expression{col}, false)
 	avgAgg := expression.NewAggFunction(ast.AggFuncAvg, []expression.Expression{col}, false)
 	maxAgg := expression.NewAggFunction(ast.AggFuncMax, []expression.Expression{col}, false)
+	varSampAgg := expression.NewAggFunction(ast.AggFuncVarSamp, []expression.Expression{col}, false)
 	cases := []struct {
 		aggFunc expression.AggregationFunction
 		result  string
@@ -330,6 +493,16 @@ func (s *testSuite) TestStreamAgg(c *C) {
 				"1", "3",
 			},
 		},
+		{
+			varSampAgg,
+			"<nil>",
+			[][]interface{}{
+				{0, 1}, {0, nil}, {1, 2}, {1, 4},
+			},
+			[]string{
+				"<nil>", "2",
+			},
+		},
 	}
 	ctx := mock.NewContext()
 	for _, ca := range cases {
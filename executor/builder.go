@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/plan"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -63,6 +64,8 @@ func (b *executorBuilder) build(p plan.Plan) Executor {
 		return b.buildExecute(v)
 	case *plan.Explain:
 		return b.buildExplain(v)
+	case *plan.SelectInto:
+		return b.buildSelectInto(v)
 	case *plan.Insert:
 		return b.buildInsert(v)
 	case *plan.LoadData:
@@ -115,6 +118,8 @@ func (b *executorBuilder) build(p plan.Plan) Executor {
 		return b.buildMaxOneRow(v)
 	case *plan.Trim:
 		return b.buildTrim(v)
+	case *plan.Window:
+		return b.buildWindow(v)
 	case *plan.PhysicalDummyScan:
 		return b.buildDummyScan(v)
 	case *plan.Cache:
@@ -255,7 +260,6 @@ func (b *executorBuilder) buildInsert(v *plan.Insert) Executor {
 		ctx:     b.ctx,
 		Columns: v.Columns,
 		Lists:   v.Lists,
-		Setlist: v.Setlist,
 	}
 	if len(v.GetChildren()) > 0 {
 		ivs.SelectExec = b.build(v.GetChildByIndex(0))
@@ -280,17 +284,42 @@ func (b *executorBuilder) buildLoadData(v *plan.LoadData) Executor {
 		return nil
 	}
 
+	cols := tbl.Cols()
+	if len(v.Columns) > 0 {
+		names := make([]string, 0, len(v.Columns))
+		for _, col := range v.Columns {
+			names = append(names, col.Name.O)
+		}
+		var err error
+		cols, err = table.FindCols(cols, names)
+		if err != nil {
+			b.err = errors.Trace(err)
+			return nil
+		}
+		if err = table.CheckOnce(cols); err != nil {
+			b.err = errors.Trace(err)
+			return nil
+		}
+	}
+	loadDataInfo := &LoadDataInfo{
+		row:         make([]types.Datum, len(cols)),
+		insertVal:   &InsertValues{ctx: b.ctx, Table: tbl},
+		Path:        v.Path,
+		Table:       tbl,
+		Columns:     cols,
+		FieldsInfo:  v.FieldsInfo,
+		LinesInfo:   v.LinesInfo,
+		IgnoreLines: v.IgnoreLines,
+		Ctx:         b.ctx,
+	}
+	// Committing in batches would break the atomicity of an explicit transaction,
+	// or of the transaction started implicitly when autocommit is off.
+	if sessVars := b.ctx.GetSessionVars(); sessVars.IsAutocommit() && !sessVars.InTxn() {
+		loadDataInfo.batchSize = loadDataBatchSize
+	}
 	return &LoadData{
-		IsLocal: v.IsLocal,
-		loadDataInfo: &LoadDataInfo{
-			row:        make([]types.Datum, len(tbl.Cols())),
-			insertVal:  &InsertValues{ctx: b.ctx, Table: tbl},
-			Path:       v.Path,
-			Table:      tbl,
-			FieldsInfo: v.FieldsInfo,
-			LinesInfo:  v.LinesInfo,
-			Ctx:        b.ctx,
-		},
+		IsLocal:      v.IsLocal,
+		loadDataInfo: loadDataInfo,
 	}
 }
 
@@ -318,10 +347,23 @@ func (b *executorBuilder) buildDDL(v *plan.DDL) Executor {
 func (b *executorBuilder) buildExplain(v *plan.Explain) Executor {
 	return &ExplainExec{
 		StmtPlan: v.StmtPlan,
+		Format:   v.Format,
 		schema:   v.GetSchema(),
 	}
 }
 
+func (b *executorBuilder) buildSelectInto(v *plan.SelectInto) Executor {
+	src := b.build(v.GetChildByIndex(0))
+	if b.err != nil {
+		return nil
+	}
+	return &SelectIntoExec{
+		ctx:     b.ctx,
+		src:     src,
+		IntoOpt: v.IntoOpt,
+	}
+}
+
 func (b *executorBuilder) buildUnionScanExec(v *plan.PhysicalUnionScan) Executor {
 	src := b.build(v.GetChildByIndex(0))
 	if b.err != nil {
@@ -458,6 +500,7 @@ func (b *executorBuilder) buildAggregation(v *plan.PhysicalAggregation) Executor
 		GroupByItems: v.GroupByItems,
 		aggType:      v.AggType,
 		hasGby:       v.HasGby,
+		withRollup:   v.WithRollup,
 	}
 }
 
@@ -627,6 +670,21 @@ func (b *executorBuilder) buildTrim(v *plan.Trim) Executor {
 	}
 }
 
+func (b *executorBuilder) buildWindow(v *plan.Window) Executor {
+	e := &WindowExec{
+		Src:         b.build(v.GetChildByIndex(0)),
+		schema:      v.GetSchema(),
+		ctx:         b.ctx,
+		FuncName:    v.FuncName,
+		PartitionBy: v.PartitionBy,
+		OrderBy:     make([]expression.Expression, 0, len(v.OrderBy)),
+	}
+	for _, item := range v.OrderBy {
+		e.OrderBy = append(e.OrderBy, item.Expr)
+	}
+	return e
+}
+
 func (b *executorBuilder) buildUnion(v *plan.Union) Executor {
 	e := &UnionExec{
 		schema: v.GetSchema(),
@@ -642,7 +700,7 @@ func (b *executorBuilder) buildUnion(v *plan.Union) Executor {
 
 func (b *executorBuilder) buildUpdate(v *plan.Update) Executor {
 	selExec := b.build(v.GetChildByIndex(0))
-	return &UpdateExec{ctx: b.ctx, SelectExec: selExec, OrderedList: v.OrderedList}
+	return &UpdateExec{ctx: b.ctx, SelectExec: selExec, OrderedList: v.OrderedList, Ignore: v.Ignore}
 }
 
 func (b *executorBuilder) buildDummyScan(v *plan.PhysicalDummyScan) Executor {
@@ -33,6 +33,8 @@ const (
 	SimpleSelect = "Simple-Select"
 	// ComplexSelect represents the other SELECT statements besides SIMPLE_SELECT.
 	ComplexSelect = "Complex-Select"
+	// AlterDatabase represents alter database statements.
+	AlterDatabase = "AlterDatabase"
 	// AlterTable represents alter table statements.
 	AlterTable = "AlterTable"
 	// AnalyzeTable represents analyze table statements.
@@ -49,6 +51,8 @@ const (
 	CreateTable = "CreateTable"
 	// CreateUser represents create user statements.
 	CreateUser = "CreateUser"
+	// CreateView represents create view statements.
+	CreateView = "CreateView"
 	// Delete represents delete statements.
 	Delete = "Delete"
 	// DropDatabase represents drop database statements.
@@ -79,6 +83,8 @@ const (
 
 func statementLabel(node ast.StmtNode) string {
 	switch x := node.(type) {
+	case *ast.AlterDatabaseStmt:
+		return AlterDatabase
 	case *ast.AlterTableStmt:
 		return AlterTable
 	case *ast.AnalyzeTableStmt:
@@ -95,6 +101,8 @@ func statementLabel(node ast.StmtNode) string {
 		return CreateTable
 	case *ast.CreateUserStmt:
 		return CreateUser
+	case *ast.CreateViewStmt:
+		return CreateView
 	case *ast.DeleteStmt:
 		return Delete
 	case *ast.DropDatabaseStmt:
@@ -172,7 +180,7 @@ func (c *Compiler) Compile(ctx context.Context, node ast.StmtNode) (ast.Statemen
 	return sa, nil
 }
 
-// GetInfoSchema gets TxnCtx InfoSchema if snapshot schema is not set,
+// GetInfoSchema gets TxnCtx InfoSchema with the temporary tables of the session if snapshot schema is not set,
 // Otherwise, snapshot schema is returned.
 func GetInfoSchema(ctx context.Context) infoschema.InfoSchema {
 	sessVar := ctx.GetSessionVars()
@@ -181,7 +189,7 @@ func GetInfoSchema(ctx context.Context) infoschema.InfoSchema {
 		is = snap.(infoschema.InfoSchema)
 		log.Infof("[%d] use snapshot schema %d", sessVar.ConnectionID, is.SchemaMetaVersion())
 	} else {
-		is = sessVar.TxnCtx.InfoSchema.(infoschema.InfoSchema)
+		is = infoschema.WithTemporaryTables(sessVar.TxnCtx.InfoSchema.(infoschema.InfoSchema), sessVar)
 	}
 	return is
 }
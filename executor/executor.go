@@ -65,14 +65,33 @@ var (
 
 // Error instances.
 var (
-	ErrUnknownPlan     = terror.ClassExecutor.New(codeUnknownPlan, "Unknown plan")
-	ErrPrepareMulti    = terror.ClassExecutor.New(codePrepareMulti, "Can not prepare multiple statements")
-	ErrStmtNotFound    = terror.ClassExecutor.New(codeStmtNotFound, "Prepared statement not found")
-	ErrSchemaChanged   = terror.ClassExecutor.New(codeSchemaChanged, "Schema has changed")
-	ErrWrongParamCount = terror.ClassExecutor.New(codeWrongParamCount, "Wrong parameter count")
-	ErrRowKeyCount     = terror.ClassExecutor.New(codeRowKeyCount, "Wrong row key entry count")
-	ErrPrepareDDL      = terror.ClassExecutor.New(codePrepareDDL, "Can not prepare DDL statements")
-	ErrPasswordNoMatch = terror.ClassExecutor.New(CodePasswordNoMatch, "Can't find any matching row in the user table")
+	ErrUnknownPlan             = terror.ClassExecutor.New(codeUnknownPlan, "Unknown plan")
+	ErrPrepareMulti            = terror.ClassExecutor.New(codePrepareMulti, "Can not prepare multiple statements")
+	ErrStmtNotFound            = terror.ClassExecutor.New(codeStmtNotFound, "Prepared statement not found")
+	ErrSchemaChanged           = terror.ClassExecutor.New(codeSchemaChanged, "Schema has changed")
+	ErrWrongParamCount         = terror.ClassExecutor.New(codeWrongParamCount, "Wrong parameter count")
+	ErrRowKeyCount             = terror.ClassExecutor.New(codeRowKeyCount, "Wrong row key entry count")
+	ErrPrepareDDL              = terror.ClassExecutor.New(codePrepareDDL, "Can not prepare DDL statements")
+	ErrPasswordNoMatch         = terror.ClassExecutor.New(CodePasswordNoMatch, "Can't find any matching row in the user table")
+	ErrWrongValueCount         = terror.ClassExecutor.New(CodeWrongValueCount, "Column count doesn't match value count at row %d")
+	ErrSubqueryNo1Row          = terror.ClassExecutor.New(CodeSubqueryNo1Row, "Subquery returns more than 1 row")
+	ErrNoDB                    = terror.ClassExecutor.New(CodeNoDB, "No database selected")
+	ErrSavepointNotExists      = terror.ClassExecutor.New(CodeSavepointNotExists, "SAVEPOINT %s does not exist")
+	ErrQueryInterrupted        = terror.ClassExecutor.New(CodeQueryInterrupted, "Query execution was interrupted")
+	ErrNoSuchThread            = terror.ClassExecutor.New(CodeNoSuchThread, "Unknown thread id: %d")
+	ErrKillDenied              = terror.ClassExecutor.New(CodeKillDenied, "You are not owner of thread %d")
+	ErrSpecificAccessDenied    = terror.ClassExecutor.New(CodeSpecificAccessDenied, "Access denied; you need (at least one of) the %s privilege(s) for this operation")
+	ErrTableaccessDenied       = terror.ClassExecutor.New(CodeTableaccessDenied, "%s command denied to user '%s'@'%s' for table '%s'")
+	ErrNotSupportedYet         = terror.ClassExecutor.New(CodeNotSupportedYet, "This version of TiDB doesn't yet support '%s'")
+	ErrOptionPreventsStatement = terror.ClassExecutor.New(CodeOptionPreventsStatement, "The MySQL server is running with the %s option so it cannot execute this statement")
+	ErrFileExists              = terror.ClassExecutor.New(CodeFileExists, "File '%s' already exists")
+	ErrCheckConstraintViolated = terror.ClassExecutor.New(CodeCheckConstraintViolated, "Check constraint '%s' is violated.")
+	ErrRowIsReferenced         = terror.ClassExecutor.New(CodeRowIsReferenced, "Cannot delete or update a parent row: a foreign key constraint fails (%s)")
+	ErrNoReferencedRow         = terror.ClassExecutor.New(CodeNoReferencedRow, "Cannot add or update a child row: a foreign key constraint fails (%s)")
+	ErrFKDepthExceeded         = terror.ClassExecutor.New(CodeFKDepthExceeded, "Foreign key cascade delete/update exceeds max depth of %d.")
+	ErrViewSelectVariable      = terror.ClassExecutor.New(CodeViewSelectVariable, "View's SELECT contains a variable or parameter")
+	ErrViewSelectTmptable      = terror.ClassExecutor.New(CodeViewSelectTmptable, "View's SELECT refers to a temporary table '%s'")
+	ErrViewWrongList           = terror.ClassExecutor.New(CodeViewWrongList, "View's SELECT and view's field list have different column counts")
 )
 
 // Error codes.
@@ -85,8 +104,27 @@ const (
 	codeRowKeyCount     terror.ErrCode = 6
 	codePrepareDDL      terror.ErrCode = 7
 	// MySQL error code
-	CodePasswordNoMatch terror.ErrCode = 1133
-	CodeCannotUser      terror.ErrCode = 1396
+	CodeNoDB                    terror.ErrCode = 1046
+	CodeFileExists              terror.ErrCode = 1086
+	CodeNoSuchThread            terror.ErrCode = 1094
+	CodeKillDenied              terror.ErrCode = 1095
+	CodePasswordNoMatch         terror.ErrCode = 1133
+	CodeWrongValueCount         terror.ErrCode = 1136
+	CodeTableaccessDenied       terror.ErrCode = 1142
+	CodeSpecificAccessDenied    terror.ErrCode = 1227
+	CodeNotSupportedYet         terror.ErrCode = 1235
+	CodeSubqueryNo1Row          terror.ErrCode = 1242
+	CodeOptionPreventsStatement terror.ErrCode = 1290
+	CodeSavepointNotExists      terror.ErrCode = 1305
+	CodeQueryInterrupted        terror.ErrCode = 1317
+	CodeViewSelectVariable      terror.ErrCode = 1351
+	CodeViewSelectTmptable      terror.ErrCode = 1352
+	CodeViewWrongList           terror.ErrCode = 1353
+	CodeCannotUser              terror.ErrCode = 1396
+	CodeRowIsReferenced         terror.ErrCode = 1451
+	CodeNoReferencedRow         terror.ErrCode = 1452
+	CodeFKDepthExceeded         terror.ErrCode = 3008
+	CodeCheckConstraintViolated terror.ErrCode = 3819
 )
 
 // Row represents a result set row, it may be returned from a table, a join, or a projection.
@@ -216,10 +254,12 @@ func (e *CheckTableExec) Close() error {
 
 // SelectLockExec represents a select lock executor.
 // It is built from the "SELECT .. FOR UPDATE" or the "SELECT .. LOCK IN SHARE MODE" statement.
-// For "SELECT .. FOR UPDATE" statement, it locks every row key from source Executor.
+// It locks every row key from source Executor.
 // After the execution, the keys are buffered in transaction, and will be sent to KV
-// when doing commit. If there is any key already locked by another transaction,
-// the transaction will rollback and retry.
+// when doing commit. If there is any key already locked or modified by another transaction,
+// the commit fails and the transaction is not retried, because the rows it has read may be changed.
+// "LOCK IN SHARE MODE" locks the rows in shared mode, so the transactions reading the same rows with
+// shared locks don't conflict with each other, the lock is exclusive if the transaction writes the row.
 type SelectLockExec struct {
 	Src    Executor
 	Lock   ast.SelectLockType
@@ -241,12 +281,16 @@ func (e *SelectLockExec) Next() (*Row, error) {
 	if row == nil {
 		return nil, nil
 	}
-	if len(row.RowKeys) != 0 && e.Lock == ast.SelectLockForUpdate {
+	if len(row.RowKeys) != 0 && e.Lock != ast.SelectLockNone {
 		e.ctx.GetSessionVars().TxnCtx.ForUpdate = true
 		txn := e.ctx.Txn()
 		for _, k := range row.RowKeys {
 			lockKey := tablecodec.EncodeRowKeyWithHandle(k.Tbl.Meta().ID, k.Handle)
-			err = txn.LockKeys(lockKey)
+			if e.Lock == ast.SelectLockInShareMode {
+				err = txn.LockKeysShared(lockKey)
+			} else {
+				err = txn.LockKeys(lockKey)
+			}
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -416,12 +460,40 @@ func init() {
 		return row.Data, nil
 	}
 	tableMySQLErrCodes := map[terror.ErrCode]uint16{
-		CodeCannotUser:      mysql.ErrCannotUser,
-		CodePasswordNoMatch: mysql.ErrPasswordNoMatch,
+		CodeCannotUser:              mysql.ErrCannotUser,
+		CodePasswordNoMatch:         mysql.ErrPasswordNoMatch,
+		CodeWrongValueCount:         mysql.ErrWrongValueCountOnRow,
+		CodeSubqueryNo1Row:          mysql.ErrSubqueryNo1Row,
+		CodeNoDB:                    mysql.ErrNoDB,
+		CodeFileExists:              mysql.ErrFileExists,
+		CodeNoSuchThread:            mysql.ErrNoSuchThread,
+		CodeKillDenied:              mysql.ErrKillDenied,
+		CodeSpecificAccessDenied:    mysql.ErrSpecificAccessDenied,
+		CodeTableaccessDenied:       mysql.ErrTableaccessDenied,
+		CodeNotSupportedYet:         mysql.ErrNotSupportedYet,
+		CodeOptionPreventsStatement: mysql.ErrOptionPreventsStatement,
+		CodeSavepointNotExists:      mysql.ErrSpDoesNotExist,
+		CodeQueryInterrupted:        mysql.ErrQueryInterrupted,
+		CodeCheckConstraintViolated: mysql.ErrCheckConstraintViolated,
+		CodeRowIsReferenced:         mysql.ErrRowIsReferenced2,
+		CodeNoReferencedRow:         mysql.ErrNoReferencedRow2,
+		CodeViewSelectVariable:      mysql.ErrViewSelectVariable,
+		CodeViewSelectTmptable:      mysql.ErrViewSelectTmptable,
+		CodeViewWrongList:           mysql.ErrViewWrongList,
+		CodeFKDepthExceeded:         mysql.ErrFkDepthExceeded,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExecutor] = tableMySQLErrCodes
 }
 
+// checkKilled returns ErrQueryInterrupted if the executing statement of the session has been killed.
+// Executors that may scan a lot of rows should call it periodically, so KILL QUERY takes effect promptly.
+func checkKilled(ctx context.Context) error {
+	if atomic.LoadUint32(&ctx.GetSessionVars().Killed) == 1 {
+		return ErrQueryInterrupted
+	}
+	return nil
+}
+
 // HashJoinExec implements the hash join algorithm.
 type HashJoinExec struct {
 	hashTable     map[string][]*Row
@@ -879,17 +951,25 @@ func (e *HashSemiJoinExec) rowIsMatched(bigRow *Row) (matched bool, hasNull bool
 	}
 	// match eq condition
 	for _, smallRow := range rows {
-		matched = true
-		if e.otherFilter != nil {
-			var matchedRow *Row
-			matchedRow = makeJoinRow(bigRow, smallRow)
-			matched, err = expression.EvalBool(e.otherFilter, matchedRow.Data, e.ctx)
-			if err != nil {
-				return false, false, errors.Trace(err)
-			}
+		if e.otherFilter == nil {
+			return true, false, nil
 		}
-		if matched {
-			return
+		matchedRow := makeJoinRow(bigRow, smallRow)
+		d, err := e.otherFilter.Eval(matchedRow.Data, e.ctx)
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		// An unknown result makes the row unmatched only if no other row matches it.
+		if d.IsNull() {
+			hasNull = true
+			continue
+		}
+		b, err := d.ToBool(sc)
+		if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		if b != 0 {
+			return true, false, nil
 		}
 	}
 	return
@@ -964,12 +1044,24 @@ type HashAggExec struct {
 	groups            [][]byte
 	currentGroupIndex int
 	GroupByItems      []expression.Expression
+
+	// withRollup is true for GROUP BY ... WITH ROLLUP, every row is also aggregated into the
+	// super-aggregate groups that roll up the trailing group-by items.
+	withRollup   bool
+	rollupGroups []*rollupGroup
+	// rollupLevels[i] is the number of group-by items that are not rolled up in groups[i].
+	rollupLevels []int
+	// rollupItems[i] is the index of the group-by item that is the argument of the i-th firstrow
+	// function, or -1.
+	rollupItems []int
 }
 
 // Close implements the Executor Close interface.
 func (e *HashAggExec) Close() error {
 	e.executed = false
 	e.groups = nil
+	e.rollupGroups = nil
+	e.rollupLevels = nil
 	e.currentGroupIndex = 0
 	for _, agg := range e.AggFuncs {
 		agg.Clear()
@@ -997,6 +1089,12 @@ func (e *HashAggExec) Next() (*Row, error) {
 			}
 		}
 		e.executed = true
+		if e.withRollup {
+			err := e.sortRollupGroups()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 		if (len(e.groups) == 0) && !e.hasGby {
 			// If no groupby and no data, we should add an empty group.
 			// For example:
@@ -1010,7 +1108,12 @@ func (e *HashAggExec) Next() (*Row, error) {
 	}
 	retRow := &Row{Data: make([]types.Datum, 0, len(e.AggFuncs))}
 	groupKey := e.groups[e.currentGroupIndex]
-	for _, af := range e.AggFuncs {
+	for i, af := range e.AggFuncs {
+		if e.withRollup && e.rollupItems[i] >= 0 && e.rollupItems[i] >= e.rollupLevels[e.currentGroupIndex] {
+			// The group-by item is rolled up in this group.
+			retRow.Data = append(retRow.Data, types.Datum{})
+			continue
+		}
 		retRow.Data = append(retRow.Data, af.GetGroupResult(groupKey))
 	}
 	e.currentGroupIndex++
@@ -1062,6 +1165,9 @@ func (e *HashAggExec) innerNext() (ret bool, err error) {
 		}
 	}
 	e.executed = true
+	if e.withRollup {
+		return true, errors.Trace(e.rollupUpdate(srcRow))
+	}
 	groupKey, err := e.getGroupKey(srcRow)
 	if err != nil {
 		return false, errors.Trace(err)
@@ -1076,6 +1182,105 @@ func (e *HashAggExec) innerNext() (ret bool, err error) {
 	return true, nil
 }
 
+// rollupGroup is a group of the aggregation with rollup, vals are the group-by values that are not rolled up.
+type rollupGroup struct {
+	key  []byte
+	vals []types.Datum
+}
+
+// rollupUpdate updates the aggregate functions of the detailed group of the row and of all the
+// super-aggregate groups it belongs to, down to the grand total.
+func (e *HashAggExec) rollupUpdate(row *Row) error {
+	vals := make([]types.Datum, 0, len(e.GroupByItems)+1)
+	vals = append(vals, types.Datum{})
+	for _, item := range e.GroupByItems {
+		v, err := item.Eval(row.Data, e.ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		vals = append(vals, v)
+	}
+	for level := len(e.GroupByItems); level >= 0; level-- {
+		// The level is encoded first, so a rolled-up group never equals a group whose values are NULL.
+		vals[0].SetInt64(int64(level))
+		key, err := codec.EncodeValue([]byte{}, vals[:level+1]...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := e.groupMap[string(key)]; !ok {
+			e.groupMap[string(key)] = true
+			e.rollupGroups = append(e.rollupGroups, &rollupGroup{key: key, vals: vals[1 : level+1]})
+		}
+		for _, af := range e.AggFuncs {
+			err = af.Update(row.Data, key, e.ctx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// sortRollupGroups sorts the groups by the group-by values like MySQL, each super-aggregate
+// group follows the groups it rolls up.
+func (e *HashAggExec) sortRollupGroups() error {
+	e.rollupItems = make([]int, len(e.AggFuncs))
+	for i, af := range e.AggFuncs {
+		e.rollupItems[i] = -1
+		if af.GetName() != ast.AggFuncFirstRow {
+			continue
+		}
+		for j, item := range e.GroupByItems {
+			if item.Equal(af.GetArgs()[0], e.ctx) {
+				e.rollupItems[i] = j
+				break
+			}
+		}
+	}
+	sorter := &rollupGroupSorter{groups: e.rollupGroups, sc: e.ctx.GetSessionVars().StmtCtx}
+	sort.Sort(sorter)
+	if sorter.err != nil {
+		return errors.Trace(sorter.err)
+	}
+	e.groups = make([][]byte, 0, len(e.rollupGroups))
+	e.rollupLevels = make([]int, 0, len(e.rollupGroups))
+	for _, g := range e.rollupGroups {
+		e.groups = append(e.groups, g.key)
+		e.rollupLevels = append(e.rollupLevels, len(g.vals))
+	}
+	return nil
+}
+
+type rollupGroupSorter struct {
+	groups []*rollupGroup
+	sc     *variable.StatementContext
+	err    error
+}
+
+func (s *rollupGroupSorter) Len() int {
+	return len(s.groups)
+}
+
+func (s *rollupGroupSorter) Swap(i, j int) {
+	s.groups[i], s.groups[j] = s.groups[j], s.groups[i]
+}
+
+func (s *rollupGroupSorter) Less(i, j int) bool {
+	a, b := s.groups[i].vals, s.groups[j].vals
+	for k := 0; k < len(a) && k < len(b); k++ {
+		cmp, err := a[k].CompareDatum(s.sc, b[k])
+		if err != nil {
+			s.err = errors.Trace(err)
+			return true
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	// The more detailed group goes first.
+	return len(a) > len(b)
+}
+
 // StreamAggExec deals with all the aggregate functions.
 // It assumes all the input datas is sorted by group by key.
 // When Next() is called, it will return a result for the same group.
@@ -1338,6 +1543,9 @@ func (e *TableScanExec) Schema() expression.Schema {
 
 // Next implements the Executor interface.
 func (e *TableScanExec) Next() (*Row, error) {
+	if err := checkKilled(e.ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if e.isInfoSchema {
 		return e.nextForInfoSchema()
 	}
@@ -1842,13 +2050,116 @@ func (e *MaxOneRowExec) Next() (*Row, error) {
 			return nil, errors.Trace(err)
 		}
 		if srcRow1 != nil {
-			return nil, errors.New("subquery returns more than 1 row")
+			return nil, ErrSubqueryNo1Row.GenByArgs()
 		}
 		return srcRow, nil
 	}
 	return nil, nil
 }
 
+// WindowExec computes a window function for the Src rows, which are sorted by the partition items
+// and then the order items. The result is appended to every row, no row is collapsed.
+type WindowExec struct {
+	Src         Executor
+	schema      expression.Schema
+	ctx         context.Context
+	FuncName    string
+	PartitionBy []expression.Expression
+	OrderBy     []expression.Expression
+
+	started      bool
+	partitionKey []types.Datum
+	orderKey     []types.Datum
+	rowNumber    int64
+	rank         int64
+	denseRank    int64
+}
+
+// Schema implements the Executor Schema interface.
+func (e *WindowExec) Schema() expression.Schema {
+	return e.schema
+}
+
+// Close implements the Executor Close interface.
+func (e *WindowExec) Close() error {
+	e.started = false
+	e.partitionKey = nil
+	e.orderKey = nil
+	e.rowNumber, e.rank, e.denseRank = 0, 0, 0
+	return e.Src.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *WindowExec) Next() (*Row, error) {
+	row, err := e.Src.Next()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if row == nil {
+		return nil, nil
+	}
+	var newPartition, newPeer bool
+	e.partitionKey, newPartition, err = e.evalKey(row, e.PartitionBy, e.partitionKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e.orderKey, newPeer, err = e.evalKey(row, e.OrderBy, e.orderKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !e.started || newPartition {
+		e.started = true
+		e.rowNumber, e.rank, e.denseRank = 0, 0, 0
+		newPeer = true
+	}
+	e.rowNumber++
+	// The rows with the same order key are peers, they have the same rank.
+	if newPeer {
+		e.rank = e.rowNumber
+		e.denseRank++
+	}
+	var value int64
+	switch e.FuncName {
+	case ast.WindowFuncRowNumber:
+		value = e.rowNumber
+	case ast.WindowFuncRank:
+		value = e.rank
+	case ast.WindowFuncDenseRank:
+		value = e.denseRank
+	default:
+		return nil, errors.Errorf("unknown window function %s", e.FuncName)
+	}
+	data := make([]types.Datum, 0, len(row.Data)+1)
+	data = append(data, row.Data...)
+	row.Data = append(data, types.NewIntDatum(value))
+	return row, nil
+}
+
+// evalKey evaluates items on row, it returns the values and whether they differ from the ones of the last row.
+func (e *WindowExec) evalKey(row *Row, items []expression.Expression, lastKey []types.Datum) ([]types.Datum, bool, error) {
+	if len(items) == 0 {
+		return nil, false, nil
+	}
+	key := make([]types.Datum, 0, len(items))
+	changed := lastKey == nil
+	sc := e.ctx.GetSessionVars().StmtCtx
+	for i, item := range items {
+		v, err := item.Eval(row.Data, e.ctx)
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		if !changed {
+			c, err := v.CompareDatum(sc, lastKey[i])
+			if err != nil {
+				return nil, false, errors.Trace(err)
+			}
+			changed = c != 0
+		}
+		key = append(key, v)
+	}
+	return key, changed, nil
+}
+
 // TrimExec truncates extra columns in the Src rows.
 // Some columns in src rows are not needed in the result.
 // For example, in the 'SELECT a from t order by b' statement,
@@ -1930,10 +2241,13 @@ func (e *UnionExec) fetchData(idx int) {
 				}
 				return
 			}
-			if idx != 0 {
-				// TODO: Add cast function in plan building phase.
-				for j := range row.Data {
-					col := e.schema.Columns[j]
+			srcCols := e.Srcs[idx].Schema().Columns
+			for j := range row.Data {
+				col := e.schema.Columns[j]
+				// The result type of the union is taken from the first select unless it has been widened.
+				srcType := srcCols[j].RetType
+				if idx != 0 || srcType.Tp != col.RetType.Tp || srcType.Decimal != col.RetType.Decimal {
+					// TODO: Add cast function in plan building phase.
 					val, err := row.Data[j].ConvertTo(e.ctx.GetSessionVars().StmtCtx, col.RetType)
 					if err != nil {
 						e.finished.Store(true)
@@ -1986,8 +2300,11 @@ func (e *UnionExec) Next() (*Row, error) {
 
 // Close implements the Executor Close interface.
 func (e *UnionExec) Close() error {
-	e.finished.Store(true)
-	<-e.closedCh
+	// The fetching goroutines are only started by the first call of Next.
+	if e.inited {
+		e.finished.Store(true)
+		<-e.closedCh
+	}
 	e.cursor = 0
 	e.inited = false
 	e.rows = nil
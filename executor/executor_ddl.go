@@ -14,6 +14,7 @@
 package executor
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -22,13 +23,21 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessionctx/varsutil"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -65,15 +74,20 @@ func (e *DDLExec) Next() (*Row, error) {
 		needWait = true
 	case *ast.CreateTableStmt:
 		err = e.executeCreateTable(x)
-		needWait = true
+		needWait = !x.IsTemporary
 	case *ast.CreateIndexStmt:
 		err = e.executeCreateIndex(x)
+	case *ast.CreateViewStmt:
+		err = e.executeCreateView(x)
+		needWait = true
 	case *ast.DropDatabaseStmt:
 		err = e.executeDropDatabase(x)
 		needWait = true
+	case *ast.AlterDatabaseStmt:
+		err = e.executeAlterDatabase(x)
 	case *ast.DropTableStmt:
 		err = e.executeDropTable(x)
-		needWait = true
+		needWait = !x.IsTemporary
 	case *ast.DropIndexStmt:
 		err = e.executeDropIndex(x)
 	case *ast.AlterTableStmt:
@@ -109,8 +123,30 @@ func (e *DDLExec) Close() error {
 
 func (e *DDLExec) executeTruncateTable(s *ast.TruncateTableStmt) error {
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	temps := infoschema.GetTemporaryTables(e.ctx.GetSessionVars())
+	if tbl, ok := temps.TableByName(ident.Schema, ident.Name); ok {
+		if err := e.deleteTemporaryTableData(tbl); err != nil {
+			return errors.Trace(err)
+		}
+		// The auto increment ID starts over like a new table.
+		tbl, err := tables.TableFromMeta(autoid.NewLocalAllocator(), tbl.Meta())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		temps.Add(ident.Schema, tbl)
+		return nil
+	}
+	if err := e.checkBaseTable(ident); err != nil {
+		return errors.Trace(err)
+	}
 	err := sessionctx.GetDomain(e.ctx).DDL().TruncateTable(e.ctx, ident)
-	return errors.Trace(err)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tbl, err := e.is.TableByName(ident.Schema, ident.Name); err == nil {
+		plan.DropStatisticsTableCache(tbl.Meta().ID)
+	}
+	return nil
 }
 
 func (e *DDLExec) executeCreateDatabase(s *ast.CreateDatabaseStmt) error {
@@ -135,8 +171,25 @@ func (e *DDLExec) executeCreateDatabase(s *ast.CreateDatabaseStmt) error {
 	return errors.Trace(err)
 }
 
+func (e *DDLExec) executeAlterDatabase(s *ast.AlterDatabaseStmt) error {
+	opt := &ast.CharsetOpt{}
+	for _, val := range s.Options {
+		switch val.Tp {
+		case ast.DatabaseOptionCharset:
+			opt.Chs = val.Value
+		case ast.DatabaseOptionCollate:
+			opt.Col = val.Value
+		}
+	}
+	err := sessionctx.GetDomain(e.ctx).DDL().AlterSchema(e.ctx, model.NewCIStr(s.Name), opt)
+	return errors.Trace(err)
+}
+
 func (e *DDLExec) executeCreateTable(s *ast.CreateTableStmt) error {
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	if s.IsTemporary {
+		return errors.Trace(e.executeCreateTemporaryTable(ident, s))
+	}
 	err := sessionctx.GetDomain(e.ctx).DDL().CreateTable(e.ctx, ident, s.Cols, s.Constraints, s.Options)
 	if terror.ErrorEqual(err, infoschema.ErrTableExists) {
 		if s.IfNotExists {
@@ -147,14 +200,158 @@ func (e *DDLExec) executeCreateTable(s *ast.CreateTableStmt) error {
 	return errors.Trace(err)
 }
 
+// executeCreateTemporaryTable creates a table which is only registered in the session. Its data are stored
+// like a base table with a new table ID, the other sessions can't see them because they can't find the table.
+func (e *DDLExec) executeCreateTemporaryTable(ident ast.Ident, s *ast.CreateTableStmt) error {
+	temps := infoschema.GetTemporaryTables(e.ctx.GetSessionVars())
+	if _, ok := temps.TableByName(ident.Schema, ident.Name); ok {
+		if s.IfNotExists {
+			return nil
+		}
+		return infoschema.ErrTableExists.GenByArgs(ident)
+	}
+	// Like the other DDL statements, the current transaction is committed.
+	if err := e.ctx.NewTxn(); err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo, err := sessionctx.GetDomain(e.ctx).DDL().BuildTableInfo(e.ctx, ident, s.Cols, s.Constraints, s.Options)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(tbInfo.ForeignKeys) > 0 {
+		return infoschema.ErrCannotAddForeign
+	}
+	tbInfo.State = model.StatePublic
+	tbl, err := tables.TableFromMeta(autoid.NewLocalAllocator(), tbInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tbInfo.AutoIncID > 1 {
+		if err = tbl.RebaseAutoID(tbInfo.AutoIncID-1, false); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	temps.Add(ident.Schema, tbl)
+	invalidatePreparedStmts(e.ctx)
+	return nil
+}
+
+func (e *DDLExec) executeCreateView(s *ast.CreateViewStmt) error {
+	ident := ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name}
+	checker := viewSelectChecker{temps: infoschema.GetTemporaryTables(e.ctx.GetSessionVars())}
+	s.Select.Accept(&checker)
+	if checker.err != nil {
+		return errors.Trace(checker.err)
+	}
+	// The columns of the view are the output columns of the select statement.
+	p, err := plan.Optimize(e.ctx, s.Select, e.is)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	selectCols := p.GetSchema().Columns
+	names := s.Cols
+	if len(names) == 0 {
+		for _, col := range selectCols {
+			names = append(names, col.ColName)
+		}
+	} else if len(names) != len(selectCols) {
+		return ErrViewWrongList
+	}
+	cols := make([]*model.ColumnInfo, 0, len(names))
+	nameSet := make(map[string]struct{}, len(names))
+	for i, name := range names {
+		if _, ok := nameSet[name.L]; ok {
+			return infoschema.ErrColumnExists.GenByArgs(name.O)
+		}
+		nameSet[name.L] = struct{}{}
+		cols = append(cols, &model.ColumnInfo{Name: name, FieldType: *selectCols[i].RetType})
+	}
+
+	vars := e.ctx.GetSessionVars()
+	charset, collation := vars.GetCharsetInfo()
+	view := &model.ViewInfo{
+		SelectStmt:    s.Select.Text(),
+		Cols:          s.Cols,
+		DefaultSchema: vars.CurrentDB,
+		Charset:       charset,
+		Collate:       collation,
+	}
+	err = sessionctx.GetDomain(e.ctx).DDL().CreateView(e.ctx, ident, cols, view, s.OrReplace)
+	return errors.Trace(err)
+}
+
+// viewSelectChecker checks the select statement of a view doesn't refer to the variables or the temporary tables,
+// which can't be found when the view is queried.
+type viewSelectChecker struct {
+	temps *infoschema.TemporaryTables
+	err   error
+}
+
+// Enter implements Visitor interface.
+func (c *viewSelectChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.VariableExpr, *ast.ParamMarkerExpr:
+		c.err = ErrViewSelectVariable
+	case *ast.TableName:
+		if _, ok := c.temps.TableByName(x.Schema, x.Name); ok {
+			c.err = ErrViewSelectTmptable.GenByArgs(x.Name.O)
+		}
+	}
+	return in, c.err != nil
+}
+
+// Leave implements Visitor interface.
+func (c *viewSelectChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.err == nil
+}
+
+// invalidatePreparedStmts makes the prepared statements of the session be prepared again on the next execution.
+// Creating or dropping a temporary table doesn't change the schema version, but it changes the tables the names refer to.
+func invalidatePreparedStmts(ctx context.Context) {
+	for _, v := range ctx.GetSessionVars().PreparedStmts {
+		if prepared, ok := v.(*Prepared); ok {
+			prepared.SchemaVersion = -1
+		}
+	}
+}
+
+// deleteTemporaryTableData commits the current transaction like the other DDL statements,
+// and deletes the data of the temporary table in a new transaction.
+func (e *DDLExec) deleteTemporaryTableData(tbl table.Table) error {
+	if err := e.ctx.NewTxn(); err != nil {
+		return errors.Trace(err)
+	}
+	prefix := tablecodec.EncodeTablePrefix(tbl.Meta().ID)
+	err := kv.RunInNewTxn(sessionctx.GetDomain(e.ctx).Store(), true, func(txn kv.Transaction) error {
+		return errors.Trace(util.DelKeyWithPrefix(txn, prefix))
+	})
+	return errors.Trace(err)
+}
+
+// checkBaseTable returns an error if ident is a temporary table or a view, whose definition can't be changed by the DDL jobs.
+func (e *DDLExec) checkBaseTable(ident ast.Ident) error {
+	temps := infoschema.GetTemporaryTables(e.ctx.GetSessionVars())
+	if _, ok := temps.TableByName(ident.Schema, ident.Name); ok {
+		return ErrNotSupportedYet.GenByArgs(fmt.Sprintf("changing the definition of temporary table %s", ident))
+	}
+	if tbl, err := e.is.TableByName(ident.Schema, ident.Name); err == nil && tbl.Meta().IsView() {
+		return infoschema.ErrWrongObject.GenByArgs(ident.Schema, ident.Name, "BASE TABLE")
+	}
+	return nil
+}
+
 func (e *DDLExec) executeCreateIndex(s *ast.CreateIndexStmt) error {
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	if err := e.checkBaseTable(ident); err != nil {
+		return errors.Trace(err)
+	}
 	err := sessionctx.GetDomain(e.ctx).DDL().CreateIndex(e.ctx, ident, s.Unique, model.NewCIStr(s.IndexName), s.IndexColNames)
 	return errors.Trace(err)
 }
 
 func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	dbName := model.NewCIStr(s.Name)
+	dbInfo, dbExists := e.is.SchemaByName(dbName)
 	err := sessionctx.GetDomain(e.ctx).DDL().DropSchema(e.ctx, dbName)
 	if terror.ErrorEqual(err, infoschema.ErrDatabaseNotExists) {
 		if s.IfExists {
@@ -163,6 +360,15 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 			err = infoschema.ErrDatabaseDropExists.GenByArgs(s.Name)
 		}
 	}
+	if err == nil && dbExists {
+		for _, tbl := range e.is.SchemaTables(dbName) {
+			plan.DropStatisticsTableCache(tbl.Meta().ID)
+		}
+		err = dropDBPrivEntries(e.ctx, dbInfo.Name.O)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	sessionVars := e.ctx.GetSessionVars()
 	if err == nil && strings.ToLower(sessionVars.CurrentDB) == dbName.L {
 		sessionVars.CurrentDB = ""
@@ -178,10 +384,36 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	return errors.Trace(err)
 }
 
+// Remove the privilege entries of the dropped database from mysql.DB, mysql.Tables_priv and mysql.Columns_priv.
+func dropDBPrivEntries(ctx context.Context, db string) error {
+	for _, tbl := range []string{mysql.DBTable, mysql.TablePrivTable, mysql.ColumnPrivTable} {
+		sql := fmt.Sprintf(`DELETE FROM %s.%s WHERE DB="%s";`, mysql.SystemDB, tbl, db)
+		_, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, sql)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
 	var notExistTables []string
+	temps := infoschema.GetTemporaryTables(e.ctx.GetSessionVars())
 	for _, tn := range s.Tables {
 		fullti := ast.Ident{Schema: tn.Schema, Name: tn.Name}
+		// A temporary table is dropped before the table it shadows, its privileges aren't checked like MySQL.
+		if tbl, ok := temps.TableByName(tn.Schema, tn.Name); ok && !s.IsView {
+			if err := e.deleteTemporaryTableData(tbl); err != nil {
+				return errors.Trace(err)
+			}
+			temps.Remove(tn.Schema, tn.Name)
+			invalidatePreparedStmts(e.ctx)
+			continue
+		}
+		if s.IsTemporary {
+			notExistTables = append(notExistTables, fullti.String())
+			continue
+		}
 		schema, ok := e.is.SchemaByName(tn.Schema)
 		if !ok {
 			// TODO: we should return special error for table not exist, checking "not exist" is not enough,
@@ -196,6 +428,14 @@ func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
 		} else if err != nil {
 			return errors.Trace(err)
 		}
+		if tb.Meta().IsView() != s.IsView {
+			// Like MySQL, DROP TABLE doesn't find a view, but DROP VIEW reports a base table.
+			if s.IsView {
+				return infoschema.ErrWrongObject.GenByArgs(tn.Schema, tn.Name, "VIEW")
+			}
+			notExistTables = append(notExistTables, fullti.String())
+			continue
+		}
 		// Check Privilege
 		privChecker := privilege.GetPrivilegeChecker(e.ctx)
 		hasPriv, err := privChecker.Check(e.ctx, schema, tb.Meta(), mysql.DropPriv)
@@ -203,7 +443,11 @@ func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
 			return errors.Trace(err)
 		}
 		if !hasPriv {
-			return errors.Errorf("You do not have the privilege to drop table %s.%s.", tn.Schema, tn.Name)
+			user, host := e.ctx.GetSessionVars().User, ""
+			if i := strings.LastIndex(user, "@"); i >= 0 {
+				user, host = user[:i], user[i+1:]
+			}
+			return privilegeDenied(e.ctx, ErrTableaccessDenied.GenByArgs("DROP", user, host, tn.Name.O))
 		}
 
 		err = sessionctx.GetDomain(e.ctx).DDL().DropTable(e.ctx, fullti)
@@ -211,6 +455,8 @@ func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
 			notExistTables = append(notExistTables, fullti.String())
 		} else if err != nil {
 			return errors.Trace(err)
+		} else {
+			plan.DropStatisticsTableCache(tb.Meta().ID)
 		}
 	}
 	if len(notExistTables) > 0 && !s.IfExists {
@@ -221,6 +467,9 @@ func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
 
 func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
 	ti := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	if err := e.checkBaseTable(ti); err != nil {
+		return errors.Trace(err)
+	}
 	err := sessionctx.GetDomain(e.ctx).DDL().DropIndex(e.ctx, ti, model.NewCIStr(s.IndexName))
 	if (infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err)) && s.IfExists {
 		err = nil
@@ -230,6 +479,9 @@ func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
 
 func (e *DDLExec) executeAlterTable(s *ast.AlterTableStmt) error {
 	ti := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	if err := e.checkBaseTable(ti); err != nil {
+		return errors.Trace(err)
+	}
 	err := sessionctx.GetDomain(e.ctx).DDL().AlterTable(e.ctx, ti, s.Specs)
 	return errors.Trace(err)
 }
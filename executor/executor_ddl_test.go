@@ -17,6 +17,15 @@ import (
 	"fmt"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/plan"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -36,6 +45,26 @@ func (s *testSuite) TestTruncateTable(c *C) {
 	tk.MustExec("truncate table truncate_test")
 	result = tk.MustQuery("select * from truncate_test")
 	result.Check(nil)
+
+	// Truncate resets the auto increment ID.
+	tk.MustExec(`drop table if exists truncate_test;`)
+	tk.MustExec(`create table truncate_test (id int primary key auto_increment, a int)`)
+	tk.MustExec(`insert truncate_test (a) values (1),(2),(3)`)
+	tk.MustExec("truncate truncate_test")
+	tk.MustExec(`insert truncate_test (a) values (4)`)
+	tk.MustQuery("select * from truncate_test").Check(testkit.Rows("1 4"))
+
+	// The auto increment ID restarts from the start value in the table definition.
+	tk.MustExec(`drop table if exists truncate_test;`)
+	tk.MustExec(`create table truncate_test (id int primary key auto_increment, a int) auto_increment = 100`)
+	tk.MustExec(`insert truncate_test (a) values (1),(2)`)
+	tk.MustQuery("select id from truncate_test").Check(testkit.Rows("100", "101"))
+	tk.MustExec("truncate table truncate_test")
+	tk.MustExec(`insert truncate_test (a) values (3)`)
+	tk.MustQuery("select * from truncate_test").Check(testkit.Rows("100 3"))
+
+	_, err := tk.Exec("truncate table truncate_not_exist")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableNotExists), IsTrue, Commentf("err %v", err))
 }
 
 func (s *testSuite) TestCreateTable(c *C) {
@@ -109,6 +138,24 @@ func (s *testSuite) TestCreateTable(c *C) {
 	r = tk.MustQuery("select * from create_auto_increment_test;")
 	rowStr1 = fmt.Sprintf("%v %v", 1000, []byte("aa"))
 	r.Check(testkit.Rows(rowStr1))
+
+	// The charset and collation of a column are filled by each other.
+	tk.MustExec("create table create_collate_test (a varchar(10) collate utf8_bin, b varchar(10) charset latin1, c text charset utf8mb4 collate utf8mb4_general_ci)")
+	tbl, err := sessionctx.GetDomain(tk.Se.(context.Context)).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("create_collate_test"))
+	c.Assert(err, IsNil)
+	cols := tbl.Meta().Columns
+	c.Assert(cols[0].Charset, Equals, "utf8")
+	c.Assert(cols[0].Collate, Equals, "utf8_bin")
+	c.Assert(cols[1].Charset, Equals, "latin1")
+	c.Assert(cols[1].Collate, Equals, "latin1_swedish_ci")
+	c.Assert(cols[2].Charset, Equals, "utf8mb4")
+	c.Assert(cols[2].Collate, Equals, "utf8mb4_general_ci")
+	_, err = tk.Exec("create table create_collate_test1 (a varchar(10) collate xxx)")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCollation), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create table create_collate_test1 (a varchar(10) charset xxx)")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCharacterSet), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create table create_collate_test1 (a varchar(10) charset utf8 collate latin1_bin)")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrCollationCharsetMismatch), IsTrue, Commentf("err %v", err))
 }
 
 func (s *testSuite) TestCreateDropDatabase(c *C) {
@@ -118,6 +165,92 @@ func (s *testSuite) TestCreateDropDatabase(c *C) {
 	tk.MustExec("drop database if exists drop_test;")
 	tk.MustExec("create database drop_test;")
 	tk.MustExec("drop database drop_test;")
+
+	tk.MustExec("create database charset_test character set UTF8 collate utf8_general_ci")
+	tk.MustExec("use charset_test")
+	tk.MustQuery("select @@character_set_database, @@collation_database").Check(testkit.Rows("utf8 utf8_general_ci"))
+	tk.MustExec("drop database charset_test")
+	tk.MustExec("create database charset_test collate utf8_bin")
+	tk.MustExec("use charset_test")
+	tk.MustQuery("select @@character_set_database, @@collation_database").Check(testkit.Rows("utf8 utf8_bin"))
+	tk.MustExec("drop database charset_test")
+	tk.MustExec("create database charset_test character set latin1")
+	tk.MustExec("use charset_test")
+	tk.MustQuery("select @@character_set_database, @@collation_database").Check(testkit.Rows("latin1 latin1_swedish_ci"))
+	tk.MustExec("create database if not exists charset_test character set utf8")
+	tk.MustExec("drop database charset_test")
+
+	// The columns inherit the collation of the table, which inherits the collation of the database.
+	tk.MustExec("create database charset_test collate utf8_general_ci")
+	tk.MustExec("use charset_test")
+	tk.MustExec("create table t (s varchar(10))")
+	tk.MustExec("create table t1 (s varchar(10)) charset latin1")
+	tk.MustExec("create table t2 (s varchar(10)) collate utf8_bin")
+	tk.MustExec("alter table t add column s1 varchar(10)")
+	tk.MustExec("insert t values ('abc', 'abc')")
+	tk.MustQuery("select s, s1 from t where s = 'ABC' and s1 = 'ABC'").Check(testkit.Rows(fmt.Sprintf("%v %v", []byte("abc"), []byte("abc"))))
+	is := sessionctx.GetDomain(tk.Se.(context.Context)).InfoSchema()
+	for _, ca := range []struct {
+		table   string
+		charset string
+		collate string
+	}{
+		{"t", "utf8", "utf8_general_ci"},
+		{"t1", "latin1", "latin1_swedish_ci"},
+		{"t2", "utf8", "utf8_bin"},
+	} {
+		tbl, err := is.TableByName(model.NewCIStr("charset_test"), model.NewCIStr(ca.table))
+		c.Assert(err, IsNil)
+		for _, col := range tbl.Meta().Columns {
+			c.Assert(col.Charset, Equals, ca.charset)
+			c.Assert(col.Collate, Equals, ca.collate)
+		}
+	}
+	tk.MustExec("drop database charset_test")
+
+	_, err := tk.Exec("create database charset_test character set xxx")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCharacterSet), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create database charset_test collate xxx")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCollation), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create database charset_test character set utf8 collate latin1_bin")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrCollationCharsetMismatch), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSuite) TestAlterDatabase(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database alter_db_test character set utf8")
+	tk.MustExec("alter database alter_db_test character set latin1")
+	tk.MustQuery("show create database alter_db_test").Check(testkit.Rows(
+		"alter_db_test CREATE DATABASE `alter_db_test` /* !40100 DEFAULT CHARACTER SET latin1 */"))
+	tk.MustExec("use alter_db_test")
+	tk.MustQuery("select @@character_set_database, @@collation_database").Check(testkit.Rows("latin1 latin1_swedish_ci"))
+
+	// New tables inherit the changed default charset.
+	tk.MustExec("create table t (a varchar(10))")
+	tk.MustExec("create table t1 (a int) charset = ascii")
+	is := sessionctx.GetDomain(tk.Se.(context.Context)).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("alter_db_test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Charset, Equals, "latin1")
+	c.Assert(tbl.Meta().Collate, Equals, "latin1_swedish_ci")
+	tbl, err = is.TableByName(model.NewCIStr("alter_db_test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Charset, Equals, "ascii")
+
+	tk.MustExec("alter schema alter_db_test collate utf8_bin")
+	tk.MustExec("use alter_db_test")
+	tk.MustQuery("select @@character_set_database, @@collation_database").Check(testkit.Rows("utf8 utf8_bin"))
+	// The tables of the database are kept.
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("0"))
+
+	_, err = tk.Exec("alter database alter_db_not_exist character set utf8")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrDatabaseNotExists), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("alter database alter_db_test character set xxx")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCharacterSet), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("alter database alter_db_test character set utf8 collate latin1_bin")
+	c.Assert(terror.ErrorEqual(err, ddl.ErrCollationCharsetMismatch), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop database alter_db_test")
 }
 
 func (s *testSuite) TestCreateDropTable(c *C) {
@@ -130,6 +263,99 @@ func (s *testSuite) TestCreateDropTable(c *C) {
 	tk.MustExec("drop table drop_test")
 }
 
+func (s *testSuite) TestCreateView(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table view_t (a int, b int)")
+	tk.MustExec("insert view_t values (1, 2), (3, 4), (5, 6)")
+	tk.MustExec("create view v1 as select a, b * 2 from view_t where a > 1")
+	tk.MustQuery("select * from v1").Check(testkit.Rows("3 8", "5 12"))
+	tk.MustQuery("select `b * 2` from v1 where a = 5").Check(testkit.Rows("12"))
+	// The column list renames the columns of the select statement.
+	tk.MustExec("create view v2 (x, y) as select a, count(*) from view_t group by a")
+	tk.MustQuery("select y, v2.x from v2 where x < 4 order by x").Check(testkit.Rows("1 1", "1 3"))
+	tk.MustQuery("select test.v2.x from v2 join view_t on v2.x = view_t.b").Check(nil)
+	tk.MustQuery("select v.x, t.a from v2 v join view_t t on v.x = t.a - 2").Check(testkit.Rows("1 3", "3 5"))
+	tk.MustQuery("select * from view_t where a in (select x from v2 where x > 2)").Check(testkit.Rows("3 4", "5 6"))
+	// A view can be defined by a union and other views.
+	tk.MustExec("create view v3 as select a from v1 union select x from v2")
+	tk.MustQuery("select * from v3 order by a").Check(testkit.Rows("1", "3", "5"))
+	// The view sees the current data of its base tables.
+	tk.MustExec("insert view_t values (7, 8)")
+	tk.MustQuery("select count(*) from v1").Check(testkit.Rows("3"))
+
+	_, err := tk.Exec("create view v1 as select 1")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableExists), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create view view_t as select 1")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableExists), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create or replace view view_t as select 1")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrWrongObject), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create view v4 (x) as select a, b from view_t")
+	c.Assert(terror.ErrorEqual(err, executor.ErrViewWrongList), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create view v4 as select a, b as a from view_t")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrColumnExists), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create view v4 as select a from view_t where a > @a")
+	c.Assert(terror.ErrorEqual(err, executor.ErrViewSelectVariable), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create view v4 as select c from view_t")
+	c.Assert(err, NotNil)
+
+	// The views are not insertable or updatable.
+	_, err = tk.Exec("insert v1 values (1, 2)")
+	c.Assert(terror.ErrorEqual(err, plan.ErrNonInsertableTable), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("update v1 set a = 1")
+	c.Assert(terror.ErrorEqual(err, plan.ErrNonUpdatableTable), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("delete from v1")
+	c.Assert(terror.ErrorEqual(err, plan.ErrNonUpdatableTable), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("alter table v1 add column c int")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrWrongObject), IsTrue, Commentf("err %v", err))
+
+	// SHOW CREATE VIEW reconstructs the definition.
+	tk.MustQuery("show create view v2").Check(testkit.Rows(
+		"v2 CREATE VIEW `v2` (`x`, `y`) AS select a, count(*) from view_t group by a utf8 utf8_unicode_ci"))
+	tk.MustQuery("show create table v1").Check(testkit.Rows(
+		"v1 CREATE VIEW `v1` AS select a, b * 2 from view_t where a > 1"))
+	rs, err := tk.Exec("show create view view_t")
+	c.Assert(err, IsNil)
+	_, err = rs.Next()
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrWrongObject), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("show full tables like 'v%'").Check(testkit.Rows("v1 VIEW", "v2 VIEW", "v3 VIEW", "view_t BASE TABLE"))
+	tk.MustQuery("show columns from v2").Check(testkit.Rows("x int(11) YES  <nil> ", "y bigint(21) YES  <nil> "))
+
+	// CREATE OR REPLACE VIEW changes the definition.
+	tk.MustExec("create or replace view v1 as select b from view_t where a = 1")
+	tk.MustQuery("select * from v1").Check(testkit.Rows("2"))
+	tk.MustQuery("show create view v1").Check(testkit.Rows(
+		"v1 CREATE VIEW `v1` AS select b from view_t where a = 1 utf8 utf8_unicode_ci"))
+	// The view becomes invalid if its select statement doesn't match its base tables.
+	_, err = tk.Exec("select * from v3")
+	c.Assert(terror.ErrorEqual(err, plan.ErrViewInvalid), IsTrue, Commentf("err %v", err))
+	tk.MustExec("create or replace view v1 as select a from view_t where a = 1")
+	tk.MustQuery("select * from v3 order by a").Check(testkit.Rows("1", "3", "5", "7"))
+	// A view can't refer to itself.
+	tk.MustExec("create or replace view v1 as select * from v3")
+	_, err = tk.Exec("select * from v1")
+	c.Assert(terror.ErrorEqual(err, plan.ErrViewRecursive), IsTrue, Commentf("err %v", err))
+	tk.MustExec("create or replace view v2 (x, y) as select * from view_t")
+	tk.MustExec("alter table view_t add column c int")
+	_, err = tk.Exec("select * from v2")
+	c.Assert(terror.ErrorEqual(err, plan.ErrViewInvalid), IsTrue, Commentf("err %v", err))
+
+	// DROP VIEW only drops the views.
+	_, err = tk.Exec("drop view view_t")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrWrongObject), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("drop table v1")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableDropExists), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop view v1, v2")
+	tk.MustExec("drop view if exists v2, v3")
+	_, err = tk.Exec("select * from v1")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableNotExists), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop table view_t")
+}
+
 func (s *testSuite) TestCreateDropIndex(c *C) {
 	defer testleak.AfterTest(c)()
 	tk := testkit.NewTestKit(c, s.store)
@@ -138,6 +364,25 @@ func (s *testSuite) TestCreateDropIndex(c *C) {
 	tk.MustExec("create index idx_a on drop_test (a)")
 	tk.MustExec("drop index idx_a on drop_test")
 	tk.MustExec("drop table drop_test")
+
+	tk.MustExec("create table drop_test (a int, b int)")
+	tk.MustExec("insert drop_test values (1, 1), (1, 2)")
+	tk.MustExec("create index idx_ab on drop_test (a, b)")
+	tk.MustQuery("select a, b from drop_test use index (idx_ab) where a = 1").Check(testkit.Rows("1 1", "1 2"))
+	// The index name must be unique in the table.
+	_, err := tk.Exec("create index idx_ab on drop_test (b)")
+	c.Assert(err, NotNil)
+	// The existing rows are checked when a unique index is created.
+	_, err = tk.Exec("create unique index idx_a on drop_test (a)")
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue)
+	tk.MustExec("create unique index idx_ab_unique on drop_test (a, b)")
+	_, err = tk.Exec("insert drop_test values (1, 1)")
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue)
+	tk.MustExec("drop index idx_ab_unique on drop_test")
+	tk.MustExec("insert drop_test values (1, 1)")
+	_, err = tk.Exec("drop index idx_ab_unique on drop_test")
+	c.Assert(err, NotNil)
+	tk.MustExec("drop table drop_test")
 }
 
 func (s *testSuite) TestAlterTableAddColumn(c *C) {
@@ -182,7 +427,7 @@ func (s *testSuite) TestAlterTableModifyColumn(c *C) {
 	tk.MustExec("alter table mc modify column c2 text")
 	result := tk.MustQuery("show create table mc")
 	createSQL := result.Rows()[0][1]
-	expected := "CREATE TABLE `mc` (\n  `c1` bigint(21) DEFAULT NULL,\n  `c2` text DEFAULT NULL\n) ENGINE=InnoDB"
+	expected := "CREATE TABLE `mc` (\n  `c1` bigint(21) DEFAULT NULL,\n  `c2` text DEFAULT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"
 	c.Assert(createSQL, Equals, expected)
 }
 
@@ -386,6 +386,9 @@ func (e *XSelectIndexExec) Next() (*Row, error) {
 	if e.indexPlan.LimitCount != nil && e.returnedRows >= uint64(*e.indexPlan.LimitCount) {
 		return nil, nil
 	}
+	if err := checkKilled(e.ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
 	e.returnedRows++
 	if e.singleReadMode {
 		return e.nextForSingleRead()
@@ -593,7 +596,7 @@ func (e *XSelectIndexExec) doIndexRequest() (distsql.SelectResult, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return distsql.Select(e.ctx.GetClient(), selIdxReq, keyRanges, concurrency, !e.indexPlan.OutOfOrder)
+	return distsql.Select(e.ctx.GetClient(), e.ctx.GetSessionVars().StmtCtx, selIdxReq, keyRanges, concurrency, !e.indexPlan.OutOfOrder)
 }
 
 func (e *XSelectIndexExec) buildTableTasks(handles []int64) []*lookupTableTask {
@@ -724,7 +727,7 @@ func (e *XSelectIndexExec) doTableRequest(handles []int64) (distsql.SelectResult
 	selTableReq.GroupBy = e.byItems
 	keyRanges := tableHandlesToKVRanges(e.table.Meta().ID, handles)
 
-	resp, err := distsql.Select(e.ctx.GetClient(), selTableReq, keyRanges, e.scanConcurrency, false)
+	resp, err := distsql.Select(e.ctx.GetClient(), e.ctx.GetSessionVars().StmtCtx, selTableReq, keyRanges, e.scanConcurrency, false)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -806,7 +809,7 @@ func (e *XSelectTableExec) doRequest() error {
 
 	kvRanges := tableRangesToKVRanges(e.table.Meta().ID, e.ranges)
 	concurrency := e.scanConcurrency
-	e.result, err = distsql.Select(e.ctx.GetClient(), selReq, kvRanges, concurrency, e.keepOrder)
+	e.result, err = distsql.Select(e.ctx.GetClient(), e.ctx.GetSessionVars().StmtCtx, selReq, kvRanges, concurrency, e.keepOrder)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -843,6 +846,9 @@ func (e *XSelectTableExec) Next() (*Row, error) {
 		}
 	}
 	for {
+		if err := checkKilled(e.ctx); err != nil {
+			return nil, errors.Trace(err)
+		}
 		// Get partial result.
 		if e.partialResult == nil {
 			var err error
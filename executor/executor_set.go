@@ -21,11 +21,11 @@ import (
 	"github.com/ngaut/log"
 	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessionctx/varsutil"
-	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -110,6 +110,13 @@ func (e *SetExecutor) executeSet() error {
 			if err != nil {
 				return errors.Trace(err)
 			}
+			if name == variable.SQLModeVar {
+				mode, err1 := varsutil.ParseSQLMode(svalue)
+				if err1 != nil {
+					return errors.Trace(err1)
+				}
+				svalue = mode.String()
+			}
 			err = sessionVars.GlobalVarsAccessor.SetGlobalSysVar(name, svalue)
 			if err != nil {
 				return errors.Trace(err)
@@ -144,19 +151,16 @@ func (e *SetExecutor) Close() error {
 	return nil
 }
 
+// setCharset sets the charset and collation of the connection.
+// The collation is only recorded, string comparison still compares bytes no matter what
+// collation_connection is, because the comparison may be pushed down to the storage layer
+// which doesn't know about collations.
 func (e *SetExecutor) setCharset(cs, co string) error {
-	var err error
-	if len(co) == 0 {
-		co, err = charset.GetDefaultCollation(cs)
-		if err != nil {
-			return errors.Trace(err)
-		}
-	}
-	sessionVars := e.ctx.GetSessionVars()
-	for _, v := range variable.SetNamesVariables {
-		sessionVars.Systems[v] = cs
+	cs, co, err := ddl.ResolveCharsetCollate(cs, co)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	sessionVars.Systems[variable.CollationConnection] = co
+	e.ctx.GetSessionVars().SetCharsetInfo(cs, co)
 	return nil
 }
 
@@ -16,8 +16,10 @@ package executor_test
 import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessionctx/varsutil"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -125,6 +127,25 @@ func (s *testSuite) TestSetVar(c *C) {
 	c.Assert(vars.SkipConstraintCheck, IsFalse)
 }
 
+func (s *testSuite) TestUserVar(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @x = 1 + 2, @s = 'AbC'")
+	tk.MustQuery("select @x, @x + 1, @s, @unset").Check(testkit.Rows("3 4 AbC <nil>"))
+
+	// Assignment in SELECT is visible to the rest of the statement and to later statements.
+	tk.MustQuery("select @y := 5, @y + 1").Check(testkit.Rows("5 6"))
+	tk.MustQuery("select @y").Check(testkit.Rows("5"))
+	tk.MustQuery("select @y := null, @y is null").Check(testkit.Rows("<nil> 1"))
+	tk.MustExec("set @x = null")
+	tk.MustQuery("select @x is null").Check(testkit.Rows("1"))
+
+	// User variables belong to the session.
+	tk.MustExec("set @x = 1")
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustQuery("select @x").Check(testkit.Rows("<nil>"))
+}
+
 func (s *testSuite) TestSetCharset(c *C) {
 	defer testleak.AfterTest(c)()
 	tk := testkit.NewTestKit(c, s.store)
@@ -146,4 +167,25 @@ func (s *testSuite) TestSetCharset(c *C) {
 
 	// Issue 1523
 	tk.MustExec(`SET NAMES binary`)
+
+	tk.MustExec(`SET NAMES latin1 COLLATE latin1_bin`)
+	tk.MustQuery("select @@character_set_connection, @@collation_connection").Check(testkit.Rows("latin1 latin1_bin"))
+	tk.MustExec(`SET NAMES UTF8MB4`)
+	tk.MustQuery("select @@character_set_results, @@collation_connection").Check(testkit.Rows("utf8mb4 utf8mb4_general_ci"))
+	tk.MustExec(`SET CHARACTER SET utf8`)
+	tk.MustQuery("select @@character_set_client, @@collation_connection").Check(testkit.Rows("utf8 utf8_general_ci"))
+	_, err := tk.Exec(`SET NAMES xxx`)
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCharacterSet), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`SET NAMES utf8 COLLATE xxx`)
+	c.Assert(terror.ErrorEqual(err, ddl.ErrUnknownCollation), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`SET NAMES utf8 COLLATE latin1_bin`)
+	c.Assert(terror.ErrorEqual(err, ddl.ErrCollationCharsetMismatch), IsTrue, Commentf("err %v", err))
+	// The charset is not changed by a failed statement.
+	tk.MustQuery("select @@character_set_client, @@collation_connection").Check(testkit.Rows("utf8 utf8_general_ci"))
+
+	// The strings without a column are compared with the collation of the connection.
+	tk.MustExec(`SET NAMES utf8 COLLATE utf8_general_ci`)
+	tk.MustQuery("select 'a' = 'A', 'a' in ('A'), 'a' < 'B', 'a' = binary 'A'").Check(testkit.Rows("1 1 1 0"))
+	tk.MustExec(`SET NAMES utf8 COLLATE utf8_bin`)
+	tk.MustQuery("select 'a' = 'A', 'a' in ('A'), 'a' < 'B', 'a' = binary 'A'").Check(testkit.Rows("0 0 0 0"))
 }
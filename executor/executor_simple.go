@@ -24,21 +24,25 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/plan/statistics"
+	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/types"
+	"github.com/pingcap/tipb/go-binlog"
 )
 
 // SimpleExec represents simple statement executor.
 // For statements do simple execution.
 // includes `UseStmt`, 'SetStmt`, `DoStmt`,
-// `BeginStmt`, `CommitStmt`, `RollbackStmt`.
+// `BeginStmt`, `CommitStmt`, `RollbackStmt`, `SavepointStmt`, `ReleaseSavepointStmt`.
 // TODO: list all simple statements.
 type SimpleExec struct {
 	Statement ast.StmtNode
@@ -69,6 +73,10 @@ func (e *SimpleExec) Next() (*Row, error) {
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
 		err = e.executeRollback(x)
+	case *ast.SavepointStmt:
+		err = e.executeSavepoint(x)
+	case *ast.ReleaseSavepointStmt:
+		err = e.executeReleaseSavepoint(x)
 	case *ast.CreateUserStmt:
 		err = e.executeCreateUser(x)
 	case *ast.AlterUserStmt:
@@ -79,6 +87,8 @@ func (e *SimpleExec) Next() (*Row, error) {
 		err = e.executeSetPwd(x)
 	case *ast.AnalyzeTableStmt:
 		err = e.executeAnalyzeTable(x)
+	case *ast.KillStmt:
+		err = e.executeKillStmt(x)
 	case *ast.BinlogStmt:
 		// We just ignore it.
 		return nil, nil
@@ -120,6 +130,7 @@ func (e *SimpleExec) executeBegin(s *ast.BeginStmt) error {
 	// the transaction with COMMIT or ROLLBACK. The autocommit mode then
 	// reverts to its previous state.
 	e.ctx.GetSessionVars().SetStatusFlag(mysql.ServerStatusInTrans, true)
+	e.ctx.GetSessionVars().TxnCtx.Savepoints = nil
 	return nil
 }
 
@@ -128,6 +139,9 @@ func (e *SimpleExec) executeCommit(s *ast.CommitStmt) {
 }
 
 func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
+	if s.SavepointName != "" {
+		return e.executeRollbackToSavepoint(s)
+	}
 	sessVars := e.ctx.GetSessionVars()
 	log.Infof("[%d] execute rollback statement", sessVars.ConnectionID)
 	sessVars.SetStatusFlag(mysql.ServerStatusInTrans, false)
@@ -137,8 +151,85 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+// savepoint records the state of the current transaction when a savepoint is set.
+type savepoint struct {
+	name    string
+	kvSp    *kv.Savepoint
+	dirtyDB *dirtyDB
+	binlog  *binlog.PrewriteValue
+}
+
+func getSavepoints(ctx context.Context) []*savepoint {
+	sps, _ := ctx.GetSessionVars().TxnCtx.Savepoints.([]*savepoint)
+	return sps
+}
+
+// findSavepoint returns the offset of the savepoint with the given name, or -1 if it does not exist.
+func findSavepoint(sps []*savepoint, name string) int {
+	for i, sp := range sps {
+		if strings.EqualFold(sp.name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *SimpleExec) executeSavepoint(s *ast.SavepointStmt) error {
+	kvSp, err := e.ctx.Txn().Savepoint()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sps := getSavepoints(e.ctx)
+	// If a savepoint with the same name already exists, the old one is deleted.
+	if i := findSavepoint(sps, s.Name); i >= 0 {
+		sps = append(sps[:i], sps[i+1:]...)
+	}
+	sps = append(sps, &savepoint{
+		name:    s.Name,
+		kvSp:    kvSp,
+		dirtyDB: getDirtyDB(e.ctx).clone(),
+		binlog:  binloginfo.CopyPrewriteValue(binloginfo.GetPrewriteValue(e.ctx, false)),
+	})
+	e.ctx.GetSessionVars().TxnCtx.Savepoints = sps
+	return nil
+}
+
+func (e *SimpleExec) executeRollbackToSavepoint(s *ast.RollbackStmt) error {
+	sps := getSavepoints(e.ctx)
+	i := findSavepoint(sps, s.SavepointName)
+	if i < 0 {
+		return ErrSavepointNotExists.GenByArgs(s.SavepointName)
+	}
+	sp := sps[i]
+	err := e.ctx.Txn().RollbackToSavepoint(sp.kvSp)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sessVars := e.ctx.GetSessionVars()
+	sessVars.TxnCtx.DirtyDB = sp.dirtyDB.clone()
+	if sp.binlog != nil {
+		sessVars.TxnCtx.Binlog = binloginfo.CopyPrewriteValue(sp.binlog)
+	} else {
+		sessVars.TxnCtx.Binlog = nil
+	}
+	// Savepoints set after the named savepoint are deleted.
+	sessVars.TxnCtx.Savepoints = sps[:i+1]
+	return nil
+}
+
+func (e *SimpleExec) executeReleaseSavepoint(s *ast.ReleaseSavepointStmt) error {
+	sps := getSavepoints(e.ctx)
+	i := findSavepoint(sps, s.Name)
+	if i < 0 {
+		return ErrSavepointNotExists.GenByArgs(s.Name)
+	}
+	e.ctx.GetSessionVars().TxnCtx.Savepoints = sps[:i]
+	return nil
+}
+
 func (e *SimpleExec) executeCreateUser(s *ast.CreateUserStmt) error {
 	users := make([]string, 0, len(s.Specs))
+	failedUsers := make([]string, 0, len(s.Specs))
 	for _, spec := range s.Specs {
 		userName, host := parseUser(spec.User)
 		exists, err1 := userExists(e.ctx, userName, host)
@@ -147,7 +238,7 @@ func (e *SimpleExec) executeCreateUser(s *ast.CreateUserStmt) error {
 		}
 		if exists {
 			if !s.IfNotExists {
-				return errors.New("Duplicate user")
+				failedUsers = append(failedUsers, spec.User)
 			}
 			continue
 		}
@@ -162,12 +253,17 @@ func (e *SimpleExec) executeCreateUser(s *ast.CreateUserStmt) error {
 		user := fmt.Sprintf(`("%s", "%s", "%s")`, host, userName, pwd)
 		users = append(users, user)
 	}
+	if len(failedUsers) > 0 {
+		errMsg := "Operation CREATE USER failed for " + strings.Join(failedUsers, ",")
+		return terror.ClassExecutor.New(CodeCannotUser, errMsg)
+	}
 	if len(users) == 0 {
 		return nil
 	}
 	sql := fmt.Sprintf(`INSERT INTO %s.%s (Host, User, Password) VALUES %s;`, mysql.SystemDB, mysql.UserTable, strings.Join(users, ", "))
 	_, err := e.ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(e.ctx, sql)
 	if err != nil {
+		// An account listed twice fails on the primary key of the user table.
 		return errors.Trace(err)
 	}
 	return nil
@@ -303,6 +399,30 @@ func (e *SimpleExec) executeSetPwd(s *ast.SetPwdStmt) error {
 	return errors.Trace(err)
 }
 
+func (e *SimpleExec) executeKillStmt(s *ast.KillStmt) error {
+	sm, ok := e.ctx.Value(SessionManagerKey).(util.SessionManager)
+	if !ok {
+		return ErrNoSuchThread.GenByArgs(s.ConnectionID)
+	}
+	// Without the SUPER privilege, only the connections of the current user can be killed.
+	hasSuperPriv, err := hasGlobalPriv(e.ctx, mysql.SuperPriv)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !hasSuperPriv {
+		loginUser := loginUserName(e.ctx)
+		for _, pi := range sm.ShowProcessList() {
+			if pi.ID == s.ConnectionID && pi.User != loginUser {
+				return privilegeDenied(e.ctx, ErrKillDenied.GenByArgs(s.ConnectionID))
+			}
+		}
+	}
+	if !sm.Kill(s.ConnectionID, s.Query) {
+		return ErrNoSuchThread.GenByArgs(s.ConnectionID)
+	}
+	return nil
+}
+
 func (e *SimpleExec) executeFlushTable(s *ast.FlushTableStmt) error {
 	// TODO: A dummy implement
 	return nil
@@ -324,11 +444,9 @@ const (
 )
 
 func (e *SimpleExec) createStatisticsForTable(tn *ast.TableName) error {
-	var tableName string
-	if tn.Schema.L == "" {
-		tableName = tn.Name.L
-	} else {
-		tableName = tn.Schema.L + "." + tn.Name.L
+	tableName := quoteIdentifier(tn.Name.O)
+	if tn.Schema.L != "" {
+		tableName = quoteIdentifier(tn.Schema.O) + "." + tableName
 	}
 	sql := "select * from " + tableName
 	result, err := e.ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(e.ctx, sql)
@@ -347,6 +465,11 @@ func (e *SimpleExec) createStatisticsForTable(tn *ast.TableName) error {
 	return nil
 }
 
+// quoteIdentifier quotes name with backticks, the backticks in name are doubled.
+func quoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
 // collectSamples collects sample from the result set, using Reservoir Sampling algorithm.
 // See https://en.wikipedia.org/wiki/Reservoir_sampling
 func (e *SimpleExec) collectSamples(result ast.RecordSet) (count int64, samples []*ast.Row, err error) {
@@ -390,6 +513,7 @@ func (e *SimpleExec) buildStatisticsAndSaveToKV(tn *ast.TableName, count int64,
 	if err != nil {
 		return errors.Trace(err)
 	}
+	plan.SetStatisticsTableCache(tn.TableInfo, t)
 	return nil
 }
 
@@ -15,20 +15,25 @@ package executor_test
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/plan/statistics"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/types"
 )
 
 func (s *testSuite) TestCharsetDatabase(c *C) {
@@ -86,12 +91,134 @@ func (s *testSuite) TestTransaction(c *C) {
 	tk.MustExec("create table txn2 (a int)")
 	tk.MustExec("rollback")
 	tk.MustQuery("select * from txn").Check(testkit.Rows("1", "2"))
+
+	// Test start transaction and autocommit.
+	tk.MustExec("start transaction")
+	tk.MustExec("insert txn values (3)")
+	tk.MustExec("rollback")
+	tk.MustExec("set autocommit = 0")
+	tk.MustExec("insert txn values (4)")
+	c.Assert(inTxn(ctx), IsTrue)
+	tk.MustExec("rollback")
+	tk.MustExec("insert txn values (5)")
+	tk.MustExec("commit")
+	tk.MustExec("set autocommit = 1")
+	tk.MustQuery("select * from txn").Check(testkit.Rows("1", "2", "5"))
+
+	// Test that the writes of GRANT are in the explicit transaction.
+	tk.MustExec("create user 'txn_user'@'localhost'")
+	tk.MustExec("begin")
+	tk.MustExec("grant select on test.* to 'txn_user'@'localhost'")
+	tk.MustExec("rollback")
+	tk.MustQuery("select count(*) from mysql.db where user = 'txn_user'").Check(testkit.Rows("0"))
+	tk.MustExec("begin")
+	tk.MustExec("grant select on test.* to 'txn_user'@'localhost'")
+	tk.MustExec("commit")
+	tk.MustQuery("select count(*) from mysql.db where user = 'txn_user'").Check(testkit.Rows("1"))
+	tk.MustExec("drop user 'txn_user'@'localhost'")
 }
 
 func inTxn(ctx context.Context) bool {
 	return (ctx.GetSessionVars().Status & mysql.ServerStatusInTrans) > 0
 }
 
+func (s *testSuite) TestSavepoint(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table sp (a int primary key, b int)")
+	tk.MustExec("begin")
+	tk.MustExec("insert sp values (1, 1)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert sp values (2, 2)")
+	tk.MustExec("update sp set b = 10 where a = 1")
+	tk.MustExec("savepoint s2")
+	tk.MustExec("delete from sp where a = 2")
+	tk.MustQuery("select * from sp").Check(testkit.Rows("1 10"))
+	tk.MustExec("rollback to savepoint s2")
+	tk.MustQuery("select * from sp").Check(testkit.Rows("1 10", "2 2"))
+	tk.MustExec("rollback to s1")
+	tk.MustQuery("select * from sp").Check(testkit.Rows("1 1"))
+	// A savepoint can be rolled back to more than once, savepoints set after it are deleted.
+	tk.MustExec("insert sp values (3, 3)")
+	tk.MustExec("rollback to savepoint S1")
+	tk.MustQuery("select * from sp").Check(testkit.Rows("1 1"))
+	_, err := tk.Exec("rollback to savepoint s2")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSavepointNotExists), IsTrue)
+	tk.MustExec("insert sp values (2, 20)")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from sp").Check(testkit.Rows("1 1", "2 20"))
+
+	// Test release savepoint.
+	tk.MustExec("begin")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert sp values (3, 3)")
+	tk.MustExec("release savepoint s1")
+	_, err = tk.Exec("rollback to savepoint s1")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSavepointNotExists), IsTrue)
+	_, err = tk.Exec("release savepoint s1")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSavepointNotExists), IsTrue)
+	tk.MustExec("commit")
+	tk.MustQuery("select * from sp").Check(testkit.Rows("1 1", "2 20", "3 3"))
+
+	// Savepoints are deleted when the transaction ends.
+	tk.MustExec("begin")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("rollback")
+	tk.MustExec("begin")
+	_, err = tk.Exec("rollback to savepoint s1")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSavepointNotExists), IsTrue)
+	tk.MustExec("rollback")
+}
+
+func (s *testSuite) TestKill(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table kill_t (a int primary key, b int)")
+	tk.MustExec("insert kill_t values (1, 1), (2, 2), (3, 3)")
+
+	// There is no session manager without a server, so no connection can be found.
+	_, err := tk.Exec("kill 1")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNoSuchThread), IsTrue)
+	_, err = tk.Exec("kill query 1")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNoSuchThread), IsTrue)
+
+	// A killed statement stops scanning and returns ErrQueryInterrupted.
+	rs, err := tk.Exec("select * from kill_t")
+	c.Assert(err, IsNil)
+	row, err := rs.Next()
+	c.Assert(err, IsNil)
+	c.Assert(row, NotNil)
+	atomic.StoreUint32(&tk.Se.GetSessionVars().Killed, 1)
+	_, err = rs.Next()
+	c.Assert(terror.ErrorEqual(err, executor.ErrQueryInterrupted), IsTrue)
+	rs.Close()
+
+	// The next statement is not affected.
+	tk.MustQuery("select count(*) from kill_t").Check(testkit.Rows("3"))
+
+	// Without the SUPER privilege, only the connections of the current user can be killed.
+	tk.MustExec("create user 'kill_user'@'localhost'")
+	defer tk.MustExec("drop user 'kill_user'@'localhost'")
+	sm := &mockSessionManager{processInfo: []util.ProcessInfo{
+		{ID: 1, User: "root"},
+		{ID: 2, User: "kill_user"},
+	}}
+	tk.Se, err = tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk.Se.SetValue(executor.SessionManagerKey, sm)
+	tk.Se.(context.Context).GetSessionVars().User = "kill_user@localhost"
+	_, err = tk.Exec("kill 1")
+	c.Assert(terror.ErrorEqual(err, executor.ErrKillDenied), IsTrue)
+	tk.MustExec("kill query 2")
+	_, err = tk.Exec("kill 3")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNoSuchThread), IsTrue)
+	tk.Se.(context.Context).GetSessionVars().User = "root@localhost"
+	tk.MustExec("kill 1")
+	c.Assert(sm.killed, DeepEquals, []uint64{2, 1})
+}
+
 func (s *testSuite) TestUser(c *C) {
 	defer testleak.AfterTest(c)()
 	tk := testkit.NewTestKit(c, s.store)
@@ -113,6 +240,11 @@ func (s *testSuite) TestUser(c *C) {
 	createUserSQL = `CREATE USER 'test'@'localhost' IDENTIFIED BY '123';`
 	_, err := tk.Exec(createUserSQL)
 	c.Check(err, NotNil)
+	c.Check(terror.ErrorEqual(err, terror.ClassExecutor.New(executor.CodeCannotUser, "")), IsTrue, Commentf("err %v", err))
+	// The same account listed twice is a duplicate entry in the user table.
+	_, err = tk.Exec(`CREATE USER 'test_dup'@'localhost', 'test_dup'@'localhost';`)
+	c.Check(err, NotNil)
+	c.Check(err.Error(), Equals, "[kv:1062]Duplicate entry 'localhost-test_dup' for key 'PRIMARY'")
 	dropUserSQL := `DROP USER IF EXISTS 'test'@'localhost' ;`
 	tk.MustExec(dropUserSQL)
 	// Create user test.
@@ -232,11 +364,81 @@ func (s *testSuite) TestAnalyzeTable(c *C) {
 
 	err = ctx.NewTxn()
 	c.Check(err, IsNil)
-	meta := meta.NewMeta(ctx.Txn())
-	tpb, err := meta.GetTableStats(tableID)
+	m := meta.NewMeta(ctx.Txn())
+	tpb, err := m.GetTableStats(tableID)
 	c.Check(err, IsNil)
 	c.Check(tpb, NotNil)
 	tStats, err := statistics.TableFromPB(t.Meta(), tpb)
 	c.Check(err, IsNil)
 	c.Check(tStats, NotNil)
+
+	// Analyze several tables in one statement, including an empty one.
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b varchar(10))")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert t1 values (1, 'a'), (2, 'a'), (3, 'b')")
+	tk.MustExec("analyze table t1, test.t2")
+	// The statistics are still used after a column is added.
+	tk.MustExec("alter table t1 add column c int")
+	is = sessionctx.GetDomain(ctx).InfoSchema()
+	err = ctx.NewTxn()
+	c.Check(err, IsNil)
+	m = meta.NewMeta(ctx.Txn())
+	for _, tbl := range []struct {
+		name    string
+		count   int64
+		columns int
+	}{{"t1", 3, 3}, {"t2", 0, 1}} {
+		t, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr(tbl.name))
+		c.Check(err, IsNil)
+		tpb, err = m.GetTableStats(t.Meta().ID)
+		c.Check(err, IsNil)
+		c.Check(tpb, NotNil)
+		tStats, err = statistics.TableFromPB(t.Meta(), tpb)
+		c.Check(err, IsNil)
+		c.Check(tStats.Count, Equals, tbl.count)
+		c.Check(tStats.Columns, HasLen, tbl.columns)
+	}
+
+	// The statistics saved by another server are used, the pseudo statistics isn't cached.
+	tk.MustExec("create table t3 (a int)")
+	is = sessionctx.GetDomain(ctx).InfoSchema()
+	t, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("t3"))
+	c.Check(err, IsNil)
+	saveStats := func(ts, count int64) {
+		err := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+			sc := new(variable.StatementContext)
+			tStats, err := statistics.NewTable(sc, t.Meta(), ts, count, 256, [][]types.Datum{nil})
+			if err != nil {
+				return err
+			}
+			tpb, err := tStats.ToPB()
+			if err != nil {
+				return err
+			}
+			return meta.NewMeta(txn).SetTableStats(t.Meta().ID, tpb)
+		})
+		c.Check(err, IsNil)
+	}
+	getStats := func() *statistics.Table {
+		c.Check(ctx.NewTxn(), IsNil)
+		return plan.GetStatisticsTable(ctx, t.Meta())
+	}
+	c.Check(getStats().Pseudo, IsTrue)
+	saveStats(1, 10)
+	c.Check(getStats().Count, Equals, int64(10))
+	saveStats(2, 20)
+	c.Check(getStats().Count, Equals, int64(20))
+
+	// The backticks in the table name are escaped.
+	tk.MustExec("create table `t``4` (a int)")
+	tk.MustExec("insert `t``4` values (1), (2)")
+	tk.MustExec("analyze table `t``4`, test.`t``4`")
+	is = sessionctx.GetDomain(ctx).InfoSchema()
+	t, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("t`4"))
+	c.Check(err, IsNil)
+	c.Check(ctx.NewTxn(), IsNil)
+	c.Check(plan.GetStatisticsTable(ctx, t.Meta()).Count, Equals, int64(2))
+	tk.MustExec("drop table `t``4`")
 }
@@ -26,13 +26,17 @@ import (
 	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/inspectkv"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/types"
@@ -79,9 +83,13 @@ func (s *testSuite) TearDownSuite(c *C) {
 func (s *testSuite) cleanEnv(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
-	r := tk.MustQuery("show tables")
+	r := tk.MustQuery("show full tables")
 	for _, tb := range r.Rows() {
 		tableName := tb[0]
+		if tb[1] == "VIEW" {
+			tk.MustExec(fmt.Sprintf("drop view %v", tableName))
+			continue
+		}
 		tk.MustExec(fmt.Sprintf("drop table %v", tableName))
 	}
 }
@@ -199,6 +207,17 @@ func (s *testSuite) TestSelectWithoutFrom(c *C) {
 	r = tk.MustQuery(`select _utf8"string";`)
 	r.Check(testkit.Rows("string"))
 	tk.MustExec("commit")
+
+	r = tk.MustQuery("select database(), schema(), version(), now() is not null, connection_id() > 0")
+	r.Check(testkit.Rows("test test " + mysql.ServerVersion + " 1 1"))
+	r = tk.MustQuery("select 1 + 1, 'a', null from dual")
+	r.Check(testkit.Rows("2 a <nil>"))
+	r = tk.MustQuery("select 1 limit 0")
+	r.Check(testkit.Rows())
+	r = tk.MustQuery("select 1 limit 1, 1")
+	r.Check(testkit.Rows())
+	r = tk.MustQuery("select 1 from dual where 1 = 0")
+	r.Check(testkit.Rows())
 }
 
 func (s *testSuite) TestSelectLimit(c *C) {
@@ -246,6 +265,27 @@ func (s *testSuite) TestSelectLimit(c *C) {
 	_, err := tk.Exec("select * from select_limit limit 18446744073709551616 offset 3;")
 	c.Assert(err, NotNil)
 	tk.MustExec("rollback")
+
+	tk.MustExec("create table select_offset (a int primary key, b int, index b (b))")
+	tk.MustExec("insert select_offset values (1, 5), (2, 4), (3, 3), (4, 2), (5, 1)")
+	tk.MustQuery("select a from select_offset order by b limit 1, 2").Check(testkit.Rows("4", "3"))
+	tk.MustQuery("select a from select_offset order by b limit 2 offset 1").Check(testkit.Rows("4", "3"))
+	tk.MustQuery("select a from select_offset order by b desc limit 10 offset 3").Check(testkit.Rows("4", "5"))
+	tk.MustQuery("select a from select_offset order by b limit 2 offset 5").Check(testkit.Rows())
+	tk.MustQuery("select a from select_offset limit 2 offset 10").Check(testkit.Rows())
+	tk.MustQuery("select a from select_offset limit 0").Check(testkit.Rows())
+	tk.MustQuery("select a from select_offset limit 0 offset 1").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from select_offset limit 1, 1").Check(testkit.Rows())
+	tk.MustQuery("select a from (select a from select_offset order by a limit 2, 2) t").Check(testkit.Rows("3", "4"))
+
+	// Test offset with the rows written in the current transaction.
+	tk.MustExec("begin")
+	tk.MustExec("insert select_offset values (6, 0), (7, 6)")
+	tk.MustExec("delete from select_offset where a = 3")
+	tk.MustQuery("select a from select_offset order by b limit 1, 2").Check(testkit.Rows("5", "4"))
+	tk.MustQuery("select a from select_offset limit 2 offset 4").Check(testkit.Rows("6", "7"))
+	tk.MustQuery("select a from select_offset use index (b) order by b limit 2, 2").Check(testkit.Rows("4", "2"))
+	tk.MustExec("rollback")
 }
 
 func (s *testSuite) TestSelectOrderBy(c *C) {
@@ -330,6 +370,33 @@ func (s *testSuite) TestSelectOrderBy(c *C) {
 	r.Check(testkit.Rows("0", "-1", "-2"))
 	r = tk.MustQuery("select t.d from t order by d;")
 	r.Check(testkit.Rows("1", "2", "3"))
+
+	// Multiple keys with mixed directions, nulls come first in ascending order.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10), c double)")
+	tk.MustExec("insert t values (1, 'b', 1.5), (null, 'A', 2), (2, null, 3), (1, 'a', null), (2, 'C', 5), (null, 'c', 6)")
+	r = tk.MustQuery("select a, c from t order by a desc, upper(b) asc")
+	r.Check(testkit.Rows("2 3", "2 5", "1 <nil>", "1 1.5", "<nil> 2", "<nil> 6"))
+	r = tk.MustQuery("select a, c from t order by a, b desc")
+	r.Check(testkit.Rows("<nil> 6", "<nil> 2", "1 1.5", "1 <nil>", "2 5", "2 3"))
+	r = tk.MustQuery("select c from t order by c desc")
+	r.Check(testkit.Rows("6", "5", "3", "2", "1.5", "<nil>"))
+	r = tk.MustQuery("select a, c from t order by a is null, -a desc, concat(b, 'x')")
+	r.Check(testkit.Rows("1 <nil>", "1 1.5", "2 3", "2 5", "<nil> 2", "<nil> 6"))
+	r = tk.MustQuery("select a, c from t order by a + c desc limit 3")
+	r.Check(testkit.Rows("2 5", "2 3", "1 1.5"))
+
+	// An integer literal refers to a select field, other integer expressions are sort keys.
+	r = tk.MustQuery("select c, a from t order by 2 desc, 1 limit 3")
+	r.Check(testkit.Rows("3 2", "5 2", "<nil> 1"))
+	r = tk.MustQuery("select a + 1, count(*) from t group by 1 order by 1")
+	r.Check(testkit.Rows("<nil> 2", "2 2", "3 2"))
+	r = tk.MustQuery("select c from t order by 1 + 1, c limit 2")
+	r.Check(testkit.Rows("<nil>", "1.5"))
+	_, err := tk.Exec("select a, c from t order by 3")
+	c.Assert(terror.ErrorEqual(err, plan.ErrUnknownColumn), IsTrue)
+	_, err = tk.Exec("select a, count(*) from t group by 2")
+	c.Assert(terror.ErrorEqual(err, plan.ErrWrongGroupField), IsTrue)
 }
 
 func (s *testSuite) TestSelectDistinct(c *C) {
@@ -347,6 +414,22 @@ func (s *testSuite) TestSelectDistinct(c *C) {
 	r.Check(testkit.Rows(rowStr))
 	tk.MustExec("commit")
 
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert t values (1, 1, 1), (null, 1, 2), (2, 2, 3), (4, 1, 4), (null, 1, 5), (1, 1, 6)")
+	r = tk.MustQuery("select distinct a, b from t")
+	r.Check(testkit.Rows("1 1", "<nil> 1", "2 2", "4 1"))
+	r = tk.MustQuery("select distinct a from t order by a desc limit 2")
+	r.Check(testkit.Rows("4", "2"))
+	r = tk.MustQuery("select distinct a + b from t order by 1")
+	r.Check(testkit.Rows("<nil>", "2", "4", "5"))
+	r = tk.MustQuery("select count(*) from (select distinct a, b from t) k")
+	r.Check(testkit.Rows("4"))
+	// The order by items not in the select list don't take part in the duplication check.
+	r = tk.MustQuery("select distinct b from t order by c")
+	r.Check(testkit.Rows("1", "2"))
+	r = tk.MustQuery("select distinct a from t order by b, c limit 2, 5")
+	r.Check(testkit.Rows("4", "2"))
 }
 
 func (s *testSuite) TestSelectErrorRow(c *C) {
@@ -523,6 +606,23 @@ func (s *testSuite) TestUnion(c *C) {
 	r = tk.MustQuery("select (select * from t1 where a != t.a union all (select * from t2 where a != t.a) order by a limit 1) from t1 t")
 	r.Check(testkit.Rows("1", "2"))
 
+	// The trailing order by and limit of an unparenthesized select apply to the whole union.
+	r = tk.MustQuery("select * from t1 union all select * from t2 order by a")
+	r.Check(testkit.Rows("1", "2", "3", "4"))
+	r = tk.MustQuery("select * from t1 union all select * from t2 order by a desc limit 1, 2")
+	r.Check(testkit.Rows("3", "2"))
+
+	// The column types of all the selects are unified.
+	r = tk.MustQuery("select a from t1 union all select 2.25 union all (select 'x') order by a")
+	r.Check(testkit.Rows("1", "2", "2.25", "x"))
+	r = tk.MustQuery("select 1 union all select 2.5e0")
+	r.Check(testkit.Rows("1", "2.5"))
+
+	_, err := tk.Exec("select a from t1 union select a, a from t2")
+	c.Assert(plan.ErrWrongNumberOfColumnsInSelect.Equal(err), IsTrue)
+	_, err = tk.Exec("select 1, 2 union all select 1 union all select 1, 2")
+	c.Assert(plan.ErrWrongNumberOfColumnsInSelect.Equal(err), IsTrue)
+
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (id int unsigned primary key auto_increment, c1 int, c2 int, index c1_c2 (c1, c2))")
 	tk.MustExec("insert into t (c1, c2) values (1, 1)")
@@ -550,6 +650,121 @@ func (s *testSuite) TestIn(c *C) {
 
 	queryStr = `select c2 from t where c1 in ('7a')`
 	tk.MustQuery(queryStr).Check(testkit.Rows("7"))
+
+	// NULL in the list never matches, even on an index.
+	tk.MustExec("insert t values(201, null)")
+	tk.MustQuery("select c1 from t where c2 in (null, 3)").Check(testkit.Rows("3"))
+	tk.MustQuery("select c1 from t where c2 in (null)").Check(testkit.Rows())
+	tk.MustQuery("select c1 from t where c1 in (null, 3)").Check(testkit.Rows("3"))
+}
+
+func (s *testSuite) TestRowIn(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int, b int, index idx (a, b))")
+	tk.MustExec("insert t values (1, 2), (3, 4), (5, 6), (null, 2), (1, null)")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("insert t1 values (1, 2), (5, 6)")
+	tk.MustQuery("select (1, 2) in ((1, 2), (3, 4)), (1, 2) in ((1, 3)), (1, '2') in ((1.0, 2)), (1, 2) not in ((1, 3))").
+		Check(testkit.Rows("1 0 1 1"))
+	tk.MustQuery("select (1, null) in ((1, 2)), (1, null) in ((2, 2)), (1, 2) in ((1, null), (3, 4)), (1, 2) not in ((1, null))").
+		Check(testkit.Rows("<nil> 0 <nil> <nil>"))
+	tk.MustQuery("select a, b from t where (a, b) in ((1, 2), (5, 6))").Check(testkit.Rows("1 2", "5 6"))
+	tk.MustQuery("select a, b from t where (a, b) not in ((1, 2), (5, 6))").Check(testkit.Rows("3 4"))
+	tk.MustQuery("select a, b from t where (a, b) in (select a, b from t1)").Check(testkit.Rows("1 2", "5 6"))
+	tk.MustQuery("select a, b from t where (a, b) not in (select a, b from t1)").Check(testkit.Rows("3 4"))
+	tk.MustExec("insert t1 values (3, null)")
+	tk.MustQuery("select (3, 4) in (select a, b from t1), (3, 4) not in (select a, b from t1), (4, 4) not in (select a, b from t1)").
+		Check(testkit.Rows("<nil> <nil> 1"))
+	tk.MustQuery("select a, b, (a, b) in (select a, b from t1) from t").
+		Check(testkit.Rows("1 2 1", "3 4 <nil>", "5 6 1", "<nil> 2 <nil>", "1 <nil> <nil>"))
+	_, err := tk.Exec("select (1, 2) in ((1, 2, 3))")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("select (1, 2) in (select a from t1)")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite) TestJSON(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, j json)")
+	tk.MustExec(`insert t values (1, '{"b": [1, 2.5, {"c": "x"}], "a": "hello"}'), (2, '[3, "four"]'), (3, null)`)
+	_, err := tk.Exec(`insert t values (4, '{"a": }')`)
+	c.Assert(terror.ErrorEqual(err, types.ErrInvalidJSONText), IsTrue)
+	_, err = tk.Exec(`insert t values (4, 5)`)
+	c.Assert(terror.ErrorEqual(err, types.ErrInvalidJSONText), IsTrue)
+	_, err = tk.Exec("create index j on t (j)")
+	c.Assert(err, NotNil)
+
+	// The stored text is normalized.
+	tk.MustQuery("select j from t where id = 1").Check(testkit.Rows(fmt.Sprintf("%v", []byte(`{"a": "hello", "b": [1, 2.5, {"c": "x"}]}`))))
+	tk.MustQuery("select id, j->'$.a', j->>'$.a', t.j->'$.b[2].c', j->'$[1]' from t order by id").Check(testkit.Rows(
+		`1 "hello" hello "x" <nil>`, `2 <nil> <nil> <nil> "four"`, "3 <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select json_extract(j, '$.a', '$.b[0]'), json_extract(j, '$.b[*]') from t where id = 1").Check(testkit.Rows(
+		`["hello", 1] [1, 2.5, {"c": "x"}]`))
+	tk.MustQuery("select id from t where j->>'$.a' = 'hello'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where j->'$[0]' > 2").Check(testkit.Rows("2"))
+	tk.MustQuery(`select json_unquote(json_extract('{"a": "x\\ty"}', '$.a'))`).Check(testkit.Rows("x\ty"))
+	rs, err := tk.Exec("select json_extract(j, 'a') from t")
+	c.Assert(err, IsNil)
+	_, err = tidb.GetRows(rs)
+	c.Assert(terror.ErrorEqual(err, types.ErrInvalidJSONPath), IsTrue)
+}
+
+func (s *testSuite) TestCaseInsensitiveCollation(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a varchar(10), b varchar(10) collate utf8_bin, c varchar(10) collate utf8_general_ci, index c (c))")
+	tk.MustExec("insert t values (1, 'abc', 'abc', 'abc'), (2, 'ABC', 'ABC', 'ABC'), (3, 'x', 'x', 'X')")
+	// Strings are compared as binary strings unless the column has a case-insensitive collation.
+	tk.MustQuery("select id from t where a = 'abc'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where b = 'abc'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where c = 'abc'").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t ignore index (c) where c = 'abc'").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t use index (c) where c = 'aBc' order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select c from t use index (c) where c = 'aBc' order by id").Check(testkit.Rows(fmt.Sprintf("%v", []byte("abc")), fmt.Sprintf("%v", []byte("ABC"))))
+	tk.MustQuery("select id from t use index (c) where c != 'ABC'").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t use index (c) where c > 'abc'").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t use index (c) where c >= 'abc' and c < 'x' order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t use index (c) where c in ('aBc', 'x') order by id").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t use index (c) where c = 'abc' or c = 'X' order by id").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t use index (c) where c like 'aB%' order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t ignore index (c) where c like 'aB%' order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where c = a").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t where c = binary 'abc'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from (select id, c from t) x where c = 'ABC'").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select t1.id, t2.id from t t1 join t t2 on t1.c = t2.c").Check(testkit.Rows("1 1", "1 2", "2 1", "2 2", "3 3"))
+	tk.MustQuery("select id from t where c in (select a from t)").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select 'abc' = 'ABC'").Check(testkit.Rows("0"))
+	tk.MustExec("admin check table t")
+
+	// A unique index on a case-insensitive column rejects the values that only differ in case.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (s varchar(10) collate utf8_general_ci, unique index s (s))")
+	tk.MustExec("insert t values ('abc')")
+	tk.MustQuery("select s from t where s = 'ABC'").Check(testkit.Rows(fmt.Sprintf("%v", []byte("abc"))))
+	_, err := tk.Exec("insert t values ('ABC')")
+	c.Assert(terror.ErrorEqual(err, kv.ErrKeyExists), IsTrue, Commentf("err %v", err))
+	tk.MustExec("update t set s = 'aBC' where s = 'Abc'")
+	tk.MustQuery("select s from t where s = 'abc'").Check(testkit.Rows(fmt.Sprintf("%v", []byte("aBC"))))
+	tk.MustExec("delete from t where s = 'ABC'")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("0"))
+	tk.MustExec("admin check table t")
 }
 
 func (s *testSuite) TestTablePKisHandleScan(c *C) {
@@ -713,6 +928,122 @@ func (s *testSuite) TestJoin(c *C) {
 	plan.AllowCartesianProduct = true
 }
 
+func (s *testSuite) TestBetween(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, d datetime, s varchar(10))")
+	tk.MustExec("insert t values (1, '2017-01-01 10:00:00', 'apple'), (5, '2017-02-01', 'banana'), (10, '2017-03-01', 'cherry'), (null, null, null)")
+	result := tk.MustQuery("select a from t where a between 2 and 10")
+	result.Check(testkit.Rows("5", "10"))
+	result = tk.MustQuery("select a from t where a not between 2 and 10")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select a from t where a between 10 and 2")
+	result.Check(nil)
+	result = tk.MustQuery("select a from t where d between '2017-01-15' and '2017-03-01'")
+	result.Check(testkit.Rows("5", "10"))
+	result = tk.MustQuery("select a from t where d not between '2017-01-01' and '2017-02-01'")
+	result.Check(testkit.Rows("10"))
+	result = tk.MustQuery("select a from t where s between 'b' and 'c'")
+	result.Check(testkit.Rows("5"))
+	result = tk.MustQuery("select a, a between 1 and 5, a not between 1 and 5 from t")
+	result.Check(testkit.Rows("1 1 0", "5 1 0", "10 0 1", "<nil> <nil> <nil>"))
+}
+
+func (s *testSuite) TestCompareConversion(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, d datetime, f double)")
+	tk.MustExec("insert t values (123, '2017-01-01 10:00:00', 1.5), (12, '2017-02-01', 2)")
+
+	// Numeric context, the string is converted to a number with a warning for each compared row.
+	result := tk.MustQuery("select a from t where a = '123abc'")
+	result.Check(testkit.Rows("123"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 2)
+	result = tk.MustQuery("select a = '123abc', a < '20', f = '1.5' from t")
+	result.Check(testkit.Rows("1 0 1", "0 1 0"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 2)
+	result = tk.MustQuery("select a from t where a = '123'")
+	result.Check(testkit.Rows("123"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	result = tk.MustQuery("select 123 = '123abc', '1e2' = 100, 'abc' = 0")
+	result.Check(testkit.Rows("1 1 1"))
+
+	// Temporal context, the string is converted to a datetime.
+	result = tk.MustQuery("select a from t where d = '2017-02-01'")
+	result.Check(testkit.Rows("12"))
+	result = tk.MustQuery("select a from t where d > '2017-01-01'")
+	result.Check(testkit.Rows("123", "12"))
+
+	// String context.
+	result = tk.MustQuery("select '10' < '9', 10 < 9, '10' < 9")
+	result.Check(testkit.Rows("1 0 0"))
+
+	// Explicit conversion.
+	result = tk.MustQuery("select a from t where cast(a as char) = '12'")
+	result.Check(testkit.Rows("12"))
+	result = tk.MustQuery("select a from t where a = cast('12abc' as signed)")
+	result.Check(testkit.Rows("12"))
+}
+
+func (s *testSuite) TestJoinUsing(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1 (a int, b int, c int)")
+	tk.MustExec("create table t2 (b int, a int, d int)")
+	tk.MustExec("create table t3 (a int, e int)")
+	tk.MustExec("insert t1 values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustExec("insert t2 values (1, 1, 10), (2, 4, 20), (4, 4, 40)")
+	tk.MustExec("insert t3 values (1, 100), (5, 500)")
+	result := tk.MustQuery("select * from t1 join t2 using (a) order by a")
+	result.Check(testkit.Rows("1 1 1 1 10"))
+	result = tk.MustQuery("select * from t1 join t2 using (a, b) order by a")
+	result.Check(testkit.Rows("1 1 1 10"))
+	result = tk.MustQuery("select * from t1 left join t2 using (b) order by b")
+	result.Check(testkit.Rows("1 1 1 1 10", "2 2 2 4 20", "3 3 3 <nil> <nil>"))
+	result = tk.MustQuery("select * from t1 right join t2 using (b) order by b")
+	result.Check(testkit.Rows("1 1 10 1 1", "2 4 20 2 2", "4 4 40 <nil> <nil>"))
+	result = tk.MustQuery("select a, t1.c, d from t1 left outer join t2 using (a) where a > 1 order by a")
+	result.Check(testkit.Rows("2 2 <nil>", "3 3 <nil>"))
+	result = tk.MustQuery("select a, c, d, e from t1 join t2 using (a) join t3 using (a)")
+	result.Check(testkit.Rows("1 1 10 100"))
+
+	// The using columns of the inner table can still be referenced by qualified names.
+	result = tk.MustQuery("select t1.b, t2.b from t1 left join t2 using (b) where t2.b is not null order by t2.b")
+	result.Check(testkit.Rows("1 1", "2 2"))
+	result = tk.MustQuery("select t1.b, t2.b from t1 right join t2 using (b) order by t2.b desc")
+	result.Check(testkit.Rows("<nil> 4", "2 2", "1 1"))
+	result = tk.MustQuery("select t2.* from t1 join t2 using (b) order by b")
+	result.Check(testkit.Rows("1 1 10", "2 4 20"))
+	result = tk.MustQuery("select t1.* from t1 join t2 using (b) order by b")
+	result.Check(testkit.Rows("1 1 1", "2 2 2"))
+	result = tk.MustQuery("select t1.*, t2.* from t1 join t2 using (a, b)")
+	result.Check(testkit.Rows("1 1 1 1 1 10"))
+	result = tk.MustQuery("select t3.a, t2.a from t1 join t2 using (a) join t3 using (a)")
+	result.Check(testkit.Rows("1 1"))
+
+	_, err := tk.Exec("select * from t1 join t3 using (b)")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	_, err = tk.Exec("select * from t1 join t2 using (e)")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	_, err = tk.Exec("select b from t1 join t2 using (a)")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuite) TestMultiJoin(c *C) {
 	defer func() {
 		s.cleanEnv(c)
@@ -867,6 +1198,26 @@ func (s *testSuite) TestIndexScan(c *C) {
 	result.Check(testkit.Rows("1 1 1", "3 1 2"))
 	result = tk.MustQuery("SELECT * FROM tab1 WHERE pk <= 4 AND a = 1 AND b = 2")
 	result.Check(testkit.Rows("3 1 2"))
+	// An "or" of equal conditions on an index column is used like "in".
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, primary key (a, b))")
+	tk.MustExec("insert t values (1, 1, 1), (1, 2, 2), (2, 1, 3), (2, 2, 4), (3, 1, 5)")
+	result = tk.MustQuery("select c from t where (a = 1 or a = 2) and b = 2")
+	result.Check(testkit.Rows("2", "4"))
+	result = tk.MustQuery("select c from t where b = 1 and (a = 3 or a = 1 or a = 1)")
+	result.Check(testkit.Rows("1", "5"))
+	result = tk.MustQuery("select c from t where (a = 1 or a = 2) and b > 1")
+	result.Check(testkit.Rows("2", "4"))
+	// The ranges on the second and third index columns don't include NULL.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, index idx (a, b, c))")
+	tk.MustExec("insert t values (1, 2, NULL), (1, 2, 3), (1, 2, 7), (1, NULL, 1), (1, 3, 1)")
+	result = tk.MustQuery("select * from t where a = 1 and b = 2 and c < 5")
+	result.Check(testkit.Rows("1 2 3"))
+	result = tk.MustQuery("select c from t where a = 1 and b < 3")
+	result.Check(testkit.Rows("<nil>", "3", "7"))
+	result = tk.MustQuery("select b from t where a = 1 and c = 1")
+	result.Check(testkit.Rows("<nil>", "3"))
 }
 
 func (s *testSuite) TestSubquerySameTable(c *C) {
@@ -883,6 +1234,19 @@ func (s *testSuite) TestSubquerySameTable(c *C) {
 	result.Check(testkit.Rows("2"))
 	result = tk.MustQuery("select a from t where not exists(select 1 from t as x where x.a < t.a)")
 	result.Check(testkit.Rows("1"))
+
+	// A null in the subquery never makes "not exists" unknown.
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert t1 values (1), (null)")
+	result = tk.MustQuery("select a from t where not exists(select 1 from t1 where t1.a = t.a)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a, not exists(select 1 from t1 where t1.a = t.a) from t")
+	result.Check(testkit.Rows("1 0", "2 1"))
+	result = tk.MustQuery("select a from t where not not exists(select 1 from t1 where t1.a = t.a)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select not exists(select 1 from t1), not exists(select 1 from t1 where a > 5)")
+	result.Check(testkit.Rows("0 1"))
 }
 
 func (s *testSuite) TestIndexReverseOrder(c *C) {
@@ -962,6 +1326,25 @@ func (s *testSuite) TestInSubquery(c *C) {
 	tk.MustExec("insert into t2 values (1),(2),(3),(4),(5),(6),(7),(8),(9),(10)")
 	result = tk.MustQuery("select a from t1 where (1,1) in (select * from t2 s , t2 t where t1.a = s.a and s.a = t.a limit 1)")
 	result.Check(testkit.Rows("1"))
+
+	// x in (subquery) is null rather than false when x is not found but the subquery returns a null.
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert t1 values (1), (null)")
+	result = tk.MustQuery("select a, a in (select a from t1), a not in (select a from t1) from t")
+	result.Check(testkit.Rows("1 1 0", "2 <nil> <nil>"))
+	result = tk.MustQuery("select a from t where a not in (select a from t1)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select null in (select a from t1), null in (select a from t1 where a > 5), 1 in (select a from t1 where a > 5)")
+	result.Check(testkit.Rows("<nil> 0 0"))
+	result = tk.MustQuery("select a, a in (select t1.a from t1 where t1.a is null or t1.a = t.b) from t")
+	result.Check(testkit.Rows("1 1", "2 <nil>"))
+	result = tk.MustQuery("select a, a not in (select t1.a from t1 where t1.a is null or t1.a = t.b) from t")
+	result.Check(testkit.Rows("1 0", "2 <nil>"))
+	result = tk.MustQuery("select a, a in (select t1.a from t1 where t1.a > t.a), a not in (select t1.a from t1 where t1.a > t.a) from t")
+	result.Check(testkit.Rows("1 0 1", "2 0 1"))
+	result = tk.MustQuery("select a from t where a not in (select t1.a from t1 where t1.a is null or t1.a = t.b)")
+	result.Check(testkit.Rows())
 }
 
 func (s *testSuite) TestDefaultNull(c *C) {
@@ -984,6 +1367,60 @@ func (s *testSuite) TestDefaultNull(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("1 1 <nil>"))
 }
 
+func (s *testSuite) TestIsNullAndIsTruth(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index ia(a))")
+	tk.MustExec("insert t values (1, 1), (null, 2), (0, 3)")
+	tk.MustQuery("select a is null, a is not null, a = null from t order by b").Check(testkit.Rows(
+		"0 1 <nil>", "1 0 <nil>", "0 1 <nil>"))
+	tk.MustQuery("select a is true, a is not true, a is false, a is not false from t order by b").Check(testkit.Rows(
+		"1 0 0 1", "0 1 0 1", "0 1 1 0"))
+	tk.MustQuery("select a is unknown, a is not unknown, (a > 0) is unknown from t order by b").Check(testkit.Rows(
+		"0 1 0", "1 0 1", "0 1 0"))
+
+	// The predicates are used in the index ranges as well as in the filters.
+	tk.MustQuery("select b from t where a is null").Check(testkit.Rows("2"))
+	tk.MustQuery("select b from t where a is not null order by b").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select b from t where a = null").Check(testkit.Rows())
+	tk.MustQuery("select b from t where a is unknown").Check(testkit.Rows("2"))
+	tk.MustQuery("select b from t where a is false").Check(testkit.Rows("3"))
+	tk.MustQuery("select b from t where a is not true order by b").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select b from t where b is not unknown and (a > 0) is unknown").Check(testkit.Rows("2"))
+}
+
+func (s *testSuite) TestDecimalArithmetic(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a decimal(10,2), b decimal(10,3), c int)")
+	tk.MustExec("insert t values (1.25, 2.125, 3), (0.10, 0.200, 3)")
+	// The scale of the result is kept rather than converted to float.
+	tk.MustQuery("select a + b, a - b, a * b, a * a * a, a + c from t order by a").Check(testkit.Rows(
+		"0.300 -0.100 0.02000 0.001000 3.10", "3.375 -0.875 2.65625 1.953125 4.25"))
+	tk.MustQuery("select sum(a + b) from t").Check(testkit.Rows("3.675"))
+
+	// The scale of a division result is the scale of the dividend plus div_precision_increment.
+	tk.MustQuery("select a / b, a / c, c / 3, 1 / 3 from t where c = 3 order by a").Check(testkit.Rows(
+		"0.500000 0.033333 1.0000 0.3333", "0.588235 0.416667 1.0000 0.3333"))
+	tk.MustExec("set @@div_precision_increment = 8")
+	tk.MustQuery("select a / b, 1 / 3, avg(a) from t where a > 1").Check(testkit.Rows("0.5882352941 0.33333333 1.2500000000"))
+	tk.MustExec("set @@div_precision_increment = 0")
+	tk.MustQuery("select a / b, 1 / 3, avg(a) from t where a > 1").Check(testkit.Rows("0.59 0 1.25"))
+	// A division in a condition is not pushed down if the coprocessor would compute it with another scale.
+	tk.MustExec("set @@div_precision_increment = 30")
+	tk.MustQuery("select a from t where a / b > 0.58823529411764705882352941176").Check(testkit.Rows("1.25"))
+}
+
 func (s *testSuite) TestUnsignedPKColumn(c *C) {
 	defer func() {
 		s.cleanEnv(c)
@@ -1029,6 +1466,12 @@ func (s *testSuite) TestBuiltin(c *C) {
 	result = tk.MustQuery("select * from t where b not in (a)")
 	result.Check(testkit.Rows("3 2"))
 
+	// for bit operations
+	result = tk.MustQuery("select ~0, 5 & 3, 5 | 3, 5 ^ 3, 1 << 63, -1 >> 60, 18446744073709551615 & 1, '12' & 7, 3.6 & 7")
+	result.Check(testkit.Rows("18446744073709551615 1 7 6 9223372036854775808 15 1 4 4"))
+	result = tk.MustQuery("select a | b, a & null, ~a from t where a = 2")
+	result.Check(testkit.Rows("2 <nil> 18446744073709551613"))
+
 	// test cast
 	result = tk.MustQuery("select cast(1 as decimal(3,2))")
 	result.Check(testkit.Rows("1.00"))
@@ -1077,6 +1520,15 @@ func (s *testSuite) TestBuiltin(c *C) {
 	result = tk.MustQuery("select strcmp('abc', 'abc')")
 	result.Check(testkit.Rows("0"))
 
+	// for cast
+	result = tk.MustQuery("select cast('abc' as signed), cast('12abc' as signed), cast(-1 as unsigned), cast(18446744073709551615 as signed), cast(1.5 as signed), cast('1.5' as signed)")
+	result.Check(testkit.Rows("0 12 18446744073709551615 -1 2 1"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 2)
+	result = tk.MustQuery("select cast('3.145' as decimal(4,2)), cast(12345.6 as decimal(4,1)), cast(123 as char(2)), convert(12, char), convert('12', signed)")
+	result.Check(testkit.Rows("3.15 999.9 12 12 12"))
+	result = tk.MustQuery("select cast('2017-01-02 10:11:12' as date), cast('2017-01-02' as datetime), cast('abc' as date), cast('10:11:12' as time)")
+	result.Check(testkit.Rows("2017-01-02 2017-01-02 00:00:00 <nil> 10:11:12"))
+
 	// for case
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (a varchar(255), b int)")
@@ -1109,6 +1561,34 @@ func (s *testSuite) TestBuiltin(c *C) {
 	result = tk.MustQuery("select * from t where a = case null when b then 'str3' when 10 then 'str1' else 'str2' end")
 	result.Check(testkit.Rows(rowStr2))
 
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10))")
+	tk.MustExec("insert t values (1, 'x'), (2, 'y'), (3, null), (null, 'z')")
+	result = tk.MustQuery("select a, case a when 1 then 'one' when 2 then 'two' else 'many' end from t")
+	result.Check(testkit.Rows("1 one", "2 two", "3 many", "<nil> many"))
+	result = tk.MustQuery("select a, case when a > 2 then 'big' when a > 1 then 'mid' end from t")
+	result.Check(testkit.Rows("1 <nil>", "2 mid", "3 big", "<nil> <nil>"))
+	result = tk.MustQuery("select a from t where case a when 1 then 0 else 1 end order by case when a is null then 0 else -a end")
+	result.Check(testkit.Rows("3", "2", "<nil>"))
+	result = tk.MustQuery("select case when 0 then 1 else 2.5 end, case 3 when 1 then 'a' end, case null when null then 1 else 2 end")
+	result.Check(testkit.Rows("2.5 <nil> 2"))
+	// The branches after the matched one are not evaluated.
+	result = tk.MustQuery("select case when 1 then 1 else now(8) end")
+	result.Check(testkit.Rows("1"))
+	rs, err := tk.Exec("select case when 0 then 1 else now(8) end")
+	c.Assert(err, IsNil)
+	_, err = tidb.GetRows(rs)
+	c.Assert(err, NotNil)
+
+	// for coalesce, ifnull, nullif and if
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c double)")
+	tk.MustExec("insert t values (1, 2, 1.5), (null, 3, null), (null, null, 2.5)")
+	result = tk.MustQuery("select coalesce(null, null), coalesce(a, b, 'none'), ifnull(a, c), if(a, 'yes', 2), nullif(a, 1), nullif(c, 2.5) from t")
+	result.Check(testkit.Rows("<nil> 1 1 yes <nil> 1.5", "<nil> 3 <nil> 2 <nil> <nil>", "<nil> none 2.5 2 <nil> <nil>"))
+	result = tk.MustQuery("select ifnull(null, null), if(null, 1, 2.5), nullif(null, 1), nullif(1, null)")
+	result.Check(testkit.Rows("<nil> 2.5 <nil> 1"))
+
 	// for like and regexp
 	type testCase struct {
 		pattern string
@@ -1142,6 +1622,21 @@ func (s *testSuite) TestBuiltin(c *C) {
 		{"", "a", 0},
 	}
 	patternMatching(c, tk, "like", testCases)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10), c varchar(10))")
+	tk.MustExec(`insert t values (1, 'abc', 'a%'), (2, 'a%c', 'a\\%%'), (3, 'a_c', 'a|_c'), (4, 'xyz', '%y_'), (5, null, 'a')`)
+	result = tk.MustQuery("select a from t where b like c")
+	result.Check(testkit.Rows("1", "2", "4"))
+	result = tk.MustQuery("select a from t where b like c escape '|'")
+	result.Check(testkit.Rows("1", "3", "4"))
+	result = tk.MustQuery(`select a from t where b like 'a\\%%'`)
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a from t where b like 'a|_%' escape '|'")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select a from t where b not like 'a%'")
+	result.Check(testkit.Rows("4"))
+	result = tk.MustQuery("select '你好' like '_好', 'ABC' like 'abc', null like 'a', 'a' not like null")
+	result.Check(testkit.Rows("1 1 <nil> <nil>"))
 	// for regexp
 	testCases = []testCase{
 		{"^$", "a", 0},
@@ -1156,6 +1651,33 @@ func (s *testSuite) TestBuiltin(c *C) {
 		{".*", "abcd", 1},
 	}
 	patternMatching(c, tk, "regexp", testCases)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10), c varbinary(10))")
+	tk.MustExec("insert t values (1, 'ABC', 'ABC'), (2, 'xyz', 'xyz'), (3, null, null)")
+	result = tk.MustQuery("select a from t where b regexp '^a'")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select a from t where b not regexp 'b'")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a from t where b rlike 'Y'")
+	result.Check(testkit.Rows("2"))
+	// Binary strings are matched case-sensitively.
+	result = tk.MustQuery("select a from t where c regexp '^a'")
+	result.Check(nil)
+	result = tk.MustQuery("select a from t where c regexp '^A'")
+	result.Check(testkit.Rows("1"))
+	rs, err = tk.Exec("select a from t where b regexp '('")
+	c.Assert(err, IsNil)
+	_, err = tidb.GetRows(rs)
+	c.Assert(err, NotNil)
+
+	// for math functions
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b double, c decimal(10, 2))")
+	tk.MustExec("insert t values (-7, -2.5, 2.45), (null, null, null)")
+	result = tk.MustQuery("select abs(a), abs(b), ceil(b), floor(b), round(b), round(c, 1), mod(a, 3), mod(7, a) from t")
+	result.Check(testkit.Rows("7 2.5 -2 -3 -3 2.5 -1 0", "<nil> <nil> <nil> <nil> <nil> <nil> <nil> <nil>"))
+	result = tk.MustQuery("select round(2.5), round(2.45, 1), round(-2.5), mod(-7, 3), mod(7, -3), mod(7, 0)")
+	result.Check(testkit.Rows("3 2.5 -3 -1 1 <nil>"))
 }
 
 func (s *testSuite) TestToPBExpr(c *C) {
@@ -1212,6 +1734,43 @@ func (s *testSuite) TestToPBExpr(c *C) {
 	result.Check(testkit.Rows("1", "2"))
 }
 
+func (s *testSuite) TestNullEQ(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), index idx_b (b), index idx_c (c))")
+	tk.MustExec("insert t values (1, 1, 'a'), (2, null, null), (3, 3, 'c'), (4, null, 'd')")
+	result := tk.MustQuery("select null <=> null, null <=> 1, 1 <=> null, 1 <=> 1, 1 <=> '1', 'a' <=> 'b', (1, null) <=> (1, null)")
+	result.Check(testkit.Rows("1 0 0 1 1 0 1"))
+	result = tk.MustQuery("select a from t where b <=> null")
+	result.Check(testkit.Rows("2", "4"))
+	result = tk.MustQuery("select a from t where b <=> 3")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select a from t where b <=> '1.0'")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select a from t where b <=> 1 or b <=> null")
+	result.Check(testkit.Rows("2", "4", "1"))
+	result = tk.MustQuery("select a from t where not b <=> null")
+	result.Check(testkit.Rows("1", "3"))
+	result = tk.MustQuery("select a from t where not b <=> 1")
+	result.Check(testkit.Rows("2", "3", "4"))
+	result = tk.MustQuery("select a from t where c <=> null")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a, b <=> c from t")
+	result.Check(testkit.Rows("1 0", "2 1", "3 0", "4 0"))
+
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("insert t1 values (1, 1), (2, null)")
+	result = tk.MustQuery("select t1.a, t.a from t1 join t on t1.b <=> t.b order by t1.a, t.a")
+	result.Check(testkit.Rows("1 1", "2 2", "2 4"))
+	result = tk.MustQuery("select t1.a, t.a from t1 join t on t1.b = t.b order by t1.a, t.a")
+	result.Check(testkit.Rows("1 1"))
+}
+
 func (s *testSuite) TestDatumXAPI(c *C) {
 	defer func() {
 		s.cleanEnv(c)
@@ -1288,6 +1847,55 @@ func (s *testSuite) TestSQLMode(c *C) {
 	c.Check(err, NotNil)
 	// Restore original global strict mode.
 	tk.MustExec("set @@global.sql_mode = 'STRICT_TRANS_TABLES'")
+
+	_, err = tk.Exec("set sql_mode = 'STRICT_TRANS_TABLES,NO_SUCH_MODE'")
+	c.Assert(terror.ErrorEqual(err, variable.ErrWrongValueForVar), IsTrue)
+	_, err = tk.Exec("set @@global.sql_mode = 'NO_SUCH_MODE'")
+	c.Assert(terror.ErrorEqual(err, variable.ErrWrongValueForVar), IsTrue)
+	tk.MustQuery("select @@sql_mode").Check(testkit.Rows("STRICT_TRANS_TABLES"))
+
+	// Out of range values are clipped with a warning in non-strict mode.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a tinyint, d date, e int)")
+	tk.MustExec("set sql_mode = ''")
+	tk.MustExec("insert t values (1000, '2017-01-01', 1)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	tk.MustQuery("select a from t").Check(testkit.Rows("127"))
+	tk.MustExec("insert t values (1, '2017-01-01', 1), (300, '2017-01-01', 1)")
+	warnings := tk.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(warnings[0].Error(), Equals, "[table:1264]Out of range value for column 'a' at row 2")
+	tk.MustExec("update t set a = a + 200 where a = 1")
+	warnings = tk.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(warnings[0].Error(), Equals, "[table:1264]Out of range value for column 'a' at row 1")
+	tk.MustQuery("select a from t").Check(testkit.Rows("127", "127", "127"))
+
+	// Zero dates are errors with NO_ZERO_DATE in strict mode, and warnings in non-strict mode.
+	tk.MustExec("delete from t")
+	tk.MustExec("insert t values (1, '0000-00-00', 1)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	tk.MustExec("set sql_mode = 'no_zero_date'")
+	tk.MustExec("insert t values (2, '0000-00-00', 1)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	tk.MustExec("set sql_mode = 'strict_trans_tables,no_zero_date'")
+	_, err = tk.Exec("insert t values (3, '0000-00-00', 1)")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select a, d from t").Check(testkit.Rows("1 0000-00-00", "2 0000-00-00"))
+
+	// Division by zero is an error with ERROR_FOR_DIVISION_BY_ZERO in strict mode,
+	// but it's always a warning in select statements.
+	tk.MustExec("insert t values (3, '2017-01-01', 1 / 0)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	tk.MustExec("set sql_mode = 'traditional'")
+	_, err = tk.Exec("insert t values (4, '2017-01-01', 1 / 0)")
+	c.Assert(terror.ErrorEqual(err, types.ErrDivByZero), IsTrue)
+	_, err = tk.Exec("update t set e = 1 div 0")
+	c.Assert(terror.ErrorEqual(err, types.ErrDivByZero), IsTrue)
+	tk.MustQuery("select 1 / 0, 1 % 0").Check(testkit.Rows("<nil> <nil>"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 2)
+	tk.MustQuery("select a, e from t where a = 3").Check(testkit.Rows("3 <nil>"))
+	tk.MustExec("set sql_mode = 'STRICT_TRANS_TABLES'")
 }
 
 func (s *testSuite) TestSubquery(c *C) {
@@ -1361,6 +1969,115 @@ func (s *testSuite) TestSubquery(c *C) {
 	tk.MustExec("insert into t values(1, 1), (2, 2), (3, 3)")
 	result = tk.MustQuery("select * from t where v=(select min(t1.v) from t t1, t t2, t t3 where t1.id=t2.id and t2.id=t3.id and t1.id=t.id)")
 	result.Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	// A correlated scalar subquery is evaluated for every outer row.
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(k int, x int)")
+	tk.MustExec("create table t2(k int, y int)")
+	tk.MustExec("insert t1 values (1, 5), (2, 3), (3, 1)")
+	tk.MustExec("insert t2 values (1, 5), (1, 2), (2, 4), (3, null)")
+	result = tk.MustQuery("select * from t1 where x = (select max(y) from t2 where t2.k = t1.k)")
+	result.Check(testkit.Rows("1 5"))
+	result = tk.MustQuery("select k, (select y from t2 where t2.k = t1.k and y > 3) from t1")
+	result.Check(testkit.Rows("1 5", "2 4", "3 <nil>"))
+	for _, sql := range []string{
+		"select * from t1 where x = (select y from t2 where t2.k = t1.k)",
+		"select (select y from t2)",
+	} {
+		rs, err := tk.Exec(sql)
+		if err == nil {
+			_, err = tidb.GetRows(rs)
+		}
+		c.Assert(executor.ErrSubqueryNo1Row.Equal(err), IsTrue, Commentf("for %s", sql))
+	}
+}
+
+func (s *testSuite) TestDerivedTable(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (c int, d int)")
+	tk.MustExec("insert t values (1, 1), (2, 2), (3, 4), (3, 5)")
+	result := tk.MustQuery("select * from (select c, d from t where d > 1) as k where k.c < 3")
+	result.Check(testkit.Rows("2 2"))
+	result = tk.MustQuery("select k.s, c from (select c, sum(d) as s from t group by c) k where s > 2 order by c")
+	result.Check(testkit.Rows("9 3"))
+	result = tk.MustQuery("select `c + 1`, e from (select c + 1, d as e from t) k where e = 4")
+	result.Check(testkit.Rows("4 4"))
+	result = tk.MustQuery("select count(*) from (select distinct c from t) k")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select k.c, t.d from (select c from t where d = 1) k join t on k.c + 1 = t.c")
+	result.Check(testkit.Rows("1 2"))
+	result = tk.MustQuery("select * from (select * from (select c from t where d > 4) k1) k2")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select * from (select c from t where d = 1 union select 5) k order by c")
+	result.Check(testkit.Rows("1", "5"))
+	// The derived tables with the same alias in different scopes don't conflict.
+	result = tk.MustQuery("select * from (select c from t) k where k.c in (select c from (select c from t where d = 2) k)")
+	result.Check(testkit.Rows("2"))
+
+	for _, sql := range []string{
+		"select * from (select c from t)",
+		"select * from (select c from t union select d from t)",
+		"select * from t join (select c from t) on 1",
+	} {
+		_, err := tk.Exec(sql)
+		c.Assert(plan.ErrDerivedMustHaveAlias.Equal(err), IsTrue, Commentf("for %s, err %v", sql, err))
+	}
+	for _, sql := range []string{
+		"select * from (select c, d as c from t) k",
+		"select * from (select c, c from t) k",
+		"select * from (select * from t join t as t1) k",
+	} {
+		_, err := tk.Exec(sql)
+		c.Assert(infoschema.ErrColumnExists.Equal(err), IsTrue, Commentf("for %s, err %v", sql, err))
+	}
+}
+
+func (s *testSuite) TestWindowFunctions(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c int, d int)")
+	tk.MustExec("insert t values (1, 1, 10), (2, 1, 20), (3, 1, 20), (4, 2, 30), (5, 2, 10), (6, null, 5), (7, null, 5)")
+
+	result := tk.MustQuery("select id, row_number() over (partition by c order by d, id) from t order by id")
+	result.Check(testkit.Rows("1 1", "2 2", "3 3", "4 2", "5 1", "6 1", "7 2"))
+	result = tk.MustQuery("select id, rank() over (partition by c order by d) r, dense_rank() over (partition by c order by d) dr from t order by id")
+	result.Check(testkit.Rows("1 1 1", "2 2 2", "3 2 2", "4 2 2", "5 1 1", "6 1 1", "7 1 1"))
+	result = tk.MustQuery("select id, rank() over (order by d desc) from t order by id")
+	result.Check(testkit.Rows("1 4", "2 2", "3 2", "4 1", "5 4", "6 6", "7 6"))
+	// Without ORDER BY, all the rows of a partition are peers.
+	result = tk.MustQuery("select id, rank() over (partition by c), row_number() over () > 0 from t order by id")
+	result.Check(testkit.Rows("1 1 1", "2 1 1", "3 1 1", "4 1 1", "5 1 1", "6 1 1", "7 1 1"))
+	// The rows are not collapsed, and the window function can be used in expressions and the ORDER BY clause.
+	result = tk.MustQuery("select id, 10 * row_number() over (partition by c order by id desc) from t where d > 5 order by row_number() over (partition by c order by id desc), id")
+	result.Check(testkit.Rows("3 10", "5 10", "2 20", "4 20", "1 30"))
+	result = tk.MustQuery("select id, row_number() over (order by d, id) rn from t order by rn desc limit 2")
+	result.Check(testkit.Rows("4 7", "3 6"))
+	result = tk.MustQuery("select c, sum(d), rank() over (order by sum(d) desc) from t group by c order by c")
+	result.Check(testkit.Rows("<nil> 10 3", "1 50 1", "2 40 2"))
+	result = tk.MustQuery("select * from (select id, row_number() over (partition by c order by d desc, id) rn from t) k where rn = 1 order by id")
+	result.Check(testkit.Rows("2 1", "4 1", "6 1"))
+
+	for _, sql := range []string{
+		"select id from t where row_number() over () > 1",
+		"select c from t group by row_number() over ()",
+		"select sum(row_number() over ()) from t",
+		"select id from t order by sum(row_number() over ())",
+		"select row_number() over (order by rank() over ()) from t",
+	} {
+		_, err := tk.Exec(sql)
+		c.Assert(plan.ErrWindowInvalidWindowFuncUse.Equal(err), IsTrue, Commentf("for %s, err %v", sql, err))
+	}
 }
 
 func (s *testSuite) TestNewTableDual(c *C) {
@@ -1386,13 +2103,25 @@ func (s *testSuite) TestTableScan(c *C) {
 	c.Assert(len(result.Rows()), GreaterEqual, 4)
 	tk.MustExec("use test")
 	tk.MustExec("create database mytest")
-	rowStr1 := fmt.Sprintf("%s %s %s %s %v", "def", "mysql", "utf8", "utf8_general_ci", nil)
-	rowStr2 := fmt.Sprintf("%s %s %s %s %v", "def", "mytest", "utf8", "utf8_general_ci", nil)
+	tk.MustExec("create database mytest_latin1 character set latin1")
+	rowStr1 := fmt.Sprintf("%s %s %s %s %v", "def", "mysql", "utf8", "utf8_unicode_ci", nil)
+	rowStr2 := fmt.Sprintf("%s %s %s %s %v", "def", "mytest", "utf8", "utf8_unicode_ci", nil)
+	rowStr3 := fmt.Sprintf("%s %s %s %s %v", "def", "mytest_latin1", "latin1", "latin1_swedish_ci", nil)
 	tk.MustExec("use information_schema")
 	result = tk.MustQuery("select * from schemata where schema_name = 'mysql'")
 	result.Check(testkit.Rows(rowStr1))
 	result = tk.MustQuery("select * from schemata where schema_name like 'my%'")
-	result.Check(testkit.Rows(rowStr1, rowStr2))
+	result.Check(testkit.Rows(rowStr1, rowStr2, rowStr3))
+
+	tk.MustExec("use mytest_latin1")
+	tk.MustExec("create table t (a int primary key, b varchar(10) not null) comment 'test table'")
+	tk.MustExec("create table t1 (a int) collate utf8mb4_bin")
+	tk.MustQuery("select table_name, table_type, table_collation, table_comment from information_schema.tables where table_schema = 'mytest_latin1' order by table_name").Check(testkit.Rows(
+		"t BASE TABLE latin1_swedish_ci test table", "t1 BASE TABLE utf8mb4_bin "))
+	tk.MustQuery("select table_type from information_schema.tables where table_schema = 'information_schema' and table_name = 'tables'").Check(testkit.Rows("SYSTEM VIEW"))
+	tk.MustQuery("select column_name, ordinal_position, is_nullable, data_type, column_key from information_schema.columns where table_schema = 'mytest_latin1' and table_name = 't' order by ordinal_position desc").Check(testkit.Rows(
+		"b 2 NO varchar ", "a 1 NO int PRI"))
+	tk.MustQuery("select count(*) from information_schema.columns c join information_schema.tables t on c.table_schema = t.table_schema and c.table_name = t.table_name where t.table_schema = 'mytest_latin1'").Check(testkit.Rows("3"))
 }
 
 func (s *testSuite) TestAdapterStatement(c *C) {
@@ -1472,6 +2201,71 @@ func (s *testSuite) TestColumnName(c *C) {
 	c.Check(len(fields), Equals, 2)
 	c.Check(fields[0].Column.Name.L, Equals, "d")
 	c.Check(fields[1].Column.Name.L, Equals, "c")
+	rs, err = tk.Exec("select c, tt.d, c as x, d + 1 from t as tt")
+	c.Check(err, IsNil)
+	fields, err = rs.Fields()
+	c.Check(err, IsNil)
+	c.Check(len(fields), Equals, 4)
+	c.Check(fields[0].ColumnAsName.L, Equals, "c")
+	c.Check(fields[0].TableAsName.L, Equals, "tt")
+	c.Check(fields[1].ColumnAsName.L, Equals, "d")
+	c.Check(fields[1].TableAsName.L, Equals, "tt")
+	c.Check(fields[2].ColumnAsName.L, Equals, "x")
+	c.Check(fields[3].ColumnAsName.L, Equals, "d + 1")
+	c.Check(fields[3].Column.Tp, Equals, mysql.TypeLonglong)
+	c.Check(rs.Close(), IsNil)
+	rs, err = tk.Exec("select c from t")
+	c.Check(err, IsNil)
+	fields, err = rs.Fields()
+	c.Check(err, IsNil)
+	c.Check(fields[0].TableAsName.L, Equals, "t")
+	c.Check(fields[0].Column.Tp, Equals, mysql.TypeLong)
+	c.Check(rs.Close(), IsNil)
+	// The record set can be closed without reading any row.
+	rs, err = tk.Exec("select c from t union select d from t")
+	c.Check(err, IsNil)
+	fields, err = rs.Fields()
+	c.Check(err, IsNil)
+	c.Check(fields[0].ColumnAsName.L, Equals, "c")
+	c.Check(rs.Close(), IsNil)
+}
+
+func (s *testSuite) TestCloseRecordSetEarly(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, index b (b))")
+	tk.MustExec("begin")
+	for i := 0; i < 2000; i++ {
+		tk.MustExec(fmt.Sprintf("insert t values (%d, %d)", i, i))
+	}
+	tk.MustExec("commit")
+	// The rows are fetched on demand, so the client can stop reading at any time and
+	// the resources held by the executors are released when the record set is closed.
+	sqls := []string{
+		"select * from t",
+		"select * from t where b > 10",
+		"select * from t use index (b) where b > 10",
+		"select * from t limit 1000",
+		"select * from t t1 join t t2 on t1.a = t2.b",
+		"select a from t union all select b from t",
+	}
+	for _, sql := range sqls {
+		rs, err := tk.Exec(sql)
+		c.Assert(err, IsNil, Commentf("sql: %s", sql))
+		for i := 0; i < 3; i++ {
+			row, err := rs.Next()
+			c.Assert(err, IsNil, Commentf("sql: %s", sql))
+			c.Assert(row, NotNil, Commentf("sql: %s", sql))
+		}
+		c.Assert(rs.Close(), IsNil, Commentf("sql: %s", sql))
+	}
+	tk.MustQuery("select a from t limit 2, 3").Check(testkit.Rows("2", "3", "4"))
+	tk.MustQuery("select a from t where b >= 1990 limit 3").Check(testkit.Rows("1990", "1991", "1992"))
 }
 
 func (s *testSuite) TestSelectVar(c *C) {
@@ -1485,11 +2279,43 @@ func (s *testSuite) TestSelectVar(c *C) {
 	tk.MustExec("create table t (d int)")
 	tk.MustExec("insert into t values(1), (2), (1)")
 	result := tk.MustQuery("select @a, @a := d+1 from t")
-	result.Check(testkit.Rows("<nil> 2", "<nil> 3", "<nil> 2"))
+	result.Check(testkit.Rows("<nil> 2", "2 3", "3 2"))
 	result = tk.MustQuery("select @a, @a := d+1 from t")
 	result.Check(testkit.Rows("2 2", "2 3", "3 2"))
 }
 
+func (s *testSuite) TestIndexHints(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, c int, index idx_b (b), index idx_c (c))")
+	tk.MustExec("insert into t values (1, 3, 2), (2, 2, 3), (3, 1, 1)")
+	tk.MustQuery("select a from t use index (idx_b) where b > 1").Check(testkit.Rows("2", "1"))
+	tk.MustQuery("select a from t force index (idx_c) where c > 1").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t ignore index (idx_b) where b > 1").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t use index (primary, idx_b) where a > 1").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select a from t x use key (idx_b) where x.b < 3").Check(testkit.Rows("3", "2"))
+	tk.MustQuery("select a from t use index () where b > 1").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select /*+ USE_INDEX(t, idx_b) IGNORE_INDEX(t, idx_c) */ a from t where b > 1").Check(testkit.Rows("2", "1"))
+
+	_, err := tk.Exec("select a from t use index (idx_d)")
+	c.Assert(terror.ErrorEqual(err, plan.ErrKeyDoesNotExist), IsTrue)
+	_, err = tk.Exec("select a from t ignore index (idx_b, idx_d)")
+	c.Assert(terror.ErrorEqual(err, plan.ErrKeyDoesNotExist), IsTrue)
+	_, err = tk.Exec("select a from t force index for order by (idx_d) order by b")
+	c.Assert(terror.ErrorEqual(err, plan.ErrKeyDoesNotExist), IsTrue)
+	_, err = tk.Exec("select /*+ USE_INDEX(t, idx_d) */ a from t where b > 1")
+	c.Assert(terror.ErrorEqual(err, plan.ErrKeyDoesNotExist), IsTrue)
+	_, err = tk.Exec("select /*+ IGNORE_INDEX(x, idx_b, idx_d) */ a from t x")
+	c.Assert(terror.ErrorEqual(err, plan.ErrKeyDoesNotExist), IsTrue)
+	// The hints of the other tables are not checked.
+	tk.MustQuery("select /*+ USE_INDEX(t1, idx_d) */ a from t where b > 2").Check(testkit.Rows("1"))
+}
+
 func (s *testSuite) TestHistoryRead(c *C) {
 	defer func() {
 		s.cleanEnv(c)
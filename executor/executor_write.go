@@ -15,6 +15,8 @@ package executor
 
 import (
 	"bytes"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/juju/errors"
@@ -23,7 +25,9 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/types"
@@ -37,7 +41,69 @@ var (
 	_ Executor = &LoadData{}
 )
 
-func updateRecord(ctx context.Context, h int64, oldData, newData []types.Datum, assignFlag []bool, t table.Table, offset int, onDuplicateUpdate bool) error {
+// tableChecker checks the rows written by a statement against the check constraints of the tables,
+// the check expressions of a table are built once for the statement.
+type tableChecker struct {
+	checks map[int64][]expression.Expression
+}
+
+// check returns an error naming the first check constraint the row violates, a check passes
+// if its expression evaluates to true or NULL.
+func (c *tableChecker) check(ctx context.Context, t table.Table, row []types.Datum) error {
+	tblInfo := t.Meta()
+	if len(tblInfo.Checks) == 0 {
+		return nil
+	}
+	exprs, ok := c.checks[tblInfo.ID]
+	if !ok {
+		var err error
+		exprs, err = plan.BuildTableChecks(ctx, tblInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if c.checks == nil {
+			c.checks = make(map[int64][]expression.Expression)
+		}
+		c.checks[tblInfo.ID] = exprs
+	}
+	if len(row) > len(t.Cols()) {
+		// The row contains the write only columns, only the public columns are used by the checks.
+		publicRow := make([]types.Datum, 0, len(t.Cols()))
+		for i, col := range t.WritableCols() {
+			if col.State == model.StatePublic {
+				publicRow = append(publicRow, row[i])
+			}
+		}
+		row = publicRow
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	for i, expr := range exprs {
+		d, err := expr.Eval(row, ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if d.IsNull() {
+			continue
+		}
+		b, err := d.ToBool(sc)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if b == 0 {
+			return ErrCheckConstraintViolated.GenByArgs(tblInfo.Checks[i].Name.O)
+		}
+	}
+	return nil
+}
+
+// isIgnorableError returns whether err is turned into a warning by the IGNORE modifier of INSERT and UPDATE,
+// the row causing it is skipped.
+func isIgnorableError(err error) bool {
+	return terror.ErrorEqual(err, kv.ErrKeyExists) || terror.ErrorEqual(err, ErrCheckConstraintViolated) ||
+		terror.ErrorEqual(err, ErrNoReferencedRow)
+}
+
+func updateRecord(ctx context.Context, h int64, oldData, newData []types.Datum, assignFlag []bool, t table.Table, offset int, onDuplicateUpdate bool, checker *tableChecker, fkChecker *foreignKeyChecker) error {
 	cols := t.Cols()
 	touched := make(map[int]bool, len(cols))
 	assignExists := false
@@ -113,8 +179,31 @@ func updateRecord(ctx context.Context, h int64, oldData, newData []types.Datum,
 		return nil
 	}
 
+	// The columns with ON UPDATE CURRENT_TIMESTAMP are refreshed unless they are assigned explicitly.
+	for i, col := range cols {
+		if mysql.HasOnUpdateNowFlag(col.Flag) && !touched[i] {
+			v, err := expression.GetTimeValue(ctx, expression.CurrentTimestamp, col.Tp, col.Decimal)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			newData[i] = v
+			touched[i] = true
+		}
+	}
+
+	if err := checker.check(ctx, t, newData); err != nil {
+		return errors.Trace(err)
+	}
+	if err := fkChecker.checkUpdate(ctx, t, oldData, newData); err != nil {
+		return errors.Trace(err)
+	}
+
 	var err error
 	if !newHandle.IsNull() {
+		// The row is moved to the new handle, the duplicate keys are checked before the old row is removed.
+		if err = checkDuplicateKeys(ctx, t, h, newHandle.GetInt64(), newData); err != nil {
+			return errors.Trace(err)
+		}
 		err = t.RemoveRecord(ctx, h, oldData)
 		if err != nil {
 			return errors.Trace(err)
@@ -132,11 +221,55 @@ func updateRecord(ctx context.Context, h int64, oldData, newData []types.Datum,
 	dirtyDB.deleteRow(tid, h)
 	dirtyDB.addRow(tid, h, newData)
 
-	// Record affected rows.
-	if !onDuplicateUpdate {
-		sc.AddAffectedRows(1)
-	} else {
-		sc.AddAffectedRows(2)
+	// Record affected rows, the row moved to a new handle is counted once by AddRecord already.
+	affectedRows := uint64(1)
+	if onDuplicateUpdate {
+		affectedRows = 2
+	}
+	if !newHandle.IsNull() {
+		affectedRows--
+	}
+	sc.AddAffectedRows(affectedRows)
+	return errors.Trace(fkChecker.onUpdate(ctx, t, oldData, newData, 0))
+}
+
+// checkDuplicateKeys checks whether the new row of the row h in t, which is stored at newHandle,
+// duplicates the primary key or any unique key of the other rows.
+func checkDuplicateKeys(ctx context.Context, t table.Table, h, newHandle int64, newData []types.Datum) error {
+	if newHandle != h {
+		_, err := t.Row(ctx, newHandle)
+		if err == nil {
+			return kv.ErrKeyExists.FastGen("Duplicate entry '%d' for key 'PRIMARY'", newHandle)
+		}
+		if !terror.ErrorEqual(err, kv.ErrNotExist) {
+			return errors.Trace(err)
+		}
+	}
+	for _, idx := range t.Indices() {
+		idxInfo := idx.Meta()
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic {
+			continue
+		}
+		vals, err := idx.FetchValues(newData)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// The entries of the row h itself are not duplicates, they are removed with the old row.
+		_, _, err = idx.Exist(ctx.Txn(), vals, h)
+		if terror.ErrorEqual(err, kv.ErrKeyExists) {
+			strVals := make([]string, 0, len(vals))
+			for _, v := range vals {
+				str, err1 := v.ToString()
+				if err1 != nil {
+					return errors.Trace(err1)
+				}
+				strVals = append(strVals, str)
+			}
+			return kv.ErrKeyExists.FastGen("Duplicate entry '%s' for key '%s'", strings.Join(strVals, "-"), idxInfo.Name)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	return nil
 }
@@ -150,7 +283,8 @@ type DeleteExec struct {
 	Tables       []*ast.TableName
 	IsMultiTable bool
 
-	finished bool
+	finished  bool
+	fkChecker foreignKeyChecker
 }
 
 // Schema implements the Executor Schema interface.
@@ -204,6 +338,10 @@ func (e *DeleteExec) Next() (*Row, error) {
 		for handle := range handleMap {
 			data, err := t.Row(e.ctx, handle)
 			if err != nil {
+				if terror.ErrorEqual(err, kv.ErrNotExist) {
+					// The row is deleted by a foreign key cascading from the rows deleted before.
+					continue
+				}
 				return nil, errors.Trace(err)
 			}
 			err = e.removeRow(e.ctx, t, handle, data)
@@ -244,7 +382,7 @@ func (e *DeleteExec) removeRow(ctx context.Context, t table.Table, h int64, data
 	}
 	getDirtyDB(ctx).deleteRow(t.Meta().ID, h)
 	ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
-	return nil
+	return errors.Trace(e.fkChecker.onDelete(ctx, t, data, 0))
 }
 
 // Close implements the Executor Close interface.
@@ -262,16 +400,32 @@ func NewLoadDataInfo(row []types.Datum, ctx context.Context, tbl table.Table) *L
 	}
 }
 
+const (
+	// loadDataBatchSize is the number of rows committed in one transaction by load data,
+	// so loading a big file doesn't make the transaction too large.
+	loadDataBatchSize = 20000
+	// loadDataReadSize is the size of the data read from the file each time by load data without local.
+	loadDataReadSize = 64 * 1024
+)
+
 // LoadDataInfo saves the information of loading data operation.
 type LoadDataInfo struct {
 	row       []types.Datum
 	insertVal *InsertValues
-
-	Path       string
-	Table      table.Table
+	// batchSize is the number of rows inserted in a transaction, the transaction is committed and
+	// a new one is started when it's reached. Zero means all the rows are inserted in one transaction.
+	batchSize uint64
+	txnRows   uint64
+
+	Path  string
+	Table table.Table
+	// Columns are the columns the fields are inserted into, all the columns of the table if it's nil.
+	Columns    []*table.Column
 	FieldsInfo *ast.FieldsClause
 	LinesInfo  *ast.LinesClause
-	Ctx        context.Context
+	// IgnoreLines is the number of lines to skip at the start of the file.
+	IgnoreLines uint64
+	Ctx         context.Context
 }
 
 // getValidData returns prevData and curData that starts from starting symbol.
@@ -381,7 +535,6 @@ func (e *LoadDataInfo) InsertData(prevData, curData []byte) ([]byte, error) {
 
 	var line []byte
 	var isEOF, hasStarting bool
-	cols := make([]string, 0, len(e.row))
 	if len(prevData) > 0 && len(curData) == 0 {
 		isEOF = true
 		prevData, curData = curData, prevData
@@ -406,11 +559,16 @@ func (e *LoadDataInfo) InsertData(prevData, curData []byte) ([]byte, error) {
 			line = curData[len(e.LinesInfo.Starting):]
 			curData = nil
 		}
+		if e.IgnoreLines > 0 {
+			e.IgnoreLines--
+			continue
+		}
 
-		rawCols := bytes.Split(line, []byte(e.FieldsInfo.Terminated))
-		cols = escapeCols(rawCols)
-		e.insertData(cols)
+		e.insertData(bytes.Split(line, []byte(e.FieldsInfo.Terminated)))
 		e.insertVal.currRow++
+		if err := e.commitBatch(); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	if e.insertVal.lastInsertID != 0 {
 		e.insertVal.ctx.GetSessionVars().SetLastInsertID(e.insertVal.lastInsertID)
@@ -419,26 +577,19 @@ func (e *LoadDataInfo) InsertData(prevData, curData []byte) ([]byte, error) {
 	return curData, nil
 }
 
-func escapeCols(strs [][]byte) []string {
-	ret := make([]string, len(strs))
-	for i, v := range strs {
-		ret[i] = string(escape(v))
-	}
-	return ret
-}
+// nullField is the field value which represents NULL, it's written by select into outfile for NULL.
+var nullField = []byte{'\\', 'N'}
 
-// TODO: escape need to be improved, it should support ESCAPED BY to specify
-// the escape character and handle \N escape.
+// TODO: escape need to be improved, it should support ESCAPED BY to specify the escape character.
 // See http://dev.mysql.com/doc/refman/5.7/en/load-data.html
 func escape(str []byte) []byte {
 	pos := 0
 	for i := 0; i < len(str); i++ {
 		c := str[i]
 		if c == '\\' && i+1 < len(str) {
-			var ok bool
-			if c, ok = escapeChar(str[i+1]); ok {
-				i++
-			}
+			// For characters not in the escape sequences, the escape character is dropped.
+			c = escapeChar(str[i+1])
+			i++
 		}
 
 		str[pos] = c
@@ -447,45 +598,99 @@ func escape(str []byte) []byte {
 	return str[:pos]
 }
 
-func escapeChar(c byte) (byte, bool) {
+func escapeChar(c byte) byte {
 	switch c {
 	case '0':
-		return 0, true
+		return 0
 	case 'b':
-		return '\b', true
+		return '\b'
 	case 'n':
-		return '\n', true
+		return '\n'
 	case 'r':
-		return '\r', true
+		return '\r'
 	case 't':
-		return '\t', true
+		return '\t'
 	case 'Z':
-		return 26, true
-	case '\\':
-		return '\\', true
+		return 26
 	}
-	return c, false
+	return c
 }
 
-func (e *LoadDataInfo) insertData(cols []string) {
+func (e *LoadDataInfo) insertData(fields [][]byte) {
 	for i := 0; i < len(e.row); i++ {
-		if i >= len(cols) {
+		if i >= len(fields) {
 			e.row[i].SetString("")
 			continue
 		}
-		e.row[i].SetString(cols[i])
+		if bytes.Equal(fields[i], nullField) {
+			e.row[i].SetNull()
+			continue
+		}
+		e.row[i].SetString(string(escape(fields[i])))
+	}
+	tableCols := e.Columns
+	if tableCols == nil {
+		tableCols = e.Table.Cols()
 	}
-	row, err := e.insertVal.fillRowData(e.Table.Cols(), e.row, true)
+	row, err := e.insertVal.fillRowData(tableCols, e.row, true)
 	if err != nil {
 		log.Warnf("Load Data: insert data:%v failed:%v", e.row, errors.ErrorStack(err))
 		return
 	}
+	if err = e.insertVal.checker.check(e.insertVal.ctx, e.Table, row); err != nil {
+		log.Warnf("Load Data: insert data:%v failed:%v", row, errors.ErrorStack(err))
+		return
+	}
+	if err = e.insertVal.fkChecker.checkInsert(e.insertVal.ctx, e.Table, row); err != nil {
+		log.Warnf("Load Data: insert data:%v failed:%v", row, errors.ErrorStack(err))
+		return
+	}
 	_, err = e.Table.AddRecord(e.insertVal.ctx, row)
 	if err != nil {
 		log.Warnf("Load Data: insert data:%v failed:%v", row, errors.ErrorStack(err))
 	}
 }
 
+// commitBatch commits the current transaction and starts a new one if batchSize rows are inserted in it.
+func (e *LoadDataInfo) commitBatch() error {
+	if e.batchSize == 0 {
+		return nil
+	}
+	e.txnRows++
+	if e.txnRows < e.batchSize {
+		return nil
+	}
+	e.txnRows = 0
+	return errors.Trace(e.Ctx.NewTxn())
+}
+
+// loadFile reads the file on the TiDB server and inserts the data, it's used by load data without local.
+func (e *LoadDataInfo) loadFile() error {
+	f, err := os.Open(e.Path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	var prevData []byte
+	for {
+		// The data returned by InsertData may refer to curData, so a new buffer is used for every read.
+		curData := make([]byte, loadDataReadSize)
+		n, err := io.ReadFull(f, curData)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return errors.Trace(err)
+		}
+		if n == 0 {
+			_, err = e.InsertData(prevData, nil)
+			return errors.Trace(err)
+		}
+		prevData, err = e.InsertData(prevData, curData[:n])
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
 // LoadData represents a load data executor.
 type LoadData struct {
 	IsLocal      bool
@@ -505,14 +710,17 @@ const LoadDataVarKey loadDataVarKeyType = 0
 
 // Next implements the Executor Next interface.
 func (e *LoadData) Next() (*Row, error) {
-	// TODO: support load data without local field.
-	if !e.IsLocal {
-		return nil, errors.New("Load Data: don't support load data without local field")
-	}
 	// TODO: support lines terminated is "".
 	if len(e.loadDataInfo.LinesInfo.Terminated) == 0 {
 		return nil, errors.New("Load Data: don't support load data terminated is nil")
 	}
+	if !e.IsLocal {
+		// The file is on the TiDB server, so it's loaded here instead of by the client connection.
+		if err := checkFileAccess(e.loadDataInfo.Ctx, e.loadDataInfo.Path); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, errors.Trace(e.loadDataInfo.loadFile())
+	}
 
 	ctx := e.loadDataInfo.insertVal.ctx
 	val := ctx.Value(LoadDataVarKey)
@@ -548,8 +756,10 @@ type InsertValues struct {
 	Table     table.Table
 	Columns   []*ast.ColumnName
 	Lists     [][]expression.Expression
-	Setlist   []*expression.Assignment
 	IsPrepare bool
+
+	checker   tableChecker
+	fkChecker foreignKeyChecker
 }
 
 // InsertExec represents an insert executor.
@@ -598,6 +808,18 @@ func (e *InsertExec) Next() (*Row, error) {
 	}
 
 	for _, row := range rows {
+		err = e.checker.check(e.ctx, e.Table, row)
+		if err == nil {
+			err = e.fkChecker.checkInsert(e.ctx, e.Table, row)
+		}
+		if err != nil {
+			if e.Ignore && isIgnorableError(err) {
+				// With IGNORE, the row violating a constraint is discarded with a warning.
+				e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+				continue
+			}
+			return nil, errors.Trace(err)
+		}
 		if len(e.OnDuplicate) == 0 && !e.Ignore {
 			txn.SetOption(kv.PresumeKeyNotExists, nil)
 		}
@@ -609,16 +831,18 @@ func (e *InsertExec) Next() (*Row, error) {
 		}
 
 		if terror.ErrorEqual(err, kv.ErrKeyExists) {
+			if len(e.OnDuplicate) > 0 {
+				err = e.onDuplicateUpdate(row, h, toUpdateColumns)
+			}
 			// If you use the IGNORE keyword, duplicate-key error that occurs while executing the INSERT statement are ignored.
 			// For example, without IGNORE, a row that duplicates an existing UNIQUE index or PRIMARY KEY value in
-			// the table causes a duplicate-key error and the statement is aborted. With IGNORE, the row is discarded and no error occurs.
-			if e.Ignore {
+			// the table causes a duplicate-key error and the statement is aborted. With IGNORE, the row is discarded and
+			// the error is reported as a warning.
+			if err != nil && e.Ignore && isIgnorableError(err) {
+				e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 				continue
 			}
-			if len(e.OnDuplicate) > 0 {
-				if err = e.onDuplicateUpdate(row, h, toUpdateColumns); err != nil {
-					return nil, errors.Trace(err)
-				}
+			if err == nil {
 				continue
 			}
 		}
@@ -650,36 +874,19 @@ func (e *InsertValues) getColumns(tableCols []*table.Column) ([]*table.Column, e
 	var cols []*table.Column
 	var err error
 
-	if len(e.Setlist) > 0 {
-		// Process `set` type column.
-		columns := make([]string, 0, len(e.Setlist))
-		for _, v := range e.Setlist {
-			columns = append(columns, v.Col.ColName.O)
-		}
-
-		cols, err = table.FindCols(tableCols, columns)
-		if err != nil {
-			return nil, errors.Errorf("INSERT INTO %s: %s", e.Table.Meta().Name.O, err)
-		}
-
-		if len(cols) == 0 {
-			return nil, errors.Errorf("INSERT INTO %s: empty column", e.Table.Meta().Name.O)
-		}
-	} else {
-		// Process `name` type column.
-		columns := make([]string, 0, len(e.Columns))
-		for _, v := range e.Columns {
-			columns = append(columns, v.Name.O)
-		}
-		cols, err = table.FindCols(tableCols, columns)
-		if err != nil {
-			return nil, errors.Errorf("INSERT INTO %s: %s", e.Table.Meta().Name.O, err)
-		}
+	// `insert ... set` is translated to the column list form in the plan builder.
+	columns := make([]string, 0, len(e.Columns))
+	for _, v := range e.Columns {
+		columns = append(columns, v.Name.O)
+	}
+	cols, err = table.FindCols(tableCols, columns)
+	if err != nil {
+		return nil, errors.Errorf("INSERT INTO %s: %s", e.Table.Meta().Name.O, err)
+	}
 
-		// If cols are empty, use all columns instead.
-		if len(cols) == 0 {
-			cols = tableCols
-		}
+	// If cols are empty, use all columns instead.
+	if len(cols) == 0 {
+		cols = tableCols
 	}
 
 	// Check column whether is specified only once.
@@ -691,20 +898,6 @@ func (e *InsertValues) getColumns(tableCols []*table.Column) ([]*table.Column, e
 	return cols, nil
 }
 
-func (e *InsertValues) fillValueList() error {
-	if len(e.Setlist) > 0 {
-		if len(e.Lists) > 0 {
-			return errors.Errorf("INSERT INTO %s: set type should not use values", e.Table)
-		}
-		l := make([]expression.Expression, 0, len(e.Setlist))
-		for _, v := range e.Setlist {
-			l = append(l, v.Expr)
-		}
-		e.Lists = append(e.Lists, l)
-	}
-	return nil
-}
-
 func (e *InsertValues) checkValueCount(insertValueCount, valueCount, num int, cols []*table.Column) error {
 	// TODO: This check should be done in plan builder.
 	if insertValueCount != valueCount {
@@ -713,23 +906,19 @@ func (e *InsertValues) checkValueCount(insertValueCount, valueCount, num int, co
 		// "insert into t values (1), ()" is not valid.
 		// "insert into t values (1,2), (1)" is not valid.
 		// So the value count must be same for all insert list.
-		return errors.Errorf("Column count doesn't match value count at row %d", num+1)
+		return ErrWrongValueCount.GenByArgs(num + 1)
 	}
 	if valueCount == 0 && len(e.Columns) > 0 {
 		// "insert into t (c1) values ()" is not valid.
-		return errors.Errorf("INSERT INTO %s: expected %d value(s), have %d", e.Table.Meta().Name.O, len(e.Columns), 0)
+		return ErrWrongValueCount.GenByArgs(num + 1)
 	} else if valueCount > 0 && valueCount != len(cols) {
-		return errors.Errorf("INSERT INTO %s: expected %d value(s), have %d", e.Table.Meta().Name.O, len(cols), valueCount)
+		// "insert into t (c1) values (1, 2)" is not valid.
+		return ErrWrongValueCount.GenByArgs(num + 1)
 	}
 	return nil
 }
 
 func (e *InsertValues) getRows(cols []*table.Column) (rows [][]types.Datum, err error) {
-	// process `insert|replace ... set x=y...`
-	if err = e.fillValueList(); err != nil {
-		return nil, errors.Trace(err)
-	}
-
 	rows = make([][]types.Datum, len(e.Lists))
 	length := len(e.Lists[0])
 	for i, list := range e.Lists {
@@ -745,6 +934,9 @@ func (e *InsertValues) getRows(cols []*table.Column) (rows [][]types.Datum, err
 	return
 }
 
+// getRow evaluates the value expressions of one row, every row is evaluated on its own,
+// so non-constant expressions like now() or rand() are computed for each row.
+// The evaluated values are converted to the types of the target columns in fillRowData.
 func (e *InsertValues) getRow(cols []*table.Column, list []expression.Expression) ([]types.Datum, error) {
 	vals := make([]types.Datum, len(list))
 	for i, expr := range list {
@@ -760,7 +952,7 @@ func (e *InsertValues) getRow(cols []*table.Column, list []expression.Expression
 func (e *InsertValues) getRowsSelect(cols []*table.Column) ([][]types.Datum, error) {
 	// process `insert|replace into ... select ... from ...`
 	if e.SelectExec.Schema().Len() != len(cols) {
-		return nil, errors.Errorf("Column count %d doesn't match value count %d", len(cols), e.SelectExec.Schema().Len())
+		return nil, ErrWrongValueCount.GenByArgs(1)
 	}
 	var rows [][]types.Datum
 	for {
@@ -782,14 +974,13 @@ func (e *InsertValues) getRowsSelect(cols []*table.Column) ([][]types.Datum, err
 }
 
 func (e *InsertValues) fillRowData(cols []*table.Column, vals []types.Datum, ignoreErr bool) ([]types.Datum, error) {
+	e.ctx.GetSessionVars().StmtCtx.SetCurrentRow(uint64(e.currRow + 1))
 	row := make([]types.Datum, len(e.Table.Cols()))
 	marked := make(map[int]struct{}, len(vals))
 	for i, v := range vals {
 		offset := cols[i].Offset
 		row[offset] = v
-		if !ignoreErr {
-			marked[offset] = struct{}{}
-		}
+		marked[offset] = struct{}{}
 	}
 	err := e.initDefaultValues(row, marked, ignoreErr)
 	if err != nil {
@@ -798,12 +989,31 @@ func (e *InsertValues) fillRowData(cols []*table.Column, vals []types.Datum, ign
 	if err = table.CastValues(e.ctx, row, cols, ignoreErr); err != nil {
 		return nil, errors.Trace(err)
 	}
-	if err = table.CheckNotNull(e.Table.Cols(), row); err != nil {
+	if err = e.checkNotNull(row); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return row, nil
 }
 
+// checkNotNull checks the NULL values set to the NOT NULL columns. Like MySQL, a multiple-row insert
+// in non-strict mode uses the implicit default value of the column instead, with a warning.
+func (e *InsertValues) checkNotNull(row []types.Datum) error {
+	sessVars := e.ctx.GetSessionVars()
+	multiRows := len(e.Lists) > 1 || e.SelectExec != nil
+	for _, c := range e.Table.Cols() {
+		err := c.CheckNotNull(row[c.Offset])
+		if err == nil {
+			continue
+		}
+		if sessVars.StrictSQLMode || !multiRows {
+			return errors.Trace(err)
+		}
+		sessVars.StmtCtx.AppendWarning(err)
+		row[c.Offset] = table.GetZeroValue(c.ToInfo())
+	}
+	return nil
+}
+
 func filterErr(err error, ignoreErr bool) error {
 	if err == nil {
 		return nil
@@ -913,7 +1123,7 @@ func (e *InsertExec) onDuplicateUpdate(row []types.Datum, h int64, cols map[int]
 			assignFlag[i] = false
 		}
 	}
-	if err = updateRecord(e.ctx, h, data, newData, assignFlag, e.Table, 0, true); err != nil {
+	if err = updateRecord(e.ctx, h, data, newData, assignFlag, e.Table, 0, true, &e.checker, &e.fkChecker); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
@@ -1005,6 +1215,12 @@ func (e *ReplaceExec) Next() (*Row, error) {
 			break
 		}
 		row := rows[idx]
+		if err1 := e.checker.check(e.ctx, e.Table, row); err1 != nil {
+			return nil, errors.Trace(err1)
+		}
+		if err1 := e.fkChecker.checkInsert(e.ctx, e.Table, row); err1 != nil {
+			return nil, errors.Trace(err1)
+		}
 		h, err1 := e.Table.AddRecord(e.ctx, row)
 		if err1 == nil {
 			getDirtyDB(e.ctx).addRow(e.Table.Meta().ID, h, row)
@@ -1035,6 +1251,9 @@ func (e *ReplaceExec) Next() (*Row, error) {
 		}
 		getDirtyDB(e.ctx).deleteRow(e.Table.Meta().ID, h)
 		e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
+		if err1 = e.fkChecker.onDelete(e.ctx, e.Table, oldRow, 0); err1 != nil {
+			return nil, errors.Trace(err1)
+		}
 	}
 
 	if e.lastInsertID != 0 {
@@ -1048,6 +1267,7 @@ func (e *ReplaceExec) Next() (*Row, error) {
 type UpdateExec struct {
 	SelectExec  Executor
 	OrderedList []*expression.Assignment
+	Ignore      bool
 
 	// Map for unique (Table, handle) pair.
 	updatedRowKeys map[table.Table]map[int64]struct{}
@@ -1057,6 +1277,8 @@ type UpdateExec struct {
 	newRowsData [][]types.Datum // The new values to be set.
 	fetched     bool
 	cursor      int
+	checker     tableChecker
+	fkChecker   foreignKeyChecker
 }
 
 // Schema implements the Executor Schema interface.
@@ -1086,6 +1308,7 @@ func (e *UpdateExec) Next() (*Row, error) {
 	}
 	row := e.rows[e.cursor]
 	newData := e.newRowsData[e.cursor]
+	e.ctx.GetSessionVars().StmtCtx.SetCurrentRow(uint64(e.cursor + 1))
 	for _, entry := range row.RowKeys {
 		tbl := entry.Tbl
 		if e.updatedRowKeys[tbl] == nil {
@@ -1101,9 +1324,13 @@ func (e *UpdateExec) Next() (*Row, error) {
 			continue
 		}
 		// Update row
-		err1 := updateRecord(e.ctx, handle, oldData, newTableData, assignFlag, tbl, offset, false)
+		err1 := updateRecord(e.ctx, handle, oldData, newTableData, assignFlag, tbl, offset, false, &e.checker, &e.fkChecker)
 		if err1 != nil {
-			return nil, errors.Trace(err1)
+			if !e.Ignore || !isIgnorableError(err1) {
+				return nil, errors.Trace(err1)
+			}
+			// With IGNORE, the row is left unchanged and the error is reported as a warning.
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err1)
 		}
 		e.updatedRowKeys[tbl][handle] = struct{}{}
 	}
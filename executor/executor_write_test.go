@@ -16,14 +16,22 @@ package executor_test
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/types"
@@ -212,6 +220,66 @@ func (s *testSuite) TestInsertAutoInc(c *C) {
 	r = tk.MustQuery("select * from insert_autoinc_test;")
 	rowStr6 = fmt.Sprintf("%v %v", "6", "6")
 	r.Check(testkit.Rows(rowStr3, rowStr1, rowStr2, rowStr4, rowStr5, rowStr6))
+
+	// LAST_INSERT_ID() returns the first value generated by the last insert of the session.
+	tk.MustExec(`drop table if exists insert_autoinc_test; create table insert_autoinc_test (id int primary key auto_increment, c1 int);`)
+	tk.MustExec("insert into insert_autoinc_test values (0, 1), (null, 2)")
+	tk.MustQuery("select last_insert_id()").Check(testkit.Rows("1"))
+	tk.MustExec("insert into insert_autoinc_test values (10, 3)")
+	tk.MustQuery("select last_insert_id()").Check(testkit.Rows("1"))
+	tk.MustExec("insert into insert_autoinc_test(c1) values (4)")
+	tk.MustQuery("select last_insert_id()").Check(testkit.Rows("11"))
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	tk2.MustQuery("select last_insert_id()").Check(testkit.Rows("0"))
+
+	// Concurrent inserts never get the same value.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tk := testkit.NewTestKit(c, s.store)
+			tk.MustExec("use test")
+			for j := 0; j < 5; j++ {
+				tk.MustExec("insert into insert_autoinc_test(c1) values (0)")
+			}
+		}()
+	}
+	wg.Wait()
+	tk.MustQuery("select count(*), count(distinct id) from insert_autoinc_test").Check(testkit.Rows("24 24"))
+}
+
+func (s *testSuite) TestInsertDefaultAndNotNull(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table insert_not_null (a int, b int not null, c int default 7, d int not null default 8, e timestamp default current_timestamp)")
+	tk.MustExec("insert insert_not_null (a, b) values (1, 1)")
+	tk.MustExec("insert insert_not_null values (2, 2, default, default, default)")
+	tk.MustQuery("select a, b, c, d, e is not null from insert_not_null").Check(testkit.Rows("1 1 7 8 1", "2 2 7 8 1"))
+
+	// In strict mode, a missing or NULL value for the NOT NULL column without default is an error.
+	tk.MustExec("set sql_mode = 'STRICT_TRANS_TABLES'")
+	_, err := tk.Exec("insert insert_not_null (a) values (3)")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("insert insert_not_null (a, b) values (3, 3), (4, null)")
+	c.Assert(err, NotNil)
+
+	// In non-strict mode, the implicit default value is used with a warning,
+	// but a single-row insert of NULL is still an error.
+	tk.MustExec("set sql_mode = ''")
+	tk.MustExec("insert insert_not_null (a) values (5)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	_, err = tk.Exec("insert insert_not_null (a, b) values (6, null)")
+	c.Assert(err, NotNil)
+	tk.MustExec("insert insert_not_null (a, b) values (7, 7), (8, null)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	tk.MustExec("insert insert_not_null (a, b) select 9, null")
+	tk.MustQuery("select a, b from insert_not_null where a > 2").Check(testkit.Rows("5 0", "7 7", "8 0", "9 0"))
 }
 
 func (s *testSuite) TestInsertIgnore(c *C) {
@@ -233,18 +301,385 @@ func (s *testSuite) TestInsertIgnore(c *C) {
 	r.Check(testkit.Rows(rowStr))
 
 	tk.MustExec("insert ignore into t values (1, 3), (2, 3)")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	warnings := tk.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(terror.ErrorEqual(warnings[0], kv.ErrKeyExists), IsTrue)
 
 	r = tk.MustQuery("select * from t;")
 	rowStr = fmt.Sprintf("%v %v", "1", "2")
 	rowStr1 := fmt.Sprintf("%v %v", "2", "3")
 	r.Check(testkit.Rows(rowStr, rowStr1))
 
+	// With ON DUPLICATE KEY UPDATE, the duplicate row is still updated, and a conflict of the update is ignored.
+	tk.MustExec("create table t_ignore (id int primary key, u int, unique key (u))")
+	tk.MustExec("insert into t_ignore values (1, 1), (2, 2)")
+	tk.MustExec("insert ignore into t_ignore values (1, 5) on duplicate key update u = 3")
+	tk.MustExec("insert ignore into t_ignore values (1, 5) on duplicate key update u = 2")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	tk.MustQuery("select * from t_ignore").Check(testkit.Rows("1 3", "2 2"))
+
 	cfg.SetGetError(errors.New("foo"))
 	_, err := tk.Exec("insert ignore into t values (1, 3)")
 	c.Assert(err, NotNil)
 	cfg.SetGetError(nil)
 }
 
+func (s *testSuite) TestUpdateIgnore(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, u int, unique key uk (u))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+	// The rows causing conflicts are left unchanged with a warning each.
+	tk.MustExec("update ignore t set u = 3 where id < 3")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(0))
+	warnings := tk.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 2)
+	c.Assert(warnings[0].Error(), Equals, "[kv:1062]Duplicate entry '3' for key 'uk'")
+	tk.MustExec("update ignore t set u = u + 1 order by id desc")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(3))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2", "2 3", "3 4"))
+
+	// Moving a row to a new primary key keeps the row when the new key is taken.
+	tk.MustExec("update ignore t set id = id + 1")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 2)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2", "2 3", "4 4"))
+	_, err := tk.Exec("update t set id = 4, u = 5 where id = 1")
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '4' for key 'PRIMARY'")
+	_, err = tk.Exec("update t set id = 5, u = 4 where id = 1")
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '4' for key 'uk'")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2", "2 3", "4 4"))
+	tk.MustExec("admin check table t")
+
+	// Without IGNORE, the statement fails.
+	_, err = tk.Exec("update t set u = 3 where id = 1")
+	c.Assert(terror.ErrorEqual(err, kv.ErrKeyExists), IsTrue)
+}
+
+func (s *testSuite) TestInsertOnDupUpdate(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, a int, b int default 5, unique key (a))")
+	tk.MustExec("insert into t values (1, 1, 1)")
+	tk.CheckExecResult(1, 0)
+
+	// Conflict on the primary key.
+	tk.MustExec("insert into t values (1, 5, 5) on duplicate key update b = values(b) + 10")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1 15"))
+
+	// Conflict on the unique key, values(col) refers to the value that would have been inserted.
+	tk.MustExec("insert into t values (2, 1, 7) on duplicate key update b = values(b), a = values(a) + 1")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2 7"))
+
+	// Multiple rows, only the conflicting one is updated.
+	tk.MustExec("insert into t values (3, 3, 3), (4, 2, 4) on duplicate key update b = b + values(b)")
+	tk.CheckExecResult(3, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2 11", "3 3 3"))
+
+	// The row is not changed.
+	tk.MustExec("insert into t values (3, 3, 3) on duplicate key update b = values(b)")
+	tk.CheckExecResult(0, 0)
+
+	// values(col) returns the default value when col is not in the insert column list.
+	tk.MustExec("insert into t (id, a) values (1, 100) on duplicate key update b = values(b)")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t where id = 1").Check(testkit.Rows("1 2 5"))
+
+	_, err := tk.Exec("insert into t values (1, 1, 1) on duplicate key update c = 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	_, err = tk.Exec("insert into t set c = 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+}
+
+func (s *testSuite) TestCheckConstraint(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (id int primary key, a int check (a > 0), b varchar(10),
+		constraint b_not_empty check (length(b) > 0), check (a < 100 or b = 'big'))`)
+	tk.MustExec("insert into t values (1, 1, 'x'), (2, null, 'y'), (3, 200, 'big')")
+	tk.MustQuery("select id from t").Check(testkit.Rows("1", "2", "3"))
+
+	checkViolated := func(sql, name string) {
+		_, err := tk.Exec(sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrCheckConstraintViolated), IsTrue, Commentf("err %v", err))
+		c.Assert(err.Error(), Equals, fmt.Sprintf("[executor:3819]Check constraint '%s' is violated.", name))
+	}
+	checkViolated("insert into t values (4, 0, 'x')", "t_chk_2")
+	checkViolated("insert into t values (4, 1, '')", "b_not_empty")
+	checkViolated("insert into t values (4, 101, 'x')", "t_chk_1")
+	checkViolated("insert into t values (1, 2, 'x') on duplicate key update a = -1", "t_chk_2")
+	checkViolated("replace into t values (1, -1, 'x')", "t_chk_2")
+	checkViolated("update t set b = '' where id = 1", "b_not_empty")
+	checkViolated("update t set a = a + 100", "t_chk_1")
+	tk.MustQuery("select id, a from t").Check(testkit.Rows("1 1", "2 <nil>", "3 200"))
+
+	tk.MustExec("insert ignore into t values (4, 0, 'x'), (5, 5, 'z')")
+	tk.MustQuery("select id from t").Check(testkit.Rows("1", "2", "3", "5"))
+	tk.MustExec("update t set a = 99 where a is null")
+	tk.MustQuery("select a from t where id = 2").Check(testkit.Rows("99"))
+
+	// The check constraints are kept after adding a column.
+	tk.MustExec("alter table t add column c int default 1")
+	checkViolated("insert into t values (6, 0, 'x', 1)", "t_chk_2")
+	tk.MustExec("insert into t values (6, 6, 'x', 2)")
+}
+
+func (s *testSuite) TestForeignKey(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists child, parent")
+	tk.MustExec("create table parent (id int primary key, code int, unique key (code))")
+	tk.MustExec(`create table child (id int primary key, pid int, pcode int, index (pid),
+		constraint fk_id foreign key (pid) references parent (id),
+		constraint fk_code foreign key (pcode) references parent (code) on delete cascade on update cascade)`)
+	tk.MustExec("insert into parent values (1, 10), (2, 20), (3, 30)")
+	tk.MustExec("insert into child values (1, 1, 10), (2, null, 20), (3, 2, null), (4, 3, 30)")
+
+	noParent := func(sql string) {
+		_, err := tk.Exec(sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrNoReferencedRow), IsTrue, Commentf("sql %s, err %v", sql, err))
+	}
+	referenced := func(sql string) {
+		_, err := tk.Exec(sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrRowIsReferenced), IsTrue, Commentf("sql %s, err %v", sql, err))
+	}
+	noParent("insert into child values (5, 4, null)")
+	noParent("insert into child values (5, null, 40)")
+	noParent("update child set pid = 4 where id = 1")
+	noParent("replace into child values (1, 1, 40)")
+	_, err := tk.Exec("insert into child values (5, 4, null)")
+	c.Assert(err.Error(), Equals, "[executor:1452]Cannot add or update a child row: a foreign key constraint fails "+
+		"(`test`.`child`, CONSTRAINT `fk_id` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`))")
+	tk.MustExec("insert ignore into child values (5, 4, null), (6, 1, null)")
+	tk.MustQuery("select id from child").Check(testkit.Rows("1", "2", "3", "4", "6"))
+
+	// The parent rows referenced by fk_id can't be deleted or changed.
+	referenced("delete from parent where id = 1")
+	referenced("update parent set id = 5 where id = 2")
+	tk.MustExec("update parent set code = 21 where id = 2")
+	tk.MustQuery("select id, pcode from child where id = 2").Check(testkit.Rows("2 21"))
+
+	// Deleting the parent row cascades to the child rows referencing its code.
+	tk.MustExec("delete from child where id in (1, 6)")
+	tk.MustExec("delete from parent where id = 1")
+	tk.MustExec("delete from child where id = 3")
+	tk.MustExec("delete from parent where code = 21")
+	tk.MustQuery("select id from child").Check(testkit.Rows("4"))
+	tk.MustQuery("select id from parent").Check(testkit.Rows("3"))
+
+	// The checks are skipped when foreign_key_checks is off.
+	tk.MustExec("set foreign_key_checks = 0")
+	tk.MustExec("insert into child values (7, 9, 90)")
+	tk.MustExec("delete from parent")
+	tk.MustExec("set foreign_key_checks = 1")
+	tk.MustQuery("select id from child").Check(testkit.Rows("4", "7"))
+
+	// SET NULL without an index on the child columns, and a self referencing table cascading on delete.
+	tk.MustExec("drop table if exists child, parent, tree")
+	tk.MustExec("create table parent (id int primary key)")
+	tk.MustExec("create table child (id int primary key, pid int, foreign key (pid) references parent (id) on delete set null on update set null)")
+	tk.MustExec("insert into parent values (1), (2)")
+	tk.MustExec("insert into child values (1, 1), (2, 2), (3, 2)")
+	tk.MustExec("delete from parent where id = 1")
+	tk.MustExec("update parent set id = 3 where id = 2")
+	tk.MustQuery("select id from child where pid is null").Check(testkit.Rows("1", "2", "3"))
+	tk.MustExec("create table tree (id int primary key, pid int, foreign key (pid) references tree (id) on delete cascade)")
+	tk.MustExec("insert into tree values (1, 1), (2, 1), (3, 2), (4, null)")
+	noParent("insert into tree values (5, 6)")
+	tk.MustExec("delete from tree where id = 1")
+	tk.MustQuery("select id from tree").Check(testkit.Rows("4"))
+	tk.MustExec("begin")
+	tk.MustExec("insert into tree values (10, 4)")
+	for i := 11; i <= 26; i++ {
+		tk.MustExec(fmt.Sprintf("insert into tree values (%d, %d)", i, i-1))
+	}
+	_, err = tk.Exec("delete from tree where id = 4")
+	c.Assert(terror.ErrorEqual(err, executor.ErrFKDepthExceeded), IsTrue, Commentf("err %v", err))
+	tk.MustExec("rollback")
+
+	// The referenced columns must be the leading columns of an index.
+	tk.MustExec("drop table if exists child2, parent2")
+	tk.MustExec("create table parent2 (id int primary key, a int, b int, index (a, b))")
+	_, err = tk.Exec("create table child2 (id int, pb int, foreign key (pb) references parent2 (b))")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrCannotAddForeign), IsTrue, Commentf("err %v", err))
+	tk.MustExec("create table child2 (id int, pa int, foreign key (pa) references parent2 (a))")
+	_, err = tk.Exec("alter table child2 add constraint fk_b foreign key (pa) references parent2 (b)")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrCannotAddForeign), IsTrue, Commentf("err %v", err))
+
+	// The parent row is locked, the child row can't be committed if the parent row is deleted meanwhile.
+	tk.MustExec("insert into parent2 values (1, 1, 1)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into child2 values (1, 1)")
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustExec("delete from parent2 where id = 1")
+	_, err = tk.Exec("commit")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select * from child2").Check(testkit.Rows())
+}
+
+func (s *testSuite) TestInsertEnumSet(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, e enum('a', 'b', 'c'), s set('x', 'y', 'z'))")
+	// The members can be assigned by name or by index, a SET value is a comma list of the members.
+	tk.MustExec("insert t values (1, 'a', 'y,x'), (2, 'B', 'Z,x,z'), (3, 3, 6), (4, null, '')")
+	tk.MustQuery("select id, e+0, s+0 from t").Check(testkit.Rows("1 1 3", "2 2 5", "3 3 6", "4 <nil> 0"))
+	tk.MustQuery("select id from t where e = 'b'").Check(testkit.Rows("2"))
+	tk.MustQuery("select id from t where e = 3").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t where s = 'x,y'").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where s = 5").Check(testkit.Rows("2"))
+	tk.MustExec("update t set s = 'z,y' where id = 1")
+	tk.MustQuery("select s+0 from t where id = 1").Check(testkit.Rows("6"))
+
+	// The values not in the list are errors in strict sql mode.
+	_, err := tk.Exec("insert t values (5, 'd', 'x')")
+	c.Assert(terror.ErrorEqual(err, types.ErrTruncated), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("insert t values (5, 'a', 'x,w')")
+	c.Assert(terror.ErrorEqual(err, types.ErrTruncated), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("insert t values (5, 4, 8)")
+	c.Assert(terror.ErrorEqual(err, types.ErrTruncated), IsTrue, Commentf("err %v", err))
+
+	// Otherwise an invalid ENUM value is the empty string and the invalid SET members are dropped.
+	tk.MustExec("set sql_mode = ''")
+	tk.MustExec("insert t values (5, 'd', 'x,w,Y')")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 2)
+	tk.MustQuery("select e+0, s+0 from t where id = 5").Check(testkit.Rows("0 3"))
+	tk.MustQuery("select id from t where e = ''").Check(testkit.Rows("5"))
+}
+
+func (s *testSuite) TestInsertSet(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists ist")
+	tk.MustExec("create table ist (a int default 1, b int default 5, c varchar(10) default 'q')")
+
+	tk.MustExec("insert into ist set a = 2, b = default, c = concat('x', 'y')")
+	tk.CheckExecResult(1, 0)
+	tk.MustExec("insert ist set c = default(b), a = 1 + 2")
+	tk.MustExec("replace into ist set a = 4, b = 2 * 3")
+	tk.MustQuery("select * from ist").Check(testkit.Rows(
+		fmt.Sprintf("%v %v %v", 2, 5, []byte("xy")), fmt.Sprintf("%v %v %v", 3, 5, []byte("5")), fmt.Sprintf("%v %v %v", 4, 6, []byte("q"))))
+
+	// DEFAULT takes the default value of the column it is inserted into.
+	tk.MustExec("delete from ist")
+	tk.MustExec("insert into ist (c, b) values (default, 7)")
+	tk.MustExec("insert into ist values (default, 8, default)")
+	tk.MustQuery("select * from ist").Check(testkit.Rows(
+		fmt.Sprintf("%v %v %v", 1, 7, []byte("q")), fmt.Sprintf("%v %v %v", 1, 8, []byte("q"))))
+
+	_, err := tk.Exec("insert into ist set a = 1, a = 2")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("insert into ist set d = 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	_, err = tk.Exec("insert into ist values (default, default, default, default)")
+	c.Assert(executor.ErrWrongValueCount.Equal(err), IsTrue)
+}
+
+func (s *testSuite) TestInsertMultiRowValues(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists mr")
+	tk.MustExec("create table mr (a int, b datetime, c varchar(10))")
+
+	// Non-constant expressions are evaluated for each row.
+	tk.MustExec("insert into mr values (1, now(), 'x'), (2, now(), concat('a', 'b'))")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select a, b is not null, c from mr").Check(testkit.Rows(
+		fmt.Sprintf("%v %v %v", 1, 1, []byte("x")), fmt.Sprintf("%v %v %v", 2, 1, []byte("ab"))))
+	tk.MustExec("set @n = 10")
+	tk.MustExec("insert into mr (a) values (@n := @n + 1), (@n := @n + 1), (@n := @n + 1)")
+	tk.MustQuery("select a from mr where a > 10").Check(testkit.Rows("11", "12", "13"))
+
+	// Values are converted to the column types after evaluation.
+	tk.MustExec("delete from mr")
+	tk.MustExec("insert into mr values (1 + 1.6, concat('2017', '-01-02'), 1 + 2)")
+	tk.MustQuery("select * from mr").Check(testkit.Rows(fmt.Sprintf("%v %v %v", 3, "2017-01-02 00:00:00", []byte("3"))))
+
+	// The error reports the row whose value count doesn't match.
+	_, err := tk.Exec("insert into mr values (1, now())")
+	c.Assert(executor.ErrWrongValueCount.Equal(err), IsTrue)
+	c.Assert(err.Error(), Equals, executor.ErrWrongValueCount.GenByArgs(1).Error())
+	_, err = tk.Exec("insert into mr values (1, now(), 'x'), (2, now())")
+	c.Assert(err.Error(), Equals, executor.ErrWrongValueCount.GenByArgs(2).Error())
+	_, err = tk.Exec("insert into mr (a, b) values (1, now()), (2, now()), (3, now(), 'x')")
+	c.Assert(err.Error(), Equals, executor.ErrWrongValueCount.GenByArgs(3).Error())
+	_, err = tk.Exec("insert into mr (a) values ()")
+	c.Assert(executor.ErrWrongValueCount.Equal(err), IsTrue)
+	tk.MustQuery("select count(*) from mr").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite) TestInsertSelect(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists src, dst")
+	tk.MustExec("create table src (x varchar(10), y int, z double)")
+	tk.MustExec("create table dst (a int, b varchar(10), c int default 7)")
+	tk.MustExec(`insert into src values ("1", 10, 1.4), ("2", 20, 2.6), ("3", 30, 3.5)`)
+
+	// Selected values are converted to the types of the target columns.
+	tk.MustExec("insert into dst (a, b) select x, y from src where y < 30")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from dst").Check(testkit.Rows(fmt.Sprintf("%v %v %v", 1, []byte("10"), 7), fmt.Sprintf("%v %v %v", 2, []byte("20"), 7)))
+	tk.MustExec("insert into dst (c, a) select z, y from src where y = 30")
+	tk.MustQuery("select * from dst where a = 30").Check(testkit.Rows("30 <nil> 4"))
+	tk.MustExec("insert into dst select y, x, z from src where y = 20")
+	tk.MustQuery("select * from dst where a = 20").Check(testkit.Rows(fmt.Sprintf("%v %v %v", 20, []byte("2"), 3)))
+
+	// An empty result inserts nothing.
+	tk.MustExec("insert into dst select y, x, z from src where y > 100")
+	tk.CheckExecResult(0, 0)
+
+	// The select list must match the target columns.
+	_, err := tk.Exec("insert into dst (a, b) select x from src")
+	c.Assert(executor.ErrWrongValueCount.Equal(err), IsTrue)
+	_, err = tk.Exec("insert into dst select x, y from src")
+	c.Assert(executor.ErrWrongValueCount.Equal(err), IsTrue)
+	_, err = tk.Exec("insert into dst (a) values (1), (2, 3)")
+	c.Assert(executor.ErrWrongValueCount.Equal(err), IsTrue)
+	tk.MustQuery("select count(*) from dst").Check(testkit.Rows("4"))
+}
+
 func (s *testSuite) TestReplace(c *C) {
 	defer func() {
 		s.cleanEnv(c)
@@ -368,6 +803,21 @@ func (s *testSuite) TestReplace(c *C) {
 	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(3))
 	r = tk.MustQuery("select * from tIssue1012;")
 	r.Check(testkit.Rows("1 1"))
+
+	// Rows in the same value list may conflict with each other, both the deleted and inserted rows are counted.
+	tk.MustExec(`replace into tIssue1012(a, b) values (2, 2), (3, 2), (1, 3);`)
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(5))
+	r = tk.MustQuery("select * from tIssue1012;")
+	r.Check(testkit.Rows("1 3", "3 2"))
+
+	// Replace works on the system tables.
+	tk.MustExec(`replace into mysql.db (Host, User, DB, Select_priv) values ("localhost", "replace", "test", "Y");`)
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(1))
+	tk.MustExec(`replace into mysql.db (Host, User, DB, Insert_priv) values ("localhost", "replace", "test", "Y");`)
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(2))
+	r = tk.MustQuery(`select Select_priv, Insert_priv from mysql.db where User = "replace";`)
+	r.Check(testkit.Rows("N Y"))
+	tk.MustExec(`delete from mysql.db where User = "replace";`)
 }
 
 func (s *testSuite) TestUpdate(c *C) {
@@ -429,6 +879,50 @@ func (s *testSuite) TestUpdate(c *C) {
 	r = tk.MustQuery("select * from update_test;")
 	r.Check(testkit.Rows("2"))
 	tk.MustExec("commit")
+
+	// The rows are sorted after filtering and before the limit is applied.
+	tk.MustExec("drop table update_test")
+	tk.MustExec("create table update_test(id int, c int)")
+	tk.MustExec("insert into update_test values (1, 5), (2, 4), (3, 3), (4, 2), (5, 1)")
+	tk.MustExec("update update_test set c = c + 10 where id > 1 order by c limit 2")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(2))
+	r = tk.MustQuery("select * from update_test;")
+	r.Check(testkit.Rows("1 5", "2 4", "3 3", "4 12", "5 11"))
+	tk.MustExec("update update_test set c = 0 where c < 10 order by id desc limit 1")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	r = tk.MustQuery("select * from update_test;")
+	r.Check(testkit.Rows("1 5", "2 4", "3 0", "4 12", "5 11"))
+	// Multiple-table syntax doesn't allow order by and limit.
+	_, err = tk.Exec("update update_test, t set c = 0 order by id limit 1")
+	c.Assert(err, NotNil)
+
+	// Updating a row to a duplicate unique key reports the key and the values.
+	tk.MustExec("drop table update_test")
+	tk.MustExec("create table update_test (id int primary key, a varchar(10), b int, unique key ab (a, b))")
+	tk.MustExec("insert into update_test values (1, 'x', 1), (2, 'x', 2)")
+	_, err = tk.Exec("update update_test set b = 1 where id = 2")
+	c.Assert(terror.ErrorEqual(err, kv.ErrKeyExists), IsTrue, Commentf("err %v", err))
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry 'x-1' for key 'ab'")
+	_, err = tk.Exec("update update_test set id = 1 where id = 2")
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '1' for key 'PRIMARY'")
+	_, err = tk.Exec("insert into update_test values (3, 'x', 3) on duplicate key update b = 2")
+	c.Assert(err, IsNil)
+	_, err = tk.Exec("insert into update_test values (2, 'y', 3) on duplicate key update b = 1")
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry 'x-1' for key 'ab'")
+
+	// The ON UPDATE CURRENT_TIMESTAMP column is refreshed only when the row changes.
+	tk.MustExec("drop table update_test")
+	tk.MustExec("create table update_test (id int primary key, c int, u timestamp default '2000-01-01 00:00:00' on update current_timestamp)")
+	tk.MustExec("insert into update_test (id, c) values (1, 1), (2, 2)")
+	tk.MustExec("update update_test set c = 1 where id = 1")
+	tk.MustQuery("select id, u from update_test").Check(testkit.Rows("1 2000-01-01 00:00:00", "2 2000-01-01 00:00:00"))
+	tk.MustExec("update update_test set c = 3 where id = 1")
+	tk.MustQuery("select id, u > '2001-01-01' from update_test").Check(testkit.Rows("1 1", "2 0"))
+	// An explicit assignment takes precedence.
+	tk.MustExec("update update_test set c = 4, u = '2010-01-01 00:00:00' where id = 2")
+	tk.MustQuery("select id, u from update_test where id = 2").Check(testkit.Rows("2 2010-01-01 00:00:00"))
+	tk.MustExec("insert into update_test (id, c) values (2, 2) on duplicate key update c = 5")
+	tk.MustQuery("select id, u > '2011-01-01' from update_test where id = 2").Check(testkit.Rows("2 1"))
 }
 
 func (s *testSuite) fillMultiTableForUpdate(tk *testkit.TestKit) {
@@ -501,6 +995,29 @@ func (s *testSuite) TestMultipleTableUpdate(c *C) {
 
 	r = tk.MustQuery("select * from t1")
 	r.Check(testkit.Rows("10", "10"))
+
+	// Assignments may target every table of the join.
+	testSQL = `DROP TABLE IF EXISTS t1, t2;
+		create table t1 (id int primary key, c1 int);
+		create table t2 (id int primary key, c2 int);
+		insert into t1 values (1, 1), (2, 2), (3, 3);
+		insert into t2 values (1, 10), (3, 30), (4, 40);`
+	tk.MustExec(testSQL)
+	tk.MustExec("update t1 join t2 on t1.id = t2.id set t1.c1 = t2.c2, t2.c2 = t1.id + 100")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 10", "2 2", "3 30"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 101", "3 103", "4 40"))
+
+	// Assignments to columns outside the joined tables are rejected.
+	_, err := tk.Exec("update t1 join t2 on t1.id = t2.id set t1.c2 = 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	c.Assert(err.Error(), Matches, ".*Unknown column 't1.c2' in 'field list'")
+	_, err = tk.Exec("update t1 join t2 on t1.id = t2.id set c3 = 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	_, err = tk.Exec("update t1 join t2 on t1.id = t2.id set t3.c1 = 1")
+	c.Assert(plan.ErrUnknownColumn.Equal(err), IsTrue)
+	_, err = tk.Exec("update t1 join t2 on t1.id = t2.id set id = 1")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 10", "2 2", "3 30"))
 }
 
 func (s *testSuite) TestDelete(c *C) {
@@ -534,6 +1051,20 @@ func (s *testSuite) TestDelete(c *C) {
 
 	tk.MustExec(`delete from delete_test ;`)
 	tk.CheckExecResult(1, 0)
+
+	// The rows are sorted after filtering and before the limit is applied.
+	tk.MustExec("insert into delete_test values (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')")
+	tk.MustExec("delete from delete_test where id < 5 order by name desc limit 2")
+	tk.CheckExecResult(2, 0)
+	rows = tk.MustQuery("select id from delete_test")
+	rows.Check(testkit.Rows("1", "2", "5"))
+	tk.MustExec("delete from delete_test order by id limit 1")
+	tk.CheckExecResult(1, 0)
+	rows = tk.MustQuery("select id from delete_test")
+	rows.Check(testkit.Rows("2", "5"))
+	// Multiple-table syntax doesn't allow order by and limit.
+	_, err := tk.Exec("delete delete_test from delete_test, t1 order by id limit 1")
+	c.Assert(err, NotNil)
 }
 
 func (s *testSuite) fillDataMultiTable(tk *testkit.TestKit) {
@@ -567,6 +1098,16 @@ func (s *testSuite) TestMultiTableDelete(c *C) {
 	// Select data
 	r := tk.MustQuery("select * from t3")
 	c.Assert(r.Rows(), HasLen, 3)
+
+	// Only the target tables are deleted from, the other joined tables are kept.
+	tk.MustExec(`delete t3 from t1 right join t3 on t1.id = t3.id where t3.id > 20;`)
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("12 122", "13 123"))
+	tk.MustQuery("select * from t3").Check(testkit.Rows("11 321"))
+
+	// The target table must be part of the join.
+	_, err := tk.Exec(`delete t2 from t1 join t3 on t1.id = t3.id;`)
+	c.Assert(plan.ErrUnknownTable.Equal(err), IsTrue)
 }
 
 func (s *testSuite) TestQualifiedDelete(c *C) {
@@ -817,6 +1358,60 @@ func (s *testSuite) TestLoadDataEscape(c *C) {
 	checkCases(cases, ld, c, tk, ctx, selectSQL, deleteSQL)
 }
 
+func (s *testSuite) TestLoadDataInfile(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	dir, err := ioutil.TempDir("", "load_data")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	f, err := ioutil.TempFile(dir, "load_data")
+	c.Assert(err, IsNil)
+	_, err = f.WriteString("header line\n1\ta\t10\n2\t\\N\t20\n3\tc\\x\t30\n")
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c1 varchar(10) default 'def', c2 int default 7)")
+	// LOAD DATA INFILE is disabled if secure_file_priv is not set.
+	_, err = tk.Exec("load data infile '" + f.Name() + "' into table t ignore 1 lines")
+	c.Assert(terror.ErrorEqual(err, executor.ErrOptionPreventsStatement), IsTrue)
+	defer func(old string) { executor.SecureFilePriv = old }(executor.SecureFilePriv)
+	executor.SecureFilePriv = filepath.Join(dir, "sub")
+	_, err = tk.Exec("load data infile '" + f.Name() + "' into table t ignore 1 lines")
+	c.Assert(terror.ErrorEqual(err, executor.ErrOptionPreventsStatement), IsTrue)
+	executor.SecureFilePriv = dir
+	tk.MustExec("load data infile '" + f.Name() + "' into table t ignore 1 lines")
+	tk.CheckExecResult(3, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows(
+		fmt.Sprintf("1 %v 10", []byte("a")), "2 <nil> 20", fmt.Sprintf("3 %v 30", []byte("cx"))))
+
+	// Columns not in the column list get their default values.
+	tk.MustExec("delete from t")
+	tk.MustExec("load data infile '" + f.Name() + "' into table t ignore 1 lines (id, c2)")
+	tk.MustQuery("select * from t").Check(testkit.Rows(
+		fmt.Sprintf("1 %v 0", []byte("def")), fmt.Sprintf("2 %v <nil>", []byte("def")), fmt.Sprintf("3 %v 0", []byte("def"))))
+
+	_, err = tk.Exec("load data infile '" + f.Name() + "' into table t (id, c3)")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("load data infile '" + f.Name() + "' into table t (id, id)")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("load data infile '" + filepath.Join(dir, "nonexistence.csv") + "' into table t")
+	c.Assert(err, NotNil)
+
+	// The FILE privilege is required.
+	tk.MustExec("create user 'load_user'@'localhost'")
+	defer tk.MustExec("drop user 'load_user'@'localhost'")
+	tk.Se, err = tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk.Se.(context.Context).GetSessionVars().User = "load_user@localhost"
+	_, err = tk.Exec("load data infile '" + f.Name() + "' into table test.t")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSpecificAccessDenied), IsTrue)
+}
+
 func makeLoadDataInfo(column int, ctx context.Context, c *C) (ld *executor.LoadDataInfo) {
 	domain := sessionctx.GetDomain(ctx)
 	is := domain.InfoSchema()
@@ -17,7 +17,9 @@ import (
 	"encoding/json"
 
 	"github.com/juju/errors"
+	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/util/types"
 )
@@ -26,6 +28,7 @@ import (
 // See https://dev.mysql.com/doc/refman/5.7/en/explain-output.html
 type ExplainExec struct {
 	StmtPlan plan.Plan
+	Format   string
 	schema   expression.Schema
 	rows     []*Row
 	cursor   int
@@ -58,12 +61,88 @@ func (e *ExplainExec) prepareExplainInfo(p plan.Plan, parent plan.Plan) error {
 	return nil
 }
 
+// prepareTraditionalInfo appends a row for every operator of the plan tree, the children come first.
+func (e *ExplainExec) prepareTraditionalInfo(p plan.Plan, parent plan.Plan, selectType string) {
+	for i, child := range p.GetChildren() {
+		childSelectType := selectType
+		switch p.(type) {
+		case *plan.Union:
+			childSelectType = "UNION"
+			if i == 0 {
+				childSelectType = "PRIMARY"
+			}
+		case *plan.PhysicalApply:
+			if i == 1 {
+				childSelectType = "DEPENDENT SUBQUERY"
+			}
+		}
+		e.prepareTraditionalInfo(child, p, childSelectType)
+	}
+	if _, ok := p.(*plan.Union); ok {
+		selectType = "UNION RESULT"
+	}
+	// key is the index chosen by the optimizer, the integer primary key is shown as PRIMARY.
+	var table, accessType, key string
+	switch x := p.(type) {
+	case *plan.PhysicalTableScan:
+		table = explainTableName(x.Table, x.TableAsName)
+		accessType = "ALL"
+		if len(x.AccessCondition) > 0 {
+			accessType = "range"
+			key = "PRIMARY"
+		}
+	case *plan.PhysicalIndexScan:
+		table = explainTableName(x.Table, x.TableAsName)
+		accessType = "index"
+		if len(x.AccessCondition) > 0 {
+			accessType = "range"
+		}
+		key = x.Index.Name.O
+	}
+	parentStr := ""
+	if parent != nil {
+		parentStr = parent.GetID()
+	}
+	var count uint64
+	if pp, ok := p.(plan.PhysicalPlan); ok {
+		count = pp.EstimatedCount()
+	}
+	row := &Row{
+		Data: types.MakeDatums(p.GetID(), selectType, table, accessType, key, count, parentStr),
+	}
+	e.rows = append(e.rows, row)
+}
+
+func explainTableName(tbl *model.TableInfo, asName *model.CIStr) string {
+	if asName != nil && asName.L != "" {
+		return asName.O
+	}
+	return tbl.Name.O
+}
+
+// explainSelectType returns the select type of the statement plan, like the select_type column in MySQL.
+func explainSelectType(p plan.Plan) string {
+	switch p.(type) {
+	case *plan.Insert:
+		return "INSERT"
+	case *plan.Update:
+		return "UPDATE"
+	case *plan.Delete:
+		return "DELETE"
+	}
+	return "SIMPLE"
+}
+
 // Next implements Execution Next interface.
 func (e *ExplainExec) Next() (*Row, error) {
 	if e.cursor == 0 {
-		err := e.prepareExplainInfo(e.StmtPlan, nil)
-		if err != nil {
-			return nil, errors.Trace(err)
+		if e.Format == ast.ExplainFormatJSON {
+			err := e.prepareExplainInfo(e.StmtPlan, nil)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		} else {
+			e.prepareTraditionalInfo(e.StmtPlan, nil, explainSelectType(e.StmtPlan))
 		}
 	}
 	if e.cursor >= len(e.rows) {
@@ -14,6 +14,9 @@
 package executor_test
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
@@ -351,10 +354,10 @@ func (s *testSuite) TestExplain(c *C) {
 		{
 			"select * from t2 order by t2.c2 limit 0, 1",
 			[]string{
-				"TableScan_5", "",
+				"TableScan_5", "Sort_8",
 			},
 			[]string{
-				"", "",
+				"Sort_8", "",
 			},
 			[]string{
 				`{
@@ -442,7 +445,7 @@ func (s *testSuite) TestExplain(c *C) {
 		},
 	}
 	for _, ca := range cases {
-		result := tk.MustQuery("explain " + ca.sql)
+		result := tk.MustQuery("explain format = json " + ca.sql)
 		var resultList []string
 		for i := range ca.ids {
 			resultList = append(resultList, ca.ids[i]+" "+ca.result[i]+" "+ca.parentIds[i])
@@ -450,3 +453,68 @@ func (s *testSuite) TestExplain(c *C) {
 		result.Check(testkit.Rows(resultList...))
 	}
 }
+
+func (s *testSuite) TestExplainTraditional(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (c1 int primary key, c2 int, c3 int, index c2 (c2))")
+	tk.MustExec("create table t2 (c1 int unique, c2 int)")
+
+	tk.MustQuery("explain select * from t1 where c1 > 1").Check(testkit.Rows(
+		"TableScan_4 SIMPLE t1 range PRIMARY 3333333 ",
+	))
+	tk.MustQuery("explain format = traditional select * from t1 a where a.c1 = 1").Check(testkit.Rows(
+		"TableScan_4 SIMPLE a range PRIMARY 10000 ",
+	))
+	tk.MustQuery("explain select c1 from t1 where c2 = 1 order by c3").Check(testkit.Rows(
+		"IndexScan_9 SIMPLE t1 range c2 10000 Projection_3",
+		"Projection_3 SIMPLE    10000 Sort_4",
+		"Sort_4 SIMPLE    10000 Trim_5",
+		"Trim_5 SIMPLE    10000 ",
+	))
+	tk.MustQuery("explain select * from t1 order by c3 limit 2").Check(testkit.Rows(
+		"TableScan_5 SIMPLE t1 ALL  10000000 Sort_8",
+		"Sort_8 SIMPLE    2 ",
+	))
+	tk.MustQuery("explain select c1 from t1 union all select c1 from t2").Check(testkit.Rows(
+		"TableScan_6 PRIMARY t1 ALL  10000000 Union_1",
+		"TableScan_7 UNION t2 ALL  10000000 Union_1",
+		"Union_1 UNION RESULT    20000000 ",
+	))
+	tk.MustQuery("explain update t1 set c2 = 1 where c1 = 1").Check(testkit.Rows(
+		"TableScan_4 UPDATE t1 range PRIMARY 10000 Update_3",
+		"Update_3 UPDATE    10000 ",
+	))
+	tk.MustQuery("explain delete from t1 where c2 = 1").Check(testkit.Rows(
+		"IndexScan_5 DELETE t1 range c2 10000 Delete_3",
+		"Delete_3 DELETE    10000 ",
+	))
+
+	// After ANALYZE TABLE the estimated row counts decide between the index and the table scan.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index b (b))")
+	values := make([]string, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		b := 1
+		if i < 10 {
+			b = 2
+		}
+		values = append(values, fmt.Sprintf("(%d, %d)", i, b))
+	}
+	tk.MustExec("insert t values " + strings.Join(values, ","))
+	tk.MustQuery("explain select * from t where b = 1").Check(testkit.Rows(
+		"IndexScan_5 SIMPLE t range b 10000 ",
+	))
+	tk.MustExec("analyze table t")
+	tk.MustQuery("explain select * from t where b = 1").Check(testkit.Rows(
+		"TableScan_4 SIMPLE t ALL  2400 ",
+	))
+	tk.MustQuery("explain select * from t where b = 2").Check(testkit.Rows(
+		"IndexScan_5 SIMPLE t range b 1000 ",
+	))
+}
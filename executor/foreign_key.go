@@ -0,0 +1,557 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/types"
+)
+
+// maxForeignKeyCascadeDepth is the maximum depth of the cascading foreign key actions, the same as MySQL.
+const maxForeignKeyCascadeDepth = 15
+
+// foreignKeyChecker enforces the foreign key constraints on the rows written by a statement.
+// The child rows must reference existing parent rows, and the ON DELETE and ON UPDATE actions
+// are applied to the child rows when their parent rows are deleted or updated.
+type foreignKeyChecker struct {
+	is infoschema.InfoSchema
+	// dbNames maps the table ID to the name of its database.
+	dbNames map[int64]model.CIStr
+	// children maps the table ID to the foreign keys referencing the table.
+	children map[int64][]*childForeignKey
+}
+
+// childForeignKey is a foreign key of the child table tbl.
+type childForeignKey struct {
+	tbl table.Table
+	fk  *model.FKInfo
+}
+
+// enabled returns whether the foreign keys are enforced, the foreign keys are collected from
+// the information schema on first call.
+func (c *foreignKeyChecker) enabled(ctx context.Context) bool {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return false
+	}
+	if c.is != nil {
+		return true
+	}
+	dom := sessionctx.GetDomain(ctx)
+	if dom == nil {
+		return false
+	}
+	c.is = dom.InfoSchema()
+	c.dbNames = make(map[int64]model.CIStr)
+	c.children = make(map[int64][]*childForeignKey)
+	for _, db := range c.is.AllSchemas() {
+		for _, tblInfo := range db.Tables {
+			c.dbNames[tblInfo.ID] = db.Name
+			for _, fk := range tblInfo.ForeignKeys {
+				if fk.State != model.StatePublic {
+					continue
+				}
+				parent, err := c.is.TableByName(refSchema(db.Name, fk), fk.RefTable)
+				if err != nil {
+					// The referenced table doesn't exist, no child row can be added.
+					continue
+				}
+				child, ok := c.is.TableByID(tblInfo.ID)
+				if !ok {
+					continue
+				}
+				parentID := parent.Meta().ID
+				c.children[parentID] = append(c.children[parentID], &childForeignKey{tbl: child, fk: fk})
+			}
+		}
+	}
+	return true
+}
+
+// refSchema returns the database of the table referenced by fk, which belongs to a table in dbName.
+func refSchema(dbName model.CIStr, fk *model.FKInfo) model.CIStr {
+	if fk.RefSchema.L != "" {
+		return fk.RefSchema
+	}
+	return dbName
+}
+
+// checkInsert checks that the parent rows referenced by the new row of t exist.
+func (c *foreignKeyChecker) checkInsert(ctx context.Context, t table.Table, row []types.Datum) error {
+	if len(t.Meta().ForeignKeys) == 0 || !c.enabled(ctx) {
+		return nil
+	}
+	for _, fk := range t.Meta().ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
+		if err := c.checkParentExists(ctx, t, fk, row); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// checkUpdate checks that the parent rows referenced by the updated row of t exist,
+// only the foreign keys whose columns are changed are checked.
+func (c *foreignKeyChecker) checkUpdate(ctx context.Context, t table.Table, oldRow, newRow []types.Datum) error {
+	if len(t.Meta().ForeignKeys) == 0 || !c.enabled(ctx) {
+		return nil
+	}
+	for _, fk := range t.Meta().ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
+		changed, err := columnsChanged(ctx, t, fk.Cols, oldRow, newRow)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !changed {
+			continue
+		}
+		if err = c.checkParentExists(ctx, t, fk, newRow); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (c *foreignKeyChecker) checkParentExists(ctx context.Context, t table.Table, fk *model.FKInfo, row []types.Datum) error {
+	vals := columnValues(t, fk.Cols, row)
+	if vals == nil {
+		return nil
+	}
+	dbName := c.dbNames[t.Meta().ID]
+	errNoParent := ErrNoReferencedRow.GenByArgs(describeForeignKey(dbName, t.Meta().Name, fk))
+	parent, err := c.is.TableByName(refSchema(dbName, fk), fk.RefTable)
+	if err != nil {
+		if terror.ErrorEqual(err, infoschema.ErrTableNotExists) {
+			return errNoParent
+		}
+		return errors.Trace(err)
+	}
+	refCols, err := table.FindCols(parent.Cols(), ciStrsToStrings(fk.RefCols))
+	if err != nil {
+		return errNoParent
+	}
+	if parent.Meta().ID == t.Meta().ID {
+		// The row may reference itself.
+		matched, err1 := rowMatches(ctx, refCols, row, vals)
+		if err1 != nil {
+			return errors.Trace(err1)
+		}
+		if matched {
+			return nil
+		}
+	}
+	handles, err := fetchMatchedHandles(ctx, parent, refCols, vals, 1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(handles) == 0 {
+		return errNoParent
+	}
+	// Lock the parent row, so this transaction fails to commit if the parent row is deleted or
+	// updated by another transaction, whose own foreign key check can't see the new child row.
+	key := tablecodec.EncodeRowKeyWithHandle(parent.Meta().ID, handles[0])
+	return errors.Trace(ctx.Txn().LockKeysShared(key))
+}
+
+// onDelete applies the ON DELETE actions of the foreign keys referencing t after its row is deleted.
+func (c *foreignKeyChecker) onDelete(ctx context.Context, t table.Table, row []types.Datum, depth int) error {
+	if !c.enabled(ctx) {
+		return nil
+	}
+	for _, child := range c.children[t.Meta().ID] {
+		vals := columnValues(t, child.fk.RefCols, row)
+		if vals == nil {
+			continue
+		}
+		handles, err := child.fetchHandles(ctx, vals)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(handles) == 0 {
+			continue
+		}
+		switch ast.ReferOptionType(child.fk.OnDelete) {
+		case ast.ReferOptionCascade:
+			for _, h := range handles {
+				if err = c.cascadeDelete(ctx, child.tbl, h, depth+1); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case ast.ReferOptionSetNull:
+			for _, h := range handles {
+				if err = c.cascadeUpdate(ctx, child, h, nil, depth+1); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		default:
+			return ErrRowIsReferenced.GenByArgs(c.describe(child))
+		}
+	}
+	return nil
+}
+
+// onUpdate applies the ON UPDATE actions of the foreign keys referencing t after its row is updated,
+// only the foreign keys whose referenced columns are changed are applied.
+func (c *foreignKeyChecker) onUpdate(ctx context.Context, t table.Table, oldRow, newRow []types.Datum, depth int) error {
+	if !c.enabled(ctx) {
+		return nil
+	}
+	for _, child := range c.children[t.Meta().ID] {
+		vals := columnValues(t, child.fk.RefCols, oldRow)
+		if vals == nil {
+			continue
+		}
+		changed, err := columnsChanged(ctx, t, child.fk.RefCols, oldRow, newRow)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !changed {
+			continue
+		}
+		handles, err := child.fetchHandles(ctx, vals)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(handles) == 0 {
+			continue
+		}
+		switch ast.ReferOptionType(child.fk.OnUpdate) {
+		case ast.ReferOptionCascade:
+			// The child rows follow the new values, a NULL value sets the child columns to NULL.
+			newVals := columnValues(t, child.fk.RefCols, newRow)
+			for _, h := range handles {
+				if err = c.cascadeUpdate(ctx, child, h, newVals, depth+1); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case ast.ReferOptionSetNull:
+			for _, h := range handles {
+				if err = c.cascadeUpdate(ctx, child, h, nil, depth+1); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		default:
+			return ErrRowIsReferenced.GenByArgs(c.describe(child))
+		}
+	}
+	return nil
+}
+
+// cascadeDelete deletes the child row h of t and applies the ON DELETE actions to its own children.
+// The cascaded rows are not counted in the affected rows.
+func (c *foreignKeyChecker) cascadeDelete(ctx context.Context, t table.Table, h int64, depth int) error {
+	if depth > maxForeignKeyCascadeDepth {
+		return ErrFKDepthExceeded.GenByArgs(maxForeignKeyCascadeDepth)
+	}
+	row, err := t.Row(ctx, h)
+	if err != nil {
+		if terror.ErrorEqual(err, kv.ErrNotExist) {
+			// The row is deleted by another cascading action.
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	if err = t.RemoveRecord(ctx, h, row); err != nil {
+		return errors.Trace(err)
+	}
+	getDirtyDB(ctx).deleteRow(t.Meta().ID, h)
+	return errors.Trace(c.onDelete(ctx, t, row, depth))
+}
+
+// cascadeUpdate sets the foreign key columns of the child row h to vals, or to NULL if vals is nil,
+// and applies the ON UPDATE actions to its own children. The cascaded rows are not counted in the affected rows.
+func (c *foreignKeyChecker) cascadeUpdate(ctx context.Context, child *childForeignKey, h int64, vals []types.Datum, depth int) error {
+	if depth > maxForeignKeyCascadeDepth {
+		return ErrFKDepthExceeded.GenByArgs(maxForeignKeyCascadeDepth)
+	}
+	t := child.tbl
+	oldRow, err := t.Row(ctx, h)
+	if err != nil {
+		if terror.ErrorEqual(err, kv.ErrNotExist) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	cols := t.Cols()
+	newRow := make([]types.Datum, len(oldRow))
+	copy(newRow, oldRow)
+	touched := make(map[int]bool, len(child.fk.Cols))
+	handleChanged := false
+	for i, name := range child.fk.Cols {
+		col := table.FindCol(cols, name.O)
+		if col == nil {
+			return nil
+		}
+		if vals == nil {
+			newRow[col.Offset].SetNull()
+		} else {
+			newRow[col.Offset] = vals[i]
+		}
+		touched[col.Offset] = true
+		if col.IsPKHandleColumn(t.Meta()) {
+			handleChanged = true
+		}
+	}
+	if err = table.CastValues(ctx, newRow, cols, false); err != nil {
+		return errors.Trace(err)
+	}
+	if err = table.CheckNotNull(cols, newRow); err != nil {
+		return errors.Trace(err)
+	}
+	dirtyDB := getDirtyDB(ctx)
+	tid := t.Meta().ID
+	if handleChanged {
+		if err = t.RemoveRecord(ctx, h, oldRow); err != nil {
+			return errors.Trace(err)
+		}
+		dirtyDB.deleteRow(tid, h)
+		if h, err = t.AddRecord(ctx, newRow); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		if err = t.UpdateRecord(ctx, h, oldRow, newRow, touched); err != nil {
+			return errors.Trace(err)
+		}
+		dirtyDB.deleteRow(tid, h)
+	}
+	dirtyDB.addRow(tid, h, newRow)
+	return errors.Trace(c.onUpdate(ctx, t, oldRow, newRow, depth))
+}
+
+// describe returns the description of the foreign key used in the error messages.
+func (c *foreignKeyChecker) describe(child *childForeignKey) string {
+	tblInfo := child.tbl.Meta()
+	return describeForeignKey(c.dbNames[tblInfo.ID], tblInfo.Name, child.fk)
+}
+
+// fetchHandles returns the handles of the child rows referencing the parent values vals.
+func (child *childForeignKey) fetchHandles(ctx context.Context, vals []types.Datum) ([]int64, error) {
+	cols, err := table.FindCols(child.tbl.Cols(), ciStrsToStrings(child.fk.Cols))
+	if err != nil {
+		return nil, nil
+	}
+	handles, err := fetchMatchedHandles(ctx, child.tbl, cols, vals, 0)
+	return handles, errors.Trace(err)
+}
+
+// describeForeignKey formats the foreign key of the table dbName.tblName like the MySQL foreign key errors.
+func describeForeignKey(dbName, tblName model.CIStr, fk *model.FKInfo) string {
+	desc := fmt.Sprintf("`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (`%s`) REFERENCES `%s` (`%s`)", dbName.O, tblName.O,
+		fk.Name.O, strings.Join(ciStrsToStrings(fk.Cols), "`, `"), fk.RefTable.O, strings.Join(ciStrsToStrings(fk.RefCols), "`, `"))
+	if ast.ReferOptionType(fk.OnDelete) != ast.ReferOptionNoOption {
+		desc += fmt.Sprintf(" ON DELETE %s", ast.ReferOptionType(fk.OnDelete))
+	}
+	if ast.ReferOptionType(fk.OnUpdate) != ast.ReferOptionNoOption {
+		desc += fmt.Sprintf(" ON UPDATE %s", ast.ReferOptionType(fk.OnUpdate))
+	}
+	return desc
+}
+
+func ciStrsToStrings(names []model.CIStr) []string {
+	strs := make([]string, 0, len(names))
+	for _, name := range names {
+		strs = append(strs, name.O)
+	}
+	return strs
+}
+
+// columnValues returns the values of the named columns of t in row, it returns nil if any value
+// is NULL or any column doesn't exist, such a row is not constrained by the foreign key.
+func columnValues(t table.Table, names []model.CIStr, row []types.Datum) []types.Datum {
+	vals := make([]types.Datum, 0, len(names))
+	for _, name := range names {
+		col := table.FindCol(t.Cols(), name.O)
+		if col == nil || row[col.Offset].IsNull() {
+			return nil
+		}
+		vals = append(vals, row[col.Offset])
+	}
+	return vals
+}
+
+// columnsChanged returns whether any of the named columns of t differs between oldRow and newRow.
+func columnsChanged(ctx context.Context, t table.Table, names []model.CIStr, oldRow, newRow []types.Datum) (bool, error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	for _, name := range names {
+		col := table.FindCol(t.Cols(), name.O)
+		if col == nil {
+			continue
+		}
+		oldVal, newVal := oldRow[col.Offset], newRow[col.Offset]
+		if oldVal.IsNull() != newVal.IsNull() {
+			return true, nil
+		}
+		cmp, err := oldVal.CompareDatum(sc, newVal)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rowMatches returns whether the columns cols of row equal vals.
+func rowMatches(ctx context.Context, cols []*table.Column, row []types.Datum, vals []types.Datum) (bool, error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	for i, col := range cols {
+		d := row[col.Offset]
+		if d.IsNull() {
+			return false, nil
+		}
+		cmp, err := d.CompareDatum(sc, vals[i])
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// fetchMatchedHandles returns the handles of the rows of t whose columns cols equal vals, at most limit
+// handles are returned if limit is positive. The rows are looked up by the integer primary key or
+// an index whose leading columns are cols if possible, otherwise the table is scanned.
+func fetchMatchedHandles(ctx context.Context, t table.Table, cols []*table.Column, vals []types.Datum, limit int) ([]int64, error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	converted := make([]types.Datum, len(vals))
+	for i, col := range cols {
+		d, err := vals[i].ConvertTo(sc, &col.FieldType)
+		if err != nil {
+			// The value can't be stored in the column, so no row has it.
+			return nil, nil
+		}
+		converted[i] = d
+	}
+
+	tblInfo := t.Meta()
+	if len(cols) == 1 && cols[0].IsPKHandleColumn(tblInfo) {
+		h := converted[0].GetInt64()
+		_, err := t.Row(ctx, h)
+		if err != nil {
+			if terror.ErrorEqual(err, kv.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, errors.Trace(err)
+		}
+		return []int64{h}, nil
+	}
+	// The values of the columns with a case-insensitive collation are compared as folded strings.
+	for i, col := range cols {
+		table.FoldIndexValue(col.ToInfo(), &converted[i])
+	}
+	if idxInfo := findIndexByColumns(t, cols); idxInfo != nil {
+		handles, err := fetchHandlesByIndex(ctx, tblInfo.ID, idxInfo, converted, limit)
+		return handles, errors.Trace(err)
+	}
+
+	var handles []int64
+	err := t.IterRecords(ctx, t.FirstKey(), cols, func(h int64, data []types.Datum, _ []*table.Column) (bool, error) {
+		for i, d := range data {
+			if d.IsNull() {
+				return true, nil
+			}
+			table.FoldIndexValue(cols[i].ToInfo(), &d)
+			cmp, err := d.CompareDatum(sc, converted[i])
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			if cmp != 0 {
+				return true, nil
+			}
+		}
+		handles = append(handles, h)
+		return limit <= 0 || len(handles) < limit, nil
+	})
+	return handles, errors.Trace(err)
+}
+
+// findIndexByColumns finds a public index of t whose leading columns are cols without prefix lengths.
+func findIndexByColumns(t table.Table, cols []*table.Column) *model.IndexInfo {
+	for _, idx := range t.Indices() {
+		idxInfo := idx.Meta()
+		if idxInfo.State != model.StatePublic || len(idxInfo.Columns) < len(cols) {
+			continue
+		}
+		matched := true
+		for i, col := range cols {
+			idxCol := idxInfo.Columns[i]
+			if idxCol.Name.L != col.Name.L || idxCol.Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return idxInfo
+		}
+	}
+	return nil
+}
+
+// fetchHandlesByIndex scans the index entries whose leading values are vals and returns their handles.
+func fetchHandlesByIndex(ctx context.Context, tableID int64, idxInfo *model.IndexInfo, vals []types.Datum, limit int) ([]int64, error) {
+	encoded, err := codec.EncodeKey(nil, vals...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seekKey := tablecodec.EncodeIndexSeekKey(tableID, idxInfo.ID, encoded)
+	it, err := ctx.Txn().Seek(seekKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer it.Close()
+
+	var handles []int64
+	for it.Valid() && it.Key().HasPrefix(seekKey) {
+		var rest []types.Datum
+		if len(it.Key()) > len(seekKey) {
+			rest, err = codec.Decode(it.Key()[len(seekKey):], len(idxInfo.Columns)-len(vals)+1)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if len(vals)+len(rest) > len(idxInfo.Columns) {
+			// The handle is appended to the key of a non-distinct index entry.
+			handles = append(handles, rest[len(rest)-1].GetInt64())
+		} else {
+			handles = append(handles, int64(binary.BigEndian.Uint64(it.Value())))
+		}
+		if limit > 0 && len(handles) >= limit {
+			break
+		}
+		if err = it.Next(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return handles, nil
+}
@@ -112,21 +112,13 @@ func (e *GrantExec) Close() error {
 	return nil
 }
 
-// Check if DB scope privilege entry exists in mysql.DB.
+// Make sure DB scope privilege entry exists in mysql.DB.
 // If unexists, insert a new one.
 func (e *GrantExec) checkAndInitDBPriv(user string, host string) error {
 	db, err := e.getTargetSchema()
 	if err != nil {
 		return errors.Trace(err)
 	}
-	ok, err := dbUserExists(e.ctx, user, host, db.Name.O)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	if ok {
-		return nil
-	}
-	// Entry does not exist for user-host-db. Insert a new entry.
 	return initDBPrivEntry(e.ctx, user, host, db.Name.O)
 }
 
@@ -176,9 +168,9 @@ func (e *GrantExec) checkAndInitColumnPriv(user string, host string, cols []*ast
 	return nil
 }
 
-// Insert a new row into mysql.DB with empty privilege.
+// Insert a new row into mysql.DB with empty privilege, the existing row for user-host-db is kept as it is.
 func initDBPrivEntry(ctx context.Context, user string, host string, db string) error {
-	sql := fmt.Sprintf(`INSERT INTO %s.%s (Host, User, DB) VALUES ("%s", "%s", "%s")`, mysql.SystemDB, mysql.DBTable, host, user, db)
+	sql := fmt.Sprintf(`INSERT INTO %s.%s (Host, User, DB) VALUES ("%s", "%s", "%s") ON DUPLICATE KEY UPDATE Host=VALUES(Host)`, mysql.SystemDB, mysql.DBTable, host, user, db)
 	_, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, sql)
 	return errors.Trace(err)
 }
@@ -420,12 +412,6 @@ func recordExists(ctx context.Context, sql string) (bool, error) {
 	return row != nil, nil
 }
 
-// Check if there is an entry with key user-host-db in mysql.DB.
-func dbUserExists(ctx context.Context, name string, host string, db string) (bool, error) {
-	sql := fmt.Sprintf(`SELECT * FROM %s.%s WHERE User="%s" AND Host="%s" AND DB="%s";`, mysql.SystemDB, mysql.DBTable, name, host, db)
-	return recordExists(ctx, sql)
-}
-
 // Check if there is an entry with key user-host-db-tbl in mysql.Tables_priv.
 func tableUserExists(ctx context.Context, name string, host string, db string, tbl string) (bool, error) {
 	sql := fmt.Sprintf(`SELECT * FROM %s.%s WHERE User="%s" AND Host="%s" AND DB="%s" AND Table_name="%s";`, mysql.SystemDB, mysql.TablePrivTable, name, host, db, tbl)
@@ -18,6 +18,8 @@ import (
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb"
+	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
@@ -53,6 +55,9 @@ func (s *testSuite) TestGrantGlobal(c *C) {
 		sql := fmt.Sprintf("SELECT %s FROM mysql.User WHERE User=\"testGlobal1\" and host=\"localhost\"", mysql.Priv2UserCol[v])
 		tk.MustQuery(sql).Check(testkit.Rows("Y"))
 	}
+
+	// Global level grants must not create any DB scope privilege entry.
+	tk.MustQuery(`SELECT * FROM mysql.DB WHERE User="testGlobal" OR User="testGlobal1"`).Check(testkit.Rows())
 }
 
 func (s *testSuite) TestGrantDBScope(c *C) {
@@ -72,6 +77,8 @@ func (s *testSuite) TestGrantDBScope(c *C) {
 		sql = fmt.Sprintf("SELECT %s FROM mysql.DB WHERE User=\"testDB\" and host=\"localhost\" and db=\"test\"", mysql.Priv2UserCol[v])
 		tk.MustQuery(sql).Check(testkit.Rows("Y"))
 	}
+	// The privileges are granted to the same row, which keeps the privileges granted before.
+	tk.MustQuery("SELECT count(*), Select_priv, Drop_priv FROM mysql.DB WHERE User=\"testDB\" and host=\"localhost\" and db=\"test\"").Check(testkit.Rows("1 Y Y"))
 
 	// Create a new user.
 	createUserSQL = `CREATE USER 'testDB1'@'localhost' IDENTIFIED BY '123';`
@@ -164,3 +171,72 @@ func (s *testSuite) TestColumnScope(c *C) {
 		c.Assert(strings.Index(p, mysql.Priv2SetStr[v]), Greater, -1)
 	}
 }
+
+func (s *testSuite) TestDropDatabasePrivileges(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'testDropDB'@'localhost' IDENTIFIED BY '123';`)
+	tk.MustExec("CREATE DATABASE DropPriv;")
+	tk.MustExec("CREATE TABLE DropPriv.t(c1 int);")
+	tk.MustExec("GRANT SELECT ON DropPriv.* TO 'testDropDB'@'localhost';")
+	tk.MustExec("GRANT SELECT ON DropPriv.t TO 'testDropDB'@'localhost';")
+	tk.MustExec("GRANT SELECT(c1) ON DropPriv.t TO 'testDropDB'@'localhost';")
+	tk.MustExec("GRANT SELECT ON test.* TO 'testDropDB'@'localhost';")
+
+	tk.MustExec("DROP DATABASE droppriv;")
+	tk.MustQuery(`SELECT * FROM mysql.DB WHERE User="testDropDB" and DB="DropPriv"`).Check(testkit.Rows())
+	tk.MustQuery(`SELECT count(*) FROM mysql.DB WHERE User="testDropDB" and DB="test"`).Check(testkit.Rows("1"))
+	tk.MustQuery(`SELECT * FROM mysql.Tables_priv WHERE User="testDropDB"`).Check(testkit.Rows())
+	tk.MustQuery(`SELECT * FROM mysql.Columns_priv WHERE User="testDropDB"`).Check(testkit.Rows())
+
+	tk.MustExec("DROP DATABASE IF EXISTS DropPriv;")
+	_, err := tk.Exec("DROP DATABASE DropPriv;")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite) TestPrivilegeViews(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'testViews'@'localhost' IDENTIFIED BY '123';`)
+	tk.MustQuery(`SELECT * FROM information_schema.user_privileges WHERE grantee = "'testViews'@'localhost'"`).Check(testkit.Rows(
+		"'testViews'@'localhost' def USAGE NO"))
+
+	tk.MustExec("GRANT SELECT, CREATE USER ON *.* TO 'testViews'@'localhost';")
+	tk.MustQuery(`SELECT privilege_type, is_grantable FROM information_schema.user_privileges WHERE grantee = "'testViews'@'localhost'"`).Check(testkit.Rows(
+		"SELECT NO", "CREATE USER NO"))
+	tk.MustExec("GRANT GRANT OPTION ON *.* TO 'testViews'@'localhost';")
+	tk.MustQuery(`SELECT privilege_type, is_grantable FROM information_schema.user_privileges WHERE grantee = "'testViews'@'localhost'"`).Check(testkit.Rows(
+		"SELECT YES", "CREATE USER YES"))
+
+	tk.MustExec("GRANT INSERT, UPDATE ON test.* TO 'testViews'@'localhost';")
+	tk.MustQuery(`SELECT * FROM information_schema.schema_privileges WHERE grantee = "'testViews'@'localhost'"`).Check(testkit.Rows(
+		"'testViews'@'localhost' def test INSERT NO", "'testViews'@'localhost' def test UPDATE NO"))
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists views_t")
+	tk.MustExec("create table views_t (a int)")
+	tk.MustExec("GRANT SELECT, DELETE ON test.views_t TO 'testViews'@'localhost';")
+	tk.MustQuery(`SELECT * FROM information_schema.table_privileges WHERE grantee = "'testViews'@'localhost'"`).Check(testkit.Rows(
+		"'testViews'@'localhost' def test views_t SELECT NO", "'testViews'@'localhost' def test views_t DELETE NO"))
+
+	// A user who can't read the mysql schema only sees the privileges of its own account.
+	tk.MustExec(`CREATE USER 'testViews1'@'localhost' IDENTIFIED BY '123';`)
+	tk.MustExec("GRANT INSERT ON test.* TO 'testViews1'@'localhost';")
+	se, err := tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.Se = se
+	se.(context.Context).GetSessionVars().User = "testViews1@localhost"
+	tk1.MustQuery("SELECT DISTINCT grantee FROM information_schema.user_privileges").Check(testkit.Rows("'testViews1'@'localhost'"))
+	tk1.MustQuery("SELECT grantee, table_schema, privilege_type FROM information_schema.schema_privileges").Check(testkit.Rows(
+		"'testViews1'@'localhost' test INSERT"))
+	tk1.MustQuery("SELECT * FROM information_schema.table_privileges").Check(testkit.Rows())
+	// With the SELECT privilege on the mysql schema, the privileges of all the users are visible.
+	tk.MustExec("GRANT SELECT ON mysql.* TO 'testViews1'@'localhost';")
+	se, err = tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk1.Se = se
+	se.(context.Context).GetSessionVars().User = "testViews1@localhost"
+	tk1.MustQuery(`SELECT privilege_type FROM information_schema.table_privileges WHERE grantee = "'testViews'@'localhost'"`).Check(testkit.Rows(
+		"SELECT", "DELETE"))
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+
+	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/plan"
+	"github.com/pingcap/tidb/util/types"
+)
+
+// PlanCache caches the plans of the statements executed by a session, so executing
+// the same statement again skips parsing, preprocessing and optimizing.
+// A statement is keyed on its normalized text, which has the literal values stripped, together with
+// the literal values, because the optimizer folds them into the plan, e.g. the ranges of an index scan.
+// The least recently used plan is evicted when the cache is full.
+type PlanCache struct {
+	capacity int
+	elements map[string]*list.Element
+	lru      *list.List
+}
+
+// CachedPlan is a plan kept in the PlanCache.
+type CachedPlan struct {
+	key string
+	// Stmt is the statement the plan is built from, it must not be compiled again.
+	Stmt          ast.StmtNode
+	plan          plan.Plan
+	schemaVersion int64
+}
+
+// NewPlanCache creates a PlanCache which holds at most capacity plans.
+func NewPlanCache(capacity int) *PlanCache {
+	return &PlanCache{
+		capacity: capacity,
+		elements: make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Capacity returns the maximum number of plans in the cache.
+func (c *PlanCache) Capacity() int {
+	return c.capacity
+}
+
+// Len returns the number of plans in the cache.
+func (c *PlanCache) Len() int {
+	return c.lru.Len()
+}
+
+// Key returns the cache key of sql, it is empty if sql can not be cached.
+func (c *PlanCache) Key(ctx context.Context, sql string) string {
+	text, values, ok := parser.Normalize(sql)
+	if !ok {
+		return ""
+	}
+	vars := ctx.GetSessionVars()
+	charset, collation := vars.GetCharsetInfo()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\x00%s\x00%s\x00%d\x00%d\x00%s", vars.CurrentDB, charset, collation, vars.SQLMode,
+		vars.DivPrecisionIncrement, text)
+	for _, value := range values {
+		d := types.NewDatum(value)
+		s, err := d.ToString()
+		if err != nil {
+			return ""
+		}
+		fmt.Fprintf(&buf, "\x00%d%q", d.Kind(), s)
+	}
+	return buf.String()
+}
+
+// Get returns the plan cached for key. A plan built with an old schema is evicted,
+// so the InfoSchema of the transaction context must be set before calling Get.
+func (c *PlanCache) Get(ctx context.Context, key string) *CachedPlan {
+	element, ok := c.elements[key]
+	if !ok || !txnReadOnly(ctx) {
+		return nil
+	}
+	cached := element.Value.(*CachedPlan)
+	if cached.schemaVersion != GetInfoSchema(ctx).SchemaMetaVersion() {
+		c.remove(element)
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return cached
+}
+
+// Put caches the plan of st, which is compiled from node.
+func (c *PlanCache) Put(ctx context.Context, key string, node ast.StmtNode, st ast.Statement) {
+	sa, ok := st.(*statement)
+	if !ok || !txnReadOnly(ctx) {
+		return
+	}
+	if element, ok := c.elements[key]; ok {
+		c.remove(element)
+	}
+	cached := &CachedPlan{
+		key:           key,
+		Stmt:          node,
+		plan:          sa.plan,
+		schemaVersion: sa.is.SchemaMetaVersion(),
+	}
+	c.elements[key] = c.lru.PushFront(cached)
+	if c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+	}
+}
+
+// txnReadOnly checks the transaction has no pending writes. The plans are only cached and reused
+// in this state, because the plans built after a write read the pending writes by a union scan.
+func txnReadOnly(ctx context.Context) bool {
+	txn := ctx.Txn()
+	return txn == nil || txn.IsReadOnly()
+}
+
+func (c *PlanCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.elements, element.Value.(*CachedPlan).key)
+}
+
+// Statement returns an ast.Statement which executes the cached plan.
+func (cached *CachedPlan) Statement(ctx context.Context, text string) ast.Statement {
+	stmtCount(cached.Stmt)
+	return &statement{
+		is:   GetInfoSchema(ctx),
+		plan: cached.plan,
+		text: text,
+	}
+}
+
+// IsPlanCacheable checks whether the plan of node can be reused by the later executions.
+// It must be called before node is compiled.
+func IsPlanCacheable(node ast.StmtNode) bool {
+	switch node.(type) {
+	case *ast.SelectStmt, *ast.UnionStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return false
+	}
+	checker := cacheableChecker{cacheable: true}
+	node.Accept(&checker)
+	return checker.cacheable
+}
+
+// timeDependentFuncs are the functions evaluated when a plan is built, whose results change over time.
+var timeDependentFuncs = map[string]struct{}{
+	ast.Curdate:          {},
+	ast.CurrentDate:      {},
+	ast.CurrentTime:      {},
+	ast.CurrentTimestamp: {},
+	ast.Curtime:          {},
+	ast.Now:              {},
+	ast.Sysdate:          {},
+	ast.UTCDate:          {},
+	ast.GetLock:          {},
+	ast.ReleaseLock:      {},
+}
+
+// cacheableChecker checks a statement has nothing evaluated when it is optimized,
+// like the uncorrelated subqueries, the system variables and the current time.
+type cacheableChecker struct {
+	cacheable bool
+}
+
+// Enter implements Visitor interface.
+func (c *cacheableChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr, *ast.ParamMarkerExpr, *ast.DefaultExpr:
+		c.cacheable = false
+	case *ast.VariableExpr:
+		if x.IsSystem {
+			c.cacheable = false
+		}
+	case *ast.FuncCallExpr:
+		if _, ok := timeDependentFuncs[x.FnName.L]; ok {
+			c.cacheable = false
+		}
+	}
+	return in, !c.cacheable
+}
+
+// Leave implements Visitor interface.
+func (c *cacheableChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.cacheable
+}
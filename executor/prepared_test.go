@@ -54,6 +54,22 @@ func (s *testSuite) TestPrepared(c *C) {
 	result := tk.MustQuery("select distinct c1, c2 from prepare_test where c1 = ?", 1)
 	result.Check([][]interface{}{{1, nil}})
 
+	// Bind user variables into the placeholders and execute again with new values.
+	tk.MustExec(`prepare stmt_test_6 from 'select id, c1 from prepare_test where id > ? and c1 < ?'`)
+	tk.MustExec("set @a = 0, @b = 2")
+	tk.MustQuery("execute stmt_test_6 using @a, @b").Check(testkit.Rows("1 1"))
+	tk.MustExec("set @a = 1, @b = 3")
+	tk.MustQuery("execute stmt_test_6 using @a, @b").Check(testkit.Rows("2 2"))
+	_, err = tk.Exec("execute stmt_test_6 using @a")
+	c.Assert(executor.ErrWrongParamCount.Equal(err), IsTrue)
+
+	// Prepared statements are stored per session.
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	_, err = tk2.Exec("set @a = 0, @b = 2; execute stmt_test_6 using @a, @b")
+	c.Assert(executor.ErrStmtNotFound.Equal(err), IsTrue)
+	tk.MustExec("deallocate prepare stmt_test_6")
+
 	// Call Session PrepareStmt directly to get stmtId.
 	stmtId, _, _, err := tk.Se.PrepareStmt("select c1, c2 from prepare_test where c1 = ?")
 	c.Assert(err, IsNil)
@@ -0,0 +1,197 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/util/types"
+)
+
+// SecureFilePriv is the directory that SELECT ... INTO OUTFILE and LOAD DATA INFILE without LOCAL
+// can access files in. If it is empty, both statements are disabled.
+var SecureFilePriv string
+
+// checkFileAccess checks that the current user has the FILE privilege and fileName is in SecureFilePriv.
+func checkFileAccess(ctx context.Context, fileName string) error {
+	hasFilePriv, err := hasGlobalPriv(ctx, mysql.FilePriv)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !hasFilePriv {
+		return privilegeDenied(ctx, ErrSpecificAccessDenied.GenByArgs("FILE"))
+	}
+	if len(SecureFilePriv) == 0 {
+		return ErrOptionPreventsStatement.GenByArgs("--secure-file-priv")
+	}
+	dir, err := filepath.Abs(SecureFilePriv)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	path, err := filepath.Abs(fileName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ErrOptionPreventsStatement.GenByArgs("--secure-file-priv")
+	}
+	return nil
+}
+
+// SelectIntoExec represents a select into outfile executor, it writes the rows of the select statement
+// to a file instead of returning them to the client.
+// See https://dev.mysql.com/doc/refman/5.7/en/select-into.html
+type SelectIntoExec struct {
+	ctx     context.Context
+	src     Executor
+	IntoOpt *ast.SelectIntoOption
+	done    bool
+}
+
+// Schema implements the Executor Schema interface.
+func (e *SelectIntoExec) Schema() expression.Schema {
+	return expression.NewSchema(nil)
+}
+
+// Next implements the Executor Next interface.
+func (e *SelectIntoExec) Next() (*Row, error) {
+	if e.done {
+		return nil, nil
+	}
+	e.done = true
+	if err := checkFileAccess(e.ctx, e.IntoOpt.FileName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Like MySQL, an existing file is never overwritten.
+	f, err := os.OpenFile(e.IntoOpt.FileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, ErrFileExists.GenByArgs(e.IntoOpt.FileName)
+		}
+		return nil, errors.Trace(err)
+	}
+	cnt, err := e.writeRows(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a partially written file behind.
+		os.Remove(e.IntoOpt.FileName)
+		return nil, errors.Trace(err)
+	}
+	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(cnt)
+	return nil, nil
+}
+
+// writeRows writes all the rows of the source executor to f, and returns the number of rows written.
+func (e *SelectIntoExec) writeRows(f *os.File) (uint64, error) {
+	w := bufio.NewWriter(f)
+	var (
+		buf []byte
+		cnt uint64
+	)
+	for {
+		row, err := e.src.Next()
+		if err != nil {
+			return cnt, errors.Trace(err)
+		}
+		if row == nil {
+			break
+		}
+		buf, err = e.encodeRow(buf[:0], row.Data)
+		if err != nil {
+			return cnt, errors.Trace(err)
+		}
+		if _, err = w.Write(buf); err != nil {
+			return cnt, errors.Trace(err)
+		}
+		cnt++
+	}
+	return cnt, errors.Trace(w.Flush())
+}
+
+// encodeRow appends a row in the format specified by the FIELDS and LINES clauses to buf.
+// NULL is written as the escape character followed by 'N', or NULL if the escape character is empty.
+func (e *SelectIntoExec) encodeRow(buf []byte, data []types.Datum) ([]byte, error) {
+	fields, lines := e.IntoOpt.FieldsInfo, e.IntoOpt.LinesInfo
+	buf = append(buf, lines.Starting...)
+	for i, d := range data {
+		if i > 0 {
+			buf = append(buf, fields.Terminated...)
+		}
+		if d.IsNull() {
+			if fields.Escaped != 0 {
+				buf = append(buf, fields.Escaped, 'N')
+			} else {
+				buf = append(buf, "NULL"...)
+			}
+			continue
+		}
+		str, err := d.ToString()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fields.Enclosed != 0 {
+			buf = append(buf, fields.Enclosed)
+		}
+		buf = e.escape(buf, str)
+		if fields.Enclosed != 0 {
+			buf = append(buf, fields.Enclosed)
+		}
+	}
+	buf = append(buf, lines.Terminated...)
+	return buf, nil
+}
+
+// escape appends str to buf, the escape character is prefixed to the escape character itself,
+// the enclosed character, ASCII NUL (written as '0') and, if fields are not enclosed, the first
+// characters of the fields and lines terminators.
+func (e *SelectIntoExec) escape(buf []byte, str string) []byte {
+	fields, lines := e.IntoOpt.FieldsInfo, e.IntoOpt.LinesInfo
+	if fields.Escaped == 0 {
+		return append(buf, str...)
+	}
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case c == 0:
+			buf = append(buf, fields.Escaped, '0')
+			continue
+		case c == fields.Escaped:
+		case fields.Enclosed != 0 && c == fields.Enclosed:
+		case fields.Enclosed == 0 && len(fields.Terminated) > 0 && c == fields.Terminated[0]:
+		case fields.Enclosed == 0 && len(lines.Terminated) > 0 && c == lines.Terminated[0]:
+		default:
+			buf = append(buf, c)
+			continue
+		}
+		buf = append(buf, fields.Escaped, c)
+	}
+	return buf
+}
+
+// Close implements the Executor Close interface.
+func (e *SelectIntoExec) Close() error {
+	e.done = false
+	return e.src.Close()
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func (s *testSuite) TestSelectIntoOutfile(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	dir, err := ioutil.TempDir("", "select_into")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(20), c double)")
+	tk.MustExec(`insert into t values (1, 'x\ty', 1.5), (2, null, null), (3, 'a"b\\c\nd', 2)`)
+
+	// SELECT INTO OUTFILE is disabled if secure_file_priv is not set.
+	_, err = tk.Exec("select * from t into outfile '" + filepath.Join(dir, "disabled.txt") + "'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrOptionPreventsStatement), IsTrue)
+	defer func(old string) { executor.SecureFilePriv = old }(executor.SecureFilePriv)
+	executor.SecureFilePriv = dir
+	// Files out of the secure_file_priv directory can't be written.
+	_, err = tk.Exec("select * from t into outfile '" + filepath.Join(dir, "..", "outside.txt") + "'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrOptionPreventsStatement), IsTrue)
+
+	// The default format is tab separated fields and newline terminated lines, NULL is written as \N.
+	outfile := filepath.Join(dir, "default.txt")
+	tk.MustExec("select * from t into outfile '" + outfile + "'")
+	tk.CheckExecResult(3, 0)
+	content, err := ioutil.ReadFile(outfile)
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals, "1\tx\\\ty\t1.5\n2\t\\N\t\\N\n3\ta\"b\\\\c\\\nd\t2\n")
+
+	outfile = filepath.Join(dir, "csv.txt")
+	tk.MustExec("select a, b from t where a > 1 into outfile '" + outfile + `' fields terminated by ',' enclosed by '"' lines starting by '>' terminated by '\r\n'`)
+	tk.CheckExecResult(2, 0)
+	content, err = ioutil.ReadFile(outfile)
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals, ">\"2\",\\N\r\n>\"3\",\"a\\\"b\\\\c\nd\"\r\n")
+
+	// Nothing is escaped with an empty escape character, and NULL is written as NULL.
+	outfile = filepath.Join(dir, "noescape.txt")
+	tk.MustExec("select b from t into outfile '" + outfile + "' fields escaped by ''")
+	content, err = ioutil.ReadFile(outfile)
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals, "x\ty\nNULL\na\"b\\c\nd\n")
+
+	// An existing file is not overwritten.
+	_, err = tk.Exec("select * from t into outfile '" + outfile + "'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrFileExists), IsTrue)
+
+	// A partially written file is removed if the statement fails.
+	outfile = filepath.Join(dir, "failed.txt")
+	_, err = tk.Exec("select (select a from t t2 where t2.a > t1.a) from t t1 into outfile '" + outfile + "'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSubqueryNo1Row), IsTrue)
+	_, err = os.Stat(outfile)
+	c.Assert(os.IsNotExist(err), IsTrue)
+
+	// The FILE privilege is required.
+	tk.MustExec("create user 'file_user'@'localhost'")
+	defer tk.MustExec("drop user 'file_user'@'localhost'")
+	tk.Se, err = tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk.Se.(context.Context).GetSessionVars().User = "file_user@localhost"
+	_, err = tk.Exec("select * from test.t into outfile '" + filepath.Join(dir, "nopriv.txt") + "'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSpecificAccessDenied), IsTrue)
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/ast"
@@ -26,10 +28,14 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/plan"
+	"github.com/pingcap/tidb/plan/statistics"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessionctx/varsutil"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
@@ -89,6 +95,8 @@ func (e *ShowExec) fetchAll() error {
 		return e.fetchShowCreateTable()
 	case ast.ShowCreateDatabase:
 		return e.fetchShowCreateDatabase()
+	case ast.ShowCreateView:
+		return e.fetchShowCreateView()
 	case ast.ShowDatabases:
 		return e.fetchShowDatabases()
 	case ast.ShowEngines:
@@ -109,12 +117,44 @@ func (e *ShowExec) fetchAll() error {
 		return e.fetchShowTriggers()
 	case ast.ShowVariables:
 		return e.fetchShowVariables()
-	case ast.ShowWarnings, ast.ShowProcessList, ast.ShowEvents:
+	case ast.ShowProcessList:
+		return e.fetchShowProcessList()
+	case ast.ShowWarnings:
+		return e.fetchShowWarnings(false)
+	case ast.ShowErrors:
+		return e.fetchShowWarnings(true)
+	case ast.ShowEvents:
 		// empty result
 	}
 	return nil
 }
 
+// fetchShowWarnings fetches the diagnostics of the previous statement, if errOnly is true,
+// only the errors are returned.
+func (e *ShowExec) fetchShowWarnings(errOnly bool) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	if !errOnly {
+		for _, warn := range sc.GetWarnings() {
+			e.appendDiagnostic("Warning", warn)
+		}
+	}
+	for _, err := range sc.GetErrors() {
+		e.appendDiagnostic("Error", err)
+	}
+	return nil
+}
+
+func (e *ShowExec) appendDiagnostic(level string, err error) {
+	var sqlErr *mysql.SQLError
+	if te, ok := errors.Cause(err).(*terror.Error); ok {
+		sqlErr = te.ToSQLError()
+	} else {
+		sqlErr = mysql.NewErrf(mysql.ErrUnknown, "%s", errors.Cause(err).Error())
+	}
+	row := &Row{Data: types.MakeDatums(level, int64(sqlErr.Code), sqlErr.Message)}
+	e.rows = append(e.rows, row)
+}
+
 func (e *ShowExec) fetchShowEngines() error {
 	row := &Row{
 		Data: types.MakeDatums(
@@ -141,21 +181,28 @@ func (e *ShowExec) fetchShowDatabases() error {
 }
 
 func (e *ShowExec) fetchShowTables() error {
+	if e.DBName.L == "" {
+		return ErrNoDB
+	}
 	if !e.is.SchemaExists(e.DBName) {
-		return errors.Errorf("Can not find DB: %s", e.DBName)
+		return infoschema.ErrDatabaseNotExists.GenByArgs(e.DBName)
 	}
 	// sort for tables
 	var tableNames []string
+	tableTypes := make(map[string]string)
 	for _, v := range e.is.SchemaTables(e.DBName) {
 		tableNames = append(tableNames, v.Meta().Name.O)
+		if v.Meta().IsView() {
+			tableTypes[v.Meta().Name.O] = "VIEW"
+		} else {
+			tableTypes[v.Meta().Name.O] = "BASE TABLE"
+		}
 	}
 	sort.Strings(tableNames)
 	for _, v := range tableNames {
 		data := types.MakeDatums(v)
 		if e.Full {
-			// TODO: support "VIEW" later if we have supported view feature.
-			// now, just use "BASE TABLE".
-			data = append(data, types.NewDatum("BASE TABLE"))
+			data = append(data, types.NewDatum(tableTypes[v]))
 		}
 		e.rows = append(e.rows, &Row{Data: data})
 	}
@@ -163,8 +210,11 @@ func (e *ShowExec) fetchShowTables() error {
 }
 
 func (e *ShowExec) fetchShowTableStatus() error {
+	if e.DBName.L == "" {
+		return ErrNoDB
+	}
 	if !e.is.SchemaExists(e.DBName) {
-		return errors.Errorf("Can not find DB: %s", e.DBName)
+		return infoschema.ErrDatabaseNotExists.GenByArgs(e.DBName)
 	}
 
 	// sort for tables
@@ -228,6 +278,7 @@ func (e *ShowExec) fetchShowIndex() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	statsTbl := plan.GetStatisticsTable(e.ctx, tb.Meta())
 	if tb.Meta().PKIsHandle {
 		var pkCol *table.Column
 		for _, col := range tb.Cols() {
@@ -236,6 +287,7 @@ func (e *ShowExec) fetchShowIndex() error {
 				break
 			}
 		}
+		cardinality := indexCardinality(statsTbl, nil, true)
 		data := types.MakeDatums(
 			tb.Meta().Name.O, // Table
 			0,                // Non_unique
@@ -243,7 +295,7 @@ func (e *ShowExec) fetchShowIndex() error {
 			1,                // Seq_in_index
 			pkCol.Name.O,     // Column_name
 			"utf8_bin",       // Colation
-			0,                // Cardinality
+			cardinality,      // Cardinality
 			nil,              // Sub_part
 			nil,              // Packed
 			"",               // Null
@@ -263,6 +315,9 @@ func (e *ShowExec) fetchShowIndex() error {
 			if col.Length != types.UnspecifiedLength {
 				subPart = col.Length
 			}
+			// The prefix of a unique index is unique only if it has all the index columns.
+			unique := idx.Meta().Unique && i == len(idx.Meta().Columns)-1
+			cardinality := indexCardinality(statsTbl, idx.Meta().Columns[:i+1], unique)
 			data := types.MakeDatums(
 				tb.Meta().Name.O,  // Table
 				nonUniq,           // Non_unique
@@ -270,7 +325,7 @@ func (e *ShowExec) fetchShowIndex() error {
 				i+1,               // Seq_in_index
 				col.Name.O,        // Column_name
 				"utf8_bin",        // Colation
-				0,                 // Cardinality
+				cardinality,       // Cardinality
 				subPart,           // Sub_part
 				nil,               // Packed
 				"YES",             // Null
@@ -284,6 +339,116 @@ func (e *ShowExec) fetchShowIndex() error {
 	return nil
 }
 
+// indexCardinality estimates the number of distinct values of the index column prefix from the
+// statistics, it returns nil if the table has never been analyzed.
+func indexCardinality(statsTbl *statistics.Table, cols []*model.IndexColumn, unique bool) interface{} {
+	if statsTbl.Pseudo {
+		return nil
+	}
+	if unique {
+		return statsTbl.Count
+	}
+	cardinality := int64(1)
+	for _, col := range cols {
+		cardinality *= statsTbl.Columns[col.Offset].NDV
+		if cardinality >= statsTbl.Count {
+			return statsTbl.Count
+		}
+	}
+	return cardinality
+}
+
+// sessionManagerKeyType is a dummy type to avoid naming collision in context.
+type sessionManagerKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k sessionManagerKeyType) String() string {
+	return "session_manager"
+}
+
+// SessionManagerKey is a variable key for the session manager which lists the connected sessions.
+const SessionManagerKey sessionManagerKeyType = 0
+
+// hasGlobalPriv checks whether the current user has the global privilege priv.
+func hasGlobalPriv(ctx context.Context, priv mysql.PrivilegeType) (bool, error) {
+	checker := privilege.GetPrivilegeChecker(ctx)
+	if checker == nil {
+		return true, nil
+	}
+	ok, err := checker.Check(ctx, nil, nil, priv)
+	return ok, errors.Trace(err)
+}
+
+// privilegeDenied returns err, the error of a denied privilege check. If privilege trace is enabled
+// for the session, the trace of the last check is appended to the message.
+func privilegeDenied(ctx context.Context, err *terror.Error) error {
+	tracer, ok := privilege.GetPrivilegeChecker(ctx).(privilege.Tracer)
+	if !ok || len(tracer.Trace()) == 0 {
+		return err
+	}
+	return err.Gen("%s Privilege trace: [%s]", err.ToSQLError().Message, strings.Join(tracer.Trace(), "; "))
+}
+
+// loginUserName returns the user name part of the current user, it matches the User of util.ProcessInfo.
+func loginUserName(ctx context.Context) string {
+	return strings.Split(ctx.GetSessionVars().User, "@")[0]
+}
+
+// showProcessListInfoLen is the max length of the Info column if FULL is not specified.
+const showProcessListInfoLen = 100
+
+// See https://dev.mysql.com/doc/refman/5.7/en/show-processlist.html
+func (e *ShowExec) fetchShowProcessList() error {
+	sm, ok := e.ctx.Value(SessionManagerKey).(util.SessionManager)
+	if !ok {
+		return nil
+	}
+	// Without the PROCESS privilege, only the connections of the current user are listed.
+	hasProcessPriv, err := hasGlobalPriv(e.ctx, mysql.ProcessPriv)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	loginUser := loginUserName(e.ctx)
+	for _, pi := range sm.ShowProcessList() {
+		if !hasProcessPriv && pi.User != loginUser {
+			continue
+		}
+		var db, info interface{}
+		if len(pi.DB) > 0 {
+			db = pi.DB
+		}
+		if len(pi.Info) > 0 {
+			if !e.Full && len(pi.Info) > showProcessListInfoLen {
+				info = truncateAtRuneBoundary(pi.Info, showProcessListInfoLen)
+			} else {
+				info = pi.Info
+			}
+		}
+		row := &Row{
+			Data: types.MakeDatums(
+				pi.ID,
+				pi.User,
+				pi.Host,
+				db,
+				pi.Command,
+				int64(time.Since(pi.Time)/time.Second),
+				pi.State,
+				info,
+			),
+		}
+		e.rows = append(e.rows, row)
+	}
+	return nil
+}
+
+// truncateAtRuneBoundary cuts s to at most n bytes without splitting a multi-byte character.
+func truncateAtRuneBoundary(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
 // See http://dev.mysql.com/doc/refman/5.7/en/show-character-set.html
 func (e *ShowExec) fetchShowCharset() error {
 	descs := charset.GetAllCharsets()
@@ -328,12 +493,23 @@ func (e *ShowExec) fetchShowVariables() error {
 				return errors.Trace(err)
 			}
 		}
+		if v.Name == variable.AutocommitVar || v.Name == variable.ForeignKeyChecks {
+			// Like MySQL, the boolean variables are shown as ON or OFF, no matter how they are set.
+			value = boolVarToOnOff(value)
+		}
 		row := &Row{Data: types.MakeDatums(v.Name, value)}
 		e.rows = append(e.rows, row)
 	}
 	return nil
 }
 
+func boolVarToOnOff(value string) string {
+	if strings.EqualFold(value, "ON") || value == "1" {
+		return "ON"
+	}
+	return "OFF"
+}
+
 func (e *ShowExec) fetchShowStatus() error {
 	statusVars, err := variable.GetStatusVars()
 	if err != nil {
@@ -362,6 +538,11 @@ func (e *ShowExec) fetchShowCreateTable() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if tb.Meta().IsView() {
+		data := types.MakeDatums(tb.Meta().Name.O, showCreateView(tb.Meta()))
+		e.rows = append(e.rows, &Row{Data: data})
+		return nil
+	}
 
 	// TODO: let the result more like MySQL.
 	var buf bytes.Buffer
@@ -387,7 +568,7 @@ func (e *ShowExec) fetchShowCreateTable() error {
 				case "CURRENT_TIMESTAMP":
 					buf.WriteString(" DEFAULT CURRENT_TIMESTAMP")
 				default:
-					buf.WriteString(fmt.Sprintf(" DEFAULT '%v'", col.DefaultValue))
+					buf.WriteString(fmt.Sprintf(" DEFAULT '%s'", escapeStringLiteral(fmt.Sprintf("%v", col.DefaultValue))))
 				}
 			}
 			if mysql.HasOnUpdateNowFlag(col.Flag) {
@@ -395,7 +576,7 @@ func (e *ShowExec) fetchShowCreateTable() error {
 			}
 		}
 		if len(col.Comment) > 0 {
-			buf.WriteString(fmt.Sprintf(" COMMENT '%s'", col.Comment))
+			buf.WriteString(fmt.Sprintf(" COMMENT '%s'", escapeStringLiteral(col.Comment)))
 		}
 		if i != len(tb.Cols())-1 {
 			buf.WriteString(",\n")
@@ -411,7 +592,15 @@ func (e *ShowExec) fetchShowCreateTable() error {
 		buf.WriteString(fmt.Sprintf(" PRIMARY KEY (`%s`)", pkCol.Name.O))
 	}
 
-	if len(tb.Indices()) > 0 || len(tb.Meta().ForeignKeys) > 0 {
+	// Only the public foreign keys are shown.
+	fks := make([]*model.FKInfo, 0, len(tb.Meta().ForeignKeys))
+	for _, fk := range tb.Meta().ForeignKeys {
+		if fk.State == model.StatePublic {
+			fks = append(fks, fk)
+		}
+	}
+
+	if len(tb.Indices()) > 0 || len(fks) > 0 {
 		buf.WriteString(",\n")
 	}
 
@@ -427,21 +616,25 @@ func (e *ShowExec) fetchShowCreateTable() error {
 
 		cols := make([]string, 0, len(idxInfo.Columns))
 		for _, c := range idxInfo.Columns {
-			cols = append(cols, c.Name.O)
+			colName := fmt.Sprintf("`%s`", c.Name.O)
+			if c.Length != types.UnspecifiedLength {
+				colName += fmt.Sprintf("(%d)", c.Length)
+			}
+			cols = append(cols, colName)
 		}
-		buf.WriteString(fmt.Sprintf("(`%s`)", strings.Join(cols, "`,`")))
+		buf.WriteString(fmt.Sprintf("(%s)", strings.Join(cols, ",")))
 		if i != len(tb.Indices())-1 {
 			buf.WriteString(",\n")
 		}
 	}
 
-	if len(tb.Indices()) > 0 && len(tb.Meta().ForeignKeys) > 0 {
+	if len(tb.Indices()) > 0 && len(fks) > 0 {
 		buf.WriteString(",\n")
 	}
 
-	for _, fk := range tb.Meta().ForeignKeys {
-		if fk.State != model.StatePublic {
-			continue
+	for i, fk := range fks {
+		if i > 0 {
+			buf.WriteString(",\n")
 		}
 
 		cols := make([]string, 0, len(fk.Cols))
@@ -450,7 +643,7 @@ func (e *ShowExec) fetchShowCreateTable() error {
 		}
 
 		refCols := make([]string, 0, len(fk.RefCols))
-		for _, c := range fk.Cols {
+		for _, c := range fk.RefCols {
 			refCols = append(refCols, c.O)
 		}
 
@@ -477,7 +670,7 @@ func (e *ShowExec) fetchShowCreateTable() error {
 	}
 
 	if len(tb.Meta().Comment) > 0 {
-		buf.WriteString(fmt.Sprintf(" COMMENT='%s'", tb.Meta().Comment))
+		buf.WriteString(fmt.Sprintf(" COMMENT='%s'", escapeStringLiteral(tb.Meta().Comment)))
 	}
 
 	data := types.MakeDatums(tb.Meta().Name.O, buf.String())
@@ -485,6 +678,43 @@ func (e *ShowExec) fetchShowCreateTable() error {
 	return nil
 }
 
+func (e *ShowExec) fetchShowCreateView() error {
+	tb, err := e.getTable()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tb.Meta()
+	if !tbInfo.IsView() {
+		return infoschema.ErrWrongObject.GenByArgs(e.Table.Schema, tbInfo.Name, "VIEW")
+	}
+	data := types.MakeDatums(tbInfo.Name.O, showCreateView(tbInfo), tbInfo.View.Charset, tbInfo.View.Collate)
+	e.rows = append(e.rows, &Row{Data: data})
+	return nil
+}
+
+// showCreateView reconstructs the CREATE VIEW statement of a view from its column list and select statement.
+func showCreateView(tbInfo *model.TableInfo) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "CREATE VIEW `%s`", tbInfo.Name.O)
+	if len(tbInfo.View.Cols) > 0 {
+		buf.WriteString(" (")
+		for i, col := range tbInfo.View.Cols {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "`%s`", col.O)
+		}
+		buf.WriteString(")")
+	}
+	fmt.Fprintf(&buf, " AS %s", tbInfo.View.SelectStmt)
+	return buf.String()
+}
+
+// escapeStringLiteral escapes the single quotes in s, so it can be quoted as a string literal in SHOW CREATE TABLE.
+func escapeStringLiteral(s string) string {
+	return strings.Replace(s, "'", "''", -1)
+}
+
 // Compose show create database result.
 func (e *ShowExec) fetchShowCreateDatabase() error {
 	db, ok := e.is.SchemaByName(e.DBName)
@@ -14,8 +14,19 @@
 package executor_test
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -61,7 +72,7 @@ func (s *testSuite) TestShow(c *C) {
 	c.Check(result.Rows(), HasLen, 1)
 	row = result.Rows()[0]
 	expectedRow = []interface{}{
-		"ptest", "CREATE TABLE `ptest` (\n  `a` int(11) NOT NULL,\n  `b` double NOT NULL DEFAULT '2.0',\n  `c` varchar(10) NOT NULL,\n  `d` time DEFAULT NULL,\n  `e` timestamp NULL DEFAULT NULL,\n PRIMARY KEY (`a`),\n  UNIQUE KEY `d` (`d`)\n) ENGINE=InnoDB"}
+		"ptest", "CREATE TABLE `ptest` (\n  `a` int(11) NOT NULL,\n  `b` double NOT NULL DEFAULT '2.0',\n  `c` varchar(10) NOT NULL,\n  `d` time DEFAULT NULL,\n  `e` timestamp NULL DEFAULT NULL,\n PRIMARY KEY (`a`),\n  UNIQUE KEY `d` (`d`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"}
 	for i, r := range row {
 		c.Check(r, Equals, expectedRow[i])
 	}
@@ -77,7 +88,7 @@ func (s *testSuite) TestShow(c *C) {
 	c.Check(result.Rows(), HasLen, 2)
 	expectedRow = []interface{}{
 		"show_index", int64(0), "PRIMARY", int64(1), "id", "utf8_bin",
-		int64(0), nil, nil, "", "BTREE", "", ""}
+		nil, nil, nil, "", "BTREE", "", ""}
 	row = result.Rows()[0]
 	c.Check(row, HasLen, len(expectedRow))
 	for i, r := range row {
@@ -85,7 +96,7 @@ func (s *testSuite) TestShow(c *C) {
 	}
 	expectedRow = []interface{}{
 		"show_index", int64(1), "cIdx", int64(1), "c", "utf8_bin",
-		int64(0), nil, nil, "YES", "HASH", "", "index_comment_for_cIdx"}
+		nil, nil, nil, "YES", "HASH", "", "index_comment_for_cIdx"}
 	row = result.Rows()[1]
 	c.Check(row, HasLen, len(expectedRow))
 	for i, r := range row {
@@ -125,6 +136,27 @@ func (s *testSuite) TestShow(c *C) {
 	tk.MustExec("use show_test_DB")
 	result = tk.MustQuery("SHOW index from show_index from test where Column_name = 'c'")
 	c.Check(result.Rows(), HasLen, 1)
+
+	// The cardinality comes from the statistics after the table is analyzed.
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists show_card")
+	tk.MustExec("create table show_card (a int primary key, b int, c int, index bc (b, c), unique index cb (c, b))")
+	tk.MustExec("insert show_card values (1, 1, 1), (2, 1, 2), (3, 2, 1), (4, 2, 2)")
+	tk.MustQuery("show indexes in show_card from test").Check(testkit.Rows(
+		"show_card 0 PRIMARY 1 a utf8_bin <nil> <nil> <nil>  BTREE  ",
+		"show_card 1 bc 1 b utf8_bin <nil> <nil> <nil> YES BTREE  ",
+		"show_card 1 bc 2 c utf8_bin <nil> <nil> <nil> YES BTREE  ",
+		"show_card 0 cb 1 c utf8_bin <nil> <nil> <nil> YES BTREE  ",
+		"show_card 0 cb 2 b utf8_bin <nil> <nil> <nil> YES BTREE  ",
+	))
+	tk.MustExec("analyze table show_card")
+	tk.MustQuery("show keys from test.show_card").Check(testkit.Rows(
+		"show_card 0 PRIMARY 1 a utf8_bin 4 <nil> <nil>  BTREE  ",
+		"show_card 1 bc 1 b utf8_bin 2 <nil> <nil> YES BTREE  ",
+		"show_card 1 bc 2 c utf8_bin 4 <nil> <nil> YES BTREE  ",
+		"show_card 0 cb 1 c utf8_bin 2 <nil> <nil> YES BTREE  ",
+		"show_card 0 cb 2 b utf8_bin 4 <nil> <nil> YES BTREE  ",
+	))
 }
 
 type stats struct {
@@ -149,7 +181,7 @@ func (s *testSuite) TestForeignKeyInShowCreateTable(c *C) {
 	tk.MustExec(testSQL)
 	testSQL = `drop table if exists t1`
 	tk.MustExec(testSQL)
-	testSQL = `CREATE TABLE t1 (id int PRIMARY KEY AUTO_INCREMENT)`
+	testSQL = `CREATE TABLE t1 (id int PRIMARY KEY AUTO_INCREMENT, a int, index (a))`
 	tk.MustExec(testSQL)
 
 	testSQL = "create table show_test (`id` int PRIMARY KEY AUTO_INCREMENT, FOREIGN KEY `Fk` (`id`) REFERENCES `t1` (`a`) ON DELETE CASCADE ON UPDATE CASCADE) ENGINE=InnoDB"
@@ -159,7 +191,7 @@ func (s *testSuite) TestForeignKeyInShowCreateTable(c *C) {
 	c.Check(result.Rows(), HasLen, 1)
 	row := result.Rows()[0]
 	expectedRow := []interface{}{
-		"show_test", "CREATE TABLE `show_test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n PRIMARY KEY (`id`),\n  CONSTRAINT `Fk` FOREIGN KEY (`id`) REFERENCES `t1` (`id`) ON DELETE CASCADE ON UPDATE CASCADE\n) ENGINE=InnoDB"}
+		"show_test", "CREATE TABLE `show_test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n PRIMARY KEY (`id`),\n  CONSTRAINT `Fk` FOREIGN KEY (`id`) REFERENCES `t1` (`a`) ON DELETE CASCADE ON UPDATE CASCADE\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"}
 	for i, r := range row {
 		c.Check(r, Equals, expectedRow[i])
 	}
@@ -171,7 +203,7 @@ func (s *testSuite) TestForeignKeyInShowCreateTable(c *C) {
 	c.Check(result.Rows(), HasLen, 1)
 	row = result.Rows()[0]
 	expectedRow = []interface{}{
-		"show_test", "CREATE TABLE `show_test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n PRIMARY KEY (`id`)\n) ENGINE=InnoDB"}
+		"show_test", "CREATE TABLE `show_test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"}
 	for i, r := range row {
 		c.Check(r, Equals, expectedRow[i])
 	}
@@ -183,9 +215,239 @@ func (s *testSuite) TestForeignKeyInShowCreateTable(c *C) {
 	c.Check(result.Rows(), HasLen, 1)
 	row = result.Rows()[0]
 	expectedRow = []interface{}{
-		"show_test", "CREATE TABLE `show_test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n PRIMARY KEY (`id`),\n  CONSTRAINT `Fk` FOREIGN KEY (`id`) REFERENCES `t1` (`id`) ON DELETE CASCADE ON UPDATE CASCADE\n) ENGINE=InnoDB"}
+		"show_test", "CREATE TABLE `show_test` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n PRIMARY KEY (`id`),\n  CONSTRAINT `Fk` FOREIGN KEY (`id`) REFERENCES `t1` (`id`) ON DELETE CASCADE ON UPDATE CASCADE\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"}
 	for i, r := range row {
 		c.Check(r, Equals, expectedRow[i])
 	}
 
 }
+
+func (s *testSuite) TestShowTables(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	// SHOW TABLES needs a schema when no database is selected.
+	rs, err := tk.Exec("show tables")
+	c.Assert(err, IsNil)
+	_, err = tidb.GetRows(rs)
+	c.Assert(terror.ErrorEqual(err, executor.ErrNoDB), IsTrue)
+	rs, err = tk.Exec("show tables from no_such_db")
+	c.Assert(err, IsNil)
+	_, err = tidb.GetRows(rs)
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrDatabaseNotExists), IsTrue)
+	tk.MustQuery("show databases like 'te%'").Check(testkit.Rows("test"))
+
+	tk.MustExec("use test")
+	tk.MustExec("create table show_t1 (a int)")
+	tk.MustExec("create table show_t2 (a int)")
+	tk.MustExec("create table other_t (a int)")
+	tk.MustQuery("show tables like 'show%'").Check(testkit.Rows("show_t1", "show_t2"))
+	tk.MustQuery("show full tables from test like 'show_t1'").Check(testkit.Rows("show_t1 BASE TABLE"))
+	tk.MustQuery("show tables in mysql like 'user'").Check(testkit.Rows("user"))
+}
+
+func (s *testSuite) TestShowCreateTableRoundTrip(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table show_parent (a int primary key, b int, unique key ub (b))")
+	tk.MustExec(`create table show_round_trip (
+		id int not null auto_increment,
+		name varchar(20) not null default 'it''s' comment 'o''k',
+		t text,
+		pa int, pb int,
+		primary key (id, name),
+		unique key u (t(10)),
+		key k (pa, pb),
+		constraint fa foreign key (pa) references show_parent (a),
+		constraint fb foreign key (pb) references show_parent (b) on delete cascade
+	) comment='c''d'`)
+	row := tk.MustQuery("show create table show_round_trip").Rows()[0]
+	c.Assert(row[1], Equals, "CREATE TABLE `show_round_trip` (\n"+
+		"  `id` int(11) NOT NULL AUTO_INCREMENT,\n"+
+		"  `name` varchar(20) NOT NULL DEFAULT 'it''s' COMMENT 'o''k',\n"+
+		"  `t` text DEFAULT NULL,\n"+
+		"  `pa` int(11) DEFAULT NULL,\n"+
+		"  `pb` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`,`name`),\n"+
+		"  UNIQUE KEY `u` (`t`(10)),\n"+
+		"  KEY `k` (`pa`,`pb`),\n"+
+		"  CONSTRAINT `fa` FOREIGN KEY (`pa`) REFERENCES `show_parent` (`a`),\n"+
+		"  CONSTRAINT `fb` FOREIGN KEY (`pb`) REFERENCES `show_parent` (`b`) ON DELETE CASCADE\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='c''d'")
+
+	// The output can be executed to recreate the same table.
+	tk.MustExec("drop table show_round_trip")
+	tk.MustExec(row[1].(string))
+	tk.MustQuery("show create table show_round_trip").Check([][]interface{}{row})
+
+	// Multi-byte comments are preserved and the longest allowed comments are accepted.
+	colComment, tblComment := strings.Repeat("列", 1024), strings.Repeat("表", 2048)
+	tk.MustExec(fmt.Sprintf("create table show_comment (a int comment '说明 a', b int comment '%s') comment = '%s'", colComment, tblComment))
+	row = tk.MustQuery("show create table show_comment").Rows()[0]
+	c.Assert(row[1], Equals, "CREATE TABLE `show_comment` (\n"+
+		"  `a` int(11) DEFAULT NULL COMMENT '说明 a',\n"+
+		"  `b` int(11) DEFAULT NULL COMMENT '"+colComment+"'\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='"+tblComment+"'")
+	tk.MustQuery("select column_name, column_comment from information_schema.columns where table_name = 'show_comment' and column_name = 'a'").Check(testkit.Rows("a 说明 a"))
+	tk.MustQuery("select char_length(table_comment) from information_schema.tables where table_name = 'show_comment'").Check(testkit.Rows("2048"))
+	tk.MustExec("drop table show_comment")
+	tk.MustExec(row[1].(string))
+	tk.MustQuery("show create table show_comment").Check([][]interface{}{row})
+}
+
+func (s *testSuite) TestShowColumns(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table show_columns (id int unsigned primary key auto_increment, name varchar(255) not null default 'x',
+		price decimal(10,2), b tinyint unsigned zerofill, c int, key k(price), unique key uk(name))`)
+	tk.MustQuery("show columns from show_columns").Check(testkit.Rows(
+		"id int(10) unsigned NO PRI <nil> auto_increment",
+		"name varchar(255) NO UNI x ",
+		"price decimal(10,2) YES MUL <nil> ",
+		"b tinyint(3) unsigned zerofill YES  <nil> ",
+		"c int(11) YES  <nil> "))
+	tk.MustQuery("describe show_columns name").Check(testkit.Rows("name varchar(255) NO UNI x "))
+	tk.MustQuery("desc show_columns").Check(tk.MustQuery("show fields from show_columns").Rows())
+	tk.MustQuery("show columns from show_columns like 'p%'").Check(testkit.Rows("price decimal(10,2) YES MUL <nil> "))
+	tk.MustQuery("show columns in show_columns from test where field = 'c'").Check(testkit.Rows("c int(11) YES  <nil> "))
+}
+
+func (s *testSuite) TestShowVariables(c *C) {
+	defer testleak.AfterTest(c)()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("show variables like 'max_allowed_packet'").Check(testkit.Rows("max_allowed_packet 4194304"))
+	tk.MustQuery("show session variables where variable_name = 'tx_isolation'").Check(testkit.Rows("tx_isolation REPEATABLE-READ"))
+
+	// SET GLOBAL is visible through SHOW GLOBAL VARIABLES.
+	tk.MustExec("set global max_allowed_packet = 1024")
+	tk.MustQuery("show global variables like 'max_allowed_packet'").Check(testkit.Rows("max_allowed_packet 1024"))
+	tk.MustExec("set global max_allowed_packet = 4194304")
+
+	// SET SESSION only changes the value of the current session.
+	tk.MustExec("set session wait_timeout = 100")
+	tk.MustQuery("show session variables like 'wait_timeout'").Check(testkit.Rows("wait_timeout 100"))
+	tk.MustQuery("show global variables like 'wait_timeout'").Check(testkit.Rows("wait_timeout 28800"))
+
+	// The boolean variables are shown as ON or OFF.
+	tk.MustExec("set session autocommit = 0")
+	tk.MustQuery("show session variables like 'autocommit'").Check(testkit.Rows("autocommit OFF"))
+	tk.MustExec("set global autocommit = 1")
+	tk.MustQuery("show global variables like 'autocommit'").Check(testkit.Rows("autocommit ON"))
+	tk.MustExec("set session autocommit = 1")
+
+	// Known but ignored variables are accepted, unknown variables are rejected.
+	tk.MustExec("set session innodb_lock_wait_timeout = 100")
+	_, err := tk.Exec("set session no_such_variable = 1")
+	c.Assert(terror.ErrorEqual(err, variable.UnknownSystemVar), IsTrue)
+}
+
+func (s *testSuite) TestShowWarnings(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustQuery("select 1/0")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1365 Division by 0"))
+	// SHOW WARNINGS doesn't clear the warnings, but other statements do.
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1365 Division by 0"))
+	tk.MustQuery("show errors").Check(testkit.Rows())
+	tk.MustQuery("select 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	_, err := tk.Exec("select * from no_such_table")
+	c.Assert(err, NotNil)
+	tk.MustQuery("show errors").Check(testkit.Rows("Error 1146 Table 'test.no_such_table' doesn't exist"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Error 1146 Table 'test.no_such_table' doesn't exist"))
+	tk.MustExec("drop table if exists show_warnings")
+	tk.MustQuery("show errors").Check(testkit.Rows())
+
+	// Parse errors are reported.
+	_, err = tk.Exec("select 1 from where")
+	c.Assert(err, NotNil)
+	rows := tk.MustQuery("show errors").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][1], Equals, int64(mysql.ErrUnknown))
+
+	// Errors returned when fetching the rows are reported.
+	tk.MustExec("create table show_warnings (a int)")
+	tk.MustExec("insert show_warnings values (1), (2)")
+	rs, err := tk.Exec("select (select a from show_warnings t2 where t2.a > t1.a - 1) from show_warnings t1")
+	c.Assert(err, IsNil)
+	_, err = rs.Next()
+	c.Assert(terror.ErrorEqual(err, executor.ErrSubqueryNo1Row), IsTrue)
+	rs.Close()
+	tk.MustQuery("show errors").Check(testkit.Rows("Error 1242 Subquery returns more than 1 row"))
+	tk.MustExec("drop table show_warnings")
+}
+
+type mockSessionManager struct {
+	processInfo []util.ProcessInfo
+	killed      []uint64
+}
+
+func (sm *mockSessionManager) ShowProcessList() []util.ProcessInfo {
+	return sm.processInfo
+}
+
+func (sm *mockSessionManager) Kill(connectionID uint64, query bool) bool {
+	for _, pi := range sm.processInfo {
+		if pi.ID == connectionID {
+			sm.killed = append(sm.killed, connectionID)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *testSuite) TestShowProcessList(c *C) {
+	defer func() {
+		s.cleanEnv(c)
+		testleak.AfterTest(c)()
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create user 'pl_user'@'localhost'")
+	defer tk.MustExec("drop user 'pl_user'@'localhost'")
+
+	longInfo := strings.Repeat("a", 99) + "中文"
+	sm := &mockSessionManager{processInfo: []util.ProcessInfo{
+		{ID: 1, User: "root", Host: "127.0.0.1:1000", Command: "Query", Time: time.Now(), Info: "select 1"},
+		{ID: 2, User: "pl_user", Host: "127.0.0.1:1001", Command: "Query", Time: time.Now(), Info: longInfo},
+	}}
+	userAndInfo := func(rows [][]interface{}) []string {
+		var res []string
+		for _, row := range rows {
+			res = append(res, fmt.Sprintf("%v %v", row[1], row[7]))
+		}
+		return res
+	}
+
+	var err error
+	tk.Se, err = tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk.Se.SetValue(executor.SessionManagerKey, sm)
+	tk.Se.(context.Context).GetSessionVars().User = "root@127.0.0.1"
+	rows := tk.MustQuery("show processlist").Rows()
+	c.Assert(userAndInfo(rows), DeepEquals, []string{"root select 1", "pl_user " + strings.Repeat("a", 99)})
+	rows = tk.MustQuery("show full processlist").Rows()
+	c.Assert(userAndInfo(rows), DeepEquals, []string{"root select 1", "pl_user " + longInfo})
+
+	// Without the PROCESS privilege, only the connections of the current user are listed.
+	tk.Se, err = tidb.CreateSession(s.store)
+	c.Assert(err, IsNil)
+	tk.Se.SetValue(executor.SessionManagerKey, sm)
+	tk.Se.(context.Context).GetSessionVars().User = "pl_user@localhost"
+	rows = tk.MustQuery("show processlist").Rows()
+	c.Assert(userAndInfo(rows), DeepEquals, []string{"pl_user " + strings.Repeat("a", 99)})
+}
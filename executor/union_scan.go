@@ -53,6 +53,26 @@ func (udb *dirtyDB) truncateTable(tid int64) {
 	dt.truncated = true
 }
 
+// clone returns a copy of the dirtyDB which is not affected by later changes of udb.
+func (udb *dirtyDB) clone() *dirtyDB {
+	tables := make(map[int64]*dirtyTable, len(udb.tables))
+	for tid, dt := range udb.tables {
+		newDt := &dirtyTable{
+			addedRows:   make(map[int64][]types.Datum, len(dt.addedRows)),
+			deletedRows: make(map[int64]struct{}, len(dt.deletedRows)),
+			truncated:   dt.truncated,
+		}
+		for handle, row := range dt.addedRows {
+			newDt.addedRows[handle] = row
+		}
+		for handle := range dt.deletedRows {
+			newDt.deletedRows[handle] = struct{}{}
+		}
+		tables[tid] = newDt
+	}
+	return &dirtyDB{tables: tables}
+}
+
 func (udb *dirtyDB) getDirtyTable(tid int64) *dirtyTable {
 	dt, ok := udb.tables[tid]
 	if !ok {
@@ -17,13 +17,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/ngaut/log"
 	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/distinct"
 	"github.com/pingcap/tidb/util/types"
@@ -94,13 +98,21 @@ func NewAggFunction(funcType string, funcArgs []Expression, distinct bool) Aggre
 	case ast.AggFuncAvg:
 		return &avgFunction{aggFunction: newAggFunc(tp, funcArgs, distinct)}
 	case ast.AggFuncGroupConcat:
-		return &concatFunction{aggFunction: newAggFunc(tp, funcArgs, distinct)}
+		return &concatFunction{aggFunction: newAggFunc(tp, funcArgs, distinct), separator: ","}
 	case ast.AggFuncMax:
 		return &maxMinFunction{aggFunction: newAggFunc(tp, funcArgs, distinct), isMax: true}
 	case ast.AggFuncMin:
 		return &maxMinFunction{aggFunction: newAggFunc(tp, funcArgs, distinct), isMax: false}
 	case ast.AggFuncFirstRow:
 		return &firstRowFunction{aggFunction: newAggFunc(tp, funcArgs, distinct)}
+	case ast.AggFuncStddevPop:
+		return &varianceFunction{aggFunction: newAggFunc(tp, funcArgs, distinct), stddev: true}
+	case ast.AggFuncStddevSamp:
+		return &varianceFunction{aggFunction: newAggFunc(tp, funcArgs, distinct), stddev: true, sample: true}
+	case ast.AggFuncVarPop:
+		return &varianceFunction{aggFunction: newAggFunc(tp, funcArgs, distinct)}
+	case ast.AggFuncVarSamp:
+		return &varianceFunction{aggFunction: newAggFunc(tp, funcArgs, distinct), sample: true}
 	}
 	return nil
 }
@@ -134,11 +146,12 @@ func (af *aggFunction) Equal(b AggregationFunction, ctx context.Context) bool {
 	if af.Distinct != b.IsDistinct() {
 		return false
 	}
-	if len(af.GetArgs()) == len(b.GetArgs()) {
-		for i, argA := range af.GetArgs() {
-			if !argA.Equal(b.GetArgs()[i], ctx) {
-				return false
-			}
+	if len(af.GetArgs()) != len(b.GetArgs()) {
+		return false
+	}
+	for i, argA := range af.GetArgs() {
+		if !argA.Equal(b.GetArgs()[i], ctx) {
+			return false
 		}
 	}
 	return true
@@ -489,6 +502,8 @@ func (cf *countFunction) GetStreamResult() (d types.Datum) {
 
 type avgFunction struct {
 	aggFunction
+	// divPrecIncr is taken from the evaluation context in updating, it's used for the scale of a decimal result.
+	divPrecIncr int
 }
 
 // Clone implements AggregationFunction interface.
@@ -543,6 +558,7 @@ func (af *avgFunction) updateAvg(row []types.Datum, groupKey []byte, ectx contex
 
 // Update implements AggregationFunction interface.
 func (af *avgFunction) Update(row []types.Datum, groupKey []byte, ctx context.Context) error {
+	af.divPrecIncr = ctx.GetSessionVars().DivPrecisionIncrement
 	if af.mode == FinalMode {
 		return af.updateAvg(row, groupKey, ctx)
 	}
@@ -551,6 +567,7 @@ func (af *avgFunction) Update(row []types.Datum, groupKey []byte, ctx context.Co
 
 // StreamUpdate implements AggregationFunction interface.
 func (af *avgFunction) StreamUpdate(row []types.Datum, ctx context.Context) error {
+	af.divPrecIncr = ctx.GetSessionVars().DivPrecisionIncrement
 	return af.streamUpdateSum(row, ctx)
 }
 
@@ -563,8 +580,8 @@ func (af *avgFunction) calculateResult(ctx *ast.AggEvaluateContext) (d types.Dat
 		x := ctx.Value.GetMysqlDecimal()
 		y := types.NewDecFromInt(ctx.Count)
 		to := new(types.MyDecimal)
-		types.DecimalDiv(x, y, to, types.DivFracIncr)
-		to.Round(to, ctx.Value.Frac()+types.DivFracIncr)
+		types.DecimalDiv(x, y, to, af.divPrecIncr)
+		to.Round(to, ctx.Value.Frac()+af.divPrecIncr)
 		d.SetMysqlDecimal(to)
 	}
 	return
@@ -586,8 +603,138 @@ func (af *avgFunction) GetStreamResult() (d types.Datum) {
 	return
 }
 
+// varianceFunction computes the population or sample variance, or their standard deviations.
+// It takes a single pass over the values by Welford's method, which is numerically stable.
+type varianceFunction struct {
+	aggFunction
+	// sample is true for var_samp and stddev_samp, they divide the sum of squares by count - 1 rather than count.
+	sample bool
+	// stddev is true for the standard deviations, which are the square roots of the variances.
+	stddev bool
+}
+
+// Clone implements AggregationFunction interface.
+func (vf *varianceFunction) Clone() AggregationFunction {
+	nf := *vf
+	for i, arg := range vf.Args {
+		nf.Args[i] = arg.Clone()
+	}
+	nf.resultMapper = make(aggCtxMapper)
+	return &nf
+}
+
+// GetType implements AggregationFunction interface.
+func (vf *varianceFunction) GetType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeDouble)
+	ft.Charset = charset.CharsetBin
+	ft.Collate = charset.CollationBin
+	return ft
+}
+
+func (vf *varianceFunction) updateVariance(ctx *ast.AggEvaluateContext, row []types.Datum, ectx context.Context) error {
+	value, err := vf.Args[0].Eval(row, ectx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if value.IsNull() {
+		return nil
+	}
+	if vf.Distinct {
+		d, err1 := ctx.DistinctChecker.Check([]interface{}{value.GetValue()})
+		if err1 != nil {
+			return errors.Trace(err1)
+		}
+		if !d {
+			return nil
+		}
+	}
+	x, err := value.ToFloat64(ectx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The differences from the first value are small for the values close to each other, so their sums
+	// keep the precision of the large values, and they are exact for the integers.
+	if ctx.Count == 0 {
+		ctx.Shift = x
+	}
+	ctx.Count++
+	delta := x - ctx.Shift
+	ctx.ShiftedSum += delta
+	ctx.ShiftedSquares += delta * delta
+	return nil
+}
+
+// Update implements AggregationFunction interface.
+func (vf *varianceFunction) Update(row []types.Datum, groupKey []byte, ctx context.Context) error {
+	return vf.updateVariance(vf.getContext(groupKey), row, ctx)
+}
+
+// StreamUpdate implements AggregationFunction interface.
+func (vf *varianceFunction) StreamUpdate(row []types.Datum, ctx context.Context) error {
+	return vf.updateVariance(vf.getStreamedContext(), row, ctx)
+}
+
+func (vf *varianceFunction) calculateResult(ctx *ast.AggEvaluateContext) (d types.Datum) {
+	count := ctx.Count
+	if vf.sample {
+		count--
+	}
+	// The result is NULL for an empty group, and for the sample functions on a single row.
+	if count <= 0 {
+		return
+	}
+	m2 := ctx.ShiftedSquares - ctx.ShiftedSum*ctx.ShiftedSum/float64(ctx.Count)
+	if m2 < 0 {
+		// It may be slightly negative by the rounding errors.
+		m2 = 0
+	}
+	variance := m2 / float64(count)
+	if vf.stddev {
+		d.SetFloat64(math.Sqrt(variance))
+	} else {
+		d.SetFloat64(variance)
+	}
+	return
+}
+
+// GetGroupResult implements AggregationFunction interface.
+func (vf *varianceFunction) GetGroupResult(groupKey []byte) types.Datum {
+	return vf.calculateResult(vf.getContext(groupKey))
+}
+
+// GetStreamResult implements AggregationFunction interface.
+func (vf *varianceFunction) GetStreamResult() (d types.Datum) {
+	if vf.streamCtx == nil {
+		return
+	}
+	d = vf.calculateResult(vf.streamCtx)
+	vf.streamCtx = nil
+	return
+}
+
 type concatFunction struct {
 	aggFunction
+	separator string
+	// byDescs are the directions of the ORDER BY items, the ORDER BY expressions are the last len(byDescs) args.
+	byDescs []bool
+	// maxLen and sc are taken from the evaluation context in updating, they are used for truncating the result.
+	maxLen uint64
+	sc     *variable.StatementContext
+	// resultRows is the number of results that have been calculated, it is reported in the truncation warning.
+	resultRows int
+}
+
+// NewGroupConcatFunction creates a group_concat function, byItems are the expressions of its ORDER BY clause
+// and descs are their directions.
+func NewGroupConcatFunction(args []Expression, distinct bool, separator string, byItems []Expression, descs []bool) AggregationFunction {
+	funcArgs := make([]Expression, 0, len(args)+len(byItems))
+	funcArgs = append(funcArgs, args...)
+	funcArgs = append(funcArgs, byItems...)
+	return &concatFunction{
+		aggFunction: newAggFunc(ast.AggFuncGroupConcat, funcArgs, distinct),
+		separator:   separator,
+		byDescs:     descs,
+	}
 }
 
 // Clone implements AggregationFunction interface.
@@ -600,24 +747,51 @@ func (cf *concatFunction) Clone() AggregationFunction {
 	return &nf
 }
 
+// Equal implements AggregationFunction interface.
+func (cf *concatFunction) Equal(b AggregationFunction, ctx context.Context) bool {
+	bf, ok := b.(*concatFunction)
+	if !ok || cf.separator != bf.separator || len(cf.byDescs) != len(bf.byDescs) {
+		return false
+	}
+	for i, desc := range cf.byDescs {
+		if desc != bf.byDescs[i] {
+			return false
+		}
+	}
+	return cf.aggFunction.Equal(b, ctx)
+}
+
+// Clear implements AggregationFunction interface.
+func (cf *concatFunction) Clear() {
+	cf.aggFunction.Clear()
+	cf.resultRows = 0
+}
+
 // GetType implements AggregationFunction interface.
 func (cf *concatFunction) GetType() *types.FieldType {
 	return types.NewFieldType(mysql.TypeVarString)
 }
 
-// Update implements AggregationFunction interface.
-func (cf *concatFunction) Update(row []types.Datum, groupKey []byte, ectx context.Context) error {
-	ctx := cf.getContext(groupKey)
-	vals := make([]interface{}, 0, len(cf.Args))
-	for _, a := range cf.Args {
+func (cf *concatFunction) update(ctx *ast.AggEvaluateContext, row []types.Datum, ectx context.Context) error {
+	sessVars := ectx.GetSessionVars()
+	cf.maxLen, cf.sc = sessVars.GroupConcatMaxLen, sessVars.StmtCtx
+	valArgs := cf.Args[:len(cf.Args)-len(cf.byDescs)]
+	vals := make([]interface{}, 0, len(valArgs))
+	strs := make([]string, 0, len(valArgs))
+	for _, a := range valArgs {
 		value, err := a.Eval(row, ectx)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		if value.GetValue() == nil {
+		if value.IsNull() {
 			return nil
 		}
+		str, err := value.ToString()
+		if err != nil {
+			return errors.Trace(err)
+		}
 		vals = append(vals, value.GetValue())
+		strs = append(strs, str)
 	}
 	if cf.Distinct {
 		d, err := ctx.DistinctChecker.Check(vals)
@@ -628,64 +802,81 @@ func (cf *concatFunction) Update(row []types.Datum, groupKey []byte, ectx contex
 			return nil
 		}
 	}
+	if len(cf.byDescs) > 0 {
+		// The values are concatenated in order when calculating the result.
+		sortRow := make([]types.Datum, 0, 1+len(cf.byDescs))
+		sortRow = append(sortRow, types.NewStringDatum(strings.Join(strs, "")))
+		for _, by := range cf.Args[len(valArgs):] {
+			key, err := by.Eval(row, ectx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			sortRow = append(sortRow, key)
+		}
+		ctx.SortRows = append(ctx.SortRows, sortRow)
+		return nil
+	}
 	if ctx.Buffer == nil {
 		ctx.Buffer = &bytes.Buffer{}
+	} else if uint64(ctx.Buffer.Len()) > cf.maxLen {
+		// The result will be truncated, so the remaining values are useless.
+		return nil
 	} else {
-		// now use comma separator
-		ctx.Buffer.WriteString(",")
+		ctx.Buffer.WriteString(cf.separator)
 	}
-	for _, val := range vals {
-		ctx.Buffer.WriteString(fmt.Sprintf("%v", val))
+	for _, str := range strs {
+		ctx.Buffer.WriteString(str)
 	}
-	// TODO: if total length is greater than global var group_concat_max_len, truncate it.
 	return nil
 }
 
+// Update implements AggregationFunction interface.
+func (cf *concatFunction) Update(row []types.Datum, groupKey []byte, ectx context.Context) error {
+	return cf.update(cf.getContext(groupKey), row, ectx)
+}
+
 // StreamUpdate implements AggregationFunction interface.
 func (cf *concatFunction) StreamUpdate(row []types.Datum, ectx context.Context) error {
-	ctx := cf.getStreamedContext()
-	vals := make([]interface{}, 0, len(cf.Args))
-	for _, a := range cf.Args {
-		value, err := a.Eval(row, ectx)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if value.GetValue() == nil {
-			return nil
-		}
-		vals = append(vals, value)
-	}
-	if cf.Distinct {
-		d, err := ctx.DistinctChecker.Check(vals)
-		if err != nil {
-			return errors.Trace(err)
+	return cf.update(cf.getStreamedContext(), row, ectx)
+}
+
+func (cf *concatFunction) calculateResult(ctx *ast.AggEvaluateContext) (d types.Datum) {
+	cf.resultRows++
+	if len(ctx.SortRows) > 0 {
+		sorter := &concatSorter{rows: ctx.SortRows, descs: cf.byDescs, sc: cf.sc}
+		sort.Stable(sorter)
+		if sorter.err != nil {
+			cf.sc.AppendWarning(sorter.err)
 		}
-		if !d {
-			return nil
+		ctx.Buffer = &bytes.Buffer{}
+		for i, row := range ctx.SortRows {
+			if i > 0 {
+				ctx.Buffer.WriteString(cf.separator)
+			}
+			ctx.Buffer.WriteString(row[0].GetString())
 		}
+		ctx.SortRows = nil
 	}
 	if ctx.Buffer == nil {
-		ctx.Buffer = &bytes.Buffer{}
-	} else {
-		// now use comma separator
-		ctx.Buffer.WriteString(",")
+		d.SetNull()
+		return
 	}
-	for _, val := range vals {
-		ctx.Buffer.WriteString(fmt.Sprintf("%v", val))
+	if uint64(ctx.Buffer.Len()) > cf.maxLen {
+		// The length is counted in bytes, back off to the start of a character so it's not split.
+		b, n := ctx.Buffer.Bytes(), int(cf.maxLen)
+		for i := 0; i < utf8.UTFMax-1 && n > 0 && !utf8.RuneStart(b[n]); i++ {
+			n--
+		}
+		ctx.Buffer.Truncate(n)
+		cf.sc.AppendWarning(errCutValueGroupConcat.GenByArgs(cf.resultRows))
 	}
-	// TODO: if total length is greater than global var group_concat_max_len, truncate it.
-	return nil
+	d.SetString(ctx.Buffer.String())
+	return
 }
 
 // GetGroupResult implements AggregationFunction interface.
-func (cf *concatFunction) GetGroupResult(groupKey []byte) (d types.Datum) {
-	ctx := cf.getContext(groupKey)
-	if ctx.Buffer != nil {
-		d.SetString(ctx.Buffer.String())
-	} else {
-		d.SetNull()
-	}
-	return d
+func (cf *concatFunction) GetGroupResult(groupKey []byte) types.Datum {
+	return cf.calculateResult(cf.getContext(groupKey))
 }
 
 // GetStreamResult implements AggregationFunction interface.
@@ -693,15 +884,44 @@ func (cf *concatFunction) GetStreamResult() (d types.Datum) {
 	if cf.streamCtx == nil {
 		return
 	}
-	if cf.streamCtx.Buffer != nil {
-		d.SetString(cf.streamCtx.Buffer.String())
-	} else {
-		d.SetNull()
-	}
+	d = cf.calculateResult(cf.streamCtx)
 	cf.streamCtx = nil
 	return
 }
 
+// concatSorter sorts the rows of group_concat by the ORDER BY keys following the value.
+type concatSorter struct {
+	rows  [][]types.Datum
+	descs []bool
+	sc    *variable.StatementContext
+	err   error
+}
+
+func (s *concatSorter) Len() int {
+	return len(s.rows)
+}
+
+func (s *concatSorter) Swap(i, j int) {
+	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+}
+
+func (s *concatSorter) Less(i, j int) bool {
+	for k, desc := range s.descs {
+		ret, err := s.rows[i][k+1].CompareDatum(s.sc, s.rows[j][k+1])
+		if err != nil {
+			s.err = errors.Trace(err)
+			return false
+		}
+		if desc {
+			ret = -ret
+		}
+		if ret != 0 {
+			return ret < 0
+		}
+	}
+	return false
+}
+
 type maxMinFunction struct {
 	aggFunction
 	isMax bool
@@ -50,6 +50,7 @@ var Funcs = map[string]Func{
 	ast.Abs:     {builtinAbs, 1, 1},
 	ast.Ceil:    {builtinCeil, 1, 1},
 	ast.Ceiling: {builtinCeil, 1, 1},
+	ast.Floor:   {builtinFloor, 1, 1},
 	ast.Ln:      {builtinLog, 1, 1},
 	ast.Log:     {builtinLog, 1, 2},
 	ast.Log2:    {builtinLog2, 1, 1},
@@ -65,6 +66,7 @@ var Funcs = map[string]Func{
 	ast.CurrentTime:      {builtinCurrentTime, 0, 1},
 	ast.Date:             {builtinDate, 1, 1},
 	ast.DateArith:        {builtinDateArith, 3, 3},
+	ast.DateDiff:         {builtinDateDiff, 2, 2},
 	ast.DateFormat:       {builtinDateFormat, 2, 2},
 	ast.CurrentTimestamp: {builtinNow, 0, 1},
 	ast.Curtime:          {builtinCurrentTime, 0, 1},
@@ -134,6 +136,10 @@ var Funcs = map[string]Func{
 	ast.User:         {builtinUser, 0, 0},
 	ast.Version:      {builtinVersion, 0, 0},
 
+	// json functions
+	ast.JSONExtract: {builtinJSONExtract, 2, -1},
+	ast.JSONUnquote: {builtinJSONUnquote, 1, 1},
+
 	// control functions
 	ast.If:     {builtinIf, 3, 3},
 	ast.Ifnull: {builtinIfNull, 2, 2},
@@ -18,6 +18,8 @@
 package expression
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/mysql"
@@ -45,14 +47,20 @@ func builtinFoundRows(arg []types.Datum, ctx context.Context) (d types.Datum, er
 }
 
 // See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_current-user
-// TODO: The value of CURRENT_USER() can differ from the value of USER(). We will finish this after we support grant tables.
+// The value of CURRENT_USER() can differ from the value of USER(), it's the account the user is authenticated as.
 func builtinCurrentUser(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
 	data := ctx.GetSessionVars()
 	if data == nil {
 		return d, errors.Errorf("Missing session variable when evalue builtin")
 	}
 
-	d.SetString(data.User)
+	user := data.User
+	if data.AuthHost != "" {
+		if i := strings.LastIndex(user, "@"); i >= 0 {
+			user = user[:i+1] + data.AuthHost
+		}
+	}
+	d.SetString(user)
 	return d, nil
 }
 
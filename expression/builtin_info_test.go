@@ -68,6 +68,12 @@ func (s *testEvaluatorSuite) TestCurrentUser(c *C) {
 	d, err := builtinCurrentUser(types.MakeDatums(), ctx)
 	c.Assert(err, IsNil)
 	c.Assert(d.GetString(), Equals, "root@localhost")
+
+	// The host of the authenticated account is returned.
+	sessionVars.AuthHost = "%"
+	d, err = builtinCurrentUser(types.MakeDatums(), ctx)
+	c.Assert(err, IsNil)
+	c.Assert(d.GetString(), Equals, "root@%")
 }
 
 func (s *testEvaluatorSuite) TestConnectionID(c *C) {
@@ -0,0 +1,78 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/util/types"
+)
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+func builtinJSONExtract(args []types.Datum, _ context.Context) (d types.Datum, err error) {
+	for _, arg := range args {
+		if arg.IsNull() {
+			return d, nil
+		}
+	}
+	str, err := args[0].ToString()
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	doc, err := types.ParseJSON(str)
+	if err != nil {
+		return d, types.ErrInvalidJSONText.GenByArgs(err)
+	}
+	// The matched values are wrapped in an array if there are several paths or a path has a wildcard.
+	wrap := len(args) > 2
+	var found []interface{}
+	for _, arg := range args[1:] {
+		str, err = arg.ToString()
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		path, err := types.ParseJSONPath(str)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		wrap = wrap || path.HasWildcard()
+		found = append(found, path.Extract(doc)...)
+	}
+	switch {
+	case len(found) == 0:
+		return d, nil
+	case wrap:
+		d.SetString(types.JSONToString(found))
+	default:
+		d.SetString(types.JSONToString(found[0]))
+	}
+	return d, nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+func builtinJSONUnquote(args []types.Datum, _ context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() {
+		return d, nil
+	}
+	str, err := args[0].ToString()
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	str, err = types.JSONUnquote(str)
+	if err != nil {
+		return d, types.ErrInvalidJSONText.GenByArgs(err)
+	}
+	d.SetString(str)
+	return d, nil
+}
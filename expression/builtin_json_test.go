@@ -0,0 +1,75 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/testutil"
+	"github.com/pingcap/tidb/util/types"
+)
+
+func (s *testEvaluatorSuite) TestJSONExtract(c *C) {
+	defer testleak.AfterTest(c)()
+	doc := `{"a": [1, "2", {"aa": "bb"}], "b": {"c": null}}`
+	tbl := []struct {
+		Args []interface{}
+		Ret  interface{}
+	}{
+		{[]interface{}{doc, "$.a"}, `[1, "2", {"aa": "bb"}]`},
+		{[]interface{}{doc, "$.a[1]"}, `"2"`},
+		{[]interface{}{doc, "$.a[2].aa"}, `"bb"`},
+		{[]interface{}{doc, "$.b.c"}, `null`},
+		{[]interface{}{doc, "$.c"}, nil},
+		{[]interface{}{doc, "$.a[0]", "$.b"}, `[1, {"c": null}]`},
+		{[]interface{}{doc, "$.a[0]", "$.c"}, `[1]`},
+		{[]interface{}{doc, "$.a[*]"}, `[1, "2", {"aa": "bb"}]`},
+		{[]interface{}{doc, "$.b.*"}, `[null]`},
+		{[]interface{}{nil, "$.a"}, nil},
+		{[]interface{}{doc, nil}, nil},
+	}
+	for _, t := range tbl {
+		d, err := builtinJSONExtract(types.MakeDatums(t.Args...), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.Ret))
+	}
+
+	_, err := builtinJSONExtract(types.MakeDatums(`{"a": 1`, "$.a"), s.ctx)
+	c.Assert(terror.ErrorEqual(err, types.ErrInvalidJSONText), IsTrue)
+	_, err = builtinJSONExtract(types.MakeDatums(doc, "a"), s.ctx)
+	c.Assert(terror.ErrorEqual(err, types.ErrInvalidJSONPath), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONUnquote(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{`"a\"b"`, `a"b`},
+		{`a"b`, `a"b`},
+		{`[1, "a"]`, `[1, "a"]`},
+		{nil, nil},
+	}
+	for _, t := range tbl {
+		d, err := builtinJSONUnquote(types.MakeDatums(t.Arg), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.Ret))
+	}
+
+	_, err := builtinJSONUnquote(types.MakeDatums(`"a\"`), s.ctx)
+	c.Assert(terror.ErrorEqual(err, types.ErrInvalidJSONText), IsTrue)
+}
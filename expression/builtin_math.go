@@ -42,6 +42,15 @@ func builtinAbs(args []types.Datum, ctx context.Context) (d types.Datum, err err
 		}
 		d.SetInt64(-iv)
 		return d, nil
+	case types.KindMysqlDecimal:
+		dec := d.GetMysqlDecimal()
+		if !dec.IsNegative() {
+			return d, nil
+		}
+		to := new(types.MyDecimal)
+		err = types.DecimalSub(new(types.MyDecimal), dec, to)
+		d.SetMysqlDecimal(to)
+		return d, errors.Trace(err)
 	default:
 		// we will try to convert other types to float
 		// TODO: if time has no precision, it will be a integer
@@ -66,6 +75,21 @@ func builtinCeil(args []types.Datum, ctx context.Context) (d types.Datum, err er
 	return
 }
 
+// See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func builtinFloor(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() ||
+		args[0].Kind() == types.KindUint64 || args[0].Kind() == types.KindInt64 {
+		return args[0], nil
+	}
+
+	f, err := args[0].ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetFloat64(math.Floor(f))
+	return
+}
+
 // See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
 func builtinLog(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
 	sc := ctx.GetSessionVars().StmtCtx
@@ -168,12 +192,12 @@ func builtinPow(args []types.Datum, ctx context.Context) (d types.Datum, err err
 
 // See http://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
 func builtinRound(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
-	sc := ctx.GetSessionVars().StmtCtx
-	x, err := args[0].ToFloat64(sc)
-	if err != nil {
-		return d, errors.Trace(err)
+	for _, arg := range args {
+		if arg.IsNull() {
+			return d, nil
+		}
 	}
-
+	sc := ctx.GetSessionVars().StmtCtx
 	dec := 0
 	if len(args) == 2 {
 		y, err1 := args[1].ToInt64(sc)
@@ -182,6 +206,35 @@ func builtinRound(args []types.Datum, ctx context.Context) (d types.Datum, err e
 		}
 		dec = int(y)
 	}
+
+	switch args[0].Kind() {
+	case types.KindInt64:
+		if dec >= 0 {
+			return args[0], nil
+		}
+		d.SetInt64(int64(types.Round(float64(args[0].GetInt64()), dec)))
+		return d, nil
+	case types.KindUint64:
+		if dec >= 0 {
+			return args[0], nil
+		}
+		d.SetUint64(uint64(types.Round(float64(args[0].GetUint64()), dec)))
+		return d, nil
+	case types.KindMysqlDecimal:
+		// MyDecimal rounds half away from zero, same as MySQL does for exact values.
+		to := new(types.MyDecimal)
+		err = args[0].GetMysqlDecimal().Round(to, dec)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		d.SetMysqlDecimal(to)
+		return d, nil
+	}
+
+	x, err := args[0].ToFloat64(sc)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
 	d.SetFloat64(types.Round(x, dec))
 	return d, nil
 }
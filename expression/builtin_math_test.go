@@ -32,6 +32,7 @@ func (s *testEvaluatorSuite) TestAbs(c *C) {
 		{int64(-1), int64(1)},
 		{float64(3.14), float64(3.14)},
 		{float64(-3.14), float64(3.14)},
+		{types.NewDecFromStringForTest("-2.5"), types.NewDecFromStringForTest("2.5")},
 	}
 
 	Dtbl := tblToDtbl(tbl)
@@ -66,6 +67,29 @@ func (s *testEvaluatorSuite) TestCeil(c *C) {
 	}
 }
 
+func (s *testEvaluatorSuite) TestFloor(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{nil, nil},
+		{int64(1), int64(1)},
+		{float64(1.23), float64(1)},
+		{float64(-1.23), float64(-2)},
+		{"1.23", float64(1)},
+		{"-1.23", float64(-2)},
+	}
+
+	Dtbl := tblToDtbl(tbl)
+
+	for _, t := range Dtbl {
+		v, err := builtinFloor(t["Arg"], s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v, DeepEquals, t["Ret"][0], Commentf("arg:%v", t["Arg"]))
+	}
+}
+
 func (s *testEvaluatorSuite) TestLog(c *C) {
 	defer testleak.AfterTest(c)()
 
@@ -161,6 +185,8 @@ func (s *testEvaluatorSuite) TestRound(c *C) {
 		{[]interface{}{1.298}, 1},
 		{[]interface{}{1.298, 0}, 1},
 		{[]interface{}{23.298, -1}, 20},
+		{[]interface{}{2.5}, 3},
+		{[]interface{}{-2.5}, -3},
 	}
 
 	Dtbl := tblToDtbl(tbl)
@@ -170,4 +196,25 @@ func (s *testEvaluatorSuite) TestRound(c *C) {
 		c.Assert(err, IsNil)
 		c.Assert(v, testutil.DatumEquals, t["Ret"][0])
 	}
+
+	decTbl := []struct {
+		Arg []interface{}
+		Ret interface{}
+	}{
+		{[]interface{}{nil}, nil},
+		{[]interface{}{int64(2), nil}, nil},
+		{[]interface{}{int64(23), int64(-1)}, int64(20)},
+		{[]interface{}{types.NewDecFromStringForTest("2.5")}, types.NewDecFromStringForTest("3")},
+		{[]interface{}{types.NewDecFromStringForTest("-2.5")}, types.NewDecFromStringForTest("-3")},
+		{[]interface{}{types.NewDecFromStringForTest("2.45"), int64(1)}, types.NewDecFromStringForTest("2.5")},
+		{[]interface{}{types.NewDecFromStringForTest("125.3"), int64(-1)}, types.NewDecFromStringForTest("130")},
+	}
+
+	Dtbl = tblToDtbl(decTbl)
+
+	for _, t := range Dtbl {
+		v, err := builtinRound(t["Arg"], s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, t["Ret"][0], Commentf("arg:%v", t["Arg"]))
+	}
 }
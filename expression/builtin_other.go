@@ -14,6 +14,7 @@
 package expression
 
 import (
+	"math"
 	"strings"
 	"time"
 
@@ -22,6 +23,9 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -161,15 +165,15 @@ func builtinIn(args []types.Datum, ctx context.Context) (d types.Datum, err erro
 			continue
 		}
 
-		a, b, err := types.CoerceDatum(sc, args[0], v)
+		equal, isNull, err := inValueEqual(sc, args[0], v)
 		if err != nil {
 			return d, errors.Trace(err)
 		}
-		ret, err := a.CompareDatum(sc, b)
-		if err != nil {
-			return d, errors.Trace(err)
+		if isNull {
+			hasNull = true
+			continue
 		}
-		if ret == 0 {
+		if equal {
 			d.SetInt64(1)
 			return d, nil
 		}
@@ -184,6 +188,162 @@ func builtinIn(args []types.Datum, ctx context.Context) (d types.Datum, err erro
 	return
 }
 
+// inValueEqual compares target with v like "target = v". The rows are compared element by element,
+// isNull is true if no elements differ but some are null, e.g. (1, null) = (1, 2) is null.
+func inValueEqual(sc *variable.StatementContext, target, v types.Datum) (equal bool, isNull bool, err error) {
+	if target.Kind() == types.KindRow && v.Kind() == types.KindRow {
+		targetRow, row := target.GetRow(), v.GetRow()
+		for i := range targetRow {
+			if targetRow[i].IsNull() || row[i].IsNull() {
+				isNull = true
+				continue
+			}
+			equal, null, err := inValueEqual(sc, targetRow[i], row[i])
+			if err != nil {
+				return false, false, errors.Trace(err)
+			}
+			if null {
+				isNull = true
+			} else if !equal {
+				return false, false, nil
+			}
+		}
+		return !isNull, isNull, nil
+	}
+	a, b, err := types.CoerceDatum(sc, target, v)
+	if err != nil {
+		return false, false, errors.Trace(err)
+	}
+	ret, err := a.CompareDatum(sc, b)
+	if err != nil {
+		return false, false, errors.Trace(err)
+	}
+	return ret == 0, false, nil
+}
+
+// inSet is built from the constant values in the list of an IN function. The integer and string
+// constants are put into hash sets, so an integer or a string target only needs to be compared
+// with the other arguments one by one.
+type inSet struct {
+	ints map[int64]struct{}
+	strs map[string]struct{}
+	// The indices of the arguments to compare one by one for integer, string and other targets.
+	intArgs   []int
+	strArgs   []int
+	otherArgs []int
+	hasNull   bool
+	// foldedStrs keeps the folded strings of strs, it is used if the strings are compared case-insensitively.
+	foldedStrs map[string]struct{}
+	// ci is true if the strings are compared case-insensitively, byConnection is true if the strings
+	// are compared with the collation of the connection, which may be case-insensitive.
+	ci           bool
+	byConnection bool
+}
+
+func inFuncFactory(args []Expression) BuiltinFunc {
+	s := &inSet{
+		ints:         make(map[int64]struct{}),
+		strs:         make(map[string]struct{}),
+		foldedStrs:   make(map[string]struct{}),
+		ci:           CaseInsensitiveCompare(args),
+		byConnection: CompareWithConnectionCollation(args),
+	}
+	for i := 1; i < len(args); i++ {
+		con, ok := args[i].(*Constant)
+		if !ok {
+			s.intArgs = append(s.intArgs, i)
+			s.strArgs = append(s.strArgs, i)
+			s.otherArgs = append(s.otherArgs, i)
+			continue
+		}
+		v := con.Value
+		switch v.Kind() {
+		case types.KindNull:
+			s.hasNull = true
+		case types.KindInt64:
+			s.ints[v.GetInt64()] = struct{}{}
+			s.strArgs = append(s.strArgs, i)
+			s.otherArgs = append(s.otherArgs, i)
+		case types.KindUint64:
+			if v.GetUint64() > math.MaxInt64 {
+				s.intArgs = append(s.intArgs, i)
+			} else {
+				s.ints[int64(v.GetUint64())] = struct{}{}
+			}
+			s.strArgs = append(s.strArgs, i)
+			s.otherArgs = append(s.otherArgs, i)
+		case types.KindString, types.KindBytes:
+			s.strs[v.GetString()] = struct{}{}
+			s.foldedStrs[charset.FoldCase(v.GetString())] = struct{}{}
+			s.intArgs = append(s.intArgs, i)
+			s.otherArgs = append(s.otherArgs, i)
+		default:
+			s.intArgs = append(s.intArgs, i)
+			s.strArgs = append(s.strArgs, i)
+			s.otherArgs = append(s.otherArgs, i)
+		}
+	}
+	return func(args []types.Datum, ctx context.Context) (types.Datum, error) {
+		return s.eval(args, ctx)
+	}
+}
+
+func (s *inSet) eval(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	strs := s.strs
+	if s.ci || (s.byConnection && connectionCaseInsensitive(ctx)) {
+		foldStringArgs(args)
+		strs = s.foldedStrs
+	}
+	target := args[0]
+	var found bool
+	argIndices := s.otherArgs
+	switch target.Kind() {
+	case types.KindNull:
+		return
+	case types.KindInt64:
+		_, found = s.ints[target.GetInt64()]
+		argIndices = s.intArgs
+	case types.KindUint64:
+		if target.GetUint64() <= math.MaxInt64 {
+			_, found = s.ints[int64(target.GetUint64())]
+			argIndices = s.intArgs
+		}
+	case types.KindString, types.KindBytes:
+		_, found = strs[target.GetString()]
+		argIndices = s.strArgs
+	}
+	if found {
+		d.SetInt64(1)
+		return d, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	hasNull := s.hasNull
+	for _, i := range argIndices {
+		v := args[i]
+		if v.IsNull() {
+			hasNull = true
+			continue
+		}
+		equal, isNull, err := inValueEqual(sc, target, v)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		if equal {
+			d.SetInt64(1)
+			return d, nil
+		}
+	}
+	if hasNull {
+		return
+	}
+	d.SetInt64(0)
+	return
+}
+
 func builtinLogicXor(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
 	leftDatum := args[0]
 	righDatum := args[1]
@@ -261,39 +421,56 @@ func compareFuncFactory(op opcode.Op) BuiltinFunc {
 	}
 }
 
+// ciCompareFunc wraps the comparison function f to compare strings case-insensitively,
+// byConnection is true if it depends on the collation of the connection.
+func ciCompareFunc(f BuiltinFunc, byConnection bool) BuiltinFunc {
+	return func(args []types.Datum, ctx context.Context) (types.Datum, error) {
+		if !byConnection || connectionCaseInsensitive(ctx) {
+			foldStringArgs(args)
+		}
+		return f(args, ctx)
+	}
+}
+
+// foldStringArgs folds the case of the string arguments of a case-insensitive comparison.
+func foldStringArgs(args []types.Datum) {
+	for i := range args {
+		switch args[i].Kind() {
+		case types.KindString, types.KindBytes:
+			args[i].SetString(charset.FoldCase(args[i].GetString()))
+		}
+	}
+}
+
 func bitOpFactory(op opcode.Op) BuiltinFunc {
 	return func(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
 		sc := ctx.GetSessionVars().StmtCtx
-		a, b, err := types.CoerceDatum(sc, args[0], args[1])
-		if err != nil {
-			return d, errors.Trace(err)
-		}
+		a, b := args[0], args[1]
 		if a.IsNull() || b.IsNull() {
 			return
 		}
 
-		x, err := a.ToInt64(sc)
+		x, err := bitOperand(sc, a)
 		if err != nil {
 			return d, errors.Trace(err)
 		}
 
-		y, err := b.ToInt64(sc)
+		y, err := bitOperand(sc, b)
 		if err != nil {
 			return d, errors.Trace(err)
 		}
 
-		// use a int64 for bit operator, return uint64
 		switch op {
 		case opcode.And:
-			d.SetUint64(uint64(x & y))
+			d.SetUint64(x & y)
 		case opcode.Or:
-			d.SetUint64(uint64(x | y))
+			d.SetUint64(x | y)
 		case opcode.Xor:
-			d.SetUint64(uint64(x ^ y))
+			d.SetUint64(x ^ y)
 		case opcode.RightShift:
-			d.SetUint64(uint64(x) >> uint64(y))
+			d.SetUint64(x >> y)
 		case opcode.LeftShift:
-			d.SetUint64(uint64(x) << uint64(y))
+			d.SetUint64(x << y)
 		default:
 			return d, errInvalidOperation.Gen("invalid op %v in bit operation", op)
 		}
@@ -301,6 +478,60 @@ func bitOpFactory(op opcode.Op) BuiltinFunc {
 	}
 }
 
+// bitOperand converts d to an unsigned 64-bit integer for the bit operations, like MySQL does.
+// A negative value is converted to its two's complement, and a value out of the range of
+// BIGINT UNSIGNED is clipped with a warning.
+func bitOperand(sc *variable.StatementContext, d types.Datum) (uint64, error) {
+	var (
+		u   uint64
+		err error
+	)
+	switch d.Kind() {
+	case types.KindUint64:
+		return d.GetUint64(), nil
+	case types.KindFloat32, types.KindFloat64:
+		f := types.RoundFloat(d.GetFloat64())
+		switch {
+		case f >= math.MaxUint64:
+			u, err = math.MaxUint64, types.ErrOverflow
+		case f >= 0:
+			u = uint64(f)
+		case f < math.MinInt64:
+			u, err = uint64(1)<<63, types.ErrOverflow
+		default:
+			u = uint64(int64(f))
+		}
+	case types.KindMysqlDecimal:
+		var to types.MyDecimal
+		d.GetMysqlDecimal().Round(&to, 0)
+		if to.IsNegative() {
+			var i int64
+			i, err = to.ToInt()
+			u = uint64(i)
+		} else {
+			u, err = to.ToUint()
+		}
+	case types.KindString, types.KindBytes:
+		str := strings.TrimSpace(d.GetString())
+		if strings.HasPrefix(str, "-") {
+			var i int64
+			i, err = types.StrToInt(sc, str)
+			u = uint64(i)
+		} else {
+			u, err = types.StrToUint(sc, str)
+		}
+	default:
+		var i int64
+		i, err = d.ToInt64(sc)
+		return uint64(i), errors.Trace(err)
+	}
+	if types.ErrOverflow.Equal(errors.Cause(err)) {
+		sc.AppendWarning(err)
+		err = nil
+	}
+	return u, errors.Trace(err)
+}
+
 func arithmeticFuncFactory(op opcode.Op) BuiltinFunc {
 	return func(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
 		sc := ctx.GetSessionVars().StmtCtx
@@ -329,14 +560,22 @@ func arithmeticFuncFactory(op opcode.Op) BuiltinFunc {
 		case opcode.Mul:
 			return types.ComputeMul(a, b)
 		case opcode.Div:
-			return types.ComputeDiv(sc, a, b)
+			d, err = types.ComputeDiv(sc, a, b, ctx.GetSessionVars().DivPrecisionIncrement)
 		case opcode.Mod:
-			return types.ComputeMod(sc, a, b)
+			d, err = types.ComputeMod(sc, a, b)
 		case opcode.IntDiv:
-			return types.ComputeIntDiv(sc, a, b)
+			d, err = types.ComputeIntDiv(sc, a, b)
 		default:
 			return d, errInvalidOperation.Gen("invalid op %v in arithmetic operation", op)
 		}
+		// The operands aren't NULL, so a NULL result means division by zero.
+		if err == nil && d.IsNull() {
+			if sc.DividedByZeroAsError {
+				return d, errors.Trace(types.ErrDivByZero)
+			}
+			sc.AppendWarning(types.ErrDivByZero)
+		}
+		return
 	}
 }
 
@@ -386,13 +625,12 @@ func unaryOpFactory(op opcode.Op) BuiltinFunc {
 				d.SetInt64(0)
 			}
 		case opcode.BitNeg:
-			var n int64
-			// for bit operation, we will use int64 first, then return uint64
-			n, err = aDatum.ToInt64(sc)
+			var n uint64
+			n, err = bitOperand(sc, aDatum)
 			if err != nil {
 				return d, errors.Trace(err)
 			}
-			d.SetUint64(uint64(^n))
+			d.SetUint64(^n)
 		case opcode.Plus:
 			switch aDatum.Kind() {
 			case types.KindInt64,
@@ -468,22 +706,91 @@ func CastFuncFactory(tp *types.FieldType) (BuiltinFunc, error) {
 			if d.IsNull() {
 				return
 			}
-			return d.ConvertTo(ctx.GetSessionVars().StmtCtx, tp)
+			sc := ctx.GetSessionVars().StmtCtx
+			castSc := sc
+			if k := d.Kind(); k == types.KindString || k == types.KindBytes {
+				// Like MySQL, an invalid string is cast with a warning rather than an error,
+				// so the truncation during the conversion is always treated as a warning.
+				castSc = &variable.StatementContext{TruncateAsWarning: true}
+			}
+			if tp.Tp == mysql.TypeLonglong {
+				d, err = castToInt(castSc, d, mysql.HasUnsignedFlag(tp.Flag))
+			} else {
+				d, err = d.ConvertTo(castSc, tp)
+			}
+			if castSc != sc {
+				for _, warn := range castSc.GetWarnings() {
+					sc.AppendWarning(warn)
+				}
+			}
+			if err != nil {
+				if castSc == sc && terror.ErrorEqual(err, types.ErrTruncated) {
+					// The truncation of a numeric value follows the sql mode of the statement.
+					return d, errors.Trace(err)
+				}
+				sc.AppendWarning(err)
+				switch tp.Tp {
+				case mysql.TypeDuration, mysql.TypeDatetime, mysql.TypeDate:
+					// An invalid time value is cast to NULL.
+					d.SetNull()
+				}
+			}
+			return d, nil
 		}, nil
 	}
 	return nil, errors.Errorf("unknown cast type - %v", tp)
 }
 
+// castToInt converts d to a signed or unsigned integer. Unlike the conversion for the integer column,
+// the integer value is reinterpreted instead of being clipped when it is out of range of the target type.
+func castToInt(sc *variable.StatementContext, d types.Datum, unsigned bool) (ret types.Datum, err error) {
+	var (
+		i64 int64
+		u64 uint64
+	)
+	switch d.Kind() {
+	case types.KindInt64:
+		i64 = d.GetInt64()
+		u64 = uint64(i64)
+	case types.KindUint64:
+		u64 = d.GetUint64()
+		i64 = int64(u64)
+	case types.KindString, types.KindBytes:
+		str := strings.TrimSpace(d.GetString())
+		if strings.HasPrefix(str, "-") {
+			i64, err = types.StrToInt(sc, str)
+			u64 = uint64(i64)
+		} else {
+			u64, err = types.StrToUint(sc, str)
+			i64 = int64(u64)
+		}
+	default:
+		tp := types.NewFieldType(mysql.TypeLonglong)
+		if unsigned {
+			tp.Flag |= mysql.UnsignedFlag
+		}
+		return d.ConvertTo(sc, tp)
+	}
+	if unsigned {
+		ret.SetUint64(u64)
+	} else {
+		ret.SetInt64(i64)
+	}
+	return ret, errors.Trace(err)
+}
+
 func builtinSetVar(args []types.Datum, ctx context.Context) (types.Datum, error) {
 	sessionVars := ctx.GetSessionVars()
 	varName, _ := args[0].ToString()
-	if !args[1].IsNull() {
-		strVal, err := args[1].ToString()
-		if err != nil {
-			return types.Datum{}, errors.Trace(err)
-		}
-		sessionVars.Users[varName] = strings.ToLower(strVal)
+	if args[1].IsNull() {
+		delete(sessionVars.Users, varName)
+		return args[1], nil
+	}
+	strVal, err := args[1].ToString()
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
 	}
+	sessionVars.Users[varName] = strVal
 	return args[1], nil
 }
 
@@ -316,6 +316,11 @@ func builtinSubstring(args []types.Datum, _ context.Context) (d types.Datum, err
 	// arg[0] -> StrExpr
 	// arg[1] -> Pos
 	// arg[2] -> Len (Optional)
+	for _, arg := range args {
+		if arg.IsNull() {
+			return d, nil
+		}
+	}
 	str, err := args[0].ToString()
 	if err != nil {
 		return d, errors.Errorf("Substring invalid args, need string but get %T", args[0].GetValue())
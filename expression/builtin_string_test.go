@@ -352,6 +352,17 @@ func (s *testEvaluatorSuite) TestSubstring(c *C) {
 		_, err := f.F(args, s.ctx)
 		c.Assert(err, NotNil)
 	}
+
+	// Any null argument makes the result null.
+	for _, args := range [][]interface{}{
+		{nil, 1},
+		{"Sakila", nil},
+		{"Sakila", 1, nil},
+	} {
+		r, err := Funcs[ast.Substring].F(types.MakeDatums(args...), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(r.IsNull(), IsTrue)
+	}
 }
 
 func (s *testEvaluatorSuite) TestConvert(c *C) {
@@ -17,6 +17,7 @@ import (
 	"reflect"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/testutil"
 	"github.com/pingcap/tidb/util/types"
@@ -103,6 +104,32 @@ func (s *testEvaluatorSuite) TestIsNullFunc(c *C) {
 	c.Assert(v.GetInt64(), Equals, int64(1))
 }
 
+func (s *testEvaluatorSuite) TestIsTrueOrFalse(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		arg     interface{}
+		isTrue  int64
+		isFalse int64
+	}{
+		{nil, 0, 0},
+		{0, 0, 1},
+		{1, 1, 0},
+		{-1, 1, 0},
+		{0.0, 0, 1},
+		{1.5, 1, 0},
+		{"0", 0, 1},
+		{"1", 1, 0},
+	}
+	for _, t := range tbl {
+		v, err := Funcs[ast.IsTruth].F(types.MakeDatums(t.arg), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v.GetInt64(), Equals, t.isTrue, Commentf("%v is true", t.arg))
+		v, err = Funcs[ast.IsFalsity].F(types.MakeDatums(t.arg), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v.GetInt64(), Equals, t.isFalse, Commentf("%v is false", t.arg))
+	}
+}
+
 func (s *testEvaluatorSuite) TestLock(c *C) {
 	defer testleak.AfterTest(c)()
 
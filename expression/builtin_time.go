@@ -104,6 +104,28 @@ func builtinTimeDiff(args []types.Datum, ctx context.Context) (d types.Datum, er
 	return d, nil
 }
 
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+func builtinDateDiff(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	if args[0].IsNull() || args[1].IsNull() {
+		return d, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	t1, err := convertDatumToTime(sc, args[0])
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	t2, err := convertDatumToTime(sc, args[1])
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	if t1.Time.Month() == 0 || t1.Time.Day() == 0 || t2.Time.Month() == 0 || t2.Time.Day() == 0 {
+		return d, nil
+	}
+
+	d.SetInt64(int64(types.DateDiff(t1.Time, t2.Time)))
+	return d, nil
+}
+
 // See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
 func builtinDateFormat(args []types.Datum, ctx context.Context) (types.Datum, error) {
 	var d types.Datum
@@ -221,6 +243,14 @@ func builtinMonthName(args []types.Datum, ctx context.Context) (types.Datum, err
 func builtinNow(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
 	// TODO: if NOW is used in stored function or trigger, NOW will return the beginning time
 	// of the execution.
+	now, err := getSystemTimestamp(ctx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	return convertToDatetimeWithFsp(args, ctx, now)
+}
+
+func convertToDatetimeWithFsp(args []types.Datum, ctx context.Context, now time.Time) (d types.Datum, err error) {
 	fsp := 0
 	sc := ctx.GetSessionVars().StmtCtx
 	if len(args) == 1 && !args[0].IsNull() {
@@ -230,7 +260,7 @@ func builtinNow(args []types.Datum, ctx context.Context) (d types.Datum, err err
 		}
 	}
 
-	tr, err := types.RoundFrac(time.Now(), int(fsp))
+	tr, err := types.RoundFrac(now, int(fsp))
 	if err != nil {
 		d.SetNull()
 		return d, errors.Trace(err)
@@ -521,15 +551,18 @@ func builtinStrToDate(args []types.Datum, _ context.Context) (types.Datum, error
 }
 
 func builtinSysDate(args []types.Datum, ctx context.Context) (types.Datum, error) {
-	// SYSDATE is not the same as NOW if NOW is used in a stored function or trigger.
-	// But here we can just think they are the same because we don't support stored function
-	// and trigger now.
-	return builtinNow(args, ctx)
+	// SYSDATE returns the time at which it executes, while NOW returns the time
+	// at which the statement began to execute.
+	return convertToDatetimeWithFsp(args, ctx, time.Now())
 }
 
 // See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curdate
-func builtinCurrentDate(args []types.Datum, _ context.Context) (d types.Datum, err error) {
-	year, month, day := time.Now().Date()
+func builtinCurrentDate(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	now, err := getSystemTimestamp(ctx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	year, month, day := now.Date()
 	t := types.Time{
 		Time: types.FromDate(year, int(month), day, 0, 0, 0, 0),
 		Type: mysql.TypeDate, Fsp: 0}
@@ -547,7 +580,11 @@ func builtinCurrentTime(args []types.Datum, ctx context.Context) (d types.Datum,
 			return d, errors.Trace(err)
 		}
 	}
-	d.SetString(time.Now().Format("15:04:05.000000"))
+	now, err := getSystemTimestamp(ctx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.SetString(now.Format("15:04:05.000000"))
 	return convertToDuration(ctx.GetSessionVars().StmtCtx, d, fsp)
 }
 
@@ -576,8 +613,12 @@ func builtinTime(args []types.Datum, ctx context.Context) (d types.Datum, err er
 }
 
 // See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-date
-func builtinUTCDate(args []types.Datum, _ context.Context) (d types.Datum, err error) {
-	year, month, day := time.Now().UTC().Date()
+func builtinUTCDate(args []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	now, err := getSystemTimestamp(ctx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	year, month, day := now.UTC().Date()
 	t := types.Time{
 		Time: types.FromGoTime(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)),
 		Type: mysql.TypeDate, Fsp: types.UnspecifiedFsp}
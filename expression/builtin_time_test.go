@@ -316,6 +316,11 @@ func (s *testEvaluatorSuite) TestNow(c *C) {
 	t = v.GetMysqlTime()
 	c.Assert(strings.Contains(t.String(), "."), IsTrue)
 
+	// now() returns the same value during the execution of a statement.
+	v, err = builtinNow(types.MakeDatums(6), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(v.GetMysqlTime().Compare(t), Equals, 0)
+
 	_, err = builtinNow(types.MakeDatums(8), s.ctx)
 	c.Assert(err, NotNil)
 
@@ -490,6 +495,32 @@ func (s *testEvaluatorSuite) TestTimeDiff(c *C) {
 	}
 }
 
+func (s *testEvaluatorSuite) TestDateDiff(c *C) {
+	// Test cases from https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+	tests := []struct {
+		t1     string
+		t2     string
+		expect int64
+	}{
+		{"2004-05-21", "2004:01:02", 140},
+		{"2004-04-21", "2000:01:02", 1571},
+		{"2008-12-31 23:59:59.000001", "2008-12-30 01:01:01.000002", 1},
+		{"1010-11-30 23:59:59", "2010-12-31", -365274},
+		{"1010-11-30", "2210-11-01", -438262},
+	}
+	for _, test := range tests {
+		t1 := types.NewStringDatum(test.t1)
+		t2 := types.NewStringDatum(test.t2)
+		result, err := builtinDateDiff([]types.Datum{t1, t2}, s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(result.GetInt64(), Equals, test.expect)
+	}
+
+	result, err := builtinDateDiff(types.MakeDatums(nil, "2010-12-31"), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(result.IsNull(), IsTrue)
+}
+
 func (s *testEvaluatorSuite) TestWeek(c *C) {
 	// Test cases from https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_week
 	tests := []struct {
@@ -81,6 +81,9 @@ type Column struct {
 	// IsAggOrSubq means if this column is referenced to a Aggregation column or a Subquery column.
 	// If so, this column's name will be the plain sql text.
 	IsAggOrSubq bool
+	// Hidden means this column can only be referenced by a qualified name.
+	// e.g. In SELECT * FROM t1 JOIN t2 USING (a), t2.a is hidden, it's merged into t1.a.
+	Hidden bool
 
 	// Only used for execution.
 	Index int
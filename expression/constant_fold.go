@@ -16,7 +16,6 @@ package expression
 import (
 	"github.com/ngaut/log"
 	"github.com/pingcap/tidb/context"
-	"github.com/pingcap/tidb/util/types"
 )
 
 // FoldConstant does constant folding optimization on an expression.
@@ -29,21 +28,18 @@ func FoldConstant(ctx context.Context, expr Expression) Expression {
 		return expr
 	}
 	args := scalarFunc.Args
-	datums := make([]types.Datum, 0, len(args))
 	canFold := true
 	for i := 0; i < len(args); i++ {
 		foldedArg := FoldConstant(ctx, args[i])
 		scalarFunc.Args[i] = foldedArg
-		if con, ok := foldedArg.(*Constant); ok {
-			datums = append(datums, con.Value)
-		} else {
+		if _, ok := foldedArg.(*Constant); !ok {
 			canFold = false
 		}
 	}
 	if !canFold {
 		return expr
 	}
-	value, err := scalarFunc.Function(datums, ctx)
+	value, err := scalarFunc.Eval(nil, ctx)
 	if err != nil {
 		log.Warnf("There may exist an error during constant folding. The function name is %s, args are %s", scalarFunc.FuncName, args)
 		return expr
@@ -15,6 +15,7 @@ package expression
 
 import (
 	"regexp"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/context"
@@ -106,10 +107,12 @@ func doMatch(str string, patChars, patTypes []byte) bool {
 			}
 			sIdx++
 		case patOne:
-			sIdx++
-			if sIdx > len(str) {
+			if sIdx >= len(str) {
 				return false
 			}
+			// '_' matches exactly one character, which may be encoded with several bytes.
+			_, size := utf8.DecodeRuneInString(str[sIdx:])
+			sIdx += size
 		case patAny:
 			i++
 			if i == len(patChars) {
@@ -127,8 +130,45 @@ func doMatch(str string, patChars, patTypes []byte) bool {
 	return sIdx == len(str)
 }
 
+// likeMatcher keeps the last compiled pattern, so a constant pattern
+// is only compiled once per statement instead of once per row.
+type likeMatcher struct {
+	compiled bool
+	pattern  string
+	escape   byte
+	patChars []byte
+	patTypes []byte
+}
+
+func (m *likeMatcher) match(str, pattern string, escape byte) bool {
+	if !m.compiled || m.pattern != pattern || m.escape != escape {
+		m.patChars, m.patTypes = compilePattern(pattern, escape)
+		m.pattern, m.escape, m.compiled = pattern, escape, true
+	}
+	return doMatch(str, m.patChars, m.patTypes)
+}
+
+// likeFuncFactory returns a like function which caches the compiled pattern,
+// ci is true if the strings are matched case-insensitively.
+func likeFuncFactory(ci bool) BuiltinFunc {
+	m := &likeMatcher{}
+	return func(args []types.Datum, _ context.Context) (types.Datum, error) {
+		if ci {
+			foldStringArgs(args[:2])
+			if escape := args[2].GetInt64(); escape >= 'a' && escape <= 'z' {
+				args[2].SetInt64(escape - 'a' + 'A')
+			}
+		}
+		return evalLike(args, m)
+	}
+}
+
 // See http://dev.mysql.com/doc/refman/5.7/en/string-comparison-functions.html
 func builtinLike(args []types.Datum, _ context.Context) (d types.Datum, err error) {
+	return evalLike(args, &likeMatcher{})
+}
+
+func evalLike(args []types.Datum, m *likeMatcher) (d types.Datum, err error) {
 	if args[0].IsNull() {
 		return
 	}
@@ -138,7 +178,6 @@ func builtinLike(args []types.Datum, _ context.Context) (d types.Datum, err erro
 		return d, errors.Trace(err)
 	}
 
-	// TODO: We don't need to compile pattern if it has been compiled or it is static.
 	if args[1].IsNull() {
 		return
 	}
@@ -147,15 +186,49 @@ func builtinLike(args []types.Datum, _ context.Context) (d types.Datum, err erro
 		return d, errors.Trace(err)
 	}
 	escape := byte(args[2].GetInt64())
-	patChars, patTypes := compilePattern(patternStr, escape)
-	match := doMatch(valStr, patChars, patTypes)
-	d.SetInt64(boolToInt64(match))
+	d.SetInt64(boolToInt64(m.match(valStr, patternStr, escape)))
 	return
 }
 
+// regexpMatcher keeps the last compiled regexp, so a constant pattern
+// is only compiled once per statement instead of once per row.
+type regexpMatcher struct {
+	// binary is true if the pattern is matched with binary strings, which is case-sensitive.
+	binary  bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+func (m *regexpMatcher) compile(pattern string) (*regexp.Regexp, error) {
+	if m.re != nil && m.pattern == pattern {
+		return m.re, nil
+	}
+	expr := pattern
+	if !m.binary {
+		expr = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, errRegexp.GenByArgs(err.Error())
+	}
+	m.pattern, m.re = pattern, re
+	return re, nil
+}
+
+// regexpFuncFactory returns a regexp function which caches the compiled regexp.
+func regexpFuncFactory(binary bool) BuiltinFunc {
+	m := &regexpMatcher{binary: binary}
+	return func(args []types.Datum, _ context.Context) (types.Datum, error) {
+		return evalRegexp(args, m)
+	}
+}
+
 // See http://dev.mysql.com/doc/refman/5.7/en/regexp.html#operator_regexp
 func builtinRegexp(args []types.Datum, _ context.Context) (d types.Datum, err error) {
-	// TODO: We don't need to compile pattern if it has been compiled or it is static.
+	return evalRegexp(args, &regexpMatcher{})
+}
+
+func evalRegexp(args []types.Datum, m *regexpMatcher) (d types.Datum, err error) {
 	if args[0].IsNull() || args[1].IsNull() {
 		return
 	}
@@ -168,7 +241,7 @@ func builtinRegexp(args []types.Datum, _ context.Context) (d types.Datum, err er
 	if err != nil {
 		return d, errors.Errorf("non-string Expression in LIKE: %v (Value of type %T)", args[1], args[1])
 	}
-	re, err := regexp.Compile(patternStr)
+	re, err := m.compile(patternStr)
 	if err != nil {
 		return d, errors.Trace(err)
 	}
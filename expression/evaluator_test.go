@@ -14,6 +14,7 @@
 package expression
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/testutil"
@@ -118,6 +120,12 @@ func (s *testEvaluatorSuite) TestBinopComparison(c *C) {
 		{1, ast.LT, 2, 1},
 		{1, ast.LT, 1, 0},
 		{1, ast.LE, 1, 1},
+
+		// test NullEQ, it's never NULL.
+		{nil, ast.NullEQ, nil, 1},
+		{nil, ast.NullEQ, 1, 0},
+		{1, ast.NullEQ, nil, 0},
+		{1, ast.NullEQ, 1, 1},
 	}
 	for _, t := range tbl {
 		f := Funcs[t.op]
@@ -164,21 +172,38 @@ func (s *testEvaluatorSuite) TestBinopLogic(c *C) {
 		rhs interface{}
 		ret interface{}
 	}{
-		{nil, ast.AndAnd, 1, nil},
+		// SQL three-valued logic, all the combinations of NULL, false and true.
+		{nil, ast.AndAnd, nil, nil},
 		{nil, ast.AndAnd, 0, 0},
-		{nil, ast.OrOr, 1, 1},
-		{nil, ast.OrOr, 0, nil},
-		{nil, ast.LogicXor, 1, nil},
-		{nil, ast.LogicXor, 0, nil},
+		{nil, ast.AndAnd, 1, nil},
+		{0, ast.AndAnd, nil, 0},
+		{0, ast.AndAnd, 0, 0},
+		{0, ast.AndAnd, 1, 0},
+		{1, ast.AndAnd, nil, nil},
 		{1, ast.AndAnd, 0, 0},
 		{1, ast.AndAnd, 1, 1},
+		{nil, ast.OrOr, nil, nil},
+		{nil, ast.OrOr, 0, nil},
+		{nil, ast.OrOr, 1, 1},
+		{0, ast.OrOr, nil, nil},
+		{0, ast.OrOr, 0, 0},
+		{0, ast.OrOr, 1, 1},
+		{1, ast.OrOr, nil, 1},
 		{1, ast.OrOr, 0, 1},
 		{1, ast.OrOr, 1, 1},
-		{0, ast.OrOr, 0, 0},
-		{1, ast.LogicXor, 0, 1},
-		{1, ast.LogicXor, 1, 0},
+		{nil, ast.LogicXor, nil, nil},
+		{nil, ast.LogicXor, 0, nil},
+		{nil, ast.LogicXor, 1, nil},
+		{0, ast.LogicXor, nil, nil},
 		{0, ast.LogicXor, 0, 0},
 		{0, ast.LogicXor, 1, 1},
+		{1, ast.LogicXor, nil, nil},
+		{1, ast.LogicXor, 0, 1},
+		{1, ast.LogicXor, 1, 0},
+		// Non-integer operands are converted to boolean first.
+		{0.5, ast.AndAnd, nil, nil},
+		{"0", ast.OrOr, nil, nil},
+		{2, ast.OrOr, nil, 1},
 	}
 	for _, t := range tbl {
 		f := Funcs[t.op]
@@ -186,9 +211,9 @@ func (s *testEvaluatorSuite) TestBinopLogic(c *C) {
 		c.Assert(err, IsNil)
 		switch x := t.ret.(type) {
 		case nil:
-			c.Assert(v.Kind(), Equals, types.KindNull)
+			c.Assert(v.Kind(), Equals, types.KindNull, Commentf("%v %s %v", t.lhs, t.op, t.rhs))
 		case int:
-			c.Assert(v, testutil.DatumEquals, types.NewDatum(int64(x)))
+			c.Assert(v, testutil.DatumEquals, types.NewDatum(int64(x)), Commentf("%v %s %v", t.lhs, t.op, t.rhs))
 		}
 	}
 }
@@ -212,6 +237,32 @@ func (s *testEvaluatorSuite) TestBinopBitop(c *C) {
 		{nil, ast.Xor, 1, nil},
 		{nil, ast.LeftShift, 1, nil},
 		{nil, ast.RightShift, 1, nil},
+		{5, ast.And, 3, 1},
+		{5, ast.Or, 3, 7},
+		{5, ast.Xor, 3, 6},
+		{1, ast.LeftShift, 64, 0},
+		{1, ast.LeftShift, -1, 0},
+		{1, ast.RightShift, 64, 0},
+
+		// The operands are unsigned 64-bit integers.
+		{-1, ast.And, 1, 1},
+		{-1, ast.RightShift, 60, 15},
+		{-1, ast.LeftShift, 1, uint64(math.MaxUint64 - 1)},
+		{1, ast.LeftShift, 63, uint64(1 << 63)},
+		{uint64(math.MaxUint64), ast.And, 1, 1},
+		{uint64(math.MaxUint64), ast.RightShift, 1, uint64(math.MaxInt64)},
+
+		// Non-integer operands are converted to integers first.
+		{"12", ast.And, 7, 4},
+		{"3.6", ast.Or, 0, 3},
+		{"18446744073709551615", ast.Or, 0, uint64(math.MaxUint64)},
+		{"-1", ast.Or, 0, uint64(math.MaxUint64)},
+		{3.6, ast.And, 7, 4},
+		{-2.5, ast.Or, 0, uint64(math.MaxUint64 - 2)},
+		{1e20, ast.Or, 0, uint64(math.MaxUint64)},
+		{-1e20, ast.Or, 0, uint64(1 << 63)},
+		{types.NewDecFromFloatForTest(1.5), ast.Or, 0, 2},
+		{types.NewDecFromFloatForTest(-1.5), ast.Or, 0, uint64(math.MaxUint64 - 1)},
 	}
 
 	for _, t := range tbl {
@@ -223,7 +274,9 @@ func (s *testEvaluatorSuite) TestBinopBitop(c *C) {
 		case nil:
 			c.Assert(v.Kind(), Equals, types.KindNull)
 		case int:
-			c.Assert(v, testutil.DatumEquals, types.NewDatum(uint64(x)))
+			c.Assert(v, testutil.DatumEquals, types.NewDatum(uint64(x)), Commentf("%v %s %v", t.lhs, t.op, t.rhs))
+		case uint64:
+			c.Assert(v, testutil.DatumEquals, types.NewDatum(x), Commentf("%v %s %v", t.lhs, t.op, t.rhs))
 		}
 	}
 }
@@ -426,6 +479,11 @@ func (s *testEvaluatorSuite) TestLike(c *C) {
 		{`\%a`, `%a`, '+', false},
 		{`++a`, `+a`, '+', true},
 		{`++_a`, `+xa`, '+', true},
+		{`|%|_`, `%_`, '|', true},
+		{`|%|_`, `ab`, '|', false},
+		{"_好", "你好", '\\', true},
+		{"__", "你好", '\\', true},
+		{"___", "你好", '\\', false},
 	}
 	for _, v := range tbl {
 		patChars, patTypes := compilePattern(v.pattern, v.escape)
@@ -450,6 +508,31 @@ func (s *testEvaluatorSuite) TestLike(c *C) {
 		c.Assert(err, IsNil)
 		c.Assert(r, testutil.DatumEquals, types.NewDatum(tc.match))
 	}
+
+	// The like function built by the factory caches the compiled pattern,
+	// it should be recompiled when the pattern or the escape changes.
+	f := likeFuncFactory(false)
+	likeTbl := []struct {
+		input   string
+		pattern string
+		escape  byte
+		match   int
+	}{
+		{"a%", `a\%`, '\\', 1},
+		{"ab", `a\%`, '\\', 0},
+		{"ab", `a%`, '\\', 1},
+		{"a%", `a|%`, '|', 1},
+		{"ab", `a|%`, '|', 0},
+		{"a|b", `a|%`, '\\', 1},
+	}
+	for _, tc := range likeTbl {
+		r, err := f(types.MakeDatums(tc.input, tc.pattern, int64(tc.escape)), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(r, testutil.DatumEquals, types.NewDatum(tc.match), Commentf("%v", tc))
+	}
+	r, err := f(types.MakeDatums(nil, "a%", int64('\\')), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(r.IsNull(), IsTrue)
 }
 
 func (s *testEvaluatorSuite) TestRegexp(c *C) {
@@ -468,6 +551,8 @@ func (s *testEvaluatorSuite) TestRegexp(c *C) {
 		{"..", "b", 0},
 		{".ab", "aab", 1},
 		{".*", "abcd", 1},
+		{"^a", "ABC", 1},
+		{"B$", "abc", 0},
 	}
 	for _, v := range tbl {
 		f := Funcs[ast.Regexp]
@@ -475,6 +560,106 @@ func (s *testEvaluatorSuite) TestRegexp(c *C) {
 		c.Assert(err, IsNil)
 		c.Assert(match, testutil.DatumEquals, types.NewDatum(v.match), Commentf("%v", v))
 	}
+
+	// Binary strings are matched case-sensitively.
+	f := regexpFuncFactory(true)
+	match, err := f(types.MakeDatums("ABC", "^a"), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(match, testutil.DatumEquals, types.NewDatum(0))
+	match, err = f(types.MakeDatums("abc", "^a"), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(match, testutil.DatumEquals, types.NewDatum(1))
+	match, err = f(types.MakeDatums(nil, "^a"), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(match.IsNull(), IsTrue)
+
+	_, err = f(types.MakeDatums("abc", "("), s.ctx)
+	c.Assert(terror.ErrorEqual(err, errRegexp), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testEvaluatorSuite) TestIn(c *C) {
+	defer testleak.AfterTest(c)()
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().StmtCtx.IgnoreTruncate = true
+	a, b := newColumn("a"), newColumn("b")
+	b.Index = 1
+	consts := []Expression{
+		newLonglong(1),
+		&Constant{Value: types.NewUintDatum(2)},
+		&Constant{Value: types.NewUintDatum(math.MaxUint64)},
+		&Constant{Value: types.NewStringDatum("abc")},
+		&Constant{Value: types.NewBytesDatum([]byte("4"))},
+		&Constant{Value: types.NewFloat64Datum(5.5)},
+	}
+	nullConst := &Constant{Value: types.Datum{}}
+	targets := types.MakeDatums(nil, int64(1), uint64(2), int64(2), uint64(math.MaxUint64), int64(-1), "abc", []byte("abc"),
+		"1", "4", int64(4), "4abc", float64(5.5), "5.5", float64(1), int64(7), "x")
+	others := types.MakeDatums(nil, int64(7), "x")
+	for _, withNull := range []bool{false, true} {
+		args := append([]Expression{a}, consts...)
+		args = append(args, b)
+		if withNull {
+			args = append(args, nullConst)
+		}
+		fun, err := NewFunction(ast.In, types.NewFieldType(mysql.TypeLonglong), args...)
+		c.Assert(err, IsNil)
+		sf := fun.(*ScalarFunction)
+		// The function with hash sets must give the same result as comparing the values one by one.
+		for _, target := range targets {
+			for _, other := range others {
+				row := []types.Datum{target, other}
+				r, err := sf.Eval(row, ctx)
+				c.Assert(err, IsNil)
+				argValues := []types.Datum{target}
+				for _, arg := range args[1:] {
+					v, err := arg.Eval(row, ctx)
+					c.Assert(err, IsNil)
+					argValues = append(argValues, v)
+				}
+				expected, err := builtinIn(argValues, ctx)
+				c.Assert(err, IsNil)
+				c.Assert(r, testutil.DatumEquals, expected, Commentf("%v in %v", target, argValues[1:]))
+			}
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestRowIn(c *C) {
+	defer testleak.AfterTest(c)()
+	row := func(values ...interface{}) types.Datum {
+		var d types.Datum
+		d.SetRow(types.MakeDatums(values...))
+		return d
+	}
+	tbl := []struct {
+		target types.Datum
+		list   []types.Datum
+		ret    interface{}
+	}{
+		{row(1, 2), []types.Datum{row(1, 2), row(3, 4)}, int64(1)},
+		{row(1, 2), []types.Datum{row(1, 3), row(3, 4)}, int64(0)},
+		{row(1, "2"), []types.Datum{row(1.0, 2)}, int64(1)},
+		{row(1, nil), []types.Datum{row(1, 2), row(3, 4)}, nil},
+		{row(1, nil), []types.Datum{row(1, nil)}, nil},
+		{row(1, nil), []types.Datum{row(2, 2)}, int64(0)},
+		{row(1, 2), []types.Datum{row(1, nil), row(3, 4)}, nil},
+		{row(1, 2), []types.Datum{row(1, nil), row(1, 2)}, int64(1)},
+		{row(1, 2), []types.Datum{row(2, nil)}, int64(0)},
+	}
+	for _, t := range tbl {
+		args := make([]Expression, 0, len(t.list)+1)
+		for _, d := range append([]types.Datum{t.target}, t.list...) {
+			args = append(args, &Constant{Value: d})
+		}
+		fun, err := NewFunction(ast.In, types.NewFieldType(mysql.TypeLonglong), args...)
+		c.Assert(err, IsNil)
+		r, err := fun.Eval(nil, s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(r, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v in %v", t.target, t.list))
+		r, err = builtinIn(append([]types.Datum{t.target}, t.list...), s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(r, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v in %v", t.target, t.list))
+	}
 }
 
 func (s *testEvaluatorSuite) TestUnaryOp(c *C) {
@@ -496,6 +681,10 @@ func (s *testEvaluatorSuite) TestUnaryOp(c *C) {
 		// test BitNeg.
 		{nil, ast.BitNeg, nil},
 		{-1, ast.BitNeg, uint64(0)},
+		{0, ast.BitNeg, uint64(math.MaxUint64)},
+		{uint64(math.MaxUint64), ast.BitNeg, uint64(0)},
+		{"1", ast.BitNeg, uint64(math.MaxUint64 - 1)},
+		{1.5, ast.BitNeg, uint64(math.MaxUint64 - 2)},
 
 		// test Plus.
 		{nil, ast.UnaryPlus, nil},
@@ -578,3 +767,60 @@ func (s *testEvaluatorSuite) TestMod(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(r, testutil.DatumEquals, types.NewDatum(1.5))
 }
+
+func (s *testEvaluatorSuite) TestIntDivAndModSign(c *C) {
+	defer testleak.AfterTest(c)()
+	// DIV truncates toward zero, and the sign of MOD is the sign of the dividend.
+	tbl := []struct {
+		lhs interface{}
+		rhs interface{}
+		div interface{}
+		mod interface{}
+	}{
+		{7, 2, int64(3), int64(1)},
+		{-7, 2, int64(-3), int64(-1)},
+		{7, -2, int64(-3), int64(1)},
+		{-7, -2, int64(3), int64(-1)},
+		{-7, uint64(2), nil, int64(-1)},
+		{uint64(7), -2, nil, uint64(1)},
+		{7.5, 2, int64(3), 1.5},
+		{-7.5, 2, int64(-3), -1.5},
+		{7.5, -2, int64(-3), 1.5},
+		{-7.5, -2, int64(3), -1.5},
+		{types.NewDecFromFloatForTest(-7.5), types.NewDecFromInt(2), int64(-3), types.NewDecFromFloatForTest(-1.5)},
+		{types.NewDecFromFloatForTest(7.5), types.NewDecFromInt(-2), int64(-3), types.NewDecFromFloatForTest(1.5)},
+	}
+	for _, t := range tbl {
+		args := types.MakeDatums(t.lhs, t.rhs)
+		v, err := Funcs[ast.IntDiv].F(args, s.ctx)
+		if t.div == nil {
+			// The result of a negative value divided by an unsigned value is out of the unsigned range.
+			c.Assert(err, NotNil, Commentf("%v div %v", t.lhs, t.rhs))
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(v, testutil.DatumEquals, types.NewDatum(t.div), Commentf("%v div %v", t.lhs, t.rhs))
+		}
+		v, err = Funcs[ast.Mod].F(args, s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, types.NewDatum(t.mod), Commentf("%v mod %v", t.lhs, t.rhs))
+	}
+
+	// Division by zero returns NULL with a warning.
+	sc := s.ctx.GetSessionVars().StmtCtx
+	for _, op := range []string{ast.Div, ast.IntDiv, ast.Mod} {
+		for _, zero := range []interface{}{0, uint64(0), 0.0, types.NewDecFromInt(0)} {
+			warnCnt := len(sc.GetWarnings())
+			v, err := Funcs[op].F(types.MakeDatums(-7, zero), s.ctx)
+			c.Assert(err, IsNil)
+			c.Assert(v.IsNull(), IsTrue, Commentf("-7 %s %v", op, zero))
+			warnings := sc.GetWarnings()
+			c.Assert(warnings, HasLen, warnCnt+1)
+			c.Assert(terror.ErrorEqual(warnings[warnCnt], types.ErrDivByZero), IsTrue)
+		}
+	}
+	warnCnt := len(sc.GetWarnings())
+	v, err := Funcs[ast.Div].F(types.MakeDatums(nil, 0), s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(v.IsNull(), IsTrue)
+	c.Assert(sc.GetWarnings(), HasLen, warnCnt)
+}
@@ -32,12 +32,16 @@ import (
 var (
 	errInvalidOperation        = terror.ClassExpression.New(codeInvalidOperation, "invalid operation")
 	errIncorrectParameterCount = terror.ClassExpression.New(codeIncorrectParameterCount, "Incorrect parameter count")
+	errRegexp                  = terror.ClassExpression.New(codeRegexp, "Got error '%s' from regexp")
+	errCutValueGroupConcat     = terror.ClassExpression.New(codeCutValueGroupConcat, "Row %d was cut by GROUP_CONCAT()")
 )
 
 // Error codes.
 const (
 	codeInvalidOperation        terror.ErrCode = 1
 	codeIncorrectParameterCount                = 1582
+	codeRegexp                                 = 1139
+	codeCutValueGroupConcat                    = 1260
 )
 
 // EvalAstExpr evaluates ast expression directly.
@@ -273,6 +277,8 @@ func TableInfo2Schema(tbl *model.TableInfo) Schema {
 func init() {
 	expressionMySQLErrCodes := map[terror.ErrCode]uint16{
 		codeIncorrectParameterCount: mysql.ErrWrongParamcountToNativeFct,
+		codeRegexp:                  mysql.ErrRegexp,
+		codeCutValueGroupConcat:     mysql.ErrCutValueGroupConcat,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExpression] = expressionMySQLErrCodes
 }
@@ -135,14 +135,13 @@ func IsCurrentTimeExpr(e ast.ExprNode) bool {
 }
 
 func getSystemTimestamp(ctx context.Context) (time.Time, error) {
-	value := time.Now()
-
 	if ctx == nil {
-		return value, nil
+		return time.Now(), nil
 	}
 
 	// check whether use timestamp varibale
 	sessionVars := ctx.GetSessionVars()
+	value := sessionVars.StmtCtx.NowTs()
 	ts := varsutil.GetSystemVar(sessionVars, "timestamp")
 	if !ts.IsNull() && ts.GetString() != "" {
 		timestamp, err := ts.ToInt64(ctx.GetSessionVars().StmtCtx)
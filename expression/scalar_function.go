@@ -18,8 +18,10 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/types"
 )
@@ -68,10 +70,39 @@ func NewFunction(funcName string, retType *types.FieldType, args ...Expression)
 		Args:      funcArgs,
 		FuncName:  model.NewCIStr(funcName),
 		RetType:   retType,
-		Function:  f.F,
+		Function:  newBuiltinFunc(funcName, f.F, funcArgs),
 		ArgValues: make([]types.Datum, len(funcArgs))}, nil
 }
 
+// newBuiltinFunc creates a new instance for the functions that keep state between calls or
+// prepare state from their constant arguments, so that the state is not shared by different
+// scalar functions.
+func newBuiltinFunc(funcName string, f BuiltinFunc, args []Expression) BuiltinFunc {
+	switch funcName {
+	case ast.Like:
+		return likeFuncFactory(CaseInsensitiveCompare(args[:2]))
+	case ast.Regexp:
+		binary := false
+		for _, arg := range args {
+			if tp := arg.GetType(); tp != nil && types.IsBinaryStr(tp) {
+				binary = true
+			}
+		}
+		return regexpFuncFactory(binary)
+	case ast.In:
+		return inFuncFactory(args)
+	case ast.LT, ast.LE, ast.EQ, ast.NE, ast.GE, ast.GT, ast.NullEQ:
+		if CaseInsensitiveCompare(args) {
+			return ciCompareFunc(Funcs[funcName].F, false)
+		}
+		if CompareWithConnectionCollation(args) {
+			return ciCompareFunc(Funcs[funcName].F, true)
+		}
+		return Funcs[funcName].F
+	}
+	return f
+}
+
 //ScalarFuncs2Exprs converts []*ScalarFunction to []Expression.
 func ScalarFuncs2Exprs(funcs []*ScalarFunction) []Expression {
 	result := make([]Expression, 0, len(funcs))
@@ -85,13 +116,13 @@ func ScalarFuncs2Exprs(funcs []*ScalarFunction) []Expression {
 func (sf *ScalarFunction) Clone() Expression {
 	newFunc := &ScalarFunction{
 		FuncName:  sf.FuncName,
-		Function:  sf.Function,
 		RetType:   sf.RetType,
 		ArgValues: make([]types.Datum, len(sf.Args))}
 	newFunc.Args = make([]Expression, 0, len(sf.Args))
 	for _, arg := range sf.Args {
 		newFunc.Args = append(newFunc.Args, arg.Clone())
 	}
+	newFunc.Function = newBuiltinFunc(sf.FuncName.L, sf.Function, newFunc.Args)
 	return newFunc
 }
 
@@ -140,6 +171,9 @@ func (sf *ScalarFunction) Decorrelate(schema Schema) Expression {
 
 // Eval implements Expression interface.
 func (sf *ScalarFunction) Eval(row []types.Datum, ctx context.Context) (types.Datum, error) {
+	if sf.FuncName.L == ast.Case {
+		return sf.evalCaseWhen(row, ctx)
+	}
 	var err error
 	for i, arg := range sf.Args {
 		sf.ArgValues[i], err = arg.Eval(row, ctx)
@@ -147,7 +181,69 @@ func (sf *ScalarFunction) Eval(row []types.Datum, ctx context.Context) (types.Da
 			return types.Datum{}, errors.Trace(err)
 		}
 	}
-	return sf.Function(sf.ArgValues, ctx)
+	d, err := sf.Function(sf.ArgValues, ctx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	switch sf.FuncName.L {
+	case ast.If, ast.Ifnull, ast.Coalesce:
+		return sf.convertToRetType(d, ctx)
+	}
+	return d, nil
+}
+
+// evalCaseWhen evaluates the case function lazily, only the arguments before the first
+// matched branch are evaluated.
+func (sf *ScalarFunction) evalCaseWhen(row []types.Datum, ctx context.Context) (d types.Datum, err error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	l := len(sf.Args)
+	matched := -1
+	for i := 0; i < l-1; i += 2 {
+		cond, err := sf.Args[i].Eval(row, ctx)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		if cond.IsNull() {
+			continue
+		}
+		b, err := cond.ToBool(sc)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		if b == 1 {
+			matched = i + 1
+			break
+		}
+	}
+	// If case clause has else clause, l%2 == 1.
+	if matched == -1 && l%2 == 1 {
+		matched = l - 1
+	}
+	if matched == -1 {
+		return d, nil
+	}
+	d, err = sf.Args[matched].Eval(row, ctx)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	return sf.convertToRetType(d, ctx)
+}
+
+// convertToRetType converts the result of the functions which return one of their arguments
+// to the aggregated return type, so the values of a column have the same kind.
+func (sf *ScalarFunction) convertToRetType(d types.Datum, ctx context.Context) (types.Datum, error) {
+	if d.IsNull() || sf.RetType == nil {
+		return d, nil
+	}
+	var err error
+	switch tp := sf.RetType.Tp; tp {
+	case mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeNewDecimal, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeString:
+		if d.Kind() == types.KindBytes {
+			break
+		}
+		d, err = d.ConvertTo(ctx.GetSessionVars().StmtCtx, types.NewFieldType(tp))
+	}
+	return d, errors.Trace(err)
 }
 
 // HashCode implements Expression interface.
@@ -13,7 +13,13 @@
 
 package expression
 
-import "github.com/pingcap/tidb/ast"
+import (
+	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/util/charset"
+	"github.com/pingcap/tidb/util/types"
+)
 
 // ExtractColumns extracts all columns from an expression.
 func ExtractColumns(expr Expression) (cols []*Column) {
@@ -28,6 +34,55 @@ func ExtractColumns(expr Expression) (cols []*Column) {
 	return
 }
 
+// CaseInsensitiveCompare returns true if the strings in args are compared case-insensitively.
+// Like MySQL, the collation of a column has a higher precedence than the collation of a constant,
+// so the comparison is case-insensitive if a string column in args has a case-insensitive collation
+// and no argument is a binary string.
+func CaseInsensitiveCompare(args []Expression) bool {
+	ci := false
+	for _, arg := range args {
+		tp := arg.GetType()
+		if tp == nil {
+			continue
+		}
+		if types.IsBinaryStr(tp) {
+			return false
+		}
+		if _, ok := arg.(*Column); ok && types.IsCaseInsensitiveStr(tp) {
+			ci = true
+		}
+	}
+	return ci
+}
+
+// CompareWithConnectionCollation returns true if no argument decides the collation of the strings in args,
+// e.g. "'a' = 'A'" has no string column, the strings are compared with the collation of the connection then.
+func CompareWithConnectionCollation(args []Expression) bool {
+	hasStr := false
+	for _, arg := range args {
+		tp := arg.GetType()
+		if tp == nil {
+			continue
+		}
+		if types.IsBinaryStr(tp) {
+			return false
+		}
+		isStr := types.IsTypeChar(tp.Tp) || types.IsTypeBlob(tp.Tp) || tp.Tp == mysql.TypeVarString
+		if _, ok := arg.(*Column); ok && isStr {
+			return false
+		}
+		hasStr = hasStr || isStr
+	}
+	return hasStr
+}
+
+// connectionCaseInsensitive returns true if the collation of the connection, which is set by "set names",
+// compares strings case-insensitively.
+func connectionCaseInsensitive(ctx context.Context) bool {
+	_, collation := ctx.GetSessionVars().GetCharsetInfo()
+	return charset.IsCaseInsensitive(collation)
+}
+
 // ColumnSubstitute substitutes the columns in filter to expressions in select fields.
 // e.g. select * from (select b as a from t) k where a < 10 => select * from (select b as a from t where b < 10) k.
 func ColumnSubstitute(expr Expression, schema Schema, newExprs []Expression) Expression {
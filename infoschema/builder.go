@@ -38,6 +38,8 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) error {
 	} else if diff.Type == model.ActionDropSchema {
 		b.applyDropSchema(diff.SchemaID)
 		return nil
+	} else if diff.Type == model.ActionModifySchemaCharsetAndCollate {
+		return b.applyModifySchemaCharsetAndCollate(m, diff)
 	}
 	roDBInfo, ok := b.is.SchemaByID(diff.SchemaID)
 	if !ok {
@@ -52,6 +54,12 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) error {
 	case model.ActionTruncateTable:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
+	case model.ActionCreateView:
+		// The replaced view keeps its table ID.
+		if _, ok := b.is.TableByID(diff.TableID); ok {
+			oldTableID = diff.TableID
+		}
+		newTableID = diff.TableID
 	default:
 		oldTableID = diff.TableID
 		newTableID = diff.TableID
@@ -129,6 +137,26 @@ func (b *Builder) applyCreateSchema(m *meta.Meta, diff *model.SchemaDiff) error
 	return nil
 }
 
+func (b *Builder) applyModifySchemaCharsetAndCollate(m *meta.Meta, diff *model.SchemaDiff) error {
+	di, err := m.GetDatabase(diff.SchemaID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	roDBInfo, ok := b.is.SchemaByID(diff.SchemaID)
+	if di == nil || !ok {
+		// When we apply an old schema diff, the database may has been dropped already, so we need to fall back to
+		// full load.
+		return ErrDatabaseNotExists
+	}
+	// The DBInfo in meta doesn't hold the tables, so we update the charset and collation on a copy of the old one.
+	newDbInfo := *roDBInfo
+	newDbInfo.Charset = di.Charset
+	newDbInfo.Collate = di.Collate
+	b.copySchemaTables(roDBInfo.Name.L)
+	b.is.schemaMap[roDBInfo.Name.L].dbInfo = &newDbInfo
+	return nil
+}
+
 func (b *Builder) applyDropSchema(schemaID int64) {
 	di, ok := b.is.SchemaByID(schemaID)
 	if !ok {
@@ -54,6 +54,8 @@ var (
 	ErrIndexExists = terror.ClassSchema.New(codeIndexExists, "Duplicate Index")
 	// ErrMultiplePriKey returns for multiple primary keys.
 	ErrMultiplePriKey = terror.ClassSchema.New(codeMultiplePriKey, "Multiple primary key defined")
+	// ErrWrongObject returns for a table which isn't the expected type, e.g. dropping a base table by DROP VIEW.
+	ErrWrongObject = terror.ClassSchema.New(codeWrongObject, "'%s.%s' is not %s")
 )
 
 // InfoSchema is the interface used to retrieve the schema information.
@@ -301,6 +303,7 @@ const (
 	codeColumnExists   = 1060
 	codeIndexExists    = 1831
 	codeMultiplePriKey = 1068
+	codeWrongObject    = 1347
 )
 
 func init() {
@@ -318,6 +321,7 @@ func init() {
 		codeColumnExists:        mysql.ErrDupFieldName,
 		codeIndexExists:         mysql.ErrDupIndex,
 		codeMultiplePriKey:      mysql.ErrMultiplePriKey,
+		codeWrongObject:         mysql.ErrWrongObject,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassSchema] = schemaMySQLErrCodes
 	initInfoSchemaDB()
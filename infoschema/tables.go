@@ -16,17 +16,21 @@ package infoschema
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/juju/errors"
+	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessionctx/varsutil"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/util/charset"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -45,6 +49,9 @@ const (
 	tableReferConst    = "REFERENTIAL_CONSTRAINTS"
 	tableSessionVar    = "SESSION_VARIABLES"
 	tablePlugins       = "PLUGINS"
+	tableUserPrivs     = "USER_PRIVILEGES"
+	tableSchemaPrivs   = "SCHEMA_PRIVILEGES"
+	tableTablePrivs    = "TABLE_PRIVILEGES"
 )
 
 type columnInfo struct {
@@ -257,6 +264,33 @@ var pluginsCols = []columnInfo{
 	{"LOAD_OPTION", mysql.TypeVarchar, 64, 0, nil, nil},
 }
 
+// See https://dev.mysql.com/doc/refman/5.7/en/user-privileges-table.html
+var userPrivsCols = []columnInfo{
+	{"GRANTEE", mysql.TypeVarchar, 81, mysql.NotNullFlag, nil, nil},
+	{"TABLE_CATALOG", mysql.TypeVarchar, 512, mysql.NotNullFlag, nil, nil},
+	{"PRIVILEGE_TYPE", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"IS_GRANTABLE", mysql.TypeVarchar, 3, mysql.NotNullFlag, nil, nil},
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/schema-privileges-table.html
+var schemaPrivsCols = []columnInfo{
+	{"GRANTEE", mysql.TypeVarchar, 81, mysql.NotNullFlag, nil, nil},
+	{"TABLE_CATALOG", mysql.TypeVarchar, 512, mysql.NotNullFlag, nil, nil},
+	{"TABLE_SCHEMA", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"PRIVILEGE_TYPE", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"IS_GRANTABLE", mysql.TypeVarchar, 3, mysql.NotNullFlag, nil, nil},
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/table-privileges-table.html
+var tablePrivsCols = []columnInfo{
+	{"GRANTEE", mysql.TypeVarchar, 81, mysql.NotNullFlag, nil, nil},
+	{"TABLE_CATALOG", mysql.TypeVarchar, 512, mysql.NotNullFlag, nil, nil},
+	{"TABLE_SCHEMA", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"TABLE_NAME", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"PRIVILEGE_TYPE", mysql.TypeVarchar, 64, mysql.NotNullFlag, nil, nil},
+	{"IS_GRANTABLE", mysql.TypeVarchar, 3, mysql.NotNullFlag, nil, nil},
+}
+
 // See https://dev.mysql.com/doc/refman/5.7/en/partitions-table.html
 var partitionsCols = []columnInfo{
 	{"TABLE_CATALOG", mysql.TypeVarchar, 512, 0, nil, nil},
@@ -332,6 +366,170 @@ func dataForSessionVar(ctx context.Context) (records [][]types.Datum, err error)
 	return
 }
 
+// queryPrivTable reads the rows of a privilege table in the mysql schema. The grant statements
+// write to the privilege tables directly, so the rows always reflect the latest grants.
+// Like MySQL, a user who can't read the mysql schema only gets the rows of its own account,
+// matched the same way as the privilege checker loads them. userCol is the offset of the User
+// column, the Host column is the first column of all the privilege tables.
+func queryPrivTable(ctx context.Context, tableName string, userCol int) ([]*ast.ResultField, []*ast.Row, error) {
+	exec, ok := ctx.(sqlexec.RestrictedSQLExecutor)
+	if !ok {
+		return nil, nil, nil
+	}
+	readAll, err := canReadSystemDB(ctx)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var loginUser, loginHost string
+	if !readAll {
+		strs := strings.Split(ctx.GetSessionVars().User, "@")
+		if len(strs) != 2 {
+			return nil, nil, nil
+		}
+		loginUser, loginHost = strs[0], strs[1]
+	}
+	sql := fmt.Sprintf("SELECT * FROM %s.%s", mysql.SystemDB, tableName)
+	rs, err := exec.ExecRestrictedSQL(ctx, sql)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer rs.Close()
+	fs, err := rs.Fields()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var rows []*ast.Row
+	for {
+		row, err := rs.Next()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if row == nil {
+			break
+		}
+		if !readAll {
+			host := row.Data[0].GetString()
+			if row.Data[userCol].GetString() != loginUser || (host != loginHost && host != "%") {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	return fs, rows, nil
+}
+
+// canReadSystemDB returns true if the current user has the SELECT privilege on the mysql schema.
+func canReadSystemDB(ctx context.Context) (bool, error) {
+	checker := privilege.GetPrivilegeChecker(ctx)
+	if checker == nil {
+		return true, nil
+	}
+	db := &model.DBInfo{Name: model.NewCIStr(mysql.SystemDB)}
+	ok, err := checker.Check(ctx, db, nil, mysql.SelectPriv)
+	return ok, errors.Trace(err)
+}
+
+// privColumnsToNames returns the upper case names of the privileges granted in the 'Y'/'N' privilege
+// columns of mysql.user or mysql.db, and whether the grant option is granted.
+func privColumnsToNames(fs []*ast.ResultField, row *ast.Row) (names []string, grantable bool) {
+	for i, f := range fs {
+		p, ok := mysql.Col2PrivType[f.ColumnAsName.O]
+		if !ok || row.Data[i].GetMysqlEnum().String() != "Y" {
+			continue
+		}
+		if p == mysql.GrantPriv {
+			grantable = true
+			continue
+		}
+		names = append(names, strings.ToUpper(mysql.Priv2Str[p]))
+	}
+	return
+}
+
+func grantee(user, host string) string {
+	return fmt.Sprintf("'%s'@'%s'", user, host)
+}
+
+func isGrantable(grantable bool) string {
+	if grantable {
+		return "YES"
+	}
+	return "NO"
+}
+
+// mysql.user columns are Host, User, Password and then the privilege columns.
+func dataForUserPrivileges(ctx context.Context) ([][]types.Datum, error) {
+	fs, rows, err := queryPrivTable(ctx, mysql.UserTable, 1)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var records [][]types.Datum
+	for _, row := range rows {
+		user := grantee(row.Data[1].GetString(), row.Data[0].GetString())
+		names, grantable := privColumnsToNames(fs, row)
+		if len(names) == 0 {
+			// A user without any global privilege has the USAGE privilege.
+			names = []string{"USAGE"}
+		}
+		for _, name := range names {
+			records = append(records, types.MakeDatums(user, catalogVal, name, isGrantable(grantable)))
+		}
+	}
+	return records, nil
+}
+
+// mysql.db columns are Host, DB, User and then the privilege columns.
+func dataForSchemaPrivileges(ctx context.Context) ([][]types.Datum, error) {
+	fs, rows, err := queryPrivTable(ctx, mysql.DBTable, 2)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var records [][]types.Datum
+	for _, row := range rows {
+		user := grantee(row.Data[2].GetString(), row.Data[0].GetString())
+		db := row.Data[1].GetString()
+		names, grantable := privColumnsToNames(fs, row)
+		for _, name := range names {
+			records = append(records, types.MakeDatums(user, catalogVal, db, name, isGrantable(grantable)))
+		}
+	}
+	return records, nil
+}
+
+// mysql.tables_priv columns are Host, DB, User, Table_name, Grantor, Timestamp, Table_priv and Column_priv.
+func dataForTablePrivileges(ctx context.Context) ([][]types.Datum, error) {
+	_, rows, err := queryPrivTable(ctx, mysql.TablePrivTable, 2)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var records [][]types.Datum
+	for _, row := range rows {
+		user := grantee(row.Data[2].GetString(), row.Data[0].GetString())
+		db, tbl := row.Data[1].GetString(), row.Data[3].GetString()
+		privs := row.Data[6].GetMysqlSet().Name
+		if privs == "" {
+			continue
+		}
+		var names []string
+		grantable := false
+		for _, priv := range strings.Split(privs, ",") {
+			p, ok := mysql.SetStr2Priv[priv]
+			if !ok {
+				continue
+			}
+			if p == mysql.GrantPriv {
+				grantable = true
+				continue
+			}
+			names = append(names, strings.ToUpper(mysql.Priv2Str[p]))
+		}
+		for _, name := range names {
+			records = append(records, types.MakeDatums(user, catalogVal, db, tbl, name, isGrantable(grantable)))
+		}
+	}
+	return records, nil
+}
+
 var filesCols = []columnInfo{
 	{"FILE_ID", mysql.TypeLonglong, 4, 0, nil, nil},
 	{"FILE_NAME", mysql.TypeVarchar, 64, 0, nil, nil},
@@ -373,11 +571,12 @@ var filesCols = []columnInfo{
 func dataForSchemata(schemas []*model.DBInfo) [][]types.Datum {
 	rows := [][]types.Datum{}
 	for _, schema := range schemas {
+		charset, collation := charsetAndCollation(schema.Charset, schema.Collate)
 		record := types.MakeDatums(
-			catalogVal,                 // CATALOG_NAME
-			schema.Name.O,              // SCHEMA_NAME
-			mysql.DefaultCharset,       // DEFAULT_CHARACTER_SET_NAME
-			mysql.DefaultCollationName, // DEFAULT_COLLATION_NAME
+			catalogVal,    // CATALOG_NAME
+			schema.Name.O, // SCHEMA_NAME
+			charset,       // DEFAULT_CHARACTER_SET_NAME
+			collation,     // DEFAULT_COLLATION_NAME
 			nil,
 		)
 		rows = append(rows, record)
@@ -388,29 +587,38 @@ func dataForSchemata(schemas []*model.DBInfo) [][]types.Datum {
 func dataForTables(schemas []*model.DBInfo) [][]types.Datum {
 	rows := [][]types.Datum{}
 	for _, schema := range schemas {
+		tableType := "BASE TABLE"
+		if schema.Name.L == strings.ToLower(Name) {
+			tableType = "SYSTEM VIEW"
+		}
 		for _, table := range schema.Tables {
+			_, collation := charsetAndCollation(table.Charset, table.Collate)
+			tblType := tableType
+			if table.IsView() {
+				tblType = "VIEW"
+			}
 			record := types.MakeDatums(
-				catalogVal,          // TABLE_CATALOG
-				schema.Name.O,       // TABLE_SCHEMA
-				table.Name.O,        // TABLE_NAME
-				"BASE_TABLE",        // TABLE_TYPE
-				"InnoDB",            // ENGINE
-				uint64(10),          // VERSION
-				"Compact",           // ROW_FORMAT
-				uint64(0),           // TABLE_ROWS
-				uint64(0),           // AVG_ROW_LENGTH
-				uint64(16384),       // DATA_LENGTH
-				uint64(0),           // MAX_DATA_LENGTH
-				uint64(0),           // INDEX_LENGTH
-				uint64(0),           // DATA_FREE
-				nil,                 // AUTO_INCREMENT
-				nil,                 // CREATE_TIME
-				nil,                 // UPDATE_TIME
-				nil,                 // CHECK_TIME
-				"latin1_swedish_ci", // TABLE_COLLATION
-				nil,                 // CHECKSUM
-				"",                  // CREATE_OPTIONS
-				"",                  // TABLE_COMMENT
+				catalogVal,    // TABLE_CATALOG
+				schema.Name.O, // TABLE_SCHEMA
+				table.Name.O,  // TABLE_NAME
+				tblType,       // TABLE_TYPE
+				"InnoDB",      // ENGINE
+				uint64(10),    // VERSION
+				"Compact",     // ROW_FORMAT
+				uint64(0),     // TABLE_ROWS
+				uint64(0),     // AVG_ROW_LENGTH
+				uint64(16384), // DATA_LENGTH
+				uint64(0),     // MAX_DATA_LENGTH
+				uint64(0),     // INDEX_LENGTH
+				uint64(0),     // DATA_FREE
+				nil,           // AUTO_INCREMENT
+				nil,           // CREATE_TIME
+				nil,           // UPDATE_TIME
+				nil,           // CHECK_TIME
+				collation,     // TABLE_COLLATION
+				nil,           // CHECKSUM
+				"",            // CREATE_OPTIONS
+				table.Comment, // TABLE_COMMENT
 			)
 			rows = append(rows, record)
 		}
@@ -418,6 +626,21 @@ func dataForTables(schemas []*model.DBInfo) [][]types.Datum {
 	return rows
 }
 
+// charsetAndCollation fills the missing charset or collation of a schema or table with the defaults.
+func charsetAndCollation(cs, co string) (string, string) {
+	if cs == "" {
+		cs = mysql.DefaultCharset
+	}
+	if co == "" {
+		var err error
+		co, err = charset.GetDefaultCollation(cs)
+		if err != nil {
+			co = mysql.DefaultCollationName
+		}
+	}
+	return cs, co
+}
+
 func dataForColumns(schemas []*model.DBInfo) [][]types.Datum {
 	rows := [][]types.Datum{}
 	for _, schema := range schemas {
@@ -468,7 +691,7 @@ func dataForColumnsInTable(schema *model.DBInfo, tbl *model.TableInfo) [][]types
 			columnDesc.Key,                    // COLUMN_KEY
 			columnDesc.Extra,                  // EXTRA
 			"select,insert,update,references", // PRIVILEGES
-			"", // COLUMN_COMMENT
+			col.Comment, // COLUMN_COMMENT
 		)
 		rows = append(rows, record)
 	}
@@ -568,6 +791,9 @@ var tableNameToColumns = map[string]([]columnInfo){
 	tableReferConst:    referConstCols,
 	tableSessionVar:    sessionVarCols,
 	tablePlugins:       pluginsCols,
+	tableUserPrivs:     userPrivsCols,
+	tableSchemaPrivs:   schemaPrivsCols,
+	tableTablePrivs:    tablePrivsCols,
 }
 
 func createInfoSchemaTable(handle *Handle, meta *model.TableInfo) *infoschemaTable {
@@ -623,6 +849,12 @@ func (it *infoschemaTable) getRows(ctx context.Context, cols []*table.Column) (f
 		fullRows = dataForColltions()
 	case tableSessionVar:
 		fullRows, err = dataForSessionVar(ctx)
+	case tableUserPrivs:
+		fullRows, err = dataForUserPrivileges(ctx)
+	case tableSchemaPrivs:
+		fullRows, err = dataForSchemaPrivileges(ctx)
+	case tableTablePrivs:
+		fullRows, err = dataForTablePrivileges(ctx)
 	case tableFiles:
 	case tableProfiling:
 	case tablePartitions:
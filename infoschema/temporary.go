@@ -0,0 +1,129 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"sort"
+
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table"
+)
+
+// TemporaryTables is the registry of the temporary tables created by a session.
+// The temporary tables are only visible to the session, and they shadow the tables with the same names.
+type TemporaryTables struct {
+	byName map[string]table.Table
+	byID   map[int64]table.Table
+}
+
+// GetTemporaryTables returns the temporary tables of the session, the registry is created on the first call.
+func GetTemporaryTables(vars *variable.SessionVars) *TemporaryTables {
+	if temps, ok := vars.TemporaryTables.(*TemporaryTables); ok {
+		return temps
+	}
+	temps := &TemporaryTables{
+		byName: make(map[string]table.Table),
+		byID:   make(map[int64]table.Table),
+	}
+	vars.TemporaryTables = temps
+	return temps
+}
+
+func temporaryTableKey(schema, tbl model.CIStr) string {
+	return schema.L + "." + tbl.L
+}
+
+// Add adds a temporary table in schema.
+func (t *TemporaryTables) Add(schema model.CIStr, tbl table.Table) {
+	t.byName[temporaryTableKey(schema, tbl.Meta().Name)] = tbl
+	t.byID[tbl.Meta().ID] = tbl
+}
+
+// Remove removes the temporary table schema.tbl, it returns the removed table.
+func (t *TemporaryTables) Remove(schema, tbl model.CIStr) (table.Table, bool) {
+	key := temporaryTableKey(schema, tbl)
+	removed, ok := t.byName[key]
+	if ok {
+		delete(t.byName, key)
+		delete(t.byID, removed.Meta().ID)
+	}
+	return removed, ok
+}
+
+// TableByName returns the temporary table schema.tbl.
+func (t *TemporaryTables) TableByName(schema, tbl model.CIStr) (table.Table, bool) {
+	found, ok := t.byName[temporaryTableKey(schema, tbl)]
+	return found, ok
+}
+
+// Tables returns all the temporary tables ordered by the table IDs.
+func (t *TemporaryTables) Tables() []table.Table {
+	tables := make(sortedTables, 0, len(t.byID))
+	for _, tbl := range t.byID {
+		tables = append(tables, tbl)
+	}
+	sort.Sort(tables)
+	return tables
+}
+
+// Len returns the number of the temporary tables.
+func (t *TemporaryTables) Len() int {
+	return len(t.byID)
+}
+
+// temporaryInfoSchema looks up the temporary tables of a session before the tables of the InfoSchema.
+// The temporary tables aren't listed in SchemaTables like MySQL SHOW TABLES.
+type temporaryInfoSchema struct {
+	InfoSchema
+	temps *TemporaryTables
+}
+
+// WithTemporaryTables returns an InfoSchema which finds the temporary tables of the session before the tables in is.
+func WithTemporaryTables(is InfoSchema, vars *variable.SessionVars) InfoSchema {
+	temps, ok := vars.TemporaryTables.(*TemporaryTables)
+	if !ok || temps.Len() == 0 {
+		return is
+	}
+	return &temporaryInfoSchema{InfoSchema: is, temps: temps}
+}
+
+func (is *temporaryInfoSchema) TableByName(schema, tbl model.CIStr) (table.Table, error) {
+	if t, ok := is.temps.TableByName(schema, tbl); ok {
+		return t, nil
+	}
+	return is.InfoSchema.TableByName(schema, tbl)
+}
+
+func (is *temporaryInfoSchema) TableExists(schema, tbl model.CIStr) bool {
+	if _, ok := is.temps.TableByName(schema, tbl); ok {
+		return true
+	}
+	return is.InfoSchema.TableExists(schema, tbl)
+}
+
+func (is *temporaryInfoSchema) TableByID(id int64) (table.Table, bool) {
+	if t, ok := is.temps.byID[id]; ok {
+		return t, true
+	}
+	return is.InfoSchema.TableByID(id)
+}
+
+func (is *temporaryInfoSchema) AllocByID(id int64) (autoid.Allocator, bool) {
+	if t, ok := is.temps.byID[id]; ok {
+		return t.Allocator(), true
+	}
+	return is.InfoSchema.AllocByID(id)
+}
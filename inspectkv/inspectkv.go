@@ -200,6 +200,9 @@ func checkIndexAndRecord(txn kv.Transaction, t table.Table, idx table.Index) err
 		if err != nil {
 			return errors.Trace(err)
 		}
+		for i, col := range cols {
+			table.FoldIndexValue(col.ToInfo(), &vals2[i])
+		}
 		if !reflect.DeepEqual(vals1, vals2) {
 			record1 := &RecordData{Handle: h, Values: vals1}
 			record2 := &RecordData{Handle: h, Values: vals2}
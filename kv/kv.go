@@ -79,6 +79,11 @@ type Transaction interface {
 	String() string
 	// LockKeys tries to lock the entries with the keys in KV store.
 	LockKeys(keys ...Key) error
+	// LockKeysShared locks the entries with the keys in shared mode. The commit fails if the entries are
+	// modified by other transactions after the transaction starts, but the transactions locking the same
+	// entries in shared mode don't conflict with each other. A key which is also locked by LockKeys or
+	// written in the transaction is locked exclusively.
+	LockKeysShared(keys ...Key) error
 	// SetOption sets an option with a value, when val is nil, uses the default
 	// value of this option.
 	SetOption(opt Option, val interface{})
@@ -91,6 +96,11 @@ type Transaction interface {
 	// Valid returns if the transaction is valid.
 	// A transaction become invalid after commit or rollback.
 	Valid() bool
+	// Savepoint saves the current writes of the transaction.
+	// The buffered writes are copied, so the cost is proportional to the size of the transaction.
+	Savepoint() (*Savepoint, error)
+	// RollbackToSavepoint undoes the writes made after the savepoint was taken.
+	RollbackToSavepoint(sp *Savepoint) error
 }
 
 // Client is used to send request to KV layer.
@@ -41,6 +41,10 @@ func (t *mockTxn) LockKeys(keys ...Key) error {
 	return nil
 }
 
+func (t *mockTxn) LockKeysShared(keys ...Key) error {
+	return nil
+}
+
 func (t *mockTxn) SetOption(opt Option, val interface{}) {
 	t.opts[opt] = val
 	return
@@ -85,6 +89,14 @@ func (t *mockTxn) Valid() bool {
 	return t.valid
 }
 
+func (t *mockTxn) Savepoint() (*Savepoint, error) {
+	return &Savepoint{}, nil
+}
+
+func (t *mockTxn) RollbackToSavepoint(sp *Savepoint) error {
+	return nil
+}
+
 // mockStorage is used to start a must commit-failed txn.
 type mockStorage struct {
 }
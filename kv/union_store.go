@@ -35,6 +35,10 @@ type UnionStore interface {
 	DelOption(opt Option)
 	// GetOption gets an option.
 	GetOption(opt Option) interface{}
+	// Savepoint saves the buffered kv pairs, so that they can be restored later.
+	Savepoint() (*Savepoint, error)
+	// RollbackToSavepoint discards the kv pairs buffered after the savepoint was taken.
+	RollbackToSavepoint(sp *Savepoint) error
 }
 
 // Option is used for customizing kv store's behaviors during a transaction.
@@ -54,6 +58,12 @@ type conditionPair struct {
 	err   error
 }
 
+// Savepoint is a copy of the buffered state of a UnionStore.
+type Savepoint struct {
+	buffer             MemBuffer
+	lazyConditionPairs map[string](*conditionPair)
+}
+
 // UnionStore is an in-memory Store which contains a buffer for write and a
 // snapshot for read.
 type unionStore struct {
@@ -201,6 +211,58 @@ func (us *unionStore) CheckLazyConditionPairs() error {
 	return nil
 }
 
+// Savepoint implements the UnionStore Savepoint interface.
+func (us *unionStore) Savepoint() (*Savepoint, error) {
+	buffer, err := copyMemBuffer(us.BufferStore.MemBuffer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Savepoint{
+		buffer:             buffer,
+		lazyConditionPairs: copyConditionPairs(us.lazyConditionPairs),
+	}, nil
+}
+
+// RollbackToSavepoint implements the UnionStore RollbackToSavepoint interface.
+// The savepoint is kept unchanged, so it can be rolled back to again.
+func (us *unionStore) RollbackToSavepoint(sp *Savepoint) error {
+	buffer, err := copyMemBuffer(sp.buffer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	us.BufferStore.MemBuffer = buffer
+	us.lazyConditionPairs = copyConditionPairs(sp.lazyConditionPairs)
+	return nil
+}
+
+func copyMemBuffer(mb MemBuffer) (MemBuffer, error) {
+	buffer := NewMemDbBuffer()
+	iter, err := mb.Seek(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if len(iter.Value()) == 0 {
+			err = buffer.Delete(iter.Key())
+		} else {
+			err = buffer.Set(iter.Key(), iter.Value())
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return buffer, nil
+}
+
+func copyConditionPairs(pairs map[string](*conditionPair)) map[string](*conditionPair) {
+	m := make(map[string](*conditionPair), len(pairs))
+	for k, v := range pairs {
+		m[k] = v
+	}
+	return m
+}
+
 // SetOption implements the UnionStore SetOption interface.
 func (us *unionStore) SetOption(opt Option, val interface{}) {
 	us.opts[opt] = val
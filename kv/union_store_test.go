@@ -125,6 +125,38 @@ func (s *testUnionStoreSuite) TestLazyConditionCheck(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testUnionStoreSuite) TestSavepoint(c *C) {
+	defer testleak.AfterTest(c)()
+	s.store.Set([]byte("1"), []byte("1"))
+	s.store.Set([]byte("2"), []byte("2"))
+
+	s.us.Set([]byte("1"), []byte("10"))
+	sp, err := s.us.Savepoint()
+	c.Assert(err, IsNil)
+	s.us.Set([]byte("3"), []byte("3"))
+	s.us.SetOption(PresumeKeyNotExists, nil)
+	_, err = s.us.Get([]byte("2"))
+	c.Assert(IsErrNotFound(err), IsTrue)
+	s.us.DelOption(PresumeKeyNotExists)
+	c.Assert(s.us.CheckLazyConditionPairs(), NotNil)
+	s.us.Delete([]byte("2"))
+
+	err = s.us.RollbackToSavepoint(sp)
+	c.Assert(err, IsNil)
+	iter, err := s.us.Seek(nil)
+	c.Assert(err, IsNil)
+	checkIterator(c, iter, [][]byte{[]byte("1"), []byte("2")}, [][]byte{[]byte("10"), []byte("2")})
+	c.Assert(s.us.CheckLazyConditionPairs(), IsNil)
+
+	// The savepoint is not changed by the writes after rolling back to it.
+	s.us.Delete([]byte("1"))
+	err = s.us.RollbackToSavepoint(sp)
+	c.Assert(err, IsNil)
+	v, err := s.us.Get([]byte("1"))
+	c.Assert(err, IsNil)
+	c.Assert(v, BytesEquals, []byte("10"))
+}
+
 func checkIterator(c *C, iter Iterator, keys [][]byte, values [][]byte) {
 	defer iter.Close()
 	c.Assert(len(keys), Equals, len(values))
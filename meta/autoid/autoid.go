@@ -173,6 +173,37 @@ func (alloc *memoryAllocator) Alloc(tableID int64) (int64, error) {
 	return alloc.base, nil
 }
 
+// localAllocator allocates the IDs of a table in memory, it's used by a table which is only visible to a session,
+// so the IDs don't need to be persisted or be unique across the tables.
+type localAllocator struct {
+	mu   sync.Mutex
+	base int64
+}
+
+// Rebase implements autoid.Allocator Rebase interface.
+func (alloc *localAllocator) Rebase(tableID, newBase int64, allocIDs bool) error {
+	if tableID == 0 {
+		return errInvalidTableID.Gen("Invalid tableID")
+	}
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	if newBase > alloc.base {
+		alloc.base = newBase
+	}
+	return nil
+}
+
+// Alloc implements autoid.Allocator Alloc interface.
+func (alloc *localAllocator) Alloc(tableID int64) (int64, error) {
+	if tableID == 0 {
+		return 0, errInvalidTableID.Gen("Invalid tableID")
+	}
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	alloc.base++
+	return alloc.base, nil
+}
+
 // NewAllocator returns a new auto increment id generator on the store.
 func NewAllocator(store kv.Storage, dbID int64) Allocator {
 	return &allocator{
@@ -188,6 +219,11 @@ func NewMemoryAllocator(dbID int64) Allocator {
 	}
 }
 
+// NewLocalAllocator returns a new auto increment id generator in memory for a single table.
+func NewLocalAllocator() Allocator {
+	return &localAllocator{}
+}
+
 //autoid error codes.
 const codeInvalidTableID terror.ErrCode = 1
 
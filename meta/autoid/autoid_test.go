@@ -186,3 +186,26 @@ func (*testSuite) TestConcurrentAlloc(c *C) {
 	err = <-errCh
 	c.Assert(err, IsNil)
 }
+
+func (*testSuite) TestLocalAllocator(c *C) {
+	alloc := NewLocalAllocator()
+	_, err := alloc.Alloc(0)
+	c.Assert(err, NotNil)
+	id, err := alloc.Alloc(1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(1))
+	id, err = alloc.Alloc(1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(2))
+
+	c.Assert(alloc.Rebase(1, 10, false), IsNil)
+	id, err = alloc.Alloc(1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(11))
+	// A smaller base doesn't take effect.
+	c.Assert(alloc.Rebase(1, 5, true), IsNil)
+	id, err = alloc.Alloc(1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(12))
+	c.Assert(alloc.Rebase(0, 20, false), NotNil)
+}
@@ -62,6 +62,7 @@ var (
 	mTableIDPrefix    = "TID"
 	mBootstrapKey     = []byte("BootstrapKey")
 	mTableStatsPrefix = "TStats"
+	mTableStatsVerKey = "TStatsVer"
 	mSchemaDiffPrefix = "Diff"
 )
 
@@ -704,7 +705,11 @@ func (m *Meta) tableStatsKey(tableID int64) []byte {
 	return []byte(fmt.Sprintf("%s:%d", mTableStatsPrefix, tableID))
 }
 
-// SetTableStats sets table statistics.
+func (m *Meta) tableStatsVersionKey(tableID int64) []byte {
+	return []byte(fmt.Sprintf("%s:%d", mTableStatsVerKey, tableID))
+}
+
+// SetTableStats sets table statistics, the build timestamp of the statistics is saved as its version.
 func (m *Meta) SetTableStats(tableID int64, tpb *statistics.TablePB) error {
 	key := m.tableStatsKey(tableID)
 	data, err := proto.Marshal(tpb)
@@ -715,7 +720,14 @@ func (m *Meta) SetTableStats(tableID int64, tpb *statistics.TablePB) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	err = m.txn.Set(m.tableStatsVersionKey(tableID), []byte(fmt.Sprintf("%d", tpb.GetTs())))
+	return errors.Trace(err)
+}
+
+// GetTableStatsVersion gets the version of the table statistics, it's 0 if the version isn't saved.
+func (m *Meta) GetTableStatsVersion(tableID int64) (int64, error) {
+	ver, err := m.txn.GetInt64(m.tableStatsVersionKey(tableID))
+	return ver, errors.Trace(err)
 }
 
 // GetTableStats gets table statistics.
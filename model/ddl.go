@@ -40,6 +40,8 @@ const (
 	ActionDropForeignKey
 	ActionTruncateTable
 	ActionModifyColumn
+	ActionModifySchemaCharsetAndCollate
+	ActionCreateView
 )
 
 func (action ActionType) String() string {
@@ -68,6 +70,10 @@ func (action ActionType) String() string {
 		return "truncate table"
 	case ActionModifyColumn:
 		return "modify column"
+	case ActionModifySchemaCharsetAndCollate:
+		return "modify schema charset and collate"
+	case ActionCreateView:
+		return "create view"
 	default:
 		return "none"
 	}
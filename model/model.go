@@ -83,12 +83,15 @@ type TableInfo struct {
 	Columns     []*ColumnInfo `json:"cols"`
 	Indices     []*IndexInfo  `json:"index_info"`
 	ForeignKeys []*FKInfo     `json:"fk_info"`
+	Checks      []*CheckInfo  `json:"check_info"`
 	State       SchemaState   `json:"state"`
 	PKIsHandle  bool          `json:"pk_is_handle"`
 	Comment     string        `json:"comment"`
 	AutoIncID   int64         `json:"auto_inc_id"`
 	MaxColumnID int64         `json:"max_col_id"`
 	MaxIndexID  int64         `json:"max_idx_id"`
+	// View is the definition of the view, it is nil for a base table.
+	View *ViewInfo `json:"view"`
 }
 
 // Clone clones TableInfo.
@@ -97,6 +100,7 @@ func (t *TableInfo) Clone() *TableInfo {
 	nt.Columns = make([]*ColumnInfo, len(t.Columns))
 	nt.Indices = make([]*IndexInfo, len(t.Indices))
 	nt.ForeignKeys = make([]*FKInfo, len(t.ForeignKeys))
+	nt.Checks = make([]*CheckInfo, len(t.Checks))
 
 	for i := range t.Columns {
 		nt.Columns[i] = t.Columns[i].Clone()
@@ -110,9 +114,45 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	for i := range t.Checks {
+		nt.Checks[i] = t.Checks[i].Clone()
+	}
+
+	if t.View != nil {
+		nt.View = t.View.Clone()
+	}
+
 	return &nt
 }
 
+// IsView checks whether the table is a view.
+func (t *TableInfo) IsView() bool {
+	return t.View != nil
+}
+
+// ViewInfo provides meta data describing a view.
+// It corresponds to the statement `CREATE VIEW Name (Cols) AS SelectStmt;`
+// See https://dev.mysql.com/doc/refman/5.7/en/create-view.html
+type ViewInfo struct {
+	// SelectStmt is the text of the select statement, it is parsed and planned every time the view is queried.
+	SelectStmt string `json:"view_select"`
+	// Cols is the column list in the view definition, it is empty if the column names are derived from the select statement.
+	Cols []CIStr `json:"view_cols"`
+	// DefaultSchema is the current database when the view is created, the unqualified table names in SelectStmt refer to it.
+	DefaultSchema string `json:"view_default_schema"`
+	// Charset and Collate are the client charset and the connection collation when the view is created.
+	Charset string `json:"view_charset"`
+	Collate string `json:"view_collate"`
+}
+
+// Clone clones ViewInfo.
+func (v *ViewInfo) Clone() *ViewInfo {
+	nv := *v
+	nv.Cols = make([]CIStr, len(v.Cols))
+	copy(nv.Cols, v.Cols)
+	return &nv
+}
+
 // IndexColumn provides index column info.
 type IndexColumn struct {
 	Name   CIStr `json:"name"`   // Index name
@@ -191,6 +231,9 @@ type FKInfo struct {
 	OnDelete int         `json:"on_delete"`
 	OnUpdate int         `json:"on_update"`
 	State    SchemaState `json:"state"`
+	// RefSchema is the database of the referenced table, it is empty for the foreign keys
+	// created before it was recorded, the referenced table is in the same database then.
+	RefSchema CIStr `json:"ref_schema"`
 }
 
 // Clone clones FKInfo.
@@ -205,6 +248,24 @@ func (fk *FKInfo) Clone() *FKInfo {
 	return &nfk
 }
 
+// CheckInfo provides meta data describing a check constraint.
+type CheckInfo struct {
+	Name CIStr `json:"check_name"`
+	// Expr is the text of the boolean expression, it is parsed again when the table is written.
+	Expr string `json:"expr"`
+	// Cols are the columns used in the expression.
+	Cols  []CIStr     `json:"cols"`
+	State SchemaState `json:"state"`
+}
+
+// Clone clones CheckInfo.
+func (c *CheckInfo) Clone() *CheckInfo {
+	nc := *c
+	nc.Cols = make([]CIStr, len(c.Cols))
+	copy(nc.Cols, c.Cols)
+	return &nc
+}
+
 // DBInfo provides meta data describing a DB.
 type DBInfo struct {
 	ID      int64        `json:"id"`      // Database ID
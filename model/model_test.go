@@ -59,6 +59,13 @@ func (*testSuite) TestClone(c *C) {
 		Primary: true,
 	}
 
+	check := &CheckInfo{
+		Name:  NewCIStr("t_chk_1"),
+		Expr:  "c > 0",
+		Cols:  []CIStr{NewCIStr("c")},
+		State: StatePublic,
+	}
+
 	table := &TableInfo{
 		ID:          1,
 		Name:        NewCIStr("t"),
@@ -67,6 +74,21 @@ func (*testSuite) TestClone(c *C) {
 		Columns:     []*ColumnInfo{column},
 		Indices:     []*IndexInfo{index},
 		ForeignKeys: []*FKInfo{},
+		Checks:      []*CheckInfo{check},
+	}
+
+	view := &TableInfo{
+		ID:          2,
+		Name:        NewCIStr("v"),
+		Columns:     []*ColumnInfo{column},
+		Indices:     []*IndexInfo{},
+		ForeignKeys: []*FKInfo{},
+		Checks:      []*CheckInfo{},
+		View: &ViewInfo{
+			SelectStmt:    "select c from t",
+			Cols:          []CIStr{NewCIStr("c")},
+			DefaultSchema: "test",
+		},
 	}
 
 	dbInfo := &DBInfo{
@@ -74,11 +96,15 @@ func (*testSuite) TestClone(c *C) {
 		Name:    NewCIStr("test"),
 		Charset: "utf8",
 		Collate: "utf8",
-		Tables:  []*TableInfo{table},
+		Tables:  []*TableInfo{table, view},
 	}
 
 	n := dbInfo.Clone()
 	c.Assert(n, DeepEquals, dbInfo)
+	c.Assert(n.Tables[0].IsView(), IsFalse)
+	c.Assert(n.Tables[1].IsView(), IsTrue)
+	n.Tables[1].View.Cols[0] = NewCIStr("d")
+	c.Assert(view.View.Cols[0].O, Equals, "c")
 }
 
 func (*testSuite) TestJobCodec(c *C) {
@@ -147,6 +173,8 @@ func (testSuite) TestState(c *C) {
 		ActionDropColumn,
 		ActionAddIndex,
 		ActionDropIndex,
+		ActionModifySchemaCharsetAndCollate,
+		ActionCreateView,
 	}
 
 	for _, action := range actionTbl {
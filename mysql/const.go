@@ -13,6 +13,8 @@
 
 package mysql
 
+import "strings"
+
 // Version informations.
 const (
 	MinProtocolVersion byte = 10
@@ -89,6 +91,42 @@ const (
 	ComResetConnection
 )
 
+// Command2Str is the command information to command name.
+var Command2Str = map[byte]string{
+	ComSleep:            "Sleep",
+	ComQuit:             "Quit",
+	ComInitDB:           "Init DB",
+	ComQuery:            "Query",
+	ComFieldList:        "Field List",
+	ComCreateDB:         "Create DB",
+	ComDropDB:           "Drop DB",
+	ComRefresh:          "Refresh",
+	ComShutdown:         "Shutdown",
+	ComStatistics:       "Statistics",
+	ComProcessInfo:      "Processlist",
+	ComConnect:          "Connect",
+	ComProcessKill:      "Kill",
+	ComDebug:            "Debug",
+	ComPing:             "Ping",
+	ComTime:             "Time",
+	ComDelayedInsert:    "Delayed Insert",
+	ComChangeUser:       "Change User",
+	ComBinlogDump:       "Binlog Dump",
+	ComTableDump:        "Table Dump",
+	ComConnectOut:       "Connect out",
+	ComRegisterSlave:    "Register Slave",
+	ComStmtPrepare:      "Prepare",
+	ComStmtExecute:      "Execute",
+	ComStmtSendLongData: "Long Data",
+	ComStmtClose:        "Close stmt",
+	ComStmtReset:        "Reset stmt",
+	ComSetOption:        "Set option",
+	ComStmtFetch:        "Fetch",
+	ComDaemon:           "Daemon",
+	ComBinlogDumpGtid:   "Binlog Dump GTID",
+	ComResetConnection:  "Reset connect",
+}
+
 // Client informations.
 const (
 	ClientLongPassword uint32 = 1 << iota
@@ -174,6 +212,12 @@ const (
 	ExecutePriv
 	// IndexPriv is the privilege to create/drop index.
 	IndexPriv
+	// ProcessPriv is the privilege to see the connections of other users in processlist.
+	ProcessPriv
+	// SuperPriv is the privilege to kill the connections of other users.
+	SuperPriv
+	// FilePriv is the privilege to read and write files on the server host.
+	FilePriv
 	// AllPriv is the privilege for all actions.
 	AllPriv
 )
@@ -192,6 +236,9 @@ var Priv2UserCol = map[PrivilegeType]string{
 	AlterPriv:      "Alter_priv",
 	ExecutePriv:    "Execute_priv",
 	IndexPriv:      "Index_priv",
+	ProcessPriv:    "Process_priv",
+	SuperPriv:      "Super_priv",
+	FilePriv:       "File_priv",
 }
 
 // Col2PrivType is the privilege tables column name to privilege type.
@@ -208,10 +255,13 @@ var Col2PrivType = map[string]PrivilegeType{
 	"Alter_priv":       AlterPriv,
 	"Execute_priv":     ExecutePriv,
 	"Index_priv":       IndexPriv,
+	"Process_priv":     ProcessPriv,
+	"Super_priv":       SuperPriv,
+	"File_priv":        FilePriv,
 }
 
 // AllGlobalPrivs is all the privileges in global scope.
-var AllGlobalPrivs = []PrivilegeType{SelectPriv, InsertPriv, UpdatePriv, DeletePriv, CreatePriv, DropPriv, GrantPriv, AlterPriv, ShowDBPriv, ExecutePriv, IndexPriv, CreateUserPriv}
+var AllGlobalPrivs = []PrivilegeType{SelectPriv, InsertPriv, UpdatePriv, DeletePriv, CreatePriv, DropPriv, GrantPriv, AlterPriv, ShowDBPriv, ExecutePriv, IndexPriv, CreateUserPriv, ProcessPriv, SuperPriv, FilePriv}
 
 // Priv2Str is the map for privilege to string.
 var Priv2Str = map[PrivilegeType]string{
@@ -227,6 +277,9 @@ var Priv2Str = map[PrivilegeType]string{
 	AlterPriv:      "Alter",
 	ExecutePriv:    "Execute",
 	IndexPriv:      "Index",
+	ProcessPriv:    "Process",
+	SuperPriv:      "Super",
+	FilePriv:       "File",
 }
 
 // Priv2SetStr is the map for privilege to string.
@@ -268,3 +321,117 @@ var AllColumnPrivs = []PrivilegeType{SelectPriv, InsertPriv, UpdatePriv}
 
 // AllPrivilegeLiteral is the string literal for All Privilege.
 const AllPrivilegeLiteral = "ALL PRIVILEGES"
+
+// SQLMode is the type for MySQL sql_mode.
+// See https://dev.mysql.com/doc/refman/5.7/en/sql-mode.html
+type SQLMode int
+
+// HasStrictMode detects if 'STRICT_TRANS_TABLES' or 'STRICT_ALL_TABLES' mode is set in SQLMode.
+func (m SQLMode) HasStrictMode() bool {
+	return m&ModeStrictTransTables != 0 || m&ModeStrictAllTables != 0
+}
+
+// HasNoZeroDateMode detects if 'NO_ZERO_DATE' mode is set in SQLMode.
+func (m SQLMode) HasNoZeroDateMode() bool {
+	return m&ModeNoZeroDate != 0
+}
+
+// HasErrorForDivisionByZeroMode detects if 'ERROR_FOR_DIVISION_BY_ZERO' mode is set in SQLMode.
+func (m SQLMode) HasErrorForDivisionByZeroMode() bool {
+	return m&ModeErrorForDivisionByZero != 0
+}
+
+// String returns the comma separated mode names, in the order of sqlModeNames.
+func (m SQLMode) String() string {
+	var names []string
+	for _, name := range sqlModeNames {
+		if mode := Str2SQLMode[name]; m&mode == mode {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Consts for sql modes.
+const (
+	ModeNone        SQLMode = 0
+	ModeRealAsFloat SQLMode = 1 << iota
+	ModePipesAsConcat
+	ModeANSIQuotes
+	ModeIgnoreSpace
+	ModeNotUsed
+	ModeOnlyFullGroupBy
+	ModeNoUnsignedSubtraction
+	ModeNoDirInCreate
+	ModePostgreSQL
+	ModeOracle
+	ModeMsSQL
+	ModeDb2
+	ModeMaxdb
+	ModeNoKeyOptions
+	ModeNoTableOptions
+	ModeNoFieldOptions
+	ModeMySQL323
+	ModeMySQL40
+	ModeANSI
+	ModeNoAutoValueOnZero
+	ModeNoBackslashEscapes
+	ModeStrictTransTables
+	ModeStrictAllTables
+	ModeNoZeroInDate
+	ModeNoZeroDate
+	ModeInvalidDates
+	ModeErrorForDivisionByZero
+	ModeTraditional
+	ModeNoAutoCreateUser
+	ModeHighNotPrecedence
+	ModeNoEngineSubstitution
+	ModePadCharToFullLength
+)
+
+// Str2SQLMode is the string represent of sql_mode to sql_mode map.
+// The combination modes like ANSI and TRADITIONAL are expanded to the modes they consist of.
+var Str2SQLMode = map[string]SQLMode{
+	"REAL_AS_FLOAT":              ModeRealAsFloat,
+	"PIPES_AS_CONCAT":            ModePipesAsConcat,
+	"ANSI_QUOTES":                ModeANSIQuotes,
+	"IGNORE_SPACE":               ModeIgnoreSpace,
+	"ONLY_FULL_GROUP_BY":         ModeOnlyFullGroupBy,
+	"NO_UNSIGNED_SUBTRACTION":    ModeNoUnsignedSubtraction,
+	"NO_DIR_IN_CREATE":           ModeNoDirInCreate,
+	"POSTGRESQL":                 ModePostgreSQL,
+	"ORACLE":                     ModeOracle,
+	"MSSQL":                      ModeMsSQL,
+	"DB2":                        ModeDb2,
+	"MAXDB":                      ModeMaxdb,
+	"NO_KEY_OPTIONS":             ModeNoKeyOptions,
+	"NO_TABLE_OPTIONS":           ModeNoTableOptions,
+	"NO_FIELD_OPTIONS":           ModeNoFieldOptions,
+	"MYSQL323":                   ModeMySQL323,
+	"MYSQL40":                    ModeMySQL40,
+	"ANSI":                       ModeANSI | ModeRealAsFloat | ModePipesAsConcat | ModeANSIQuotes | ModeIgnoreSpace | ModeOnlyFullGroupBy,
+	"NO_AUTO_VALUE_ON_ZERO":      ModeNoAutoValueOnZero,
+	"NO_BACKSLASH_ESCAPES":       ModeNoBackslashEscapes,
+	"STRICT_TRANS_TABLES":        ModeStrictTransTables,
+	"STRICT_ALL_TABLES":          ModeStrictAllTables,
+	"NO_ZERO_IN_DATE":            ModeNoZeroInDate,
+	"NO_ZERO_DATE":               ModeNoZeroDate,
+	"INVALID_DATES":              ModeInvalidDates,
+	"ERROR_FOR_DIVISION_BY_ZERO": ModeErrorForDivisionByZero,
+	"TRADITIONAL": ModeTraditional | ModeStrictTransTables | ModeStrictAllTables | ModeNoZeroInDate |
+		ModeNoZeroDate | ModeErrorForDivisionByZero | ModeNoAutoCreateUser | ModeNoEngineSubstitution,
+	"NO_AUTO_CREATE_USER":     ModeNoAutoCreateUser,
+	"HIGH_NOT_PRECEDENCE":     ModeHighNotPrecedence,
+	"NO_ENGINE_SUBSTITUTION":  ModeNoEngineSubstitution,
+	"PAD_CHAR_TO_FULL_LENGTH": ModePadCharToFullLength,
+}
+
+// sqlModeNames is the names of the sql modes in the order of their values, it's used for formatting.
+var sqlModeNames = []string{
+	"REAL_AS_FLOAT", "PIPES_AS_CONCAT", "ANSI_QUOTES", "IGNORE_SPACE", "ONLY_FULL_GROUP_BY",
+	"NO_UNSIGNED_SUBTRACTION", "NO_DIR_IN_CREATE", "POSTGRESQL", "ORACLE", "MSSQL", "DB2", "MAXDB",
+	"NO_KEY_OPTIONS", "NO_TABLE_OPTIONS", "NO_FIELD_OPTIONS", "MYSQL323", "MYSQL40", "ANSI",
+	"NO_AUTO_VALUE_ON_ZERO", "NO_BACKSLASH_ESCAPES", "STRICT_TRANS_TABLES", "STRICT_ALL_TABLES",
+	"NO_ZERO_IN_DATE", "NO_ZERO_DATE", "INVALID_DATES", "ERROR_FOR_DIVISION_BY_ZERO", "TRADITIONAL",
+	"NO_AUTO_CREATE_USER", "HIGH_NOT_PRECEDENCE", "NO_ENGINE_SUBSTITUTION", "PAD_CHAR_TO_FULL_LENGTH",
+}
@@ -0,0 +1,61 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSQLMode(t *testing.T) {
+	tbl := []struct {
+		mode                  SQLMode
+		str                   string
+		strict                bool
+		noZeroDate            bool
+		errorForDivisonByZero bool
+	}{
+		{ModeNone, "", false, false, false},
+		{ModeStrictTransTables | ModeNoEngineSubstitution, "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION", true, false, false},
+		{ModeStrictAllTables | ModeNoZeroDate, "STRICT_ALL_TABLES,NO_ZERO_DATE", true, true, false},
+		// The combination modes are expanded.
+		{Str2SQLMode["TRADITIONAL"], "STRICT_TRANS_TABLES,STRICT_ALL_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE," +
+			"ERROR_FOR_DIVISION_BY_ZERO,TRADITIONAL,NO_AUTO_CREATE_USER,NO_ENGINE_SUBSTITUTION", true, true, true},
+		{Str2SQLMode["ANSI"], "REAL_AS_FLOAT,PIPES_AS_CONCAT,ANSI_QUOTES,IGNORE_SPACE,ONLY_FULL_GROUP_BY,ANSI", false, false, false},
+	}
+	for _, test := range tbl {
+		if s := test.mode.String(); s != test.str {
+			t.Fatalf("invalid sql mode string %s != %s", s, test.str)
+		}
+		if test.mode.HasStrictMode() != test.strict {
+			t.Fatalf("invalid strict mode for %s", test.str)
+		}
+		if test.mode.HasNoZeroDateMode() != test.noZeroDate {
+			t.Fatalf("invalid no zero date mode for %s", test.str)
+		}
+		if test.mode.HasErrorForDivisionByZeroMode() != test.errorForDivisonByZero {
+			t.Fatalf("invalid error for division by zero mode for %s", test.str)
+		}
+	}
+
+	// Every mode name is formatted.
+	if len(sqlModeNames) != len(Str2SQLMode) {
+		t.Fatalf("invalid sql mode names count %d != %d", len(sqlModeNames), len(Str2SQLMode))
+	}
+	for _, name := range sqlModeNames {
+		if s := Str2SQLMode[name].String(); !strings.Contains(s, name) {
+			t.Fatalf("sql mode string %s doesn't contain %s", s, name)
+		}
+	}
+}
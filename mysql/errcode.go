@@ -883,3 +883,28 @@ const (
 	ErrRowInWrongPartition                                          = 1863
 	ErrErrorLast                                                    = 1863
 )
+
+// MySQL 5.7 foreign key error codes.
+const (
+	ErrFkDepthExceeded = 3008
+)
+
+// MySQL 5.7 JSON error codes.
+const (
+	ErrInvalidJSONText = 3140
+	ErrInvalidJSONPath = 3143
+	ErrJSONUsedAsKey   = 3152
+)
+
+// MySQL 8.0 window function error codes.
+const (
+	ErrWindowInvalidWindowFuncUse = 3593
+)
+
+// MySQL 8.0 check constraint error codes.
+const (
+	ErrCheckConstraintViolated            = 3819
+	ErrCheckConstraintRefersUnknownColumn = 3820
+	ErrCheckConstraintDupName             = 3822
+	ErrDependentByCheckConstraint         = 3959
+)
@@ -879,4 +879,13 @@ var MySQLErrName = map[uint16]string{
 	ErrAlterOperationNotSupportedReasonNotNull:               "cannot silently convert NULL values, as required in this SQLMODE",
 	ErrMustChangePasswordLogin:                               "Your password has expired. To log in you must change it using a client that supports expired passwords.",
 	ErrRowInWrongPartition:                                   "Found a row in wrong partition %s",
+	ErrFkDepthExceeded:                                       "Foreign key cascade delete/update exceeds max depth of %d.",
+	ErrInvalidJSONText:                                       "Invalid JSON text: %s",
+	ErrInvalidJSONPath:                                       "Invalid JSON path expression %s",
+	ErrJSONUsedAsKey:                                         "JSON column '%-.192s' cannot be used in key specification.",
+	ErrWindowInvalidWindowFuncUse:                            "You cannot use the window function '%-.192s' in this context.",
+	ErrCheckConstraintViolated:                               "Check constraint '%-.192s' is violated.",
+	ErrCheckConstraintRefersUnknownColumn:                    "Check constraint '%-.192s' refers to non-existing column '%-.192s'.",
+	ErrCheckConstraintDupName:                                "Duplicate check constraint name '%-.192s'.",
+	ErrDependentByCheckConstraint:                            "Check constraint '%-.192s' uses column '%-.192s', hence column cannot be dropped or renamed.",
 }
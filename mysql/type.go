@@ -37,6 +37,9 @@ const (
 // TypeUnspecified is an uninitialized type. TypeDecimal is not used in MySQL.
 var TypeUnspecified = TypeDecimal
 
+// TypeJSON is the MySQL 5.7 JSON type, it sits right before TypeNewDecimal.
+const TypeJSON byte = 0xf5
+
 // MySQL type informations.
 const (
 	TypeNewDecimal byte = iota + 0xf6
@@ -40,6 +40,8 @@ type Scanner struct {
 
 	// for scanning such kind of comment: /*! MySQL-specific code */
 	specialComment *specialCommentScanner
+	// lastTok is the last token returned by Lex, the optimizer hints are only recognized after the SELECT keyword.
+	lastTok int
 }
 
 type specialCommentScanner struct {
@@ -58,6 +60,7 @@ func (s *Scanner) reset(sql string) {
 	s.buf.Reset()
 	s.errs = s.errs[:0]
 	s.stmtStartPos = 0
+	s.lastTok = 0
 }
 
 func (s *Scanner) stmtText() string {
@@ -90,6 +93,11 @@ func (s *Scanner) Errorf(format string, a ...interface{}) {
 // return 0 tells parser that scanner meets EOF,
 // return invalid tells parser that scanner meets illegal character.
 func (s *Scanner) Lex(v *yySymType) int {
+	s.lastTok = s.lex(v)
+	return s.lastTok
+}
+
+func (s *Scanner) lex(v *yySymType) int {
 	tok, pos, lit := s.scan()
 	v.offset = pos.Offset
 	v.ident = lit
@@ -132,6 +140,40 @@ func NewScanner(s string) *Scanner {
 	return &Scanner{r: reader{s: s}}
 }
 
+// Normalize returns the normalized text of sql, in which the tokens are separated by a single space,
+// the comments are removed and the literal values are replaced with the parameter marker '?'.
+// The replaced values are returned in the order they appear.
+// ok is false if sql can not be normalized, e.g. it contains a MySQL-specific comment.
+func Normalize(sql string) (text string, values []interface{}, ok bool) {
+	var (
+		buf bytes.Buffer
+		v   yySymType
+	)
+	s := NewScanner(sql)
+	for {
+		tok := s.Lex(&v)
+		if s.specialComment != nil || len(s.errs) > 0 {
+			return "", nil, false
+		}
+		if tok == 0 {
+			return buf.String(), values, true
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		switch tok {
+		case intLit, floatLit, decLit, hexLit, bitLit:
+			values = append(values, v.item)
+			buf.WriteByte('?')
+		case stringLit:
+			values = append(values, v.ident)
+			buf.WriteByte('?')
+		default:
+			buf.WriteString(sql[v.offset:s.r.pos().Offset])
+		}
+	}
+}
+
 func (s *Scanner) skipWhitespace() rune {
 	return s.r.incAsLongAs(unicode.IsSpace)
 }
@@ -233,19 +275,38 @@ func startWithSharp(s *Scanner) (tok int, pos Pos, lit string) {
 
 func startWithDash(s *Scanner) (tok int, pos Pos, lit string) {
 	pos = s.r.pos()
-	if !strings.HasPrefix(s.r.s[pos.Offset:], "-- ") {
+	if strings.HasPrefix(s.r.s[pos.Offset:], "->>") {
+		tok = juss
+		s.r.incN(3)
+		return
+	}
+	if strings.HasPrefix(s.r.s[pos.Offset:], "->") {
+		tok = jss
+		s.r.incN(2)
+		return
+	}
+	// See https://dev.mysql.com/doc/refman/5.7/en/ansi-diff-comments.html
+	// The "--" comment style requires the second dash to be followed by a whitespace, a control character or the end.
+	if !strings.HasPrefix(s.r.s[pos.Offset:], "--") || !isCommentDashEnd(s.r.s[pos.Offset+2:]) {
 		tok = int('-')
 		s.r.inc()
 		return
 	}
 
-	s.r.incN(3)
+	s.r.incN(2)
 	s.r.incAsLongAs(func(ch rune) bool {
 		return ch != '\n'
 	})
 	return s.scan()
 }
 
+func isCommentDashEnd(rest string) bool {
+	if len(rest) == 0 {
+		return true
+	}
+	return unicode.IsSpace(rune(rest[0])) || unicode.IsControl(rune(rest[0]))
+}
+
 func startWithSlash(s *Scanner) (tok int, pos Pos, lit string) {
 	pos = s.r.pos()
 	s.r.inc()
@@ -266,6 +327,13 @@ func startWithSlash(s *Scanner) (tok int, pos Pos, lit string) {
 		// See http://dev.mysql.com/doc/refman/5.7/en/comments.html
 		// Convert "/*!VersionNumber MySQL-specific-code */" to "MySQL-specific-code".
 		comment := s.r.data(&pos)
+		// See https://dev.mysql.com/doc/refman/5.7/en/optimizer-hints.html
+		// The optimizer hints comment "/*+ hints */" is returned as a token if it follows the SELECT keyword,
+		// otherwise it is an ordinary comment.
+		if strings.HasPrefix(comment, "/*+") && s.lastTok == selectKwd {
+			tok, lit = hintComment, comment[3:len(comment)-2]
+			return
+		}
 		if strings.HasPrefix(comment, "/*!") {
 			sql := specCodePattern.ReplaceAllStringFunc(comment, trimComment)
 			s.specialComment = &specialCommentScanner{
@@ -285,21 +353,9 @@ func startWithSlash(s *Scanner) (tok int, pos Pos, lit string) {
 }
 
 func sqlOffsetInComment(comment string) int {
-	// find the first SQL token offset in pattern like "/*!40101 mysql specific code */"
-	offset := 0
-	for i := 0; i < len(comment); i++ {
-		if unicode.IsSpace(rune(comment[i])) {
-			offset = i
-			break
-		}
-	}
-	for offset < len(comment) {
-		offset++
-		if !unicode.IsSpace(rune(comment[offset])) {
-			break
-		}
-	}
-	return offset
+	// find the first SQL token offset in pattern like "/*!40101 mysql specific code */",
+	// which is where the SQL trimmed by trimComment starts.
+	return len(specCodeStart.FindString(comment))
 }
 
 func startWithAt(s *Scanner) (tok int, pos Pos, lit string) {
@@ -61,6 +61,9 @@ func (s *testLexerSuite) TestSingleCharOther(c *C) {
 		{"PLACEHOLDER", identifier},
 		{"=", eq},
 		{".", int('.')},
+		{"->", jss},
+		{"->>", juss},
+		{"-1", int('-')},
 	}
 	runTest(c, table)
 }
@@ -135,8 +138,20 @@ SELECT`, selectKwd},
 SELECT`, selectKwd},
 		{"#comment\n123", intLit},
 		{"--5", int('-')},
+		{"--\n123", intLit},
+		{"--\t\n123", intLit},
+		{"--", 0},
+		{"/*!40101SET character_set_client = utf8 */;", set},
+		{"/*+ USE_INDEX(t, idx) */ SELECT", selectKwd},
 	}
 	runTest(c, table)
+
+	var v yySymType
+	l := NewScanner("SELECT /*+ USE_INDEX(t, idx) */ 1")
+	c.Assert(l.Lex(&v), Equals, selectKwd)
+	c.Assert(l.Lex(&v), Equals, hintComment)
+	c.Assert(v.ident, Equals, " USE_INDEX(t, idx) ")
+	c.Assert(l.Lex(&v), Equals, intLit)
 }
 
 func (s *testLexerSuite) TestscanQuotedIdent(c *C) {
@@ -223,4 +238,33 @@ func (s *testLexerSuite) TestSpecialComment(c *C) {
 	c.Assert(tok, Equals, intLit)
 	c.Assert(lit, Equals, "5")
 	c.Assert(pos, Equals, Pos{1, 1, 16})
+
+	l = NewScanner("/*!40101select 5*/")
+	tok, pos, lit = l.scan()
+	c.Assert(tok, Equals, identifier)
+	c.Assert(lit, Equals, "select")
+	c.Assert(pos, Equals, Pos{0, 0, 8})
+}
+
+func (s *testLexerSuite) TestNormalize(c *C) {
+	defer testleak.AfterTest(c)()
+	table := []struct {
+		sql    string
+		text   string
+		values []interface{}
+	}{
+		{"select * from t", "select * from t", nil},
+		{"SELECT *\n  FROM t /* comment */ WHERE a=1 and b > 'abc'", "SELECT * FROM t WHERE a = ? and b > ?", []interface{}{int64(1), "abc"}},
+		{"insert into t1 values (-1, 1e3, 'it''s')", "insert into t1 values ( - ? , ? , ? )", []interface{}{int64(1), float64(1000), "it's"}},
+		{"select `a b` from t where a in (1,2) # 3", "select `a b` from t where a in ( ? , ? )", []interface{}{int64(1), int64(2)}},
+	}
+	for _, t := range table {
+		text, values, ok := Normalize(t.sql)
+		c.Assert(ok, IsTrue)
+		c.Assert(text, Equals, t.text)
+		c.Assert(values, DeepEquals, t.values)
+	}
+
+	_, _, ok := Normalize("/*!40101 select 1 */")
+	c.Assert(ok, IsFalse)
 }
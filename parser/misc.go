@@ -199,6 +199,7 @@ var tokenMap = map[string]int{
 	"DATABASES":           databases,
 	"DATE_ADD":            dateAdd,
 	"DATE_FORMAT":         dateFormat,
+	"DATEDIFF":            datediff,
 	"DATE_SUB":            dateSub,
 	"DAY":                 day,
 	"DAYNAME":             dayname,
@@ -211,6 +212,7 @@ var tokenMap = map[string]int{
 	"DELAYED":             delayed,
 	"DELAY_KEY_WRITE":     delayKeyWrite,
 	"DELETE":              deleteKwd,
+	"DENSE_RANK":          denseRank,
 	"DESC":                desc,
 	"DESCRIBE":            describe,
 	"DISABLE":             disable,
@@ -229,6 +231,7 @@ var tokenMap = map[string]int{
 	"ENGINES":             engines,
 	"ENUM":                enum,
 	"ESCAPE":              escape,
+	"ERRORS":              errorsKwd,
 	"ESCAPED":             escaped,
 	"EVENTS":              events,
 	"EXECUTE":             execute,
@@ -237,8 +240,10 @@ var tokenMap = map[string]int{
 	"EXTRACT":             extract,
 	"FALSE":               falseKwd,
 	"FIELDS":              fields,
+	"FILE":                file,
 	"FIRST":               first,
 	"FIXED":               fixed,
+	"FORMAT":              format,
 	"FOREIGN":             foreign,
 	"FOR":                 forKwd,
 	"FORCE":               force,
@@ -249,6 +254,7 @@ var tokenMap = map[string]int{
 	"FULLTEXT":            fulltext,
 	"FUNCTION":            function,
 	"FLUSH":               flush,
+	"FLOOR":               floor,
 	"GET_LOCK":            getLock,
 	"GLOBAL":              global,
 	"GRANT":               grant,
@@ -278,9 +284,13 @@ var tokenMap = map[string]int{
 	"ISNULL":              isNull,
 	"ISOLATION":           isolation,
 	"JOIN":                join,
+	"JSON":                jsonType,
+	"JSON_EXTRACT":        jsonExtract,
+	"JSON_UNQUOTE":        jsonUnquote,
 	"KEY":                 key,
 	"KEY_BLOCK_SIZE":      keyBlockSize,
 	"KEYS":                keys,
+	"KILL":                kill,
 	"LAST_INSERT_ID":      lastInsertID,
 	"LEADING":             leading,
 	"LEFT":                left,
@@ -327,6 +337,8 @@ var tokenMap = map[string]int{
 	"OR":                  or,
 	"ORDER":               order,
 	"OUTER":               outer,
+	"OUTFILE":             outfile,
+	"OVER":                over,
 	"PASSWORD":            password,
 	"POW":                 pow,
 	"POWER":               power,
@@ -334,15 +346,19 @@ var tokenMap = map[string]int{
 	"PRIMARY":             primary,
 	"PRIVILEGES":          privileges,
 	"PROCEDURE":           procedure,
+	"PROCESS":             process,
 	"PROCESSLIST":         processlist,
+	"QUERY":               query,
 	"QUARTER":             quarter,
 	"QUICK":               quick,
 	"RANGE":               rangeKwd,
+	"RANK":                rank,
 	"RAND":                rand,
 	"READ":                read,
 	"REDUNDANT":           redundant,
 	"REFERENCES":          references,
 	"REGEXP":              regexpKwd,
+	"RELEASE":             release,
 	"RELEASE_LOCK":        releaseLock,
 	"REPEAT":              repeat,
 	"REPEATABLE":          repeatable,
@@ -350,15 +366,19 @@ var tokenMap = map[string]int{
 	"RIGHT":               right,
 	"RLIKE":               rlike,
 	"ROLLBACK":            rollback,
+	"ROLLUP":              rollup,
 	"ROUND":               round,
 	"ROW":                 row,
 	"ROW_FORMAT":          rowFormat,
+	"ROW_NUMBER":          rowNumber,
 	"RTRIM":               rtrim,
 	"REVERSE":             reverse,
+	"SAVEPOINT":           savepoint,
 	"SCHEMA":              schema,
 	"SCHEMAS":             schemas,
 	"SECOND":              second,
 	"SELECT":              selectKwd,
+	"SEPARATOR":           separator,
 	"SERIALIZABLE":        serializable,
 	"SESSION":             session,
 	"SET":                 set,
@@ -373,6 +393,10 @@ var tokenMap = map[string]int{
 	"STARTING":            starting,
 	"STATS_PERSISTENT":    statsPersistent,
 	"STATUS":              status,
+	"STD":                 std,
+	"STDDEV":              stddev,
+	"STDDEV_POP":          stddevPop,
+	"STDDEV_SAMP":         stddevSamp,
 	"SUBDATE":             subDate,
 	"STRCMP":              strcmp,
 	"STR_TO_DATE":         strToDate,
@@ -380,9 +404,11 @@ var tokenMap = map[string]int{
 	"SUBSTRING":           substring,
 	"SUBSTRING_INDEX":     substringIndex,
 	"SUM":                 sum,
+	"SUPER":               super,
 	"SYSDATE":             sysDate,
 	"TABLE":               tableKwd,
 	"TABLES":              tables,
+	"TEMPORARY":           temporary,
 	"TERMINATED":          terminated,
 	"TIMEDIFF":            timediff,
 	"THAN":                than,
@@ -409,6 +435,9 @@ var tokenMap = map[string]int{
 	"VALUE":               value,
 	"VALUES":              values,
 	"VARIABLES":           variables,
+	"VARIANCE":            variance,
+	"VAR_POP":             varPop,
+	"VAR_SAMP":            varSamp,
 	"VERSION":             version,
 	"VIEW":                view,
 	"WARNINGS":            warnings,
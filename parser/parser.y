@@ -48,6 +48,7 @@ import (
 	/*yy:token "%c"     */	identifier      "identifier"
 	/*yy:token "\"%c\"" */	stringLit       "string literal"
 	invalid		"a special token never used by parser, used by lexer to indicate error"
+	hintComment	"optimizer hints comment"
 	andand		"&&"
 	oror		"||"
 
@@ -160,6 +161,7 @@ import (
 	or		"OR"
 	order		"ORDER"
 	outer		"OUTER"
+	outfile		"OUTFILE"
 	partition	"PARTITION"
 	partitions	"PARTITIONS"
 	precisionType	"PRECISION"
@@ -170,6 +172,7 @@ import (
 	realType	"REAL"
 	references	"REFERENCES"
 	regexpKwd	"REGEXP"
+	release		"RELEASE"
 	repeat		"REPEAT"
 	replace		"REPLACE"
 	restrict	"RESTRICT"
@@ -179,6 +182,7 @@ import (
 	schemas		"SCHEMAS"
 	secondMicrosecond	"SECOND_MICROSECOND"
 	selectKwd	"SELECT"
+	separator	"SEPARATOR"
 	set		"SET"
 	show		"SHOW"
 	smallIntType	"SMALLINT"
@@ -217,6 +221,7 @@ import (
 	admin		"ADMIN"
 	ceil		"CEIL"
 	ceiling		"CEILING"
+	floor		"FLOOR"
 	coalesce	"COALESCE"
 	concat		"CONCAT"
 	concatWs	"CONCAT_WS"
@@ -226,11 +231,13 @@ import (
 	day		"DAY"
 	dateAdd		"DATE_ADD"
 	dateFormat	"DATE_FORMAT"
+	datediff	"DATEDIFF"
 	dateSub		"DATE_SUB"
 	dayname		"DAYNAME"
 	dayofmonth	"DAYOFMONTH"
 	dayofweek	"DAYOFWEEK"
 	dayofyear	"DAYOFYEAR"
+	errorsKwd	"ERRORS"
 	events		"EVENTS"
 	foundRows	"FOUND_ROWS"
 	fromUnixTime	"FROM_UNIXTIME"
@@ -242,6 +249,8 @@ import (
 	unhex         	"UNHEX"
 	ifNull		"IFNULL"
 	isNull		"ISNULL"
+	jsonExtract	"JSON_EXTRACT"
+	jsonUnquote	"JSON_UNQUOTE"
 	lastInsertID	"LAST_INSERT_ID"
 	lcase 		"LCASE"
 	length		"LENGTH"
@@ -283,6 +292,16 @@ import (
 	weekofyear	"WEEKOFYEAR"
 	yearweek	"YEARWEEK"
 	round		"ROUND"
+	rowNumber	"ROW_NUMBER"
+	rank		"RANK"
+	denseRank	"DENSE_RANK"
+	std		"STD"
+	stddev		"STDDEV"
+	stddevPop	"STDDEV_POP"
+	stddevSamp	"STDDEV_SAMP"
+	variance	"VARIANCE"
+	varPop		"VAR_POP"
+	varSamp		"VAR_SAMP"
 	statsPersistent	"STATS_PERSISTENT"
 	getLock		"GET_LOCK"
 	releaseLock	"RELEASE_LOCK"
@@ -336,16 +355,20 @@ import (
 	escape 		"ESCAPE"
 	execute		"EXECUTE"
 	fields		"FIELDS"
+	file		"FILE"
 	first		"FIRST"
 	fixed		"FIXED"
+	format		"FORMAT"
 	flush		"FLUSH"
 	full		"FULL"
 	function	"FUNCTION"
 	hash		"HASH"
 	identified	"IDENTIFIED"
 	isolation	"ISOLATION"
+	jsonType	"JSON"
 	indexes		"INDEXES"
 	keyBlockSize	"KEY_BLOCK_SIZE"
+	kill		"KILL"
 	local		"LOCAL"
 	less		"LESS"
 	level		"LEVEL"
@@ -358,18 +381,23 @@ import (
 	no		"NO"
 	offset		"OFFSET"
 	only		"ONLY"
+	over		"OVER"
 	password	"PASSWORD"
 	prepare		"PREPARE"
 	privileges	"PRIVILEGES"
+	process		"PROCESS"
 	processlist	"PROCESSLIST"
 	quarter		"QUARTER"
+	query		"QUERY"
 	quick		"QUICK"
 	redundant	"REDUNDANT"
 	repeatable	"REPEATABLE"
 	reverse		"REVERSE"
 	rollback	"ROLLBACK"
+	rollup		"ROLLUP"
 	row 		"ROW"
 	rowFormat	"ROW_FORMAT"
+	savepoint	"SAVEPOINT"
 	serializable	"SERIALIZABLE"
 	session		"SESSION"
 	share		"SHARE"
@@ -380,9 +408,11 @@ import (
 	sqlNoCache	"SQL_NO_CACHE"
 	start		"START"
 	status		"STATUS"
+	super		"SUPER"
 	some 		"SOME"
 	global		"GLOBAL"
 	tables		"TABLES"
+	temporary	"TEMPORARY"
 	textType	"TEXT"
 	than		"THAN"
 	timeType	"TIME"
@@ -418,6 +448,8 @@ import (
 	extract		"EXTRACT"
 
 	ge		">="
+	jss		"->"
+	juss		"->>"
 	le		"<="
 	lsh		"<<"
 	neq		"!="
@@ -431,6 +463,7 @@ import (
 
 %type   <item>
 	AdminStmt		"Check table statement or show ddl statement"
+	AlterDatabaseStmt	"Alter database statement"
 	AlterTableStmt		"Alter table statement"
 	AlterTableSpec		"Alter table specification"
 	AlterTableSpecList	"Alter table specification list"
@@ -450,6 +483,7 @@ import (
 	ColumnName		"column name"
 	ColumnNameList		"column name list"
 	ColumnNameListOpt	"column name list opt"
+	ColumnNameListOptWithBrackets	"column name list opt with brackets"
 	ColumnSetValue		"insert statement set value by column name"
 	ColumnSetValueList	"insert statement set value by column name list"
 	CommitStmt		"COMMIT statement"
@@ -468,6 +502,7 @@ import (
 	DatabaseOptionListOpt	"CREATE Database specification list opt"
 	CreateTableStmt		"CREATE TABLE statement"
 	CreateUserStmt		"CREATE User statement"
+	CreateViewStmt		"CREATE VIEW statement"
 	DateArithOpt		"Date arith dateadd or datesub option"
 	DateArithMultiFormsOpt	"Date arith adddate or subdate option"
 	DateArithInterval       "Date arith interval part"
@@ -511,6 +546,7 @@ import (
 	FunctionCallConflict	"Function call with reserved keyword as function name"
 	FunctionCallKeyword	"Function call with keyword as function name"
 	FunctionCallNonKeyword	"Function call with nonkeyword as function name"
+	FunctionCallWindow	"Function call on window"
 	FuncDatetimePrec	"Function datetime precision"
 	GlobalScope		"The scope of variable"
 	GrantStmt		"Grant statement"
@@ -519,6 +555,7 @@ import (
 	HavingClause		"HAVING clause"
 	IfExists		"If Exists"
 	IfNotExists		"If Not Exists"
+	IgnoreLines		"Ignore num(int) lines"
 	IgnoreOptional		"IGNORE or empty"
 	IndexColName		"Index column name"
 	IndexColNameList	"List of index column name"
@@ -536,6 +573,7 @@ import (
 	InsertValues		"Rest part of INSERT/REPLACE INTO statement"
 	JoinTable 		"join table"
 	JoinType		"join type"
+	KillStmt		"Kill statement"
 	LikeEscapeOpt 		"like escape option"
 	LimitClause		"LIMIT clause"
 	Lines			"Lines clause"
@@ -552,6 +590,7 @@ import (
 	OnDuplicateKeyUpdate	"ON DUPLICATE KEY UPDATE value list"
 	Operand			"operand"
 	OptFull			"Full or empty"
+	OptimizerHintsOpt	"Optimizer hints comment or empty"
 	Order			"ORDER BY clause optional collation specification"
 	OrderBy			"ORDER BY clause"
 	ByItem			"BY item"
@@ -562,6 +601,7 @@ import (
 	PartitionDefinitionList "Partition definition list"
 	PartitionDefinitionListOpt	"Partition definition list option"
 	PartitionOpt		"Partition option"
+	PartitionByOptional	"Optional PARTITION BY clause in window"
 	PartitionNumOpt		"PARTITION NUM option"
 	PasswordOpt		"Password option"
 	ColumnPosition		"Column position [First|After ColumnName]"
@@ -580,9 +620,12 @@ import (
 	ReferOpt		"reference option"
 	ReplaceIntoStmt		"REPLACE INTO statement"
 	ReplacePriority		"replace statement priority"
+	ReleaseSavepointStmt	"RELEASE SAVEPOINT statement"
 	RollbackStmt		"ROLLBACK statement"
 	RowFormat		"Row format option"
+	SavepointStmt		"SAVEPOINT statement"
 	SelectLockOpt		"FOR UPDATE or LOCK IN SHARE MODE,"
+	SelectIntoStmt		"SELECT INTO OUTFILE statement"
 	SelectStmt		"SELECT statement"
 	SelectStmtCalcFoundRows	"SELECT statement optional SQL_CALC_FOUND_ROWS"
 	SelectStmtSQLCache	"SELECT statement optional SQL_CAHCE/SQL_NO_CACHE"
@@ -623,6 +666,7 @@ import (
 	TableOptionListOpt	"create table option list opt"
 	TableRef 		"table reference"
 	TableRefs 		"table references"
+	TemporaryOpt		"TEMPORARY or empty"
 	TrimDirection		"Trim string direction"
 	TruncateTableStmt	"TRANSACTION TABLE statement"
 	UnionOpt		"Union Option(empty/ALL/DISTINCT)"
@@ -638,6 +682,9 @@ import (
 	UserVariable		"User defined variable name"
 	UserVariableList	"User defined variable name list"
 	UseStmt			"USE statement"
+	ViewFieldList		"Column name list of a view"
+	ViewFieldListOpt	"Optional column name list of a view"
+	ViewSelectStmt		"Select statement of a view"
 	VariableAssignment	"set variable value"
 	VariableAssignmentList	"set variable value list"
 	Variable		"User or system variable"
@@ -669,11 +716,14 @@ import (
 	OptBinary		"Optional BINARY"
 	OptCharset		"Optional Character setting"
 	OptCollate		"Optional Collate setting"
+	OrReplace		"OR REPLACE or empty"
+	WindowSpec		"Window specification"
 	NUM			"numbers"
 	LengthNum		"Field length num(uint64)"
 
 %type	<ident>
 	KeyOrIndex		"{KEY|INDEX}"
+	IndexHintName		"Index name or PRIMARY in index hints"
 	ColumnKeywordOpt	"Column keyword or empty"
 	PrimaryOpt		"Optional primary keyword"
 	NowSym			"CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP/NOW"
@@ -701,6 +751,9 @@ import (
 	logAnd			"logical and operator"
 	logOr			"logical or operator"
 	FieldsOrColumns 	"Fields or columns"
+	GroupConcatSeparator	"GROUP_CONCAT SEPARATOR or empty"
+	WindowFuncName		"ROW_NUMBER, RANK or DENSE_RANK"
+	VarianceFuncName	"Variance or standard deviation function name"
 
 %type	<ident>
 	Identifier			"identifier or unreserved keyword"
@@ -732,7 +785,7 @@ import (
 /* A dummy token to force the priority of TableRef production in a join. */
 %left   tableRefPriority
 %precedence lowerThanOn
-%precedence on
+%precedence on using
 %right  assignmentEq
 %left 	oror or
 %left 	xor
@@ -775,6 +828,24 @@ import (
 Start:
 	StatementList
 
+/**************************************AlterDatabaseStmt***************************************
+ * See https://dev.mysql.com/doc/refman/5.7/en/alter-database.html
+ *  ALTER {DATABASE | SCHEMA} db_name
+ *      alter_specification ...
+ *
+ *  alter_specification:
+ *      [DEFAULT] CHARACTER SET [=] charset_name
+ *    | [DEFAULT] COLLATE [=] collation_name
+ *******************************************************************************************/
+AlterDatabaseStmt:
+	"ALTER" DatabaseSym DBName DatabaseOptionList
+	{
+		$$ = &ast.AlterDatabaseStmt{
+			Name:		$3.(string),
+			Options:	$4.([]*ast.DatabaseOption),
+		}
+	}
+
 /**************************************AlterTableStmt***************************************
  * See https://dev.mysql.com/doc/refman/5.7/en/alter-table.html
  *******************************************************************************************/
@@ -1058,9 +1129,11 @@ ColumnOption:
 	}
 |	"CHECK" '(' Expression ')'
 	{
-		// See https://dev.mysql.com/doc/refman/5.7/en/create-table.html
-		// The CHECK clause is parsed but ignored by all storage engines.
-		$$ = &ast.ColumnOption{}
+		startOffset := parser.startOffset(&yyS[yypt-1])
+		endOffset := parser.endOffset(&yyS[yypt])
+		expr := $3.(ast.ExprNode)
+		expr.SetText(parser.src[startOffset:endOffset])
+		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionCheck, Expr: expr}
 	}
 
 ColumnOptionList:
@@ -1083,7 +1156,15 @@ ColumnOptionListOpt:
 	}
 
 ConstraintElem:
-	"PRIMARY" "KEY" IndexTypeOpt '(' IndexColNameList ')' IndexOption
+	"CHECK" '(' Expression ')'
+	{
+		startOffset := parser.startOffset(&yyS[yypt-1])
+		endOffset := parser.endOffset(&yyS[yypt])
+		expr := $3.(ast.ExprNode)
+		expr.SetText(parser.src[startOffset:endOffset])
+		$$ = &ast.Constraint{Tp: ast.ConstraintCheck, Expr: expr}
+	}
+|	"PRIMARY" "KEY" IndexTypeOpt '(' IndexColNameList ')' IndexOption
 	{
 		c := &ast.Constraint{
 			Tp: ast.ConstraintPrimaryKey,
@@ -1424,9 +1505,9 @@ DatabaseOptionList:
  *      )
  *******************************************************************/
 CreateTableStmt:
-	"CREATE" "TABLE" IfNotExists TableName '(' TableElementList ')' TableOptionListOpt PartitionOpt
+	"CREATE" TemporaryOpt "TABLE" IfNotExists TableName '(' TableElementList ')' TableOptionListOpt PartitionOpt
 	{
-		tes := $6.([]interface {})
+		tes := $7.([]interface {})
 		var columnDefs []*ast.ColumnDef
 		var constraints []*ast.Constraint
 		for _, te := range tes {
@@ -1442,14 +1523,72 @@ CreateTableStmt:
 			return 1
 		}
 		$$ = &ast.CreateTableStmt{
-			Table:          $4.(*ast.TableName),
-			IfNotExists:    $3.(bool),
+			IsTemporary:    $2.(bool),
+			Table:          $5.(*ast.TableName),
+			IfNotExists:    $4.(bool),
 			Cols:           columnDefs,
 			Constraints:    constraints,
-			Options:        $8.([]*ast.TableOption),
+			Options:        $9.([]*ast.TableOption),
+		}
+	}
+
+/*******************************************************************
+ *
+ *  Create View Statement
+ *
+ *  Example:
+ *	CREATE OR REPLACE VIEW v (a, b) AS SELECT c, d FROM t
+ *******************************************************************/
+CreateViewStmt:
+	"CREATE" OrReplace "VIEW" TableName ViewFieldListOpt "AS" ViewSelectStmt
+	{
+		// The token after the select statement has been scanned as the lookahead,
+		// so the text of the select statement ends at its offset.
+		startOffset := parser.startOffset(&yyS[yypt])
+		endOffset := parser.endOffset(&parser.yylval)
+		sel := $7.(ast.StmtNode)
+		sel.SetText(parser.src[startOffset:endOffset])
+		$$ = &ast.CreateViewStmt{
+			OrReplace:	$2.(bool),
+			ViewName:	$4.(*ast.TableName),
+			Cols:		$5.([]model.CIStr),
+			Select:		sel,
 		}
 	}
 
+OrReplace:
+	{
+		$$ = false
+	}
+|	"OR" "REPLACE"
+	{
+		$$ = true
+	}
+
+ViewFieldListOpt:
+	{
+		var cols []model.CIStr
+		$$ = cols
+	}
+|	'(' ViewFieldList ')'
+	{
+		$$ = $2.([]model.CIStr)
+	}
+
+ViewFieldList:
+	Identifier
+	{
+		$$ = []model.CIStr{model.NewCIStr($1)}
+	}
+|	ViewFieldList ',' Identifier
+	{
+		$$ = append($1.([]model.CIStr), model.NewCIStr($3))
+	}
+
+ViewSelectStmt:
+	SelectStmt
+|	UnionStmt
+
 Default:
 	"DEFAULT" Expression
 	{
@@ -1585,19 +1724,23 @@ DropIndexStmt:
 	}
 
 DropTableStmt:
-	"DROP" TableOrTables TableNameList
+	"DROP" TemporaryOpt TableOrTables TableNameList
 	{
-		$$ = &ast.DropTableStmt{Tables: $3.([]*ast.TableName)}
+		$$ = &ast.DropTableStmt{IsTemporary: $2.(bool), Tables: $4.([]*ast.TableName)}
 	}
-|	"DROP" TableOrTables "IF" "EXISTS" TableNameList
+|	"DROP" TemporaryOpt TableOrTables "IF" "EXISTS" TableNameList
 	{
-		$$ = &ast.DropTableStmt{IfExists: true, Tables: $5.([]*ast.TableName)}
+		$$ = &ast.DropTableStmt{IsTemporary: $2.(bool), IfExists: true, Tables: $6.([]*ast.TableName)}
 	}
 
 DropViewStmt:
-	"DROP" "VIEW" "IF" "EXISTS" TableNameList
+	"DROP" "VIEW" TableNameList
+	{
+		$$ = &ast.DropTableStmt{IsView: true, Tables: $3.([]*ast.TableName)}
+	}
+|	"DROP" "VIEW" "IF" "EXISTS" TableNameList
 	{
-		$$ = &ast.DoStmt{}
+		$$ = &ast.DropTableStmt{IsView: true, IfExists: true, Tables: $5.([]*ast.TableName)}
 	}
 
 DropUserStmt:
@@ -1614,6 +1757,15 @@ TableOrTables:
 	"TABLE"
 |	"TABLES"
 
+TemporaryOpt:
+	{
+		$$ = false
+	}
+|	"TEMPORARY"
+	{
+		$$ = true
+	}
+
 EqOpt:
 	{}
 |	eq
@@ -1649,7 +1801,22 @@ ExplainStmt:
 	}
 |	ExplainSym ExplainableStmt
 	{
-		$$ = &ast.ExplainStmt{Stmt: $2.(ast.StmtNode)}
+		$$ = &ast.ExplainStmt{
+			Stmt:	$2.(ast.StmtNode),
+			Format:	ast.ExplainFormatTraditional,
+		}
+	}
+|	ExplainSym "FORMAT" "=" Identifier ExplainableStmt
+	{
+		format := strings.ToLower($4)
+		if format != ast.ExplainFormatTraditional && format != ast.ExplainFormatJSON {
+			yylex.Errorf("Unknown EXPLAIN format name: '%s'", $4)
+			return 1
+		}
+		$$ = &ast.ExplainStmt{
+			Stmt:	$5.(ast.StmtNode),
+			Format:	format,
+		}
 	}
 
 LengthNum:
@@ -1692,7 +1859,13 @@ Expression:
 	}
 |	"NOT" Expression %prec not
 	{
-		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2.(ast.ExprNode)}
+		expr, ok := $2.(*ast.ExistsSubqueryExpr)
+		if ok {
+			expr.Not = !expr.Not
+			$$ = $2
+		} else {
+			$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2.(ast.ExprNode)}
+		}
 	}
 |	Factor "IS" NotOpt trueKwd %prec is
 	{
@@ -1949,6 +2122,10 @@ GroupByClause:
 	{
 		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem)}
 	}
+|	"GROUP" "BY" ByList "WITH" "ROLLUP"
+	{
+		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem), Rollup: true}
+	}
 
 HavingClause:
 	{
@@ -2055,12 +2232,13 @@ UnReservedKeyword:
 | "COLUMNS" | "COMMIT" | "COMPACT" | "COMPRESSED" | "CONSISTENT" | "DATA" | "DATE" | "DATETIME" | "DEALLOCATE" | "DO"
 | "DYNAMIC"| "END" | "ENGINE" | "ENGINES" | "ESCAPE" | "EXECUTE" | "FIELDS" | "FIRST" | "FIXED" | "FULL" |"GLOBAL"
 | "HASH" | "LESS" | "LOCAL" | "NAMES" | "OFFSET" | "PASSWORD" %prec lowerThanEq | "PREPARE" | "QUICK" | "REDUNDANT" 
-| "ROLLBACK" | "SESSION" | "SIGNED" | "SNAPSHOT" | "START" | "STATUS" | "TABLES" | "TEXT" | "THAN" | "TIME" | "TIMESTAMP" 
+| "ROLLBACK" | "SESSION" | "SIGNED" | "SNAPSHOT" | "START" | "STATUS" | "TABLES" | "TEMPORARY" | "TEXT" | "THAN" | "TIME" | "TIMESTAMP" 
 | "TRANSACTION" | "TRUNCATE" | "UNKNOWN" | "VALUE" | "WARNINGS" | "YEAR" | "MODE"  | "WEEK"  | "ANY" | "SOME" | "USER" | "IDENTIFIED"
 | "COLLATION" | "COMMENT" | "AVG_ROW_LENGTH" | "CONNECTION" | "CHECKSUM" | "COMPRESSION" | "KEY_BLOCK_SIZE" | "MAX_ROWS"
 | "MIN_ROWS" | "NATIONAL" | "ROW" | "ROW_FORMAT" | "QUARTER" | "GRANTS" | "TRIGGERS" | "DELAY_KEY_WRITE" | "ISOLATION"
 | "REPEATABLE" | "COMMITTED" | "UNCOMMITTED" | "ONLY" | "SERIALIZABLE" | "LEVEL" | "VARIABLES" | "SQL_CACHE" | "INDEXES" | "PROCESSLIST"
-| "SQL_NO_CACHE" | "DISABLE"  | "ENABLE" | "REVERSE" | "SPACE" | "PRIVILEGES" | "NO" | "BINLOG" | "FUNCTION" | "VIEW" | "MODIFY" | "EVENTS" | "PARTITIONS"
+| "SQL_NO_CACHE" | "DISABLE"  | "ENABLE" | "REVERSE" | "SPACE" | "PRIVILEGES" | "NO" | "BINLOG" | "FUNCTION" | "VIEW" | "MODIFY" | "EVENTS" | "PARTITIONS" | "ERRORS"
+| "SAVEPOINT" | "FORMAT" | "KILL" | "QUERY" | "ROLLUP" | "JSON" | "OVER" | "PROCESS" | "SUPER" | "FILE"
 
 ReservedKeyword:
 "ADD" | "ALL" | "ALTER" | "ANALYZE" | "AND" | "AS" | "ASC" | "BETWEEN" | "BIGINT"
@@ -2075,9 +2253,9 @@ ReservedKeyword:
 | "INTERVAL" | "IS" | "JOIN" | "KEY" | "KEYS" | "LEADING" | "LEFT" | "LIKE" | "LIMIT" | "LINES" | "LOAD"
 | "LOCALTIME" | "LOCALTIMESTAMP" | "LOCK" | "LONGBLOB" | "LONGTEXT" | "MAXVALUE" | "MEDIUMBLOB" | "MEDIUMINT" | "MEDIUMTEXT"
 | "MINUTE_MICROSECOND" | "MINUTE_SECOND" | "MOD" | "NOT" | "NO_WRITE_TO_BINLOG" | "NULL" | "NUMERIC"
-| "ON" | "OPTION" | "OR" | "ORDER" | "OUTER" | "PARTITION" | "PRECISION" | "PRIMARY" | "PROCEDURE" | "RANGE" | "READ" 
-| "REAL" | "REFERENCES" | "REGEXP" | "REPEAT" | "REPLACE" | "RESTRICT" | "RIGHT" | "RLIKE"
-| "SCHEMA" | "SCHEMAS" | "SECOND_MICROSECOND" | "SELECT" | "SET" | "SHOW" | "SMALLINT"
+| "ON" | "OPTION" | "OR" | "ORDER" | "OUTER" | "OUTFILE" | "PARTITION" | "PRECISION" | "PRIMARY" | "PROCEDURE" | "RANGE" | "READ" 
+| "REAL" | "REFERENCES" | "REGEXP" | "RELEASE" | "REPEAT" | "REPLACE" | "RESTRICT" | "RIGHT" | "RLIKE"
+| "SCHEMA" | "SCHEMAS" | "SECOND_MICROSECOND" | "SELECT" | "SEPARATOR" | "SET" | "SHOW" | "SMALLINT"
 | "STARTING" | "TABLE" | "TERMINATED" | "THEN" | "TINYBLOB" | "TINYINT" | "TINYTEXT" | "TO"
 | "TRAILING" | "TRUE" | "UNION" | "UNIQUE" | "UNLOCK" | "UNSIGNED"
 | "UPDATE" | "USE" | "USING" | "UTC_DATE" | "VALUES" | "VARBINARY" | "VARCHAR"
@@ -2089,12 +2267,13 @@ ReservedKeyword:
 
 NotKeywordToken:
 	"ABS" | "ADDDATE" | "ADMIN" | "COALESCE" | "CONCAT" | "CONCAT_WS" | "CONNECTION_ID" | "CUR_TIME"| "COUNT" | "DAY"
-|	"DATE_ADD" | "DATE_FORMAT" | "DATE_SUB" | "DAYNAME" | "DAYOFMONTH" | "DAYOFWEEK" | "DAYOFYEAR" | "FOUND_ROWS"
-|	"GROUP_CONCAT"| "GREATEST" | "HOUR" | "HEX" | "UNHEX" | "IFNULL" | "ISNULL" | "LAST_INSERT_ID" | "LCASE" | "LENGTH" | "LOCATE" | "LOWER" | "LTRIM"
+|	"DATE_ADD" | "DATE_FORMAT" | "DATEDIFF" | "DATE_SUB" | "DAYNAME" | "DAYOFMONTH" | "DAYOFWEEK" | "DAYOFYEAR" | "FOUND_ROWS"
+|	"GROUP_CONCAT"| "GREATEST" | "HOUR" | "HEX" | "UNHEX" | "IFNULL" | "ISNULL" | "JSON_EXTRACT" | "JSON_UNQUOTE" | "LAST_INSERT_ID" | "LCASE" | "LENGTH" | "LOCATE" | "LOWER" | "LTRIM"
 |	"MAX" | "MICROSECOND" | "MIN" |	"MINUTE" | "NULLIF" | "MONTH" | "MONTHNAME" | "NOW" | "POW" | "POWER" | "RAND"
 |	"SECOND" | "SLEEP" | "SQL_CALC_FOUND_ROWS" | "STR_TO_DATE" | "SUBDATE" | "SUBSTRING" %prec lowerThanLeftParen |
 "SUBSTRING_INDEX" | "SUM" | "TRIM" | "RTRIM" | "UCASE" | "UPPER" | "VERSION" | "WEEKDAY" | "WEEKOFYEAR" | "YEARWEEK" | "ROUND"
-|	"STATS_PERSISTENT" | "GET_LOCK" | "RELEASE_LOCK" | "CEIL" | "CEILING" | "FROM_UNIXTIME" | "TIMEDIFF" | "LN" | "LOG" | "LOG2" | "LOG10"
+|	"STATS_PERSISTENT" | "GET_LOCK" | "RELEASE_LOCK" | "CEIL" | "CEILING" | "FLOOR" | "FROM_UNIXTIME" | "TIMEDIFF" | "LN" | "LOG" | "LOG2" | "LOG10"
+|	"ROW_NUMBER" | "RANK" | "DENSE_RANK" | "STD" | "STDDEV" | "STDDEV_POP" | "STDDEV_SAMP" | "VARIANCE" | "VAR_POP" | "VAR_SAMP"
 
 /************************************************************************************
  *
@@ -2184,10 +2363,7 @@ ColumnSetValue:
 	}
 
 ColumnSetValueList:
-	{
-		$$ = []*ast.Assignment{}
-	}
-|	ColumnSetValue
+	ColumnSetValue
 	{
 		$$ = []*ast.Assignment{$1.(*ast.Assignment)}
 	}
@@ -2294,6 +2470,23 @@ Operand:
 	{
 		$$ = &ast.ColumnNameExpr{Name: $1.(*ast.ColumnName)}
 	}
+|	ColumnName "->" stringLit
+	{
+		/* See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#operator_json-column-path */
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.JSONExtract),
+			Args: []ast.ExprNode{&ast.ColumnNameExpr{Name: $1.(*ast.ColumnName)}, ast.NewValueExpr($3)},
+		}
+	}
+|	ColumnName "->>" stringLit
+	{
+		/* See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#operator_json-inline-path */
+		extract := &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.JSONExtract),
+			Args: []ast.ExprNode{&ast.ColumnNameExpr{Name: $1.(*ast.ColumnName)}, ast.NewValueExpr($3)},
+		}
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONUnquote), Args: []ast.ExprNode{extract}}
+	}
 |	'(' Expression ')'
 	{
 		startOffset := parser.startOffset(&yyS[yypt-1])
@@ -2433,6 +2626,7 @@ Function:
 |	FunctionCallNonKeyword
 |	FunctionCallConflict
 |	FunctionCallAgg
+|	FunctionCallWindow
 
 FunctionNameConflict:
 	"DATABASE"
@@ -2603,6 +2797,10 @@ FunctionCallNonKeyword:
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3.(ast.ExprNode)}}
 	}
+|	"FLOOR" '(' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3.(ast.ExprNode)}}
+	}
 |	"DAY" '(' Expression ')'
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3.(ast.ExprNode)}}
@@ -2655,6 +2853,13 @@ FunctionCallNonKeyword:
 			},
 		}
 	}
+|	"DATEDIFF" '(' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args:	[]ast.ExprNode{$3.(ast.ExprNode), $5.(ast.ExprNode)},
+		}
+	}
 |	"DATE_FORMAT" '(' Expression ',' Expression ')'
 	{
 		$$ = &ast.FuncCallExpr{
@@ -2707,6 +2912,15 @@ FunctionCallNonKeyword:
 	{
 		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3.(ast.ExprNode)}}
 	}
+|	"JSON_EXTRACT" '(' Expression ',' ExpressionList ')'
+	{
+		args := append([]ast.ExprNode{$3.(ast.ExprNode)}, $5.([]ast.ExprNode)...)
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: args}
+	}
+|	"JSON_UNQUOTE" '(' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3.(ast.ExprNode)}}
+	}
 
 |	"IFNULL" '(' ExpressionList ')'
 	{
@@ -3083,9 +3297,13 @@ FunctionCallAgg:
 		args := []ast.ExprNode{ast.NewValueExpr(1)}
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: args, Distinct: $3.(bool)}
 	}
-|	"GROUP_CONCAT" '(' DistinctOpt ExpressionList ')'
+|	"GROUP_CONCAT" '(' DistinctOpt ExpressionList OrderByOptional GroupConcatSeparator ')'
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: $3.(bool)}
+		agg := &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: $3.(bool), Separator: $6}
+		if $5 != nil {
+			agg.Order = $5.(*ast.OrderByClause)
+		}
+		$$ = agg
 	}
 |	"MAX" '(' DistinctOpt Expression ')'
 	{
@@ -3099,6 +3317,79 @@ FunctionCallAgg:
 	{
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4.(ast.ExprNode)}, Distinct: $3.(bool)}
 	}
+|	VarianceFuncName '(' Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3.(ast.ExprNode)}}
+	}
+
+VarianceFuncName:
+	"STD"
+	{
+		$$ = ast.AggFuncStddevPop
+	}
+|	"STDDEV"
+	{
+		$$ = ast.AggFuncStddevPop
+	}
+|	"STDDEV_POP"
+	{
+		$$ = ast.AggFuncStddevPop
+	}
+|	"STDDEV_SAMP"
+	{
+		$$ = ast.AggFuncStddevSamp
+	}
+|	"VARIANCE"
+	{
+		$$ = ast.AggFuncVarPop
+	}
+|	"VAR_POP"
+	{
+		$$ = ast.AggFuncVarPop
+	}
+|	"VAR_SAMP"
+	{
+		$$ = ast.AggFuncVarSamp
+	}
+
+FunctionCallWindow:
+	WindowFuncName '(' ')' WindowSpec
+	{
+		$$ = &ast.WindowFuncExpr{F: strings.ToLower($1), Spec: $4.(ast.WindowSpec)}
+	}
+
+WindowFuncName:
+	"ROW_NUMBER"
+|	"RANK"
+|	"DENSE_RANK"
+
+WindowSpec:
+	"OVER" '(' PartitionByOptional OrderByOptional ')'
+	{
+		spec := ast.WindowSpec{PartitionBy: $3.([]ast.ExprNode)}
+		if $4 != nil {
+			spec.OrderBy = $4.(*ast.OrderByClause)
+		}
+		$$ = spec
+	}
+
+PartitionByOptional:
+	{
+		$$ = []ast.ExprNode{}
+	}
+|	"PARTITION" "BY" ExpressionList
+	{
+		$$ = $3
+	}
+
+GroupConcatSeparator:
+	{
+		$$ = ","
+	}
+|	"SEPARATOR" stringLit
+	{
+		$$ = $2
+	}
 
 FuncDatetimePrec:
 	{
@@ -3429,22 +3720,43 @@ RollbackStmt:
 	{
 		$$ = &ast.RollbackStmt{}
 	}
+|	"ROLLBACK" "TO" Identifier
+	{
+		$$ = &ast.RollbackStmt{SavepointName: $3}
+	}
+|	"ROLLBACK" "TO" "SAVEPOINT" Identifier
+	{
+		$$ = &ast.RollbackStmt{SavepointName: $4}
+	}
+
+ReleaseSavepointStmt:
+	"RELEASE" "SAVEPOINT" Identifier
+	{
+		$$ = &ast.ReleaseSavepointStmt{Name: $3}
+	}
+
+SavepointStmt:
+	"SAVEPOINT" Identifier
+	{
+		$$ = &ast.SavepointStmt{Name: $2}
+	}
 
 SelectStmt:
-	"SELECT" SelectStmtOpts SelectStmtFieldList SelectStmtLimit SelectLockOpt
+	"SELECT" OptimizerHintsOpt SelectStmtOpts SelectStmtFieldList SelectStmtLimit SelectLockOpt
 	{
 		st := &ast.SelectStmt {
-			Distinct:      $2.(bool),
-			Fields:        $3.(*ast.FieldList),
-			LockTp:	       $5.(ast.SelectLockType),
+			Distinct:      $3.(bool),
+			Fields:        $4.(*ast.FieldList),
+			LockTp:	       $6.(ast.SelectLockType),
+			TableHints:    $2.([]*ast.TableOptimizerHint),
 		}
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
 		if lastField.Expr != nil && lastField.AsName.O == "" {
 			src := parser.src
 			var lastEnd int
-			if $4 != nil {
+			if $5 != nil {
 				lastEnd = yyS[yypt-1].offset-1
-			} else if $5 != ast.SelectLockNone {
+			} else if $6 != ast.SelectLockNone {
 				lastEnd = yyS[yypt].offset-1
 			} else {
 				lastEnd = len(src)
@@ -3454,40 +3766,42 @@ SelectStmt:
 			}
 			lastField.SetText(src[lastField.Offset:lastEnd])
 		}
-		if $4 != nil {
-			st.Limit = $4.(*ast.Limit)
+		if $5 != nil {
+			st.Limit = $5.(*ast.Limit)
 		}
 		$$ = st
 	}
-|	"SELECT" SelectStmtOpts SelectStmtFieldList FromDual WhereClauseOptional SelectStmtLimit SelectLockOpt
+|	"SELECT" OptimizerHintsOpt SelectStmtOpts SelectStmtFieldList FromDual WhereClauseOptional SelectStmtLimit SelectLockOpt
 	{
 		st := &ast.SelectStmt {
-			Distinct:      $2.(bool),
-			Fields:        $3.(*ast.FieldList),
-			LockTp:	       $7.(ast.SelectLockType),
+			Distinct:      $3.(bool),
+			Fields:        $4.(*ast.FieldList),
+			LockTp:	       $8.(ast.SelectLockType),
+			TableHints:    $2.([]*ast.TableOptimizerHint),
 		}
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
 		if lastField.Expr != nil && lastField.AsName.O == "" {
 			lastEnd := yyS[yypt-3].offset-1
 			lastField.SetText(parser.src[lastField.Offset:lastEnd])
 		}
-		if $5 != nil {
-			st.Where = $5.(ast.ExprNode)
-		}
 		if $6 != nil {
-			st.Limit = $6.(*ast.Limit)
+			st.Where = $6.(ast.ExprNode)
+		}
+		if $7 != nil {
+			st.Limit = $7.(*ast.Limit)
 		}
 		$$ = st
 	}
-|	"SELECT" SelectStmtOpts SelectStmtFieldList "FROM"
+|	"SELECT" OptimizerHintsOpt SelectStmtOpts SelectStmtFieldList "FROM"
 	TableRefsClause WhereClauseOptional SelectStmtGroup HavingClause OrderByOptional
 	SelectStmtLimit SelectLockOpt
 	{
 		st := &ast.SelectStmt{
-			Distinct:	$2.(bool),
-			Fields:		$3.(*ast.FieldList),
-			From:		$5.(*ast.TableRefsClause),
-			LockTp:		$11.(ast.SelectLockType),
+			Distinct:	$3.(bool),
+			Fields:		$4.(*ast.FieldList),
+			From:		$6.(*ast.TableRefsClause),
+			LockTp:		$12.(ast.SelectLockType),
+			TableHints:	$2.([]*ast.TableOptimizerHint),
 		}
 
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
@@ -3496,24 +3810,24 @@ SelectStmt:
 			lastField.SetText(parser.src[lastField.Offset:lastEnd])
 		}
 
-		if $6 != nil {
-			st.Where = $6.(ast.ExprNode)
-		}
-
 		if $7 != nil {
-			st.GroupBy = $7.(*ast.GroupByClause)
+			st.Where = $7.(ast.ExprNode)
 		}
 
 		if $8 != nil {
-			st.Having = $8.(*ast.HavingClause)
+			st.GroupBy = $8.(*ast.GroupByClause)
 		}
 
 		if $9 != nil {
-			st.OrderBy = $9.(*ast.OrderByClause)
+			st.Having = $9.(*ast.HavingClause)
 		}
 
 		if $10 != nil {
-			st.Limit = $10.(*ast.Limit)
+			st.OrderBy = $10.(*ast.OrderByClause)
+		}
+
+		if $11 != nil {
+			st.Limit = $11.(*ast.Limit)
 		}
 
 		$$ = st
@@ -3576,14 +3890,14 @@ TableFactor:
 		tn.IndexHints = $3.([]*ast.IndexHint)
 		$$ = &ast.TableSource{Source: tn, AsName: $2.(model.CIStr)}
 	}
-|	'(' SelectStmt ')' TableAsName
+|	'(' SelectStmt ')' TableAsNameOpt
 	{
 		st := $2.(*ast.SelectStmt)
 		endOffset := parser.endOffset(&yyS[yypt-1])
 		parser.setLastSelectFieldText(st, endOffset)
 		$$ = &ast.TableSource{Source: $2.(*ast.SelectStmt), AsName: $4.(model.CIStr)}
 	}
-|	'(' UnionStmt ')' TableAsName
+|	'(' UnionStmt ')' TableAsNameOpt
 	{
 		$$ = &ast.TableSource{Source: $2.(*ast.UnionStmt), AsName: $4.(model.CIStr)}
 	}
@@ -3658,15 +3972,19 @@ IndexNameList:
 		var nameList []model.CIStr
 		$$ = nameList
 	}
-|	Identifier
+|	IndexHintName
 	{
 		$$ = []model.CIStr{model.NewCIStr($1)}
 	}
-|	IndexNameList ',' Identifier
+|	IndexNameList ',' IndexHintName
 	{
 		$$ = append($1.([]model.CIStr), model.NewCIStr($3))
 	}
 
+IndexHintName:
+	Identifier
+|	"PRIMARY"
+
 
 IndexHintList:
 	IndexHint
@@ -3699,12 +4017,19 @@ JoinTable:
 		on := &ast.OnCondition{Expr: $5.(ast.ExprNode)}
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $3.(ast.ResultSetNode), Tp: ast.CrossJoin, On: on}
 	}
+|	TableRef CrossOpt TableRef "USING" '(' ColumnNameList ')'
+	{
+		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $3.(ast.ResultSetNode), Tp: ast.CrossJoin, Using: $6.([]*ast.ColumnName)}
+	}
 |	TableRef JoinType OuterOpt "JOIN" TableRef "ON" Expression
 	{
 		on := &ast.OnCondition{Expr: $7.(ast.ExprNode)}
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $5.(ast.ResultSetNode), Tp: $2.(ast.JoinType), On: on}
 	}
-	/* Support Using */
+|	TableRef JoinType OuterOpt "JOIN" TableRef "USING" '(' ColumnNameList ')'
+	{
+		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $5.(ast.ResultSetNode), Tp: $2.(ast.JoinType), Using: $8.([]*ast.ColumnName)}
+	}
 
 JoinType:
 	"LEFT"
@@ -3765,6 +4090,16 @@ SelectStmtDistinct:
 		$$ = true
 	}
 
+OptimizerHintsOpt:
+	{
+		var hints []*ast.TableOptimizerHint
+		$$ = hints
+	}
+|	hintComment
+	{
+		$$ = parseOptimizerHints($1)
+	}
+
 SelectStmtOpts:
 	SelectStmtDistinct SelectStmtSQLCache SelectStmtCalcFoundRows
 	{
@@ -3853,7 +4188,11 @@ UnionStmt:
 		lastSelect := union.SelectList.Selects[len(union.SelectList.Selects)-1]
 		endOffset := parser.endOffset(&yyS[yypt-2])
 		parser.setLastSelectFieldText(lastSelect, endOffset)
-		union.SelectList.Selects = append(union.SelectList.Selects, $4.(*ast.SelectStmt))
+		st := $4.(*ast.SelectStmt)
+		// The trailing ORDER BY and LIMIT of an unparenthesized select apply to the whole union.
+		union.OrderBy, st.OrderBy = st.OrderBy, nil
+		union.Limit, st.Limit = st.Limit, nil
+		union.SelectList.Selects = append(union.SelectList.Selects, st)
 		$$ = union
 	}
 |	UnionClauseList "UNION" UnionOpt '(' SelectStmt ')' OrderByOptional SelectStmtLimit
@@ -4144,6 +4483,13 @@ ShowStmt:
 			Table:	$4.(*ast.TableName),
 		}
 	}
+|	"SHOW" "CREATE" "VIEW" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:	ast.ShowCreateView,
+			Table:	$4.(*ast.TableName),
+		}
+	}
 |	"SHOW" "CREATE" "DATABASE" DBName 
 	{
 		$$ = &ast.ShowStmt{
@@ -4164,10 +4510,11 @@ ShowStmt:
 			User:	$4.(string),
 		}
 	}
-|	"SHOW" "PROCESSLIST"
+|	"SHOW" OptFull "PROCESSLIST"
 	{
 		$$ = &ast.ShowStmt{
-			Tp: ast.ShowProcessList,
+			Tp:	ast.ShowProcessList,
+			Full:	$2.(bool),
 		}
 	}
 
@@ -4249,6 +4596,10 @@ ShowTargetFilterable:
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowWarnings}
 	}
+|	"ERRORS"
+	{
+		$$ = &ast.ShowStmt{Tp: ast.ShowErrors}
+	}
 |	GlobalScope "VARIABLES"
 	{
 		$$ = &ast.ShowStmt{
@@ -4370,6 +4721,27 @@ FlushStmt:
 		}
 	}
 
+KillStmt:
+	"KILL" LengthNum
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID: $2.(uint64),
+		}
+	}
+|	"KILL" "CONNECTION" LengthNum
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID: $3.(uint64),
+		}
+	}
+|	"KILL" "QUERY" LengthNum
+	{
+		$$ = &ast.KillStmt{
+			Query: true,
+			ConnectionID: $3.(uint64),
+		}
+	}
+
 NoWriteToBinLogAliasOpt:
 	{
 		$$ = false
@@ -4404,6 +4776,7 @@ WithReadLockOpt:
 Statement:
 	EmptyStmt
 |	AdminStmt
+|	AlterDatabaseStmt
 |	AlterTableStmt
 |	AlterUserStmt
 |	AnalyzeTableStmt
@@ -4418,6 +4791,7 @@ Statement:
 |	CreateIndexStmt
 |	CreateTableStmt
 |	CreateUserStmt
+|	CreateViewStmt
 |	DoStmt
 |	DropDatabaseStmt
 |	DropIndexStmt
@@ -4427,11 +4801,15 @@ Statement:
 |	FlushStmt
 |	GrantStmt
 |	InsertIntoStmt
+|	KillStmt
 |	LoadDataStmt
 |	PreparedStmt
+|	ReleaseSavepointStmt
 |	RollbackStmt
 |	ReplaceIntoStmt
+|	SavepointStmt
 |	SelectStmt
+|	SelectIntoStmt
 |	UnionStmt
 |	SetStmt
 |	ShowStmt
@@ -4496,11 +4874,6 @@ TableElement:
 	{
 		$$ = $1.(*ast.Constraint)
 	}
-|	"CHECK" '(' Expression ')'
-	{
-		/* Nothing to do now */
-		$$ = nil
-	}
 
 TableElementList:
 	TableElement
@@ -4667,6 +5040,13 @@ Type:
 	{
 		$$ = $1
 	}
+|	"JSON"
+	{
+		x := types.NewFieldType(mysql.TypeJSON)
+		x.Charset = charset.CharsetBin
+		x.Collate = charset.CollationBin
+		$$ = x
+	}
 
 NumericType:
 	IntegerType OptFieldLen FieldOpts
@@ -5097,6 +5477,7 @@ UpdateStmt:
 		}
 		st := &ast.UpdateStmt{
 			LowPriority:	$2.(bool),
+			Ignore:		$3.(bool),
 			TableRefs:	&ast.TableRefsClause{TableRefs: refs},
 			List:		$6.([]*ast.Assignment),
 		}
@@ -5115,6 +5496,7 @@ UpdateStmt:
 	{
 		st := &ast.UpdateStmt{
 			LowPriority:	$2.(bool),
+			Ignore:		$3.(bool),
 			TableRefs:	&ast.TableRefsClause{TableRefs: $4.(*ast.Join)},
 			List:		$6.([]*ast.Assignment),
 		}
@@ -5304,6 +5686,10 @@ PrivType:
 	{
 		$$ = mysql.ExecutePriv
 	}
+|	"FILE"
+	{
+		$$ = mysql.FilePriv
+	}
 |	"INDEX"
 	{
 		$$ = mysql.IndexPriv
@@ -5312,6 +5698,10 @@ PrivType:
 	{
 		$$ = mysql.InsertPriv
 	}
+|	"PROCESS"
+	{
+		$$ = mysql.ProcessPriv
+	}
 |	"SELECT"
 	{
 		$$ = mysql.SelectPriv
@@ -5320,6 +5710,10 @@ PrivType:
 	{
 		$$ = mysql.ShowDBPriv
 	}
+|	"SUPER"
+	{
+		$$ = mysql.SuperPriv
+	}
 |	"UPDATE"
 	{
 		$$ = mysql.UpdatePriv
@@ -5378,11 +5772,13 @@ PrivLevel:
  * See https://dev.mysql.com/doc/refman/5.7/en/load-data.html
  *******************************************************************************************/
 LoadDataStmt:
-	"LOAD" "DATA" LocalOpt "INFILE" stringLit "INTO" "TABLE" TableName Fields Lines
+	"LOAD" "DATA" LocalOpt "INFILE" stringLit "INTO" "TABLE" TableName Fields Lines IgnoreLines ColumnNameListOptWithBrackets
 	{
 		x := &ast.LoadDataStmt{
-			Path:       $5,
-			Table:      $8.(*ast.TableName),
+			Path:        $5,
+			Table:       $8.(*ast.TableName),
+			IgnoreLines: $11.(uint64),
+			Columns:     $12.([]*ast.ColumnName),
 		}
 		if $3 != nil {
 			x.IsLocal = true
@@ -5396,6 +5792,22 @@ LoadDataStmt:
 		$$ = x
 	}
 
+/*******************************************************************************************
+ * SELECT ... INTO OUTFILE 'file_name' [FIELDS ...] [LINES ...]
+ * See https://dev.mysql.com/doc/refman/5.7/en/select-into.html
+ *******************************************************************************************/
+SelectIntoStmt:
+	SelectStmt "INTO" "OUTFILE" stringLit Fields Lines
+	{
+		st := $1.(*ast.SelectStmt)
+		st.SelectIntoOpt = &ast.SelectIntoOption{
+			FileName:   $4,
+			FieldsInfo: $5.(*ast.FieldsClause),
+			LinesInfo:  $6.(*ast.LinesClause),
+		}
+		$$ = st
+	}
+
 LocalOpt:
 	{
 		$$ = nil 
@@ -5420,6 +5832,10 @@ Fields:
 			yylex.Errorf("Incorrect arguments %s to ESCAPE", escape)
 			return 1
 		}
+		var escaped byte
+		if len(escape) != 0 {
+			escaped = escape[0]
+		}
 		var enclosed byte
 		str := $3.(string)
 		if len(str) > 1 {
@@ -5431,7 +5847,7 @@ Fields:
 		$$ = &ast.FieldsClause{
 			Terminated: $2.(string),
 			Enclosed:   enclosed,
-			Escaped:    escape[0],
+			Escaped:    escaped,
 		}
 	}
 
@@ -5492,6 +5908,24 @@ LinesTerminated:
 		$$ = $3
 	}
 
+IgnoreLines:
+	{
+		$$ = uint64(0)
+	}
+|	"IGNORE" LengthNum "LINES"
+	{
+		$$ = $2
+	}
+
+ColumnNameListOptWithBrackets:
+	{
+		$$ = []*ast.ColumnName{}
+	}
+|	'(' ColumnNameListOpt ')'
+	{
+		$$ = $2
+	}
+
 
 /*********************************************************************
  * Lock/Unlock Tables
@@ -21,6 +21,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/util/testleak"
 )
 
@@ -51,9 +52,9 @@ func (s *testParserSuite) TestSimple(c *C) {
 		"interval", "is", "join", "key", "keys", "leading", "left", "like", "limit", "lines", "load",
 		"localtime", "localtimestamp", "lock", "longblob", "longtext", "mediumblob", "maxvalue", "mediumint", "mediumtext",
 		"minute_microsecond", "minute_second", "mod", "not", "no_write_to_binlog", "null", "numeric",
-		"on", "option", "or", "order", "outer", "partition", "precision", "primary", "procedure", "range", "read", "real",
+		"on", "option", "or", "order", "outer", "outfile", "partition", "precision", "primary", "procedure", "range", "read", "real",
 		"references", "regexp", "repeat", "replace", "restrict", "right", "rlike",
-		"schema", "schemas", "second_microsecond", "select", "set", "show", "smallint",
+		"schema", "schemas", "second_microsecond", "select", "separator", "set", "show", "smallint",
 		"starting", "table", "terminated", "then", "tinyblob", "tinyint", "tinytext", "to",
 		"trailing", "true", "union", "unique", "unlock", "unsigned",
 		"update", "use", "using", "utc_date", "values", "varbinary", "varchar",
@@ -88,8 +89,9 @@ func (s *testParserSuite) TestSimple(c *C) {
 		"curtime", "variables", "dayname", "version", "btree", "hash", "row_format", "dynamic", "fixed", "compressed",
 		"compact", "redundant", "sql_no_cache sql_no_cache", "sql_cache sql_cache", "action", "round",
 		"enable", "disable", "reverse", "space", "privileges", "get_lock", "release_lock", "sleep", "no", "greatest",
-		"binlog", "hex", "unhex", "function", "indexes", "from_unixtime", "processlist", "events", "less", "than", "timediff",
-		"ln", "log", "log2", "log10",
+		"binlog", "hex", "unhex", "function", "indexes", "from_unixtime", "processlist", "events", "errors", "rollup", "less", "than", "timediff",
+		"ln", "floor", "log", "log2", "log10", "savepoint", "format", "kill", "query", "json", "json_extract", "json_unquote",
+		"process", "super", "file",
 	}
 	for _, kw := range unreservedKws {
 		src := fmt.Sprintf("SELECT %s FROM tbl;", kw)
@@ -217,6 +219,10 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"INSERT INTO foo (a,b,) VALUES (42,314,)", false},
 		{"INSERT INTO foo () VALUES ()", true},
 		{"INSERT INTO foo VALUE ()", true},
+		{"INSERT INTO foo SET a = 1, b = DEFAULT", true},
+		{"INSERT foo SET a = 1 + 2, b = concat('x', 'y')", true},
+		{"INSERT INTO foo SET", false},
+		{"INSERT INTO foo (a) SET a = 1", false},
 
 		{"REPLACE INTO foo VALUES (1 || 2)", true},
 		{"REPLACE INTO foo VALUES (1 | 2)", true},
@@ -228,6 +234,7 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"REPLACE INTO foo (a,b,) VALUES (42,314,)", false},
 		{"REPLACE INTO foo () VALUES ()", true},
 		{"REPLACE INTO foo VALUE ()", true},
+		{"REPLACE INTO foo SET a = 1", true},
 		// 40
 		{`SELECT stuff.id
 			FROM stuff
@@ -247,6 +254,15 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 			SELECT * from tmp;
 		ROLLBACK;`, true},
 
+		// savepoint
+		{"SAVEPOINT sp1", true},
+		{"ROLLBACK TO sp1", true},
+		{"ROLLBACK TO SAVEPOINT sp1", true},
+		{"ROLLBACK TO SAVEPOINT savepoint", true},
+		{"RELEASE SAVEPOINT sp1", true},
+		{"SAVEPOINT", false},
+		{"RELEASE sp1", false},
+
 		// qualified select
 		{"SELECT a.b.c FROM t", true},
 		{"SELECT a.b.*.c FROM t", false},
@@ -268,6 +284,10 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"load data infile '/tmp/t.csv' into table t lines starting by 'ab' terminated by 'xy'", true},
 		{"load data infile '/tmp/t.csv' into table t fields terminated by 'ab' lines terminated by 'xy'", true},
 		{"load data infile '/tmp/t.csv' into table t terminated by 'xy' fields terminated by 'ab'", false},
+		{"load data infile '/tmp/t.csv' into table t ignore 1 lines", true},
+		{"load data infile '/tmp/t.csv' into table t (a, b)", true},
+		{"load data infile '/tmp/t.csv' into table t fields terminated by ',' ignore 1 lines (a, b)", true},
+		{"load data infile '/tmp/t.csv' into table t (a, b) ignore 1 lines", false},
 		{"load data local infile '/tmp/t.csv' into table t", true},
 		{"load data local infile '/tmp/t.csv' into table t fields terminated by 'ab'", true},
 		{"load data local infile '/tmp/t.csv' into table t columns terminated by 'ab'", true},
@@ -277,6 +297,15 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"load data local infile '/tmp/t.csv' into table t lines starting by 'ab' terminated by 'xy'", true},
 		{"load data local infile '/tmp/t.csv' into table t fields terminated by 'ab' lines terminated by 'xy'", true},
 		{"load data local infile '/tmp/t.csv' into table t terminated by 'xy' fields terminated by 'ab'", false},
+		{"load data infile '/tmp/t.csv' into table t fields escaped by ''", true},
+
+		// select into outfile
+		{"select * from t into outfile '/tmp/t.csv'", true},
+		{"select 1, 'a' into outfile '/tmp/t.csv'", true},
+		{"select a from t where a > 1 order by a limit 10 into outfile '/tmp/t.csv' fields terminated by ',' enclosed by '\"' escaped by '' lines starting by '>' terminated by '\r\n'", true},
+		{"select * from t into outfile", false},
+		{"select * from t into outfile '/tmp/t.csv' lines terminated by 'xy' fields terminated by 'ab'", false},
+		{"select * from (select * from t into outfile '/tmp/t.csv') as t1", false},
 
 		// Select for update
 		{"SELECT * from t for update", true},
@@ -296,6 +325,10 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"select * from t1 join t2 left join t3 on t2.id = t3.id", true},
 		{"select * from t1 right join t2 on t1.id = t2.id left join t3 on t3.id = t2.id", true},
 		{"select * from t1 right join t2 on t1.id = t2.id left join t3", false},
+		{"select * from t1 join t2 using (id)", true},
+		{"select * from t1 left join t2 using (id, name) right outer join t3 using (id)", true},
+		{"select * from t1 inner join t2 using ()", false},
+		{"select * from t1 join t2 using id", false},
 
 		// For admin
 		{"admin show ddl;", true},
@@ -341,6 +374,11 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 
 		{`ANALYZE TABLE t`, true},
 
+		// For group by with rollup
+		{`select a, b, count(*) from t group by a, b with rollup`, true},
+		{`select a, count(*) from t group by a with rollup having a is null order by a`, true},
+		{`select a from t group by with rollup`, false},
+
 		// For Binlog stmt
 		{`BINLOG '
 BxSFVw8JAAAA8QAAAPUAAAAAAAQANS41LjQ0LU1hcmlhREItbG9nAAAAAAAAAAAAAAAAAAAAAAAA
@@ -398,6 +436,18 @@ func (s *testParserSuite) TestDBAStmt(c *C) {
 		// For show create table
 		{"show create table test.t", true},
 		{"show create table t", true},
+		// For show processlist
+		{"show processlist", true},
+		{"show full processlist", true},
+		// For show warnings and errors
+		{"show warnings", true},
+		{"show errors", true},
+		// For kill statement
+		{"kill 23123", true},
+		{"kill connection 23123", true},
+		{"kill query 23123", true},
+		{"kill", false},
+		{"kill query", false},
 
 		// set
 		// user defined
@@ -487,6 +537,10 @@ func (s *testParserSuite) TestExpression(c *C) {
 		{`select _utf8"string";`, true},
 		// For comparison
 		{"select 1 <=> 0, 1 <=> null, 1 = null", true},
+		// For IS [NOT] NULL/TRUE/FALSE/UNKNOWN
+		{"select a is null, a is not null, a is true, a is not true, a is false, a is not false", true},
+		{"select a is unknown, a is not unknown, (a > 1) is unknown", true},
+		{"select a is not", false},
 	}
 	s.RunTest(c, table)
 }
@@ -506,6 +560,7 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"SELECT ROUND(1.23, 1);", true},
 		{"SELECT CEIL(-1.23);", true},
 		{"SELECT CEILING(1.23);", true},
+		{"SELECT FLOOR(-1.23);", true},
 		{"SELECT LN(1);", true},
 		{"SELECT LOG(-2);", true},
 		{"SELECT LOG(2, 65536);", true},
@@ -576,6 +631,7 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"select sysdate(), sysdate(6)", true},
 		{"SELECT time('01:02:03');", true},
 		{"SELECT TIMEDIFF('2000:01:01 00:00:00', '2000:01:01 00:00:00.000001');", true},
+		{"SELECT DATEDIFF('2007-12-31 23:59:59','2007-12-30');", true},
 
 		// Select current_time
 		{"select current_time", true},
@@ -769,9 +825,44 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{`select adddate("2011-11-11 10:10:10.123456", 0.10)`, true},
 		{`select adddate("2011-11-11 10:10:10.123456", "11,11")`, true},
 
+		// For json functions
+		{`select json_extract(c1, '$.a') from t`, true},
+		{`select json_extract(c1, '$.a', '$[0]') from t`, true},
+		{`select json_extract(c1) from t`, false},
+		{`select json_unquote(json_extract(c1, '$.a')) from t`, true},
+		{`select c1->'$.a', t.c1->>'$.a' from t where c1->'$.b' = 1`, true},
+		{`select c1->c2 from t`, false},
+		{`select 1->'$.a'`, false},
+
 		// For misc functions
 		{`SELECT GET_LOCK('lock1',10);`, true},
 		{`SELECT RELEASE_LOCK('lock1');`, true},
+
+		// For group_concat
+		{`select group_concat(c1) from t`, true},
+		{`select group_concat(distinct c1, c2) from t`, true},
+		{`select group_concat(c1 order by c2 desc, c3) from t`, true},
+		{`select group_concat(c1 separator ';') from t`, true},
+		{`select group_concat(distinct c1 order by c1 separator '') from t`, true},
+		{`select group_concat(c1 separator 1) from t`, false},
+		{`select group_concat(c1 separator ';' order by c1) from t`, false},
+
+		// For window functions
+		{`select row_number() over () from t`, true},
+		{`select c1, rank() over (partition by c2 order by c3 desc) from t`, true},
+		{`select dense_rank() over (partition by c1, c2 + 1 order by c3, c4) r from t order by r`, true},
+		{`select row_number() over (order by c1) from t`, true},
+		{`select rank from t where rank = 1`, true},
+		{`select row_number() from t`, false},
+		{`select rank(c1) over () from t`, false},
+		{`select row_number() over (order by c1 partition by c2) from t`, false},
+
+		// For variance and standard deviation
+		{`select std(c1), stddev(c1), stddev_pop(c1), stddev_samp(c1) from t`, true},
+		{`select variance(c1), var_pop(c1 + 1), var_samp(c1) from t group by c2`, true},
+		{`select std from t`, true},
+		{`select std(distinct c1) from t`, false},
+		{`select var_pop(c1, c2) from t`, false},
 	}
 	s.RunTest(c, table)
 }
@@ -869,6 +960,9 @@ func (s *testParserSuite) TestDDL(c *C) {
 		// For check clause
 		{"create table t (c1 bool, c2 bool, check (c1 in (0, 1)), check (c2 in (0, 1)))", true},
 		{"CREATE TABLE Customer (SD integer CHECK (SD > 0), First_Name varchar(30));", true},
+		{"create table t (c1 int, c2 int, constraint c1_positive check (c1 > 0), constraint check (c2 > c1))", true},
+		{"create table t (c1 int, constraint check)", false},
+		{"alter table t add constraint c1_positive check (c1 > 0)", true},
 
 		{"create database xxx", true},
 		{"create database if exists xxx", false},
@@ -876,6 +970,12 @@ func (s *testParserSuite) TestDDL(c *C) {
 		{"create schema xxx", true},
 		{"create schema if exists xxx", false},
 		{"create schema if not exists xxx", true},
+		// For alter database/schema
+		{"alter database xxx character set utf8", true},
+		{"alter database xxx default charset = utf8 collate utf8_bin", true},
+		{"alter schema xxx default collate = utf8_bin", true},
+		{"alter database xxx", false},
+		{"alter database character set utf8", false},
 		// For drop database/schema/table
 		{"drop database xxx", true},
 		{"drop database if exists xxx", true},
@@ -890,6 +990,24 @@ func (s *testParserSuite) TestDDL(c *C) {
 		{"drop table if exists xxx", true},
 		{"drop table if not exists xxx", false},
 		{"drop view if exists xxx", true},
+		// For temporary table
+		{"create temporary table t (c int)", true},
+		{"create temporary table if not exists t (c int, index idx (c))", true},
+		{"create table temporary (temporary int)", true},
+		{"drop temporary table t", true},
+		{"drop temporary tables if exists t1, t2", true},
+		{"drop temporary xxx", false},
+		// For view
+		{"create view v as select * from t", true},
+		{"create or replace view v (a, b) as select c, d from t where c > 1", true},
+		{"create view v as select 1 union select 2", true},
+		{"create view v () as select 1", false},
+		{"create or view v as select 1", false},
+		{"create view v as insert t values (1)", false},
+		{"create table view (view int)", true},
+		{"drop view v", true},
+		{"drop view v1, v2", true},
+		{"show create view v", true},
 		// For issue 974
 		{`CREATE TABLE address (
 		id bigint(20) NOT NULL AUTO_INCREMENT,
@@ -980,6 +1098,36 @@ union_id int(11) DEFAULT '0',
 PRIMARY KEY (union_name)) ENGINE=MyISAM DEFAULT CHARSET=binary;`, true},
 	}
 	s.RunTest(c, table)
+
+	parser := New()
+	stmt, err := parser.ParseOneStmt("create temporary table t (c int)", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.CreateTableStmt).IsTemporary, IsTrue)
+	stmt, err = parser.ParseOneStmt("create table t (c int)", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.CreateTableStmt).IsTemporary, IsFalse)
+	stmt, err = parser.ParseOneStmt("drop temporary table if exists t", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.DropTableStmt).IsTemporary, IsTrue)
+	c.Assert(stmt.(*ast.DropTableStmt).IfExists, IsTrue)
+
+	// The text of the select statement is stored as the definition of the view.
+	stmt, err = parser.ParseOneStmt("create or replace view v (a, b) as select c, d from t limit 1 ;", "", "")
+	c.Assert(err, IsNil)
+	view := stmt.(*ast.CreateViewStmt)
+	c.Assert(view.OrReplace, IsTrue)
+	c.Assert(view.ViewName.Name.O, Equals, "v")
+	c.Assert(view.Cols, DeepEquals, []model.CIStr{model.NewCIStr("a"), model.NewCIStr("b")})
+	c.Assert(view.Select.Text(), Equals, "select c, d from t limit 1")
+	stmts, err := parser.Parse("create view v as select 1 union select 2; create view v1 as select * from v", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmts[0].(*ast.CreateViewStmt).Select.Text(), Equals, "select 1 union select 2")
+	c.Assert(stmts[0].(*ast.CreateViewStmt).OrReplace, IsFalse)
+	c.Assert(stmts[1].(*ast.CreateViewStmt).Select.Text(), Equals, "select * from v")
+	stmt, err = parser.ParseOneStmt("drop view if exists v", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.DropTableStmt).IsView, IsTrue)
+	c.Assert(stmt.(*ast.DropTableStmt).IfExists, IsTrue)
 }
 
 func (s *testParserSuite) TestType(c *C) {
@@ -1008,6 +1156,10 @@ func (s *testParserSuite) TestType(c *C) {
 		// For blob and text field length
 		{"create table t (c1 blob(1024), c2 text(1024))", true},
 
+		// For json
+		{"create table t (c1 json, c2 json not null)", true},
+		{"create table t (c1 json(10))", false},
+
 		// For year
 		{"create table t (y year(4), y1 year)", true},
 
@@ -1048,6 +1200,9 @@ func (s *testParserSuite) TestPrivilege(c *C) {
 		{"GRANT ALL ON mydb.mytbl TO 'someuser'@'somehost';", true},
 		{"GRANT SELECT, INSERT ON mydb.mytbl TO 'someuser'@'somehost';", true},
 		{"GRANT SELECT (col1), INSERT (col1,col2) ON mydb.mytbl TO 'someuser'@'somehost';", true},
+		{"GRANT PROCESS ON *.* TO 'someuser'@'somehost';", true},
+		{"GRANT SUPER ON *.* TO 'someuser'@'somehost';", true},
+		{"GRANT FILE ON *.* TO 'someuser'@'somehost';", true},
 		{"grant all privileges on zabbix.* to 'zabbix'@'localhost' identified by 'password';", true},
 	}
 	s.RunTest(c, table)
@@ -1064,9 +1219,51 @@ func (s *testParserSuite) TestComment(c *C) {
 		{"START TRANSACTION /*!40108 WITH CONSISTENT SNAPSHOT */", true},
 		// For comment in query
 		{"/*comment*/ /*comment*/ select c /* this is a comment */ from t;", true},
+		{"select c from t -- comment\nwhere c = 1", true},
+		{"select c from t --\twhere c = 1", true},
+		{"select c from t where c = 1--", true},
+		{"select c from t where c = 1--1", true},
+		{"/*!40101SET NAMES utf8*/", true},
+		// For optimizer hints
+		{"select /*+ USE_INDEX(t, idx) */ c from t", true},
+		{"select /*+ use_index(t1, idx1, idx2) ignore_index(t2 idx) */ c from t1, t2", true},
+		{"select /*+ unknown */ c from t", true},
+		{"select c /*+ USE_INDEX(t, idx) */ from t", true},
+		{"insert /*+ USE_INDEX(t, idx) */ into t values (1)", true},
+		{"select c from t where c in (select /*+ IGNORE_INDEX(t) */ c from t)", true},
+		{"select /*+ USE_INDEX(t, idx) */ distinct c from t", true},
 	}
 	s.RunTest(c, table)
 }
+
+func (s *testParserSuite) TestOptimizerHints(c *C) {
+	defer testleak.AfterTest(c)()
+	parser := New()
+	stmt, err := parser.ParseOneStmt("select /*+ USE_INDEX(t1, idx1, `idx 2`), ignore_index(t2 idx3) use_index(t3) */ c from t1", "", "")
+	c.Assert(err, IsNil)
+	hints := stmt.(*ast.SelectStmt).TableHints
+	c.Assert(hints, HasLen, 3)
+	c.Assert(hints[0].HintName.L, Equals, "use_index")
+	c.Assert(hints[0].Table.L, Equals, "t1")
+	c.Assert(hints[0].Indexes, DeepEquals, []model.CIStr{model.NewCIStr("idx1"), model.NewCIStr("idx 2")})
+	c.Assert(hints[1].HintName.L, Equals, "ignore_index")
+	c.Assert(hints[1].Table.L, Equals, "t2")
+	c.Assert(hints[1].Indexes, DeepEquals, []model.CIStr{model.NewCIStr("idx3")})
+	c.Assert(hints[2].Table.L, Equals, "t3")
+	c.Assert(hints[2].Indexes, HasLen, 0)
+
+	// The hints after a malformed one are ignored.
+	stmt, err = parser.ParseOneStmt("select /*+ USE_INDEX(t1, idx1) USE_INDEX(t2, 1) USE_INDEX(t3, idx3) */ c from t1", "", "")
+	c.Assert(err, IsNil)
+	hints = stmt.(*ast.SelectStmt).TableHints
+	c.Assert(hints, HasLen, 1)
+	c.Assert(hints[0].Table.L, Equals, "t1")
+
+	// The hints comment not following the SELECT keyword is an ordinary comment.
+	stmt, err = parser.ParseOneStmt("select c /*+ USE_INDEX(t1, idx1) */ from t1", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.SelectStmt).TableHints, HasLen, 0)
+}
 func (s *testParserSuite) TestSubquery(c *C) {
 	defer testleak.AfterTest(c)()
 	table := []testCase{
@@ -1104,6 +1301,7 @@ func (s *testParserSuite) TestUnion(c *C) {
 		{"(select c1 from t1) union select c2 from t2 union (select c3 from t3) order by c1 limit 1", true},
 		{"select (select 1 union select 1) as a", true},
 		{"select * from (select 1 union select 2) as a", true},
+		{"select * from (select 1 union select 2)", true},
 		{"insert into t select c1 from t1 union select c2 from t2", true},
 		{"insert into t (c) select c1 from t1 union select c2 from t2", true},
 	}
@@ -1146,6 +1344,8 @@ func (s *testParserSuite) TestIndexHint(c *C) {
 		{`select * from t use index for order by (idx1)`, true},
 		{`select * from t force index for group by (idx1)`, true},
 		{`select * from t use index for group by (idx1) use index for order by (idx2), t2`, true},
+		{`select * from t use index (primary, idx1)`, true},
+		{`select * from t force index (primary)`, true},
 	}
 	s.RunTest(c, table)
 }
@@ -1202,6 +1402,10 @@ func (s *testParserSuite) TestExplain(c *C) {
 		{"explain replace into foo values (1 || 2)", true},
 		{"explain update t set id = id + 1 order by id desc;", true},
 		{"explain select c1 from t1 union (select c2 from t2) limit 1, 1", true},
+		{"explain format = json select c1 from t1", true},
+		{"explain FORMAT = TRADITIONAL update t set id = id + 1", true},
+		{"explain format = tree select c1 from t1", false},
+		{"explain format select c1 from t1", false},
 	}
 	s.RunTest(c, table)
 }
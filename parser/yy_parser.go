@@ -21,6 +21,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/hack"
@@ -39,7 +40,7 @@ const (
 
 var (
 	specCodePattern = regexp.MustCompile(`\/\*!(M?[0-9]{5,6})?([^*]|\*+[^*/])*\*+\/`)
-	specCodeStart   = regexp.MustCompile(`^\/\*!(M?[0-9]{5,6} )?[ \t]*`)
+	specCodeStart   = regexp.MustCompile(`^\/\*!(M?[0-9]{5,6})?[ \t]*`)
 	specCodeEnd     = regexp.MustCompile(`[ \t]*\*\/$`)
 )
 
@@ -137,6 +138,53 @@ func (parser *Parser) endOffset(v *yySymType) int {
 	return offset
 }
 
+// parseOptimizerHints parses the table level optimizer hints in an optimizer hints comment,
+// like "USE_INDEX(t, idx1, idx2) IGNORE_INDEX(t1 idx)". The first name in the parentheses is the table,
+// and the others are the indexes. As MySQL does, a malformed hint and the hints after it are ignored.
+func parseOptimizerHints(text string) []*ast.TableOptimizerHint {
+	var hints []*ast.TableOptimizerHint
+	s := NewScanner(text)
+	for {
+		tok, _, lit := s.scan()
+		if tok == ',' {
+			continue
+		}
+		if tok != identifier {
+			return hints
+		}
+		hint := &ast.TableOptimizerHint{HintName: model.NewCIStr(lit)}
+		if tok, _, _ = s.scan(); tok != '(' {
+			return hints
+		}
+		names, ok := scanHintNames(s)
+		if !ok || len(names) == 0 {
+			return hints
+		}
+		hint.Table, hint.Indexes = names[0], names[1:]
+		hints = append(hints, hint)
+	}
+}
+
+// scanHintNames scans the names in the parentheses of a hint until the right parenthesis,
+// the names are separated by commas or spaces, like "t1 idx1, idx2".
+func scanHintNames(s *Scanner) (names []model.CIStr, ok bool) {
+	for {
+		tok, _, lit := s.scan()
+		switch tok {
+		case ',':
+			if len(names) == 0 {
+				return nil, false
+			}
+		case ')':
+			return names, true
+		case identifier, quotedIdentifier:
+			names = append(names, model.NewCIStr(lit))
+		default:
+			return nil, false
+		}
+	}
+}
+
 func toInt(l yyLexer, lval *yySymType, str string) int {
 	n, err := strconv.ParseUint(str, 0, 64)
 	if err != nil {
@@ -293,6 +293,7 @@ func (ps *perfSchema) appendEventsStmtsHistory(record []types.Datum) error {
 func (ps *perfSchema) registerStatements() {
 	ps.stmtInfos = make(map[reflect.Type]*statementInfo)
 	// Existing instrument names are the same as MySQL 5.7
+	ps.RegisterStatement("sql", "alter_db", (*ast.AlterDatabaseStmt)(nil))
 	ps.RegisterStatement("sql", "alter_table", (*ast.AlterTableStmt)(nil))
 	ps.RegisterStatement("sql", "begin", (*ast.BeginStmt)(nil))
 	ps.RegisterStatement("sql", "commit", (*ast.CommitStmt)(nil))
@@ -300,6 +301,7 @@ func (ps *perfSchema) registerStatements() {
 	ps.RegisterStatement("sql", "create_index", (*ast.CreateIndexStmt)(nil))
 	ps.RegisterStatement("sql", "create_table", (*ast.CreateTableStmt)(nil))
 	ps.RegisterStatement("sql", "create_user", (*ast.CreateUserStmt)(nil))
+	ps.RegisterStatement("sql", "create_view", (*ast.CreateViewStmt)(nil))
 	ps.RegisterStatement("sql", "deallocate", (*ast.DeallocateStmt)(nil))
 	ps.RegisterStatement("sql", "delete", (*ast.DeleteStmt)(nil))
 	ps.RegisterStatement("sql", "do", (*ast.DoStmt)(nil))
@@ -310,8 +312,11 @@ func (ps *perfSchema) registerStatements() {
 	ps.RegisterStatement("sql", "explain", (*ast.ExplainStmt)(nil))
 	ps.RegisterStatement("sql", "grant", (*ast.GrantStmt)(nil))
 	ps.RegisterStatement("sql", "insert", (*ast.InsertStmt)(nil))
+	ps.RegisterStatement("sql", "kill", (*ast.KillStmt)(nil))
 	ps.RegisterStatement("sql", "prepare", (*ast.PrepareStmt)(nil))
+	ps.RegisterStatement("sql", "release_savepoint", (*ast.ReleaseSavepointStmt)(nil))
 	ps.RegisterStatement("sql", "rollback", (*ast.RollbackStmt)(nil))
+	ps.RegisterStatement("sql", "savepoint", (*ast.SavepointStmt)(nil))
 	ps.RegisterStatement("sql", "select", (*ast.SelectStmt)(nil))
 	ps.RegisterStatement("sql", "set", (*ast.SetStmt)(nil))
 	ps.RegisterStatement("sql", "set_password", (*ast.SetPwdStmt)(nil))
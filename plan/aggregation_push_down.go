@@ -187,6 +187,17 @@ func (a *aggPushDownSolver) decompose(aggFunc expression.AggregationFunction, sc
 	return result, schema
 }
 
+// allDecomposable checks if all the aggregate functions can be calculated from the partial results of
+// every union child, e.g. var_pop and group_concat with ORDER BY need all the rows at once.
+func (a *aggPushDownSolver) allDecomposable(aggFuncs []expression.AggregationFunction) bool {
+	for _, fun := range aggFuncs {
+		if !a.isDecomposable(fun) {
+			return false
+		}
+	}
+	return true
+}
+
 func (a *aggPushDownSolver) allFirstRow(aggFuncs []expression.AggregationFunction) bool {
 	for _, fun := range aggFuncs {
 		if fun.GetName() != ast.AggFuncFirstRow {
@@ -300,7 +311,8 @@ func (a *aggPushDownSolver) pushAggCrossUnion(agg *Aggregation, unionSchema expr
 func (a *aggPushDownSolver) aggPushDown(p LogicalPlan) {
 	if agg, ok := p.(*Aggregation); ok {
 		child := agg.GetChildByIndex(0)
-		if join, ok1 := child.(*Join); ok1 && a.checkValidJoin(join) {
+		// The aggregation with rollup is never split, it must see all the rows of every group.
+		if join, ok1 := child.(*Join); ok1 && !agg.WithRollup && a.checkValidJoin(join) {
 			if valid, leftAggFuncs, rightAggFuncs, leftGbyCols, rightGbyCols := a.splitAggFuncsAndGbyCols(agg, join); valid {
 				var lChild, rChild LogicalPlan
 				// If there exist count or sum functions in left join path, we can't push any
@@ -339,7 +351,7 @@ func (a *aggPushDownSolver) aggPushDown(p LogicalPlan) {
 			projChild := proj.children[0]
 			agg.SetChildren(projChild)
 			projChild.SetParents(agg)
-		} else if union, ok1 := child.(*Union); ok1 {
+		} else if union, ok1 := child.(*Union); ok1 && !agg.WithRollup && a.allDecomposable(agg.AggFuncs) {
 			pushedAgg := a.makeNewAgg(agg.AggFuncs, agg.groupByCols)
 			newChildren := make([]Plan, 0, len(union.children))
 			for _, child := range union.children {
@@ -109,6 +109,36 @@ func (p *Sort) PruneColumns(parentUsedCols []*expression.Column) {
 	p.SetSchema(p.GetChildByIndex(0).GetSchema())
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *Window) PruneColumns(parentUsedCols []*expression.Column) {
+	child := p.GetChildByIndex(0).(LogicalPlan)
+	windowCol := p.schema.Columns[p.schema.Len()-1]
+	var usedCols []*expression.Column
+	for _, col := range parentUsedCols {
+		if !col.Equal(windowCol, p.ctx) {
+			usedCols = append(usedCols, col)
+		}
+	}
+	for _, expr := range p.PartitionBy {
+		usedCols = append(usedCols, expression.ExtractColumns(expr)...)
+	}
+	for _, item := range p.OrderBy {
+		usedCols = append(usedCols, expression.ExtractColumns(item.Expr)...)
+	}
+	child.PruneColumns(usedCols)
+	schema := child.GetSchema().Clone()
+	schema.Append(windowCol)
+	p.SetSchema(schema)
+}
+
+// PruneColumns implements LogicalPlan interface.
+// All the columns are used to check duplication, so none of them can be pruned.
+func (p *Distinct) PruneColumns(_ []*expression.Column) {
+	child := p.GetChildByIndex(0).(LogicalPlan)
+	child.PruneColumns(p.schema.Columns)
+	p.SetSchema(child.GetSchema())
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *Union) PruneColumns(parentUsedCols []*expression.Column) {
 	used := getUsedList(parentUsedCols, p.GetSchema())
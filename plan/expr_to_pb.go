@@ -16,17 +16,19 @@ package plan
 import (
 	"github.com/ngaut/log"
 	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/types"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
-func expressionsToPB(sc *variable.StatementContext, exprs []expression.Expression, client kv.Client) (pbExpr *tipb.Expr, pushed []expression.Expression, remained []expression.Expression) {
-	pc := pbConverter{client: client, sc: sc}
+func expressionsToPB(ctx context.Context, exprs []expression.Expression, client kv.Client) (pbExpr *tipb.Expr, pushed []expression.Expression, remained []expression.Expression) {
+	pc := newPBConverter(ctx, client)
 	for _, expr := range exprs {
 		v := pc.exprToPB(expr)
 		if v == nil {
@@ -49,6 +51,17 @@ func expressionsToPB(sc *variable.StatementContext, exprs []expression.Expressio
 type pbConverter struct {
 	client kv.Client
 	sc     *variable.StatementContext
+	// divPrecIncr is the div_precision_increment of the session, the coprocessor always uses types.DivFracIncr.
+	divPrecIncr int
+	// ciConnection is true if the collation of the connection compares strings case-insensitively.
+	ciConnection bool
+}
+
+func newPBConverter(ctx context.Context, client kv.Client) pbConverter {
+	vars := ctx.GetSessionVars()
+	_, collation := vars.GetCharsetInfo()
+	return pbConverter{client: client, sc: vars.StmtCtx, divPrecIncr: vars.DivPrecisionIncrement,
+		ciConnection: charset.IsCaseInsensitive(collation)}
 }
 
 func (pc pbConverter) exprToPB(expr expression.Expression) *tipb.Expr {
@@ -141,6 +154,11 @@ func (pc pbConverter) scalarFuncToPBExpr(expr *expression.ScalarFunction) *tipb.
 }
 
 func (pc pbConverter) compareOpsToPBExpr(expr *expression.ScalarFunction) *tipb.Expr {
+	// The strings are compared as binary strings in the storage layer, only LIKE is case-insensitive there.
+	if expr.FuncName.L != ast.Like && (expression.CaseInsensitiveCompare(expr.Args) ||
+		pc.ciConnection && expression.CompareWithConnectionCollation(expr.Args)) {
+		return nil
+	}
 	var tp tipb.ExprType
 	switch expr.FuncName.L {
 	case ast.LT:
@@ -211,6 +229,10 @@ func (pc pbConverter) arithmeticalOpsToPBExpr(expr *expression.ScalarFunction) *
 	case ast.Mul:
 		tp = tipb.ExprType_Mul
 	case ast.Div:
+		// The result of the coprocessor would have a different scale.
+		if pc.divPrecIncr != types.DivFracIncr {
+			return nil
+		}
 		tp = tipb.ExprType_Div
 	case ast.Mod:
 		tp = tipb.ExprType_Mod
@@ -317,8 +339,8 @@ func (pc pbConverter) datumsToValueList(datums []types.Datum) *tipb.Expr {
 	return &tipb.Expr{Tp: tipb.ExprType_ValueList, Val: val}
 }
 
-func groupByItemToPB(sc *variable.StatementContext, client kv.Client, expr expression.Expression) *tipb.ByItem {
-	pc := pbConverter{client: client, sc: sc}
+func groupByItemToPB(ctx context.Context, client kv.Client, expr expression.Expression) *tipb.ByItem {
+	pc := newPBConverter(ctx, client)
 	e := pc.exprToPB(expr)
 	if e == nil {
 		return nil
@@ -326,8 +348,8 @@ func groupByItemToPB(sc *variable.StatementContext, client kv.Client, expr expre
 	return &tipb.ByItem{Expr: e}
 }
 
-func sortByItemToPB(sc *variable.StatementContext, client kv.Client, expr expression.Expression, desc bool) *tipb.ByItem {
-	pc := pbConverter{client: client, sc: sc}
+func sortByItemToPB(ctx context.Context, client kv.Client, expr expression.Expression, desc bool) *tipb.ByItem {
+	pc := newPBConverter(ctx, client)
 	e := pc.exprToPB(expr)
 	if e == nil {
 		return nil
@@ -335,8 +357,8 @@ func sortByItemToPB(sc *variable.StatementContext, client kv.Client, expr expres
 	return &tipb.ByItem{Expr: e, Desc: desc}
 }
 
-func aggFuncToPBExpr(sc *variable.StatementContext, client kv.Client, aggFunc expression.AggregationFunction) *tipb.Expr {
-	pc := pbConverter{client: client, sc: sc}
+func aggFuncToPBExpr(ctx context.Context, client kv.Client, aggFunc expression.AggregationFunction) *tipb.Expr {
+	pc := newPBConverter(ctx, client)
 	var tp tipb.ExprType
 	switch aggFunc.GetName() {
 	case ast.AggFuncCount:
@@ -353,6 +375,9 @@ func aggFuncToPBExpr(sc *variable.StatementContext, client kv.Client, aggFunc ex
 		tp = tipb.ExprType_Sum
 	case ast.AggFuncAvg:
 		tp = tipb.ExprType_Avg
+	default:
+		// The coprocessor has no variance functions.
+		return nil
 	}
 	if !client.SupportRequestType(kv.ReqTypeSelect, int64(tp)) {
 		return nil
@@ -14,6 +14,7 @@
 package plan
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
@@ -166,6 +167,16 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 			er.ctxStack = append(er.ctxStack, er.schema.Columns[index])
 			return inNode, true
 		}
+	case *ast.WindowFuncExpr:
+		// The window functions are computed by the windows built under the projection,
+		// they can't appear anywhere else.
+		index, ok := er.b.windowMapper[v]
+		if !ok {
+			er.err = ErrWindowInvalidWindowFuncUse.GenByArgs(v.F)
+			return inNode, true
+		}
+		er.ctxStack = append(er.ctxStack, er.schema.Columns[index])
+		return inNode, true
 	case *ast.CompareSubqueryExpr:
 		return er.handleCompareSubquery(v)
 	case *ast.ExistsSubqueryExpr:
@@ -279,23 +290,42 @@ func (er *expressionRewriter) handleExistSubquery(v *ast.ExistsSubqueryExpr) (as
 	}
 	np = er.b.buildExists(np)
 	if np.IsCorrelated() {
-		if sel, ok := np.GetChildByIndex(0).(*Selection); ok && !sel.GetChildByIndex(0).IsCorrelated() {
+		sel, ok := np.GetChildByIndex(0).(*Selection)
+		// The anti semi join follows the null semantics of "not in", so "not exists" is always evaluated by
+		// apply, which stops reading the subquery as soon as it returns a row.
+		if ok && !v.Not && !sel.GetChildByIndex(0).IsCorrelated() {
 			er.p = er.b.buildSemiJoin(er.p, sel.GetChildByIndex(0).(LogicalPlan), sel.Conditions, er.asScalar, false)
 			if !er.asScalar {
 				return v, true
 			}
-		} else {
-			// Can't be built as semi-join.
-			er.p = er.b.buildApply(er.p, np, nil)
+			er.ctxStack = append(er.ctxStack, er.p.GetSchema().Columns[er.p.GetSchema().Len()-1])
+			return v, true
+		}
+		// Can't be built as semi-join.
+		er.p = er.b.buildApply(er.p, np, nil)
+		var existsExpr expression.Expression = er.p.GetSchema().Columns[er.p.GetSchema().Len()-1]
+		if v.Not {
+			existsExpr, er.err = expression.NewFunction(ast.UnaryNot, types.NewFieldType(mysql.TypeTiny), existsExpr)
+			if er.err != nil {
+				er.err = errors.Trace(er.err)
+				return v, true
+			}
 		}
-		er.ctxStack = append(er.ctxStack, er.p.GetSchema().Columns[er.p.GetSchema().Len()-1])
+		er.ctxStack = append(er.ctxStack, existsExpr)
 	} else {
 		physicalPlan, err := doOptimize(np, er.b.ctx, er.b.allocator)
+		if err != nil {
+			er.err = errors.Trace(err)
+			return v, true
+		}
 		d, err := EvalSubquery(physicalPlan, er.b.is, er.b.ctx)
 		if err != nil {
 			er.err = errors.Trace(err)
 			return v, true
 		}
+		if v.Not {
+			d[0].SetInt64(1 - d[0].GetInt64())
+		}
 		er.ctxStack = append(er.ctxStack, &expression.Constant{
 			Value:   d[0],
 			RetType: np.GetSchema().Columns[0].GetType()})
@@ -348,6 +378,13 @@ func (er *expressionRewriter) handleInSubquery(v *ast.PatternInExpr) (ast.Node,
 	}
 	if !np.IsCorrelated() {
 		er.p = er.b.buildSemiJoin(er.p, np, expression.SplitCNFItems(checkCondition), asScalar, v.Not)
+		if asScalar || v.Not {
+			// For "1 in (select a from t)", "1 = a" is null for the null rows of t, which makes the result null
+			// rather than false. So the condition is checked by the join instead of being pushed down to t.
+			join := er.p.(*Join)
+			join.OtherConditions = append(join.OtherConditions, join.RightConditions...)
+			join.RightConditions = nil
+		}
 		if asScalar {
 			col := er.p.GetSchema().Columns[er.p.GetSchema().Len()-1]
 			er.ctxStack[len(er.ctxStack)-1] = col
@@ -430,7 +467,7 @@ func (er *expressionRewriter) Leave(inNode ast.Node) (retNode ast.Node, ok bool)
 
 	switch v := inNode.(type) {
 	case *ast.AggregateFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.WhenClause,
-		*ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr, *ast.ValuesExpr:
+		*ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr, *ast.ValuesExpr, *ast.WindowFuncExpr:
 	case *ast.ValueExpr:
 		value := &expression.Constant{Value: v.Datum, RetType: &v.Type}
 		er.ctxStack = append(er.ctxStack, value)
@@ -495,20 +532,17 @@ func (er *expressionRewriter) rewriteVariable(v *ast.VariableExpr) {
 				er.ctxStack[stkLen-1])
 			return
 		}
-		if _, ok := sessionVars.Users[name]; ok {
-			f, err := expression.NewFunction(ast.GetVar,
-				// TODO: Here is wrong, the sessionVars should store a name -> Datum map. Will fix it later.
-				types.NewFieldType(mysql.TypeString),
-				datumToConstant(types.NewStringDatum(name), mysql.TypeString))
-			if err != nil {
-				er.err = errors.Trace(err)
-				return
-			}
-			er.ctxStack = append(er.ctxStack, f)
-		} else {
-			// select null user vars is permitted.
-			er.ctxStack = append(er.ctxStack, &expression.Constant{RetType: types.NewFieldType(mysql.TypeNull)})
+		// The user variable is resolved at evaluation time, so that it can see the value
+		// assigned earlier in the same statement. An unset user variable is NULL.
+		f, err := expression.NewFunction(ast.GetVar,
+			// TODO: Here is wrong, the sessionVars should store a name -> Datum map. Will fix it later.
+			types.NewFieldType(mysql.TypeString),
+			datumToConstant(types.NewStringDatum(name), mysql.TypeString))
+		if err != nil {
+			er.err = errors.Trace(err)
+			return
 		}
+		er.ctxStack = append(er.ctxStack, f)
 		return
 	}
 
@@ -646,7 +680,7 @@ func (er *expressionRewriter) positionToScalarFunc(v *ast.PositionExpr) {
 	if v.N > 0 && v.N <= er.schema.Len() {
 		er.ctxStack = append(er.ctxStack, er.schema.Columns[v.N-1])
 	} else {
-		er.err = errors.Errorf("Position %d is out of range", v.N)
+		er.err = ErrUnknownColumn.GenByArgs(strconv.Itoa(v.N), "order clause")
 	}
 }
 
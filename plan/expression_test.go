@@ -68,6 +68,15 @@ func (s *testExpressionSuite) TestBetween(c *C) {
 	cases := []testCase{
 		{exprStr: "1 between 2 and 3", resultStr: "0"},
 		{exprStr: "1 not between 2 and 3", resultStr: "1"},
+		{exprStr: "3 between 3 and 3", resultStr: "1"},
+		{exprStr: "3 between 4 and 2", resultStr: "0"},
+		{exprStr: "2 between 1 and null", resultStr: "<nil>"},
+		{exprStr: "0 between 1 and null", resultStr: "0"},
+		{exprStr: "2 not between 1 and null", resultStr: "<nil>"},
+		{exprStr: "null between 1 and 2", resultStr: "<nil>"},
+		{exprStr: "5 between '1' and '10'", resultStr: "1"},
+		{exprStr: "'b' between 'a' and 'c'", resultStr: "1"},
+		{exprStr: "'10' between '9' and '11'", resultStr: "0"},
 	}
 	s.runTests(c, cases)
 }
@@ -144,6 +153,29 @@ func (s *testExpressionSuite) TestCast(c *C) {
 	v, err = evalAstExpr(expr, s.ctx)
 	c.Assert(err, IsNil)
 	c.Assert(v.Kind(), Equals, types.KindNull)
+
+	// The integer value is reinterpreted when it overflows.
+	f.Tp = mysql.TypeLonglong
+	expr.Expr = ast.NewValueExpr(-1)
+	v, err = evalAstExpr(expr, s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(v, testutil.DatumEquals, types.NewDatum(uint64(18446744073709551615)))
+
+	// Invalid values are cast with a warning.
+	sc := s.ctx.GetSessionVars().StmtCtx
+	sc.SetWarnings(nil)
+	f.Flag = 0
+	expr.Expr = ast.NewValueExpr("abc")
+	v, err = evalAstExpr(expr, s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(v, testutil.DatumEquals, types.NewDatum(int64(0)))
+	c.Assert(sc.GetWarnings(), HasLen, 1)
+
+	f.Tp = mysql.TypeDate
+	v, err = evalAstExpr(expr, s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(v.Kind(), Equals, types.KindNull)
+	c.Assert(sc.GetWarnings(), HasLen, 2)
 }
 
 func (s *testExpressionSuite) TestPatternIn(c *C) {
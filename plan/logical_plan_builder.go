@@ -15,13 +15,20 @@ package plan
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
+	"github.com/ngaut/log"
 	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/plan/statistics"
+	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -43,9 +50,32 @@ func (p *Aggregation) collectGroupByColumns() {
 	}
 }
 
-func (b *planBuilder) buildAggregation(p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr, gbyItems []expression.Expression) (LogicalPlan, map[int]int) {
+// buildGroupConcat builds the group_concat function, the items in its ORDER BY clause are rewritten
+// against the child plan just like the arguments.
+func (b *planBuilder) buildGroupConcat(p LogicalPlan, aggFunc *ast.AggregateFuncExpr, args []expression.Expression) (expression.AggregationFunction, LogicalPlan) {
+	var (
+		byItems []expression.Expression
+		descs   []bool
+	)
+	if aggFunc.Order != nil {
+		for _, item := range aggFunc.Order.Items {
+			newExpr, np, err := b.rewrite(item.Expr, p, nil, true)
+			if err != nil {
+				b.err = errors.Trace(err)
+				return nil, nil
+			}
+			p = np
+			byItems = append(byItems, newExpr)
+			descs = append(descs, item.Desc)
+		}
+	}
+	return expression.NewGroupConcatFunction(args, aggFunc.Distinct, aggFunc.Separator, byItems, descs), p
+}
+
+func (b *planBuilder) buildAggregation(p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr, gbyItems []expression.Expression, rollup bool) (LogicalPlan, map[int]int) {
 	agg := &Aggregation{
 		AggFuncs:        make([]expression.AggregationFunction, 0, len(aggFuncList)),
+		WithRollup:      rollup,
 		baseLogicalPlan: newBaseLogicalPlan(Agg, b.allocator)}
 	agg.self = agg
 	agg.initIDAndContext(b.ctx)
@@ -64,7 +94,15 @@ func (b *planBuilder) buildAggregation(p LogicalPlan, aggFuncList []*ast.Aggrega
 			p = np
 			newArgList = append(newArgList, newArg)
 		}
-		newFunc := expression.NewAggFunction(aggFunc.F, newArgList, aggFunc.Distinct)
+		var newFunc expression.AggregationFunction
+		if strings.ToLower(aggFunc.F) == ast.AggFuncGroupConcat {
+			newFunc, p = b.buildGroupConcat(p, aggFunc, newArgList)
+			if b.err != nil {
+				return nil, nil
+			}
+		} else {
+			newFunc = expression.NewAggFunction(aggFunc.F, newArgList, aggFunc.Distinct)
+		}
 		combined := false
 		for j, oldFunc := range agg.AggFuncs {
 			if oldFunc.Equal(newFunc, b.ctx) {
@@ -77,12 +115,19 @@ func (b *planBuilder) buildAggregation(p LogicalPlan, aggFuncList []*ast.Aggrega
 			position := len(agg.AggFuncs)
 			aggIndexMap[i] = position
 			agg.AggFuncs = append(agg.AggFuncs, newFunc)
+			retType := aggFunc.GetType()
+			if rollup && mysql.HasNotNullFlag(retType.Flag) {
+				// The group-by columns are NULL in the super-aggregate rows.
+				tp := *retType
+				tp.Flag &^= mysql.NotNullFlag
+				retType = &tp
+			}
 			schema.Append(&expression.Column{
 				FromID:      agg.id,
 				ColName:     model.NewCIStr(fmt.Sprintf("%s_col_%d", agg.id, position)),
 				Position:    position,
 				IsAggOrSubq: true,
-				RetType:     aggFunc.GetType()})
+				RetType:     retType})
 		}
 	}
 	agg.GroupByItems = gbyItems
@@ -104,7 +149,11 @@ func (b *planBuilder) buildResultSetNode(node ast.ResultSetNode) LogicalPlan {
 		case *ast.UnionStmt:
 			p = b.buildUnion(v)
 		case *ast.TableName:
-			p = b.buildDataSource(v)
+			if v.TableInfo.IsView() {
+				p = b.buildView(v)
+			} else {
+				p = b.buildDataSource(v)
+			}
 		default:
 			b.err = ErrUnsupportedType.Gen("unsupported table source type %T", v)
 			return nil
@@ -114,6 +163,17 @@ func (b *planBuilder) buildResultSetNode(node ast.ResultSetNode) LogicalPlan {
 		}
 		if v, ok := p.(*DataSource); ok {
 			v.TableAsName = &x.AsName
+			tblName := x.AsName
+			if tblName.L == "" {
+				tblName = v.tableInfo.Name
+			}
+			if hints := b.indexHintsFromTableHints(tblName); len(hints) > 0 {
+				// The indexes in the optimizer hints are checked like the ones in the index hints.
+				if b.err = checkIndexHints(hints, v.tableInfo); b.err != nil {
+					return nil
+				}
+				v.indexHints = append(hints, v.indexHints...)
+			}
 		}
 		if x.AsName.L != "" {
 			schema := p.GetSchema()
@@ -150,7 +210,8 @@ func extractOnCondition(conditions []expression.Expression, left LogicalPlan, ri
 	otherCond []expression.Expression) {
 	for _, expr := range conditions {
 		binop, ok := expr.(*expression.ScalarFunction)
-		if ok && binop.FuncName.L == ast.EQ {
+		// The join keys are compared as binary strings, so a case-insensitive equal condition is an other condition.
+		if ok && binop.FuncName.L == ast.EQ && !expression.CaseInsensitiveCompare(binop.Args) {
 			ln, lOK := binop.Args[0].(*expression.Column)
 			rn, rOK := binop.Args[1].(*expression.Column)
 			if lOK && rOK {
@@ -212,6 +273,11 @@ func (b *planBuilder) buildJoin(join *ast.Join) LogicalPlan {
 		joinPlan.LeftConditions = leftCond
 		joinPlan.RightConditions = rightCond
 		joinPlan.OtherConditions = otherCond
+	} else if join.Using != nil {
+		b.buildUsingClause(joinPlan, leftPlan, rightPlan, join.Using)
+		if b.err != nil {
+			return nil
+		}
 	} else if joinPlan.JoinType == InnerJoin {
 		joinPlan.cartesianJoin = true
 	}
@@ -227,9 +293,95 @@ func (b *planBuilder) buildJoin(join *ast.Join) LogicalPlan {
 	addChild(joinPlan, leftPlan)
 	addChild(joinPlan, rightPlan)
 	joinPlan.SetCorrelated()
+	if join.Using != nil {
+		outerPlan, innerPlan := leftPlan, rightPlan
+		if join.Tp == ast.RightJoin {
+			outerPlan, innerPlan = rightPlan, leftPlan
+		}
+		return b.buildUsingProjection(joinPlan, outerPlan, innerPlan, joinPlan.EqualConditions)
+	}
 	return joinPlan
 }
 
+// buildUsingClause sets the equal conditions of the join for the columns in the using clause.
+func (b *planBuilder) buildUsingClause(p *Join, leftPlan, rightPlan LogicalPlan, using []*ast.ColumnName) {
+	conditions := make([]expression.Expression, 0, len(using))
+	for _, col := range using {
+		name := &ast.ColumnName{Name: col.Name}
+		lCol, err := leftPlan.GetSchema().FindColumn(name)
+		if err != nil {
+			b.err = errors.Trace(err)
+			return
+		}
+		rCol, err := rightPlan.GetSchema().FindColumn(name)
+		if err != nil {
+			b.err = errors.Trace(err)
+			return
+		}
+		if lCol == nil || rCol == nil {
+			b.err = ErrUnknownColumn.GenByArgs(col.Name.O, "from clause")
+			return
+		}
+		cond, err := expression.NewFunction(ast.EQ, types.NewFieldType(mysql.TypeTiny), lCol.Clone(), rCol.Clone())
+		if err != nil {
+			b.err = errors.Trace(err)
+			return
+		}
+		conditions = append(conditions, cond)
+	}
+	p.EqualConditions, _, _, _ = extractOnCondition(conditions, leftPlan, rightPlan)
+}
+
+// buildUsingProjection builds a projection on the join to coalesce the using columns like MySQL does.
+// The coalesced using columns come first, they don't belong to any table, followed by all the columns of the outer plan and the inner plan.
+// The using columns of both sides are kept as hidden columns, so they can still be referenced by qualified names like t2.a.
+func (b *planBuilder) buildUsingProjection(p LogicalPlan, outerPlan, innerPlan LogicalPlan, eqConds []*expression.ScalarFunction) LogicalPlan {
+	usingCols := make([]*expression.Column, 0, len(eqConds))
+	hidden := make(map[*expression.Column]bool, 2*len(eqConds))
+	for _, cond := range eqConds {
+		for _, arg := range cond.Args {
+			col := arg.(*expression.Column)
+			if c := outerPlan.GetSchema().RetrieveColumn(col); c != nil {
+				usingCols = append(usingCols, c)
+				hidden[c] = true
+			} else if c := innerPlan.GetSchema().RetrieveColumn(col); c != nil {
+				hidden[c] = true
+			}
+		}
+	}
+	proj := &Projection{
+		Exprs:           make([]expression.Expression, 0, len(usingCols)+p.GetSchema().Len()),
+		baseLogicalPlan: newBaseLogicalPlan(Proj, b.allocator),
+	}
+	proj.self = proj
+	proj.initIDAndContext(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(usingCols)+p.GetSchema().Len()))
+	appendColumn := func(col *expression.Column, newCol *expression.Column) {
+		proj.Exprs = append(proj.Exprs, col.Clone())
+		newCol.FromID = proj.id
+		newCol.ColName = col.ColName
+		newCol.RetType = col.RetType
+		schema.Append(newCol)
+		newCol.Position = schema.Len()
+	}
+	for _, col := range usingCols {
+		appendColumn(col, &expression.Column{})
+	}
+	for _, plan := range []LogicalPlan{outerPlan, innerPlan} {
+		for _, col := range plan.GetSchema().Columns {
+			appendColumn(col, &expression.Column{
+				DBName:  col.DBName,
+				TblName: col.TblName,
+				Hidden:  col.Hidden || hidden[col],
+			})
+		}
+	}
+	proj.SetSchema(schema)
+	addChild(proj, p)
+	proj.SetCorrelated()
+	return proj
+}
+
 func (b *planBuilder) buildSelection(p LogicalPlan, where ast.ExprNode, AggMapper map[*ast.AggregateFuncExpr]int) LogicalPlan {
 	conditions := splitWhere(where)
 	expressions := make([]expression.Expression, 0, len(conditions))
@@ -280,12 +432,12 @@ func (b *planBuilder) buildProjection(p LogicalPlan, fields []*ast.SelectField,
 		if field.AsName.L != "" {
 			colName = field.AsName
 		} else if c, ok := newExpr.(*expression.Column); ok && !c.IsAggOrSubq {
+			// The column keeps the table it comes from, so it can be returned to the client.
+			tblName = c.TblName
 			if astCol, ok := getInnerFromParentheses(field.Expr).(*ast.ColumnNameExpr); ok {
 				colName = astCol.Name.Name
-				tblName = astCol.Name.Table
 			} else {
 				colName = c.ColName
-				tblName = c.TblName
 			}
 		} else {
 			// When the query is select t.a from t group by a; The Column Name should be a but not t.a;
@@ -330,6 +482,34 @@ func (b *planBuilder) buildDistinct(src LogicalPlan) LogicalPlan {
 	return d
 }
 
+// buildDistinctWithAuxFields removes the duplicated rows of the first distinctLen columns, the auxiliary
+// fields after them (e.g. the order by items not in the select list) take the values of the first row.
+// It is built as an aggregation grouped by the distinct columns.
+func (b *planBuilder) buildDistinctWithAuxFields(src LogicalPlan, distinctLen int) LogicalPlan {
+	agg := &Aggregation{
+		AggFuncs:        make([]expression.AggregationFunction, 0, src.GetSchema().Len()),
+		GroupByItems:    make([]expression.Expression, 0, distinctLen),
+		baseLogicalPlan: newBaseLogicalPlan(Agg, b.allocator)}
+	agg.self = agg
+	agg.initIDAndContext(b.ctx)
+	addChild(agg, src)
+	schema := expression.NewSchema(make([]*expression.Column, 0, src.GetSchema().Len()))
+	for i, col := range src.GetSchema().Columns {
+		if i < distinctLen {
+			agg.GroupByItems = append(agg.GroupByItems, col.Clone())
+		}
+		agg.AggFuncs = append(agg.AggFuncs, expression.NewAggFunction(ast.AggFuncFirstRow, []expression.Expression{col.Clone()}, false))
+		newCol := col.Clone().(*expression.Column)
+		newCol.FromID = agg.id
+		newCol.Position = i
+		schema.Append(newCol)
+	}
+	agg.SetSchema(schema)
+	agg.collectGroupByColumns()
+	agg.SetCorrelated()
+	return agg
+}
+
 func (b *planBuilder) buildUnion(union *ast.UnionStmt) LogicalPlan {
 	u := &Union{baseLogicalPlan: newBaseLogicalPlan(Un, b.allocator)}
 	u.self = u
@@ -337,11 +517,19 @@ func (b *planBuilder) buildUnion(union *ast.UnionStmt) LogicalPlan {
 	u.children = make([]Plan, len(union.SelectList.Selects))
 	for i, sel := range union.SelectList.Selects {
 		u.children[i] = b.buildSelect(sel)
+		if b.err != nil {
+			return nil
+		}
 	}
 	firstSchema := u.children[0].GetSchema().Clone()
+	// The result types are unified below, so they must not be shared with the first child.
+	for _, col := range firstSchema.Columns {
+		retType := *col.RetType
+		col.RetType = &retType
+	}
 	for _, sel := range u.children {
 		if firstSchema.Len() != sel.GetSchema().Len() {
-			b.err = errors.New("The used SELECT statements have a different number of columns")
+			b.err = ErrWrongNumberOfColumnsInSelect.GenByArgs()
 			return nil
 		}
 		for i, col := range sel.GetSchema().Columns {
@@ -355,14 +543,7 @@ func (b *planBuilder) buildUnion(union *ast.UnionStmt) LogicalPlan {
 			 * | bbbbbbbbbb    |
 			 * +---------------+
 			 */
-			if col.RetType.Flen > firstSchema.Columns[i].RetType.Flen {
-				firstSchema.Columns[i].RetType.Flen = col.RetType.Flen
-			}
-			// For select nul union select "abc", we should not convert "abc" to nil.
-			// And the result field type should be VARCHAR.
-			if firstSchema.Columns[i].RetType.Tp == 0 || firstSchema.Columns[i].RetType.Tp == mysql.TypeNull {
-				firstSchema.Columns[i].RetType.Tp = col.RetType.Tp
-			}
+			unionFieldType(firstSchema.Columns[i].RetType, col.RetType)
 		}
 		sel.SetParents(u)
 	}
@@ -387,6 +568,103 @@ func (b *planBuilder) buildUnion(union *ast.UnionStmt) LogicalPlan {
 	return p
 }
 
+// unionFieldType widens the union result type ft so that it can also hold the values of type other.
+func unionFieldType(ft, other *types.FieldType) {
+	// For select null union select "abc", we should not convert "abc" to nil.
+	// And the result field type should be VARCHAR.
+	if ft.Tp == mysql.TypeUnspecified || ft.Tp == mysql.TypeNull {
+		ft.Tp = other.Tp
+		ft.Flen, ft.Decimal, ft.Flag = other.Flen, other.Decimal, other.Flag
+		return
+	}
+	if other.Tp == mysql.TypeUnspecified || other.Tp == mysql.TypeNull {
+		return
+	}
+	if ft.Tp != other.Tp {
+		tp := unionTp(ft.Tp, other.Tp)
+		if isStringType(tp) && !isStringType(ft.Tp) {
+			// The values are converted to strings, so the binary charset of the non-string type can't be kept.
+			if isStringType(other.Tp) {
+				ft.Charset, ft.Collate = other.Charset, other.Collate
+			} else {
+				ft.Charset, ft.Collate = charset.CharsetUTF8, charset.CollationUTF8
+			}
+		}
+		ft.Tp = tp
+	}
+	if !mysql.HasUnsignedFlag(other.Flag) {
+		ft.Flag &^= mysql.UnsignedFlag
+	}
+	if ft.Flen == types.UnspecifiedLength || other.Flen == types.UnspecifiedLength {
+		ft.Flen = types.UnspecifiedLength
+	} else if ft.Decimal > 0 || other.Decimal > 0 {
+		// Keep enough digits on both sides of the decimal point.
+		decimal := ft.Decimal
+		if other.Decimal > decimal {
+			decimal = other.Decimal
+		}
+		flen := ft.Flen - ft.Decimal
+		if other.Flen-other.Decimal > flen {
+			flen = other.Flen - other.Decimal
+		}
+		ft.Flen, ft.Decimal = flen+decimal, decimal
+	} else if other.Flen > ft.Flen {
+		ft.Flen = other.Flen
+	}
+	if ft.Decimal == types.UnspecifiedLength || other.Decimal == types.UnspecifiedLength {
+		ft.Decimal = types.UnspecifiedLength
+	}
+}
+
+// unionTp returns the column type of a union whose selects produce the different types a and b.
+func unionTp(a, b byte) byte {
+	switch {
+	case isIntegerType(a) && isIntegerType(b):
+		return mysql.TypeLonglong
+	case isNumericType(a) && isNumericType(b):
+		if a == mysql.TypeFloat || a == mysql.TypeDouble || b == mysql.TypeFloat || b == mysql.TypeDouble {
+			return mysql.TypeDouble
+		}
+		return mysql.TypeNewDecimal
+	case isTemporalType(a) && isTemporalType(b):
+		if a == mysql.TypeDuration || b == mysql.TypeDuration {
+			return mysql.TypeVarchar
+		}
+		return mysql.TypeDatetime
+	case types.IsTypeBlob(a) || types.IsTypeBlob(b):
+		return mysql.TypeBlob
+	}
+	return mysql.TypeVarchar
+}
+
+func isIntegerType(tp byte) bool {
+	switch tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear, mysql.TypeBit:
+		return true
+	}
+	return false
+}
+
+func isNumericType(tp byte) bool {
+	switch tp {
+	case mysql.TypeNewDecimal, mysql.TypeFloat, mysql.TypeDouble:
+		return true
+	}
+	return isIntegerType(tp)
+}
+
+func isStringType(tp byte) bool {
+	return tp == mysql.TypeVarString || types.IsTypeChar(tp) || types.IsTypeBlob(tp)
+}
+
+func isTemporalType(tp byte) bool {
+	switch tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeNewDate, mysql.TypeDuration:
+		return true
+	}
+	return false
+}
+
 // ByItems wraps a "by" item.
 type ByItems struct {
 	Expr expression.Expression
@@ -476,7 +754,7 @@ func resolveFromSelectFields(v *ast.ColumnNameExpr, fields []*ast.SelectField, i
 				index = i
 			} else if !colMatch(matchedExpr.(*ast.ColumnNameExpr).Name, curCol.Name) &&
 				!colMatch(curCol.Name, matchedExpr.(*ast.ColumnNameExpr).Name) {
-				return -1, errors.Errorf("Column '%s' in field list is ambiguous", curCol.Name.Name.L)
+				return -1, ErrAmbiguous.GenByArgs(curCol.Name.Name.L, "field list")
 			}
 		}
 	}
@@ -508,6 +786,9 @@ func (a *havingAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChi
 		// Enter a new context, skip it.
 		// For example: select sum(c) + c + exists(select c from t) from t;
 		return n, true
+	case *ast.WindowFuncExpr:
+		// The window is resolved when the window function is built.
+		return n, true
 	default:
 		a.inExpr = true
 	}
@@ -552,6 +833,22 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			Expr:      v,
 			AsName:    model.NewCIStr(fmt.Sprintf("sel_agg_%d", len(a.selectFields))),
 		})
+	case *ast.WindowFuncExpr:
+		if !a.orderBy || a.inAggFunc {
+			a.err = ErrWindowInvalidWindowFuncUse.GenByArgs(v.F)
+			return node, false
+		}
+		// The window function is computed by an auxiliary field, the order by item is replaced by a column referring to it.
+		name := model.NewCIStr(fmt.Sprintf("sel_window_%d", len(a.selectFields)))
+		a.selectFields = append(a.selectFields, &ast.SelectField{
+			Auxiliary: true,
+			Expr:      v,
+			AsName:    name,
+		})
+		col := &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: name}}
+		col.SetType(v.GetType())
+		a.colMapper[col] = len(a.selectFields) - 1
+		return col, true
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
 		if a.inAggFunc || (a.orderBy && a.inExpr) {
@@ -664,6 +961,98 @@ func (b *planBuilder) extractAggFuncs(fields []*ast.SelectField) ([]*ast.Aggrega
 	return aggList, totalAggMapper
 }
 
+// windowFuncExtractor collects the window functions of the select fields.
+type windowFuncExtractor struct {
+	windowFuncs []*ast.WindowFuncExpr
+}
+
+// Enter implements Visitor interface.
+func (w *windowFuncExtractor) Enter(n ast.Node) (ast.Node, bool) {
+	switch v := n.(type) {
+	case *ast.WindowFuncExpr:
+		// A window function in the window of another one is invalid, it is reported when the window is built.
+		w.windowFuncs = append(w.windowFuncs, v)
+		return n, true
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr:
+		return n, true
+	}
+	return n, false
+}
+
+// Leave implements Visitor interface.
+func (w *windowFuncExtractor) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func extractWindowFuncs(fields []*ast.SelectField) []*ast.WindowFuncExpr {
+	extractor := &windowFuncExtractor{}
+	for _, f := range fields {
+		f.Expr.Accept(extractor)
+	}
+	return extractor.windowFuncs
+}
+
+// buildWindows builds a Window for each window function on top of p, the rows are sorted
+// by the partition items and the order items of the function before it is computed.
+func (b *planBuilder) buildWindows(p LogicalPlan, windowFuncs []*ast.WindowFuncExpr, aggMapper map[*ast.AggregateFuncExpr]int) LogicalPlan {
+	if b.windowMapper == nil {
+		b.windowMapper = make(map[*ast.WindowFuncExpr]int)
+	}
+	for _, fun := range windowFuncs {
+		var byItems []*ast.ByItem
+		for _, expr := range fun.Spec.PartitionBy {
+			byItems = append(byItems, &ast.ByItem{Expr: expr})
+		}
+		if fun.Spec.OrderBy != nil {
+			byItems = append(byItems, fun.Spec.OrderBy.Items...)
+		}
+		window := &Window{
+			FuncName:        fun.F,
+			baseLogicalPlan: newBaseLogicalPlan(Win, b.allocator),
+		}
+		window.self = window
+		window.initIDAndContext(b.ctx)
+		sortItems := make([]*ByItems, 0, len(byItems))
+		for i, item := range byItems {
+			expr, np, err := b.rewrite(item.Expr, p, aggMapper, true)
+			if err != nil {
+				b.err = errors.Trace(err)
+				return nil
+			}
+			p = np
+			sortItems = append(sortItems, &ByItems{Expr: expr, Desc: item.Desc})
+			if i < len(fun.Spec.PartitionBy) {
+				window.PartitionBy = append(window.PartitionBy, expr.Clone())
+			} else {
+				window.OrderBy = append(window.OrderBy, &ByItems{Expr: expr.Clone(), Desc: item.Desc})
+			}
+		}
+		if len(sortItems) > 0 {
+			sort := &Sort{ByItems: sortItems, baseLogicalPlan: newBaseLogicalPlan(Srt, b.allocator)}
+			sort.self = sort
+			sort.initIDAndContext(b.ctx)
+			addChild(sort, p)
+			sort.SetSchema(p.GetSchema().Clone())
+			sort.SetCorrelated()
+			p = sort
+		}
+		addChild(window, p)
+		schema := p.GetSchema().Clone()
+		schema.Append(&expression.Column{
+			FromID:      window.id,
+			ColName:     model.NewCIStr(fun.F),
+			Position:    schema.Len() + 1,
+			RetType:     fun.GetType(),
+			IsAggOrSubq: true,
+		})
+		window.SetSchema(schema)
+		window.SetCorrelated()
+		b.windowMapper[fun] = schema.Len() - 1
+		p = window
+	}
+	return p
+}
+
 // gbyResolver resolves group by items from select fields.
 type gbyResolver struct {
 	fields []*ast.SelectField
@@ -706,7 +1095,7 @@ func (g *gbyResolver) Leave(inNode ast.Node) (ast.Node, bool) {
 		if v.N >= 1 && v.N <= len(g.fields) {
 			return g.fields[v.N-1].Expr, true
 		}
-		g.err = errors.Errorf("Unknown column '%d' in 'group statement'", v.N)
+		g.err = ErrUnknownColumn.GenByArgs(strconv.Itoa(v.N), "group statement")
 		return inNode, false
 	}
 	return inNode, true
@@ -747,6 +1136,9 @@ func (b *planBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectFi
 		dbName := field.WildCard.Schema
 		tblName := field.WildCard.Table
 		for _, col := range p.GetSchema().Columns {
+			if col.Hidden && tblName.L == "" {
+				continue
+			}
 			if (dbName.L == "" || dbName.L == col.DBName.L) &&
 				(tblName.L == "" || tblName.L == col.TblName.L) {
 				colName := &ast.ColumnNameExpr{
@@ -766,6 +1158,11 @@ func (b *planBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectFi
 }
 
 func (b *planBuilder) buildSelect(sel *ast.SelectStmt) LogicalPlan {
+	outerHints := b.tableHints
+	b.tableHints = sel.TableHints
+	defer func() {
+		b.tableHints = outerHints
+	}()
 	hasAgg := b.detectSelectAgg(sel)
 	var (
 		p                             LogicalPlan
@@ -810,7 +1207,7 @@ func (b *planBuilder) buildSelect(sel *ast.SelectStmt) LogicalPlan {
 			return nil
 		}
 		var aggIndexMap map[int]int
-		p, aggIndexMap = b.buildAggregation(p, aggFuncs, gbyCols)
+		p, aggIndexMap = b.buildAggregation(p, aggFuncs, gbyCols, sel.GroupBy != nil && sel.GroupBy.Rollup)
 		for k, v := range totalMap {
 			totalMap[k] = aggIndexMap[v]
 		}
@@ -818,6 +1215,16 @@ func (b *planBuilder) buildSelect(sel *ast.SelectStmt) LogicalPlan {
 			return nil
 		}
 	}
+	if windowFuncs := extractWindowFuncs(sel.Fields.Fields); len(windowFuncs) > 0 {
+		if sel.Having != nil {
+			b.err = ErrUnsupportedType.Gen("window functions with HAVING clause are not supported")
+			return nil
+		}
+		p = b.buildWindows(p, windowFuncs, totalMap)
+		if b.err != nil {
+			return nil
+		}
+	}
 	var oldLen int
 	p, oldLen = b.buildProjection(p, sel.Fields.Fields, totalMap)
 	if b.err != nil {
@@ -830,7 +1237,11 @@ func (b *planBuilder) buildSelect(sel *ast.SelectStmt) LogicalPlan {
 		}
 	}
 	if sel.Distinct {
-		p = b.buildDistinct(p)
+		if oldLen != p.GetSchema().Len() {
+			p = b.buildDistinctWithAuxFields(p, oldLen)
+		} else {
+			p = b.buildDistinct(p)
+		}
 		if b.err != nil {
 			return nil
 		}
@@ -872,8 +1283,87 @@ func (b *planBuilder) buildTableDual() LogicalPlan {
 }
 
 func (b *planBuilder) getTableStats(table *model.TableInfo) *statistics.Table {
-	// TODO: Currently we always return a pseudo table for good performance. We will use a cache in future.
-	return statistics.PseudoTable(table)
+	return GetStatisticsTable(b.ctx, table)
+}
+
+// buildView expands a view to the plan of its select statement, whose output columns are renamed to the columns of the view.
+func (b *planBuilder) buildView(tn *ast.TableName) LogicalPlan {
+	tableInfo := tn.TableInfo
+	for _, id := range b.expandingViews {
+		if id == tableInfo.ID {
+			b.err = ErrViewRecursive.GenByArgs(tn.Schema.O, tn.Name.O)
+			return nil
+		}
+	}
+	sel, err := parseView(b.ctx, b.is, tableInfo.View)
+	if err != nil {
+		log.Warnf("[plan] view %s.%s is invalid: %v", tn.Schema, tn.Name, err)
+		b.err = ErrViewInvalid.GenByArgs(tn.Schema.O, tn.Name.O)
+		return nil
+	}
+	// The select statement of the view can't refer to the columns of the outer query.
+	outerSchemas := b.outerSchemas
+	b.outerSchemas = nil
+	b.expandingViews = append(b.expandingViews, tableInfo.ID)
+	p := b.buildResultSetNode(sel)
+	b.expandingViews = b.expandingViews[:len(b.expandingViews)-1]
+	b.outerSchemas = outerSchemas
+	if b.err != nil {
+		return nil
+	}
+	// The columns of the base tables may be changed after the view is created, like `select *` from a table with a new column.
+	if p.GetSchema().Len() != len(tableInfo.Columns) {
+		b.err = ErrViewInvalid.GenByArgs(tn.Schema.O, tn.Name.O)
+		return nil
+	}
+
+	proj := &Projection{
+		Exprs:           make([]expression.Expression, 0, len(tableInfo.Columns)),
+		baseLogicalPlan: newBaseLogicalPlan(Proj, b.allocator),
+	}
+	proj.self = proj
+	proj.initIDAndContext(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(tableInfo.Columns)))
+	for i, col := range p.GetSchema().Columns {
+		proj.Exprs = append(proj.Exprs, col.Clone())
+		schema.Append(&expression.Column{
+			FromID:   proj.id,
+			Position: i + 1,
+			DBName:   tn.Schema,
+			TblName:  tableInfo.Name,
+			ColName:  tableInfo.Columns[i].Name,
+			RetType:  col.RetType,
+		})
+	}
+	proj.SetSchema(schema)
+	addChild(proj, p)
+	proj.SetCorrelated()
+	return proj
+}
+
+// parseView parses the select statement of the view, and resolves the names in it like they were resolved
+// when the view was created.
+func parseView(ctx context.Context, is infoschema.InfoSchema, view *model.ViewInfo) (ast.ResultSetNode, error) {
+	stmt, err := parser.New().ParseOneStmt(view.SelectStmt, view.Charset, view.Collate)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sel, ok := stmt.(ast.ResultSetNode)
+	if !ok {
+		return nil, errors.Errorf("invalid view definition %s", view.SelectStmt)
+	}
+	resolver := nameResolver{Info: is, Ctx: ctx, DefaultSchema: model.NewCIStr(view.DefaultSchema)}
+	stmt.Accept(&resolver)
+	if resolver.Err != nil {
+		return nil, errors.Trace(resolver.Err)
+	}
+	if err = Validate(stmt, false); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = InferType(ctx.GetSessionVars().StmtCtx, stmt); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return sel, nil
 }
 
 func (b *planBuilder) buildDataSource(tn *ast.TableName) LogicalPlan {
@@ -891,6 +1381,10 @@ func (b *planBuilder) buildDataSource(tn *ast.TableName) LogicalPlan {
 		return nil
 	}
 	tableInfo := tbl.Meta()
+	if err = checkIndexHints(tn.IndexHints, tableInfo); err != nil {
+		b.err = errors.Trace(err)
+		return nil
+	}
 
 	p := &DataSource{
 		indexHints:      tn.IndexHints,
@@ -1034,14 +1528,36 @@ func (b *planBuilder) buildSemiJoin(outerPlan, innerPlan LogicalPlan, onConditio
 	return joinPlan
 }
 
+// findView finds a view in the table references, it returns nil if there is no view.
+func findView(node ast.ResultSetNode) *ast.TableName {
+	switch x := node.(type) {
+	case *ast.Join:
+		if tn := findView(x.Left); tn != nil {
+			return tn
+		}
+		if x.Right != nil {
+			return findView(x.Right)
+		}
+	case *ast.TableSource:
+		if tn, ok := x.Source.(*ast.TableName); ok && tn.TableInfo.IsView() {
+			return tn
+		}
+	}
+	return nil
+}
+
 func (b *planBuilder) buildUpdate(update *ast.UpdateStmt) LogicalPlan {
+	// The views are read only, the rows of a view can't be mapped back to the rows of its base tables.
+	if tn := findView(update.TableRefs.TableRefs); tn != nil {
+		b.err = ErrNonUpdatableTable.GenByArgs(tn.Name.O, "UPDATE")
+		return nil
+	}
 	b.inUpdateStmt = true
 	sel := &ast.SelectStmt{Fields: &ast.FieldList{}, From: update.TableRefs, Where: update.Where, OrderBy: update.Order, Limit: update.Limit}
 	p := b.buildResultSetNode(sel.From.TableRefs)
 	if b.err != nil {
 		return nil
 	}
-	_, _ = b.resolveHavingAndOrderBy(sel, p)
 	if sel.Where != nil {
 		p = b.buildSelection(p, sel.Where, nil)
 		if b.err != nil {
@@ -1065,7 +1581,7 @@ func (b *planBuilder) buildUpdate(update *ast.UpdateStmt) LogicalPlan {
 		return nil
 	}
 	p = np
-	updt := &Update{OrderedList: orderedList, baseLogicalPlan: newBaseLogicalPlan(Up, b.allocator)}
+	updt := &Update{OrderedList: orderedList, Ignore: update.Ignore, baseLogicalPlan: newBaseLogicalPlan(Up, b.allocator)}
 	updt.ctx = b.ctx
 	updt.self = updt
 	updt.initIDAndContext(b.ctx)
@@ -1084,12 +1600,17 @@ func (b *planBuilder) buildUpdateLists(list []*ast.Assignment, p LogicalPlan) ([
 			return nil, nil
 		}
 		if col == nil {
-			b.err = errors.Trace(errors.Errorf("column %s not found", assign.Column.Name.O))
+			name := assign.Column.Name.O
+			if assign.Column.Table.L != "" {
+				name = assign.Column.Table.O + "." + name
+			}
+			b.err = ErrUnknownColumn.GenByArgs(name, "field list")
 			return nil, nil
 		}
 		offset := schema.GetColumnIndex(col)
 		if offset == -1 {
 			b.err = errors.Trace(errors.Errorf("could not find column %s.%s", col.TblName, col.ColName))
+			return nil, nil
 		}
 		newExpr, np, err := b.rewrite(assign.Expr, p, nil, false)
 		if err != nil {
@@ -1103,12 +1624,15 @@ func (b *planBuilder) buildUpdateLists(list []*ast.Assignment, p LogicalPlan) ([
 }
 
 func (b *planBuilder) buildDelete(delete *ast.DeleteStmt) LogicalPlan {
+	if tn := findView(delete.TableRefs.TableRefs); tn != nil {
+		b.err = ErrNonUpdatableTable.GenByArgs(tn.Name.O, "DELETE")
+		return nil
+	}
 	sel := &ast.SelectStmt{Fields: &ast.FieldList{}, From: delete.TableRefs, Where: delete.Where, OrderBy: delete.Order, Limit: delete.Limit}
 	p := b.buildResultSetNode(sel.From.TableRefs)
 	if b.err != nil {
 		return nil
 	}
-	_, _ = b.resolveHavingAndOrderBy(sel, p)
 	if sel.Where != nil {
 		p = b.buildSelection(p, sel.Where, nil)
 		if b.err != nil {
@@ -486,6 +486,11 @@ func (s *testPlanSuite) TestPredicatePushDown(c *C) {
 			first: "DataScan(t)->Aggr(firstrow(test.t.a),sum(test.t.b))->Projection->Selection->Selection->Projection",
 			best:  "DataScan(t)->Selection->Aggr(firstrow(test.t.a),sum(test.t.b))->Selection->Projection->Projection",
 		},
+		{
+			sql:   "select * from (select a, sum(b) as s from t group by a with rollup) k where a > 1",
+			first: "DataScan(t)->Aggr(firstrow(test.t.a),sum(test.t.b))->Projection->Selection->Projection",
+			best:  "DataScan(t)->Aggr(firstrow(test.t.a),sum(test.t.b))->Selection->Projection->Projection",
+		},
 	}
 	for _, ca := range cases {
 		comment := Commentf("for %s", ca.sql)
@@ -552,7 +557,7 @@ func (s *testPlanSuite) TestPlanBuilder(c *C) {
 		},
 		{
 			sql:  "explain select * from t union all select * from t limit 1, 1",
-			plan: "UnionAll{Table(t)->Table(t)->Limit}->*plan.Explain",
+			plan: "UnionAll{Table(t)->Limit->Table(t)->Limit}->Limit->*plan.Explain",
 		},
 		{
 			sql:  "insert into t select * from t",
@@ -711,6 +716,14 @@ func (s *testPlanSuite) TestAggPushDown(c *C) {
 			sql:  "select sum(c1) from (select c c1, d c2 from t a union all select a c1, b c2 from t b union all select b c1, e c2 from t c) x group by c2",
 			best: "UnionAll{DataScan(a)->Aggr(sum(a.c),firstrow(a.d))->DataScan(b)->Aggr(sum(b.a),firstrow(b.b))->DataScan(c)->Aggr(sum(c.b),firstrow(c.e))}->Aggr(sum(join_agg_0))->Projection",
 		},
+		{
+			sql:  "select group_concat(c1 order by c1) from (select c c1 from t a union all select a c1 from t b) x",
+			best: "UnionAll{DataScan(a)->Projection->DataScan(b)->Projection}->Aggr(group_concat(x.c1, x.c1))->Projection",
+		},
+		{
+			sql:  "select sum(a.a) from t a, t b where a.c = b.c group by a.d with rollup",
+			best: "Join{DataScan(a)->DataScan(b)}(a.c,b.c)->Aggr(sum(a.a))->Projection",
+		},
 	}
 	for _, ca := range cases {
 		comment := Commentf("for %s", ca.sql)
@@ -848,7 +861,7 @@ func (s *testPlanSuite) TestRefine(c *C) {
 		},
 		{
 			sql:  "select a from t where c_str like 'abc'",
-			best: "Index(t.c_d_e_str)[[abc,abc]]->Projection",
+			best: "Index(t.c_d_e_str)[[ABC,ABC]]->Projection",
 		},
 		{
 			sql:  "select a from t where c_str not like 'abc'",
@@ -864,39 +877,39 @@ func (s *testPlanSuite) TestRefine(c *C) {
 		},
 		{
 			sql:  "select a from t where c_str like 'abc%'",
-			best: "Index(t.c_d_e_str)[[abc,abd)]->Projection",
+			best: "Index(t.c_d_e_str)[[ABC,ABD)]->Projection",
 		},
 		{
 			sql:  "select a from t where c_str like 'abc_'",
-			best: "Index(t.c_d_e_str)[(abc,abd)]->Selection->Projection",
+			best: "Index(t.c_d_e_str)[(ABC,ABD)]->Selection->Projection",
 		},
 		{
 			sql:  "select a from t where c_str like 'abc%af'",
-			best: "Index(t.c_d_e_str)[[abc,abd)]->Selection->Projection",
+			best: "Index(t.c_d_e_str)[[ABC,ABD)]->Selection->Projection",
 		},
 		{
 			sql:  `select a from t where c_str like 'abc\\_' escape ''`,
-			best: "Index(t.c_d_e_str)[[abc_,abc_]]->Projection",
+			best: "Index(t.c_d_e_str)[[ABC_,ABC_]]->Projection",
 		},
 		{
 			sql:  `select a from t where c_str like 'abc\\_'`,
-			best: "Index(t.c_d_e_str)[[abc_,abc_]]->Projection",
+			best: "Index(t.c_d_e_str)[[ABC_,ABC_]]->Projection",
 		},
 		{
 			sql:  `select a from t where c_str like 'abc\\\\_'`,
-			best: "Index(t.c_d_e_str)[(abc\\,abc])]->Selection->Projection",
+			best: "Index(t.c_d_e_str)[(ABC\\,ABC])]->Selection->Projection",
 		},
 		{
 			sql:  `select a from t where c_str like 'abc\\_%'`,
-			best: "Index(t.c_d_e_str)[[abc_,abc`)]->Projection",
+			best: "Index(t.c_d_e_str)[[ABC_,ABC`)]->Projection",
 		},
 		{
 			sql:  `select a from t where c_str like 'abc=_%' escape '='`,
-			best: "Index(t.c_d_e_str)[[abc_,abc`)]->Projection",
+			best: "Index(t.c_d_e_str)[[ABC_,ABC`)]->Projection",
 		},
 		{
 			sql:  `select a from t where c_str like 'abc\\__'`,
-			best: "Index(t.c_d_e_str)[(abc_,abc`)]->Selection->Projection",
+			best: "Index(t.c_d_e_str)[(ABC_,ABC`)]->Selection->Projection",
 		},
 		{
 			// Check that 123 is converted to string '123'. index can be used.
@@ -1166,7 +1179,11 @@ func (s *testPlanSuite) TestRangeBuilder(c *C) {
 		},
 		{
 			exprStr:   "a in (1, 3, NULL, 2)",
-			resultStr: "[[<nil> <nil>] [1 1] [2 2] [3 3]]",
+			resultStr: "[[1 1] [2 2] [3 3]]",
+		},
+		{
+			exprStr:   "a in (NULL)",
+			resultStr: "[]",
 		},
 		{
 			exprStr:   `a IN (8,8,81,45)`,
@@ -119,6 +119,8 @@ type Aggregation struct {
 
 	AggFuncs     []expression.AggregationFunction
 	GroupByItems []expression.Expression
+	// WithRollup is true for GROUP BY ... WITH ROLLUP.
+	WithRollup bool
 
 	// groupByCols stores the columns that are group-by items.
 	groupByCols []*expression.Column
@@ -273,11 +275,45 @@ func (p *Sort) SetCorrelated() {
 	}
 }
 
+// Window computes a window function for every row of its child, the result is appended to the row as the last column.
+// The child is sorted by the partition items and then the order items, so the rows of a partition are adjacent.
+type Window struct {
+	baseLogicalPlan
+
+	// FuncName is the name of the window function.
+	FuncName    string
+	PartitionBy []expression.Expression
+	OrderBy     []*ByItems
+}
+
+func (p *Window) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.basePlan.extractCorrelatedCols()
+	for _, expr := range p.PartitionBy {
+		corCols = append(corCols, extractCorColumns(expr)...)
+	}
+	for _, item := range p.OrderBy {
+		corCols = append(corCols, extractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
+// SetCorrelated implements Plan interface.
+func (p *Window) SetCorrelated() {
+	p.basePlan.SetCorrelated()
+	for _, expr := range p.PartitionBy {
+		p.correlated = p.correlated || expr.IsCorrelated()
+	}
+	for _, item := range p.OrderBy {
+		p.correlated = p.correlated || item.Expr.IsCorrelated()
+	}
+}
+
 // Update represents Update plan.
 type Update struct {
 	baseLogicalPlan
 
 	OrderedList []*expression.Assignment
+	Ignore      bool
 }
 
 // Delete represents a delete plan.
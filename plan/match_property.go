@@ -277,6 +277,11 @@ func (p *Trim) matchProperty(_ *requiredProperty, _ ...*physicalPlanInfo) *physi
 	panic("You can't call this function!")
 }
 
+// matchProperty implements PhysicalPlan matchProperty interface.
+func (p *Window) matchProperty(_ *requiredProperty, _ ...*physicalPlanInfo) *physicalPlanInfo {
+	panic("You can't call this function!")
+}
+
 // matchProperty implements PhysicalPlan matchProperty interface.
 func (p *PhysicalAggregation) matchProperty(prop *requiredProperty, _ ...*physicalPlanInfo) *physicalPlanInfo {
 	panic("You can't call this function!")
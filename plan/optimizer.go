@@ -76,6 +76,7 @@ func doOptimize(logic LogicalPlan, ctx context.Context, allocator *idAllocator)
 	pp := info.p
 	pp = EliminateProjection(pp)
 	physicalInitialize(pp)
+	initEnforcedPlanID(pp, allocator)
 	addCachePlan(pp, allocator)
 	log.Debugf("[PLAN] %s", ToString(pp))
 	return pp, nil
@@ -159,7 +159,7 @@ func (p *DataSource) convert2TableScan(prop *requiredProperty) (*physicalPlanInf
 		}
 		ts.AccessCondition, newSel.Conditions = detachTableScanConditions(conds, table)
 		ts.TableConditionPBExpr, ts.tableFilterConditions, newSel.Conditions =
-			expressionsToPB(sc, newSel.Conditions, client)
+			expressionsToPB(p.ctx, newSel.Conditions, client)
 		err := buildTableRange(ts)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -237,8 +237,8 @@ func (p *DataSource) convert2IndexScan(prop *requiredProperty, index *model.Inde
 		isDistReq := !memDB && client != nil && client.SupportRequestType(kv.ReqTypeIndex, 0)
 		if isDistReq {
 			idxConds, tblConds := detachIndexFilterConditions(newSel.Conditions, is.Index.Columns, is.Table)
-			is.IndexConditionPBExpr, is.indexFilterConditions, idxConds = expressionsToPB(sc, idxConds, client)
-			is.TableConditionPBExpr, is.tableFilterConditions, tblConds = expressionsToPB(sc, tblConds, client)
+			is.IndexConditionPBExpr, is.indexFilterConditions, idxConds = expressionsToPB(p.ctx, idxConds, client)
+			is.TableConditionPBExpr, is.tableFilterConditions, tblConds = expressionsToPB(p.ctx, tblConds, client)
 			newSel.Conditions = append(idxConds, tblConds...)
 		}
 		err := buildIndexRange(p.ctx.GetSessionVars().StmtCtx, is)
@@ -270,6 +270,10 @@ func isCoveringIndex(columns []*model.ColumnInfo, indexColumns []*model.IndexCol
 		if pkIsHandle && mysql.HasPriKeyFlag(colInfo.Flag) {
 			continue
 		}
+		// The index keys keep the folded strings of a case-insensitive column, not the original strings.
+		if types.IsCaseInsensitiveStr(&colInfo.FieldType) {
+			return false
+		}
 		isIndexColumn := false
 		for _, indexCol := range indexColumns {
 			if colInfo.Name.L == indexCol.Name.L && indexCol.Length == types.UnspecifiedLength {
@@ -389,6 +393,7 @@ func enforceProperty(prop *requiredProperty, info *physicalPlanInfo) *physicalPl
 	if info.p == nil {
 		return info
 	}
+	info.recordCount()
 	if len(prop.props) != 0 {
 		items := make([]*ByItems, 0, len(prop.props))
 		for _, col := range prop.props {
@@ -398,6 +403,7 @@ func enforceProperty(prop *requiredProperty, info *physicalPlanInfo) *physicalPl
 			ByItems:   items,
 			ExecLimit: prop.limit,
 		}
+		sort.tp = Srt
 		sort.SetSchema(info.p.GetSchema())
 		info = addPlanToResponse(sort, info)
 		count := info.count
@@ -407,6 +413,7 @@ func enforceProperty(prop *requiredProperty, info *physicalPlanInfo) *physicalPl
 		info.cost += sortCost(count)
 	} else if prop.limit != nil {
 		limit := prop.limit.Copy().(*Limit)
+		limit.tp = Lim
 		limit.SetSchema(info.p.GetSchema())
 		info = addPlanToResponse(limit, info)
 	}
@@ -777,6 +784,7 @@ func (p *Aggregation) convert2PhysicalPlanCompleteHash(childInfo *physicalPlanIn
 		AggType:      CompleteAgg,
 		AggFuncs:     p.AggFuncs,
 		GroupByItems: p.GroupByItems,
+		WithRollup:   p.WithRollup,
 	}
 	agg.tp = "HashAgg"
 	agg.allocator = p.allocator
@@ -823,6 +831,17 @@ func (p *Aggregation) convert2PhysicalPlan(prop *requiredProperty) (*physicalPla
 		return planInfo, nil
 	}
 	limit := prop.limit
+	if p.WithRollup {
+		// Only the complete hash aggregation can produce the super-aggregate rows.
+		var childInfo *physicalPlanInfo
+		childInfo, err = p.children[0].(LogicalPlan).convert2PhysicalPlan(&requiredProperty{})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		planInfo = enforceProperty(prop, p.convert2PhysicalPlanCompleteHash(childInfo))
+		err = p.storePlanInfo(prop, planInfo)
+		return planInfo, errors.Trace(err)
+	}
 	if len(prop.props) == 0 {
 		planInfo, err = p.convert2PhysicalPlanHash()
 		if err != nil {
@@ -1135,6 +1154,42 @@ func (p *Distinct) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanIn
 	return info, nil
 }
 
+// convert2PhysicalPlan implements the LogicalPlan convert2PhysicalPlan interface.
+func (p *Window) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanInfo, error) {
+	info, err := p.getPlanInfo(prop)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info != nil {
+		return info, nil
+	}
+	// The child is the sort on the partition and order items, it must not be reordered or limited.
+	info, err = p.GetChildByIndex(0).(LogicalPlan).convert2PhysicalPlan(&requiredProperty{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info = addPlanToResponse(p, info)
+	info.cost += float64(info.count) * cpuFactor
+	info = enforceProperty(prop, info)
+	err = p.storePlanInfo(prop, info)
+	return info, errors.Trace(err)
+}
+
+// convert2PhysicalPlan implements the LogicalPlan convert2PhysicalPlan interface.
+func (p *TableDual) convert2PhysicalPlan(prop *requiredProperty) (*physicalPlanInfo, error) {
+	info, err := p.getPlanInfo(prop)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info != nil {
+		return info, nil
+	}
+	// The dual table has no columns to sort on, but the limit still has to be enforced.
+	info = enforceProperty(prop, &physicalPlanInfo{p: p, count: 1})
+	p.storePlanInfo(prop, info)
+	return info, nil
+}
+
 // physicalInitialize will set value of some attributes after convert2PhysicalPlan process.
 // Currently, only attribute "correlated" is considered.
 func physicalInitialize(p PhysicalPlan) {
@@ -1145,6 +1200,22 @@ func physicalInitialize(p PhysicalPlan) {
 	p.SetCorrelated()
 }
 
+// initEnforcedPlanID allocates ids for the sort and limit plans added by enforceProperty.
+func initEnforcedPlanID(p PhysicalPlan, allocator *idAllocator) {
+	for _, child := range p.GetChildren() {
+		initEnforcedPlanID(child.(PhysicalPlan), allocator)
+	}
+	if p.GetID() != "" {
+		return
+	}
+	switch x := p.(type) {
+	case *Sort:
+		x.id = x.tp + allocator.allocID()
+	case *Limit:
+		x.id = x.tp + allocator.allocID()
+	}
+}
+
 // addCachePlan will add a Cache plan above the plan whose father's IsCorrelated() is true but its own IsCorrelated() is false.
 func addCachePlan(p PhysicalPlan, allocator *idAllocator) {
 	if len(p.GetChildren()) == 0 {
@@ -366,6 +366,22 @@ func (s *testPlanSuite) TestCBO(c *C) {
 			sql:  "select * from t t1 ignore index(c_d_e) where c < 0",
 			best: "Table(t)",
 		},
+		{
+			sql:  "select /*+ USE_INDEX(t1, c_d_e) */ * from t t1",
+			best: "Index(t.c_d_e)[[<nil>,+inf]]",
+		},
+		{
+			sql:  "select /*+ USE_INDEX(t, c_d_e) */ * from t t1",
+			best: "Table(t)",
+		},
+		{
+			sql:  "select /*+ IGNORE_INDEX(t, c_d_e) */ * from t where c < 0",
+			best: "Table(t)",
+		},
+		{
+			sql:  "select * from t where c in (select /*+ IGNORE_INDEX(t, c_d_e) */ c from t where c < 0)",
+			best: "SemiJoin{Table(t)->Table(t)}",
+		},
 		{
 			sql:  "select * from t where f in (1,2) and g in(1,2,3,4,5)",
 			best: "Index(t.f_g)[[1 1,1 1] [1 2,1 2] [1 3,1 3] [1 4,1 4] [1 5,1 5] [2 1,2 1] [2 2,2 2] [2 3,2 3] [2 4,2 4] [2 5,2 5]]",
@@ -702,6 +718,18 @@ func (s *testPlanSuite) TestFilterConditionPushDown(c *C) {
 			indexFilter: "[lt(test.t.a, 1) lt(test.t.d, test.t.e)]",
 			tableFilter: "[gt(test.t.b, minus(test.t.a, test.t.d))]",
 		},
+		{
+			sql:         "select * from t use index(c_d_e) where t.d = 1 and (t.c = 1 or t.c = 2) and t.e > 1",
+			access:      "[or(eq(test.t.c, 1), eq(test.t.c, 2)) eq(test.t.d, 1) gt(test.t.e, 1)]",
+			indexFilter: "[]",
+			tableFilter: "[]",
+		},
+		{
+			sql:         "select * from t use index(c_d_e) where t.c = 1 or t.d = 2",
+			access:      "[]",
+			indexFilter: "[or(eq(test.t.c, 1), eq(test.t.d, 2))]",
+			tableFilter: "[]",
+		},
 	}
 	for _, ca := range cases {
 		comment := Commentf("for %s", ca.sql)
@@ -747,6 +775,89 @@ func (s *testPlanSuite) TestFilterConditionPushDown(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestMultiColumnIndexRange(c *C) {
+	defer testleak.AfterTest(c)()
+	cases := []struct {
+		sql    string
+		ranges string
+	}{
+		{
+			sql:    "select * from t use index(c_d_e) where c = 1 and d > 2",
+			ranges: "[(1 2,1 +inf]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where c = 1 and d = 2 and e < 5",
+			ranges: "[[1 2 -inf,1 2 5)]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where c in (1, 2) and d >= 2 and d < 4",
+			ranges: "[[1 2,1 4) [2 2,2 4)]",
+		},
+		// Only the columns of the leading prefix narrow the range.
+		{
+			sql:    "select * from t use index(c_d_e) where c = 1 and e = 2",
+			ranges: "[[1,1]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where c > 1 and d = 2",
+			ranges: "[(1,+inf]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where d = 1 and e = 2",
+			ranges: "[[<nil>,+inf]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where c <=> 1 and d = 2",
+			ranges: "[[1 2,1 2]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where c <=> null and d > 2",
+			ranges: "[(<nil> 2,<nil> +inf]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where c <=> 1 or c <=> null",
+			ranges: "[[<nil>,<nil>] [1,1]]",
+		},
+		{
+			sql:    "select * from t use index(c_d_e) where not c <=> 1",
+			ranges: "[[<nil>,+inf]]",
+		},
+	}
+	for _, ca := range cases {
+		comment := Commentf("for %s", ca.sql)
+		stmt, err := s.ParseOneStmt(ca.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		ast.SetFlag(stmt)
+
+		is, err := mockResolve(stmt)
+		c.Assert(err, IsNil)
+		builder := &planBuilder{
+			allocator: new(idAllocator),
+			ctx:       mockContext(),
+			colMapper: make(map[*ast.ColumnNameExpr]int),
+			is:        is,
+		}
+		p := builder.build(stmt)
+		c.Assert(builder.err, IsNil)
+		lp := p.(LogicalPlan)
+
+		_, lp, err = lp.PredicatePushDown(nil)
+		c.Assert(err, IsNil)
+		lp.PruneColumns(lp.GetSchema().Columns)
+		lp.ResolveIndicesAndCorCols()
+		info, err := lp.convert2PhysicalPlan(&requiredProperty{})
+		c.Assert(err, IsNil)
+		p = info.p
+		for {
+			if x, ok := p.(*PhysicalIndexScan); ok {
+				c.Assert(fmt.Sprintf("%s", x.Ranges), Equals, ca.ranges, comment)
+				break
+			}
+			p = p.GetChildByIndex(0)
+		}
+	}
+}
+
 func (s *testPlanSuite) TestPhysicalInitialize(c *C) {
 	defer testleak.AfterTest(c)()
 	cases := []struct {
@@ -243,11 +243,10 @@ func (p *physicalTableSource) addTopN(ctx context.Context, prop *requiredPropert
 	if prop.limit == nil {
 		return false
 	}
-	sc := ctx.GetSessionVars().StmtCtx
 	count := int64(prop.limit.Count + prop.limit.Offset)
 	p.LimitCount = &count
 	for _, prop := range prop.props {
-		item := sortByItemToPB(sc, p.client, prop.col, prop.desc)
+		item := sortByItemToPB(ctx, p.client, prop.col, prop.desc)
 		if item == nil {
 			// When we fail to convert any sortItem to PB struct, we should clear the environments.
 			p.clearForTopnPushDown()
@@ -263,9 +262,8 @@ func (p *physicalTableSource) addAggregation(ctx context.Context, agg *PhysicalA
 	if p.client == nil {
 		return expression.NewSchema(nil)
 	}
-	sc := ctx.GetSessionVars().StmtCtx
 	for _, f := range agg.AggFuncs {
-		pb := aggFuncToPBExpr(sc, p.client, f)
+		pb := aggFuncToPBExpr(ctx, p.client, f)
 		if pb == nil {
 			// When we fail to convert any agg function to PB struct, we should clear the environments.
 			p.clearForAggPushDown()
@@ -275,7 +273,7 @@ func (p *physicalTableSource) addAggregation(ctx context.Context, agg *PhysicalA
 		p.aggFuncs = append(p.aggFuncs, f.Clone())
 	}
 	for _, item := range agg.GroupByItems {
-		pb := groupByItemToPB(sc, p.client, item)
+		pb := groupByItemToPB(ctx, p.client, item)
 		if pb == nil {
 			// When we fail to convert any group-by item to PB struct, we should clear the environments.
 			p.clearForAggPushDown()
@@ -402,6 +400,7 @@ type PhysicalAggregation struct {
 	AggType      AggregationType
 	AggFuncs     []expression.AggregationFunction
 	GroupByItems []expression.Expression
+	WithRollup   bool
 }
 
 // PhysicalUnionScan represents a union scan operator.
@@ -778,6 +777,31 @@ func (p *Sort) MarshalJSON() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+// Copy implements the PhysicalPlan Copy interface.
+func (p *Window) Copy() PhysicalPlan {
+	np := *p
+	return &np
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (p *Window) MarshalJSON() ([]byte, error) {
+	partitionBy, err := json.Marshal(p.PartitionBy)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	orderBy, err := json.Marshal(p.OrderBy)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	buffer := bytes.NewBufferString("{")
+	buffer.WriteString(fmt.Sprintf(
+		" \"function\": \"%s\",\n"+
+			" \"partitionBy\": %s,\n"+
+			" \"orderBy\": %s,\n"+
+			" \"child\": \"%s\"}", p.FuncName, partitionBy, orderBy, p.children[0].GetID()))
+	return buffer.Bytes(), nil
+}
+
 // Copy implements the PhysicalPlan Copy interface.
 func (p *TableDual) Copy() PhysicalPlan {
 	np := *p
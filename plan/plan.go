@@ -52,6 +52,8 @@ const (
 	Dis = "Distinct"
 	// Trm is the type of Trim.
 	Trm = "Trim"
+	// Win is the type of Window.
+	Win = "Window"
 	// MOR is the type of MaxOneRow.
 	MOR = "MaxOneRow"
 	// Ext is the type of Exists.
@@ -145,6 +147,13 @@ type physicalPlanInfo struct {
 	count uint64
 }
 
+// recordCount records the estimated row count in the root of the physical plan.
+func (info *physicalPlanInfo) recordCount() {
+	if info.p != nil {
+		info.p.setEstimatedCount(info.count)
+	}
+}
+
 // LogicalPlan is a tree of logical operators.
 // We can do a lot of logical optimizations to it, like predicate pushdown and column pruning.
 type LogicalPlan interface {
@@ -186,6 +195,11 @@ type PhysicalPlan interface {
 
 	// Copy copies the current plan.
 	Copy() PhysicalPlan
+
+	// EstimatedCount returns the row count estimated by the optimizer, it is used by explain.
+	EstimatedCount() uint64
+
+	setEstimatedCount(count uint64)
 }
 
 type baseLogicalPlan struct {
@@ -229,6 +243,7 @@ func (p *baseLogicalPlan) storePlanInfo(prop *requiredProperty, info *physicalPl
 	}
 	newInfo := *info // copy it
 	p.planMap[string(key)] = &newInfo
+	info.recordCount()
 	return nil
 }
 
@@ -305,6 +320,18 @@ type basePlan struct {
 	id        string
 	allocator *idAllocator
 	ctx       context.Context
+
+	// estimatedCount is the row count of a physical plan estimated by the optimizer.
+	estimatedCount uint64
+}
+
+// EstimatedCount implements PhysicalPlan EstimatedCount interface.
+func (p *basePlan) EstimatedCount() uint64 {
+	return p.estimatedCount
+}
+
+func (p *basePlan) setEstimatedCount(count uint64) {
+	p.estimatedCount = count
 }
 
 // MarshalJSON implements json.Marshaler interface.
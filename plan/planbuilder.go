@@ -31,21 +31,54 @@ import (
 
 // Error instances.
 var (
-	ErrUnsupportedType      = terror.ClassOptimizerPlan.New(CodeUnsupportedType, "Unsupported type")
-	SystemInternalErrorType = terror.ClassOptimizerPlan.New(SystemInternalError, "System internal error")
-	ErrUnknownColumn        = terror.ClassOptimizerPlan.New(CodeUnknownColumn, "Unknown column '%s' in '%s'")
+	ErrUnsupportedType              = terror.ClassOptimizerPlan.New(CodeUnsupportedType, "Unsupported type")
+	SystemInternalErrorType         = terror.ClassOptimizerPlan.New(SystemInternalError, "System internal error")
+	ErrUnknownColumn                = terror.ClassOptimizerPlan.New(CodeUnknownColumn, "Unknown column '%s' in '%s'")
+	ErrAmbiguous                    = terror.ClassOptimizerPlan.New(CodeAmbiguous, "Column '%s' in %s is ambiguous")
+	ErrUnknownTable                 = terror.ClassOptimizerPlan.New(CodeUnknownTable, "Unknown table '%s' in %s")
+	ErrWrongNumberOfColumnsInSelect = terror.ClassOptimizerPlan.New(CodeWrongNumberOfColumnsInSelect, "The used SELECT statements have a different number of columns")
+	ErrDerivedMustHaveAlias         = terror.ClassOptimizerPlan.New(CodeDerivedMustHaveAlias, "Every derived table must have its own alias")
+	ErrWrongGroupField              = terror.ClassOptimizerPlan.New(CodeWrongGroupField, "Can't group on '%s'")
+	ErrKeyDoesNotExist              = terror.ClassOptimizerPlan.New(CodeKeyDoesNotExist, "Key '%s' doesn't exist in table '%s'")
+	ErrNonUpdatableTable            = terror.ClassOptimizerPlan.New(CodeNonUpdatableTable, "The target table %s of the %s is not updatable")
+	ErrViewInvalid                  = terror.ClassOptimizerPlan.New(CodeViewInvalid, "View '%s.%s' references invalid table(s) or column(s) or function(s)")
+	ErrViewRecursive                = terror.ClassOptimizerPlan.New(CodeViewRecursive, "`%s`.`%s` contains view recursion")
+	ErrNonInsertableTable           = terror.ClassOptimizerPlan.New(CodeNonInsertableTable, "The target table %s of the %s is not insertable-into")
+	ErrWindowInvalidWindowFuncUse   = terror.ClassOptimizerPlan.New(CodeWindowInvalidWindowFuncUse, "You cannot use the window function '%s' in this context.")
 )
 
 // Error codes.
 const (
-	CodeUnsupportedType terror.ErrCode = 1
-	SystemInternalError terror.ErrCode = 2
-	CodeUnknownColumn   terror.ErrCode = 1054
+	CodeUnsupportedType              terror.ErrCode = 1
+	SystemInternalError              terror.ErrCode = 2
+	CodeAmbiguous                    terror.ErrCode = 1052
+	CodeUnknownColumn                terror.ErrCode = 1054
+	CodeWrongGroupField              terror.ErrCode = 1056
+	CodeUnknownTable                 terror.ErrCode = 1109
+	CodeKeyDoesNotExist              terror.ErrCode = 1176
+	CodeWrongNumberOfColumnsInSelect terror.ErrCode = 1222
+	CodeDerivedMustHaveAlias         terror.ErrCode = 1248
+	CodeNonUpdatableTable            terror.ErrCode = 1288
+	CodeViewInvalid                  terror.ErrCode = 1356
+	CodeViewRecursive                terror.ErrCode = 1462
+	CodeNonInsertableTable           terror.ErrCode = 1471
+	CodeWindowInvalidWindowFuncUse   terror.ErrCode = 3593
 )
 
 func init() {
 	tableMySQLErrCodes := map[terror.ErrCode]uint16{
-		CodeUnknownColumn: mysql.ErrBadField,
+		CodeAmbiguous:                    mysql.ErrNonUniq,
+		CodeUnknownColumn:                mysql.ErrBadField,
+		CodeWrongGroupField:              mysql.ErrWrongGroupField,
+		CodeUnknownTable:                 mysql.ErrUnknownTable,
+		CodeKeyDoesNotExist:              mysql.ErrKeyDoesNotExits,
+		CodeWrongNumberOfColumnsInSelect: mysql.ErrWrongNumberOfColumnsInSelect,
+		CodeDerivedMustHaveAlias:         mysql.ErrDerivedMustHaveAlias,
+		CodeNonUpdatableTable:            mysql.ErrNonUpdatableTable,
+		CodeViewInvalid:                  mysql.ErrViewInvalid,
+		CodeViewRecursive:                mysql.ErrViewRecursive,
+		CodeNonInsertableTable:           mysql.ErrNonInsertableTable,
+		CodeWindowInvalidWindowFuncUse:   mysql.ErrWindowInvalidWindowFuncUse,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassOptimizerPlan] = tableMySQLErrCodes
 }
@@ -63,6 +96,12 @@ type planBuilder struct {
 	inUpdateStmt bool
 	// colMapper stores the column that must be pre-resolved.
 	colMapper map[*ast.ColumnNameExpr]int
+	// tableHints are the optimizer hints of the select statement being built.
+	tableHints []*ast.TableOptimizerHint
+	// expandingViews are the IDs of the views being expanded, a view referring to itself is detected by them.
+	expandingViews []int64
+	// windowMapper maps the window functions to the columns computing them.
+	windowMapper map[*ast.WindowFuncExpr]int
 }
 
 func (b *planBuilder) build(node ast.Node) Plan {
@@ -77,12 +116,16 @@ func (b *planBuilder) build(node ast.Node) Plan {
 		return b.buildDDL(x)
 	case *ast.CreateTableStmt:
 		return b.buildDDL(x)
+	case *ast.CreateViewStmt:
+		return b.buildDDL(x)
 	case *ast.DeallocateStmt:
 		return &Deallocate{Name: x.Name}
 	case *ast.DeleteStmt:
 		return b.buildDelete(x)
 	case *ast.DropDatabaseStmt:
 		return b.buildDDL(x)
+	case *ast.AlterDatabaseStmt:
+		return b.buildDDL(x)
 	case *ast.DropIndexStmt:
 		return b.buildDDL(x)
 	case *ast.DropTableStmt:
@@ -98,6 +141,9 @@ func (b *planBuilder) build(node ast.Node) Plan {
 	case *ast.PrepareStmt:
 		return b.buildPrepare(x)
 	case *ast.SelectStmt:
+		if x.SelectIntoOpt != nil {
+			return b.buildSelectInto(x)
+		}
 		return b.buildSelect(x)
 	case *ast.UnionStmt:
 		return b.buildUnion(x)
@@ -111,7 +157,8 @@ func (b *planBuilder) build(node ast.Node) Plan {
 		return b.buildSet(x)
 	case *ast.AnalyzeTableStmt, *ast.BinlogStmt, *ast.FlushTableStmt, *ast.UseStmt,
 		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt,
-		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt:
+		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt,
+		*ast.KillStmt:
 		return b.buildSimple(node.(ast.StmtNode))
 	case *ast.TruncateTableStmt:
 		return b.buildDDL(x)
@@ -212,6 +259,41 @@ func (b *planBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	return false
 }
 
+// Table level optimizer hints.
+const (
+	hintUseIndex    = "use_index"
+	hintForceIndex  = "force_index"
+	hintIgnoreIndex = "ignore_index"
+)
+
+// indexHintsFromTableHints converts the index hints in the optimizer hints of the current select statement
+// which apply to tblName. A table is referred to by its alias in the hints if it has one.
+func (b *planBuilder) indexHintsFromTableHints(tblName model.CIStr) []*ast.IndexHint {
+	var indexHints []*ast.IndexHint
+	for _, hint := range b.tableHints {
+		if hint.Table.L != tblName.L {
+			continue
+		}
+		var hintType ast.IndexHintType
+		switch hint.HintName.L {
+		case hintUseIndex:
+			hintType = ast.HintUse
+		case hintForceIndex:
+			hintType = ast.HintForce
+		case hintIgnoreIndex:
+			hintType = ast.HintIgnore
+		default:
+			continue
+		}
+		indexHints = append(indexHints, &ast.IndexHint{
+			IndexNames: hint.Indexes,
+			HintType:   hintType,
+			HintScope:  ast.HintForScan,
+		})
+	}
+	return indexHints
+}
+
 func availableIndices(hints []*ast.IndexHint, tableInfo *model.TableInfo) (indices []*model.IndexInfo, includeTableScan bool) {
 	var usableHints []*ast.IndexHint
 	for _, hint := range hints {
@@ -228,7 +310,7 @@ func availableIndices(hints []*ast.IndexHint, tableInfo *model.TableInfo) (indic
 	if len(usableHints) == 0 {
 		return publicIndices, true
 	}
-	var hasUse bool
+	var hasUse, usePrimary bool
 	var ignores []*model.IndexInfo
 	for _, hint := range usableHints {
 		switch hint.HintType {
@@ -236,6 +318,11 @@ func availableIndices(hints []*ast.IndexHint, tableInfo *model.TableInfo) (indic
 			// Currently we don't distinguish between Force and Use because our cost estimation is not reliable.
 			hasUse = true
 			for _, idxName := range hint.IndexNames {
+				if isPrimaryHandle(tableInfo, idxName) {
+					// The integer primary key is the handle, it is used by the table scan.
+					usePrimary = true
+					continue
+				}
 				idx := findIndexByName(publicIndices, idxName)
 				if idx != nil {
 					indices = append(indices, idx)
@@ -252,9 +339,9 @@ func availableIndices(hints []*ast.IndexHint, tableInfo *model.TableInfo) (indic
 		}
 	}
 	indices = removeIgnores(indices, ignores)
-	// If we have got FORCE or USE index hint, table scan is excluded.
+	// If we have got FORCE or USE index hint, table scan is excluded unless the primary key is in the hint.
 	if len(indices) != 0 {
-		return indices, false
+		return indices, usePrimary
 	}
 	if hasUse {
 		// Empty use hint means don't use any index.
@@ -263,6 +350,27 @@ func availableIndices(hints []*ast.IndexHint, tableInfo *model.TableInfo) (indic
 	return removeIgnores(publicIndices, ignores), true
 }
 
+// checkIndexHints checks the indexes in the index hints of a table exist.
+// An index which is not public yet is not an error, it is skipped by availableIndices.
+func checkIndexHints(hints []*ast.IndexHint, tableInfo *model.TableInfo) error {
+	for _, hint := range hints {
+		for _, idxName := range hint.IndexNames {
+			if isPrimaryHandle(tableInfo, idxName) {
+				continue
+			}
+			if findIndexByName(tableInfo.Indices, idxName) == nil {
+				return ErrKeyDoesNotExist.GenByArgs(idxName.O, tableInfo.Name.O)
+			}
+		}
+	}
+	return nil
+}
+
+// isPrimaryHandle checks whether name refers to the primary key which is the handle of the table.
+func isPrimaryHandle(tableInfo *model.TableInfo, name model.CIStr) bool {
+	return tableInfo.PKIsHandle && name.L == "primary"
+}
+
 func removeIgnores(indices, ignores []*model.IndexInfo) []*model.IndexInfo {
 	if len(ignores) == 0 {
 		return indices
@@ -391,6 +499,7 @@ func (b *planBuilder) buildShow(show *ast.ShowStmt) Plan {
 		Flag:            show.Flag,
 		Full:            show.Full,
 		User:            show.User,
+		GlobalScope:     show.GlobalScope,
 		baseLogicalPlan: newBaseLogicalPlan("Show", b.allocator),
 	}
 	resultPlan = p
@@ -465,6 +574,28 @@ func (b *planBuilder) findDefaultValue(cols []*table.Column, name *ast.ColumnNam
 	return nil, ErrUnknownColumn.GenByArgs(name.Name.O, "field_list")
 }
 
+// rewriteInsertValue rewrites a value of insert statement, col is the column the value is inserted into.
+func (b *planBuilder) rewriteInsertValue(cols []*table.Column, col *table.Column, valueItem ast.ExprNode) (expression.Expression, error) {
+	if dft, ok := valueItem.(*ast.DefaultExpr); ok {
+		if dft.Name != nil {
+			return b.findDefaultValue(cols, dft.Name)
+		}
+		if col == nil {
+			// The value count doesn't match the column count, the executor will report the error.
+			return &expression.Constant{}, nil
+		}
+		return b.getDefaultValue(col)
+	}
+	if val, ok := valueItem.(*ast.ValueExpr); ok {
+		return &expression.Constant{
+			Value:   val.Datum,
+			RetType: &val.Type,
+		}, nil
+	}
+	expr, _, err := b.rewrite(valueItem, nil, nil, true)
+	return expr, errors.Trace(err)
+}
+
 func (b *planBuilder) buildInsert(insert *ast.InsertStmt) Plan {
 	// Get Table
 	ts, ok := insert.Table.TableRefs.Left.(*ast.TableSource)
@@ -478,14 +609,22 @@ func (b *planBuilder) buildInsert(insert *ast.InsertStmt) Plan {
 		return nil
 	}
 	tableInfo := tn.TableInfo
+	if tableInfo.IsView() {
+		stmtName := "INSERT"
+		if insert.IsReplace {
+			stmtName = "REPLACE"
+		}
+		b.err = ErrNonInsertableTable.GenByArgs(tn.Name.O, stmtName)
+		return nil
+	}
 	schema := expression.TableInfo2Schema(tableInfo)
-	table, ok := b.is.TableByID(tableInfo.ID)
+	tbl, ok := b.is.TableByID(tableInfo.ID)
 	if !ok {
 		b.err = errors.Errorf("Can't get table %s.", tableInfo.Name.O)
 		return nil
 	}
 	insertPlan := &Insert{
-		Table:           table,
+		Table:           tbl,
 		Columns:         insert.Columns,
 		tableSchema:     schema,
 		IsReplace:       insert.IsReplace,
@@ -493,26 +632,19 @@ func (b *planBuilder) buildInsert(insert *ast.InsertStmt) Plan {
 		Ignore:          insert.Ignore,
 		baseLogicalPlan: newBaseLogicalPlan(Ins, b.allocator),
 	}
-	cols := table.Cols()
+	cols := tbl.Cols()
 	for _, valuesItem := range insert.Lists {
 		exprList := make([]expression.Expression, 0, len(valuesItem))
 		for i, valueItem := range valuesItem {
-			var expr expression.Expression
-			var err error
-			if dft, ok := valueItem.(*ast.DefaultExpr); ok {
-				if dft.Name != nil {
-					expr, err = b.findDefaultValue(cols, dft.Name)
-				} else {
-					expr, err = b.getDefaultValue(cols[i])
+			var col *table.Column
+			if len(insert.Columns) > 0 {
+				if i < len(insert.Columns) {
+					col = table.FindCol(cols, insert.Columns[i].Name.O)
 				}
-			} else if val, ok := valueItem.(*ast.ValueExpr); ok {
-				expr = &expression.Constant{
-					Value:   val.Datum,
-					RetType: &val.Type,
-				}
-			} else {
-				expr, _, err = b.rewrite(valueItem, nil, nil, true)
+			} else if i < len(cols) {
+				col = cols[i]
 			}
+			expr, err := b.rewriteInsertValue(cols, col, valueItem)
 			if err != nil {
 				b.err = errors.Trace(err)
 			}
@@ -520,27 +652,30 @@ func (b *planBuilder) buildInsert(insert *ast.InsertStmt) Plan {
 		}
 		insertPlan.Lists = append(insertPlan.Lists, exprList)
 	}
-	for _, assign := range insert.Setlist {
-		col, err := schema.FindColumn(assign.Column)
-		if err != nil {
-			b.err = errors.Trace(err)
-			return nil
-		}
-		if col == nil {
-			b.err = errors.Errorf("Can't find column %s", assign.Column)
-			return nil
-		}
-		// Here we keep different behaviours with MySQL. MySQL allow set a = b, b = a and the result is NULL, NULL.
-		// It's unreasonable.
-		expr, _, err := b.rewrite(assign.Expr, nil, nil, true)
-		if err != nil {
-			b.err = errors.Trace(err)
-			return nil
+	if len(insert.Setlist) > 0 {
+		// `insert into t set a = x, b = y` is translated to `insert into t (a, b) values (x, y)`.
+		exprList := make([]expression.Expression, 0, len(insert.Setlist))
+		for _, assign := range insert.Setlist {
+			col, err := schema.FindColumn(assign.Column)
+			if err != nil {
+				b.err = errors.Trace(err)
+				return nil
+			}
+			if col == nil {
+				b.err = ErrUnknownColumn.GenByArgs(assign.Column.Name.O, "field list")
+				return nil
+			}
+			// Here we keep different behaviours with MySQL. MySQL allow set a = b, b = a and the result is NULL, NULL.
+			// It's unreasonable.
+			expr, err := b.rewriteInsertValue(cols, table.FindCol(cols, assign.Column.Name.O), assign.Expr)
+			if err != nil {
+				b.err = errors.Trace(err)
+				return nil
+			}
+			insertPlan.Columns = append(insertPlan.Columns, assign.Column)
+			exprList = append(exprList, expr)
 		}
-		insertPlan.Setlist = append(insertPlan.Setlist, &expression.Assignment{
-			Col:  col,
-			Expr: expr,
-		})
+		insertPlan.Lists = append(insertPlan.Lists, exprList)
 	}
 	mockTablePlan := &TableDual{}
 	mockTablePlan.SetSchema(schema)
@@ -551,7 +686,7 @@ func (b *planBuilder) buildInsert(insert *ast.InsertStmt) Plan {
 			return nil
 		}
 		if col == nil {
-			b.err = errors.Errorf("Can't find column %s", assign.Column)
+			b.err = ErrUnknownColumn.GenByArgs(assign.Column.Name.O, "field list")
 			return nil
 		}
 		expr, _, err := b.rewrite(assign.Expr, mockTablePlan, nil, true)
@@ -577,16 +712,38 @@ func (b *planBuilder) buildInsert(insert *ast.InsertStmt) Plan {
 }
 
 func (b *planBuilder) buildLoadData(ld *ast.LoadDataStmt) Plan {
+	if ld.Table.TableInfo.IsView() {
+		b.err = ErrNonInsertableTable.GenByArgs(ld.Table.Name.O, "LOAD")
+		return nil
+	}
 	p := &LoadData{
-		IsLocal:    ld.IsLocal,
-		Path:       ld.Path,
-		Table:      ld.Table,
-		FieldsInfo: ld.FieldsInfo,
-		LinesInfo:  ld.LinesInfo,
+		IsLocal:     ld.IsLocal,
+		Path:        ld.Path,
+		Table:       ld.Table,
+		Columns:     ld.Columns,
+		FieldsInfo:  ld.FieldsInfo,
+		LinesInfo:   ld.LinesInfo,
+		IgnoreLines: ld.IgnoreLines,
 	}
 	return p
 }
 
+func (b *planBuilder) buildSelectInto(sel *ast.SelectStmt) Plan {
+	logic := b.buildSelect(sel)
+	if b.err != nil {
+		return nil
+	}
+	selectPlan, err := doOptimize(logic, b.ctx, b.allocator)
+	if err != nil {
+		b.err = errors.Trace(err)
+		return nil
+	}
+	p := &SelectInto{IntoOpt: sel.SelectIntoOpt}
+	addChild(p, selectPlan)
+	p.SetSchema(expression.NewSchema(nil))
+	return p
+}
+
 func (b *planBuilder) buildDDL(node ast.DDLNode) Plan {
 	return &DDL{Statement: node}
 }
@@ -600,21 +757,26 @@ func (b *planBuilder) buildExplain(explain *ast.ExplainStmt) Plan {
 		b.err = errors.Trace(err)
 		return nil
 	}
-	p := &Explain{StmtPlan: targetPlan}
+	p := &Explain{StmtPlan: targetPlan, Format: explain.Format}
 	addChild(p, targetPlan)
-	schema := expression.NewSchema(make([]*expression.Column, 0, 3))
-	schema.Append(&expression.Column{
-		ColName: model.NewCIStr("ID"),
-		RetType: types.NewFieldType(mysql.TypeString),
-	})
-	schema.Append(&expression.Column{
-		ColName: model.NewCIStr("Json"),
-		RetType: types.NewFieldType(mysql.TypeString),
-	})
-	schema.Append(&expression.Column{
-		ColName: model.NewCIStr("ParentID"),
-		RetType: types.NewFieldType(mysql.TypeString),
-	})
+	var names []string
+	if explain.Format == ast.ExplainFormatJSON {
+		names = []string{"ID", "Json", "ParentID"}
+	} else {
+		names = []string{"id", "select_type", "table", "type", "key", "rows", "parent_id"}
+	}
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(names)))
+	for _, name := range names {
+		retType := types.NewFieldType(mysql.TypeString)
+		if name == "rows" {
+			retType = types.NewFieldType(mysql.TypeLonglong)
+			retType.Flag |= mysql.UnsignedFlag
+		}
+		schema.Append(&expression.Column{
+			ColName: model.NewCIStr(name),
+			RetType: retType,
+		})
+	}
 	p.SetSchema(schema)
 	return p
 }
@@ -697,7 +859,7 @@ func getShowColNamesAndTypes(s *ast.ShowStmt) (names []string, ftypes []byte) {
 			mysql.TypeVarchar, mysql.TypeVarchar}
 	case ast.ShowColumns:
 		names = table.ColDescFieldNames(s.Full)
-	case ast.ShowWarnings:
+	case ast.ShowWarnings, ast.ShowErrors:
 		names = []string{"Level", "Code", "Message"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeLong, mysql.TypeVarchar}
 	case ast.ShowCharset:
@@ -713,6 +875,8 @@ func getShowColNamesAndTypes(s *ast.ShowStmt) (names []string, ftypes []byte) {
 		names = []string{"Table", "Create Table"}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
+	case ast.ShowCreateView:
+		names = []string{"View", "Create View", "character_set_client", "collation_connection"}
 	case ast.ShowGrants:
 		names = []string{fmt.Sprintf("Grants for %s", s.User)}
 	case ast.ShowIndex:
@@ -181,7 +181,6 @@ type Insert struct {
 	tableSchema expression.Schema
 	Columns     []*ast.ColumnName
 	Lists       [][]expression.Expression
-	Setlist     []*expression.Assignment
 	OnDuplicate []*expression.Assignment
 
 	IsReplace bool
@@ -193,11 +192,13 @@ type Insert struct {
 type LoadData struct {
 	basePlan
 
-	IsLocal    bool
-	Path       string
-	Table      *ast.TableName
-	FieldsInfo *ast.FieldsClause
-	LinesInfo  *ast.LinesClause
+	IsLocal     bool
+	Path        string
+	Table       *ast.TableName
+	Columns     []*ast.ColumnName
+	FieldsInfo  *ast.FieldsClause
+	LinesInfo   *ast.LinesClause
+	IgnoreLines uint64
 }
 
 // DDL represents a DDL statement plan.
@@ -207,9 +208,17 @@ type DDL struct {
 	Statement ast.DDLNode
 }
 
+// SelectInto represents a select into outfile plan, its child is the plan of the select statement.
+type SelectInto struct {
+	basePlan
+
+	IntoOpt *ast.SelectIntoOption
+}
+
 // Explain represents a explain plan.
 type Explain struct {
 	basePlan
 
 	StmtPlan Plan
+	Format   string
 }
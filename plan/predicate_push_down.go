@@ -315,7 +315,9 @@ func (p *Aggregation) PredicatePushDown(predicates []expression.Expression) (ret
 			ret = append(ret, cond)
 		case *expression.ScalarFunction:
 			extractedCols := expression.ExtractColumns(cond)
-			ok := true
+			// The super-aggregate rows of WITH ROLLUP have NULL in the group-by columns,
+			// so the conditions can't be evaluated before the aggregation.
+			ok := !p.WithRollup
 			for _, col := range extractedCols {
 				if p.getGbyColIndex(col) == -1 {
 					ok = false
@@ -372,3 +374,10 @@ func (p *Limit) PredicatePushDown(predicates []expression.Expression) ([]express
 	_, _, err := p.baseLogicalPlan.PredicatePushDown(nil)
 	return predicates, p, errors.Trace(err)
 }
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *Window) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan, error) {
+	// A condition filtering the rows below the window changes the rows numbered in a partition, so none is pushed down.
+	_, _, err := p.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, p, errors.Trace(err)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -164,12 +165,17 @@ func (r *rangeBuilder) buildFormBinOp(expr *expression.ScalarFunction) []rangePo
 		value = expr.Args[1].(*expression.Constant).Value
 		op = expr.FuncName.L
 	}
+	value = foldRangeValue(expr, value)
 	if value.IsNull() {
+		// Only "a <=> null" matches the null values, the other comparisons with null are never true.
+		if op == ast.NullEQ {
+			return []rangePoint{{start: true}, {}}
+		}
 		return nil
 	}
 
 	switch op {
-	case ast.EQ:
+	case ast.EQ, ast.NullEQ:
 		startPoint := rangePoint{value: value, start: true}
 		endPoint := rangePoint{value: value}
 		return []rangePoint{startPoint, endPoint}
@@ -248,8 +254,13 @@ func (r *rangeBuilder) newBuildFromIn(expr *expression.ScalarFunction) []rangePo
 			r.err = ErrUnsupportedType.Gen("expr:%v is not constant", e)
 			return fullRange
 		}
-		startPoint := rangePoint{value: types.NewDatum(v.Value.GetValue()), start: true}
-		endPoint := rangePoint{value: types.NewDatum(v.Value.GetValue())}
+		// NULL in the list never matches, because "a = NULL" is NULL.
+		if v.Value.IsNull() {
+			continue
+		}
+		value := foldRangeValue(expr, types.NewDatum(v.Value.GetValue()))
+		startPoint := rangePoint{value: value, start: true}
+		endPoint := rangePoint{value: value}
 		rangePoints = append(rangePoints, startPoint, endPoint)
 	}
 	sorter := rangePointSorter{points: rangePoints, sc: r.sc}
@@ -297,6 +308,13 @@ func (r *rangeBuilder) newBuildFromPatternLike(expr *expression.ScalarFunction)
 	}
 	lowValue := make([]byte, 0, len(pattern))
 	escape := byte(expr.Args[2].(*expression.Constant).Value.GetInt64())
+	if expression.CaseInsensitiveCompare(expr.Args[:2]) {
+		// The index keys of the column are folded, see foldRangeValue.
+		pattern = charset.FoldCase(pattern)
+		if escape >= 'a' && escape <= 'z' {
+			escape = escape - 'a' + 'A'
+		}
+	}
 	var exclude bool
 	isExactMatch := true
 	for i := 0; i < len(pattern); i++ {
@@ -376,7 +394,7 @@ func (r *rangeBuilder) buildFromNot(expr *expression.ScalarFunction) []rangePoin
 
 func (r *rangeBuilder) buildFromScalarFunc(expr *expression.ScalarFunction) []rangePoint {
 	switch op := expr.FuncName.L; op {
-	case ast.GE, ast.GT, ast.LT, ast.LE, ast.EQ, ast.NE:
+	case ast.GE, ast.GT, ast.LT, ast.LE, ast.EQ, ast.NullEQ, ast.NE:
 		return r.buildFormBinOp(expr)
 	case ast.AndAnd:
 		return r.intersection(r.build(expr.Args[0]), r.build(expr.Args[1]))
@@ -612,3 +630,15 @@ func (r *rangeBuilder) buildTableRanges(rangePoints []rangePoint) []TableRange {
 	}
 	return tableRanges
 }
+
+// foldRangeValue folds the string value of a case-insensitive comparison, because the
+// index keys of a column with a case-insensitive collation are encoded from the folded strings.
+func foldRangeValue(expr *expression.ScalarFunction, value types.Datum) types.Datum {
+	switch value.Kind() {
+	case types.KindString, types.KindBytes:
+		if expression.CaseInsensitiveCompare(expr.Args) {
+			return types.NewStringDatum(charset.FoldCase(value.GetString()))
+		}
+	}
+	return value
+}
@@ -89,11 +89,11 @@ func refineRangeDatum(v *types.Datum, ic *model.IndexColumn) {
 	}
 }
 
-// getEQFunctionOffset judge if the expression is a eq function like A = 1 where a is an index.
+// getEQFunctionOffset judge if the expression is a eq function like A = 1 or A <=> 1 where a is an index.
 // If so, it will return the offset of A in index columns. e.g. for index(C,B,A), A's offset is 2.
 func getEQFunctionOffset(expr expression.Expression, cols []*model.IndexColumn) int {
 	f, ok := expr.(*expression.ScalarFunction)
-	if !ok || f.FuncName.L != ast.EQ {
+	if !ok || (f.FuncName.L != ast.EQ && f.FuncName.L != ast.NullEQ) || isBinaryCompareOnFoldedKeys(f.Args) {
 		return -1
 	}
 	if c, ok := f.Args[0].(*expression.Column); ok {
@@ -332,14 +332,50 @@ func (c *conditionChecker) findEqOrInFunc(conditions []expression.Expression) in
 			return i
 		}
 	}
+	for i, cond := range conditions {
+		if or, ok := cond.(*expression.ScalarFunction); ok &&
+			or.FuncName.L == ast.OrOr && c.isEQList(or) {
+			return i
+		}
+	}
 	return -1
 }
 
+// isBinaryCompareOnFoldedKeys checks if a column in args has a case-insensitive collation, whose index keys
+// are encoded from the folded strings, but the strings are compared as binary strings, e.g. "a = binary 'x'".
+// Such a comparison can't lead to a range.
+func isBinaryCompareOnFoldedKeys(args []expression.Expression) bool {
+	for _, arg := range args {
+		if col, ok := arg.(*expression.Column); ok && col.RetType != nil && types.IsCaseInsensitiveStr(col.RetType) {
+			return !expression.CaseInsensitiveCompare(args)
+		}
+	}
+	return false
+}
+
+// isEQList checks if the condition is like "a = 1 or a = 2" on the checked column, which is the same as "a in (1, 2)".
+func (c *conditionChecker) isEQList(expr expression.Expression) bool {
+	f, ok := expr.(*expression.ScalarFunction)
+	if !ok {
+		return false
+	}
+	switch f.FuncName.L {
+	case ast.OrOr:
+		return c.isEQList(f.Args[0]) && c.isEQList(f.Args[1])
+	case ast.EQ, ast.NullEQ:
+		return getEQFunctionOffset(f, c.idx.Columns) == c.columnOffset
+	}
+	return false
+}
+
 func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction) bool {
 	switch scalar.FuncName.L {
 	case ast.OrOr, ast.AndAnd:
 		return c.check(scalar.Args[0]) && c.check(scalar.Args[1])
-	case ast.EQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT:
+	case ast.EQ, ast.NullEQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT:
+		if isBinaryCompareOnFoldedKeys(scalar.Args) {
+			return false
+		}
 		if _, ok := scalar.Args[0].(*expression.Constant); ok {
 			return c.checkColumn(scalar.Args[1])
 		}
@@ -350,8 +386,9 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 		return c.checkColumn(scalar.Args[0])
 	case ast.UnaryNot:
 		// TODO: support "not like" and "not in" convert to access conditions.
+		// "not a <=> 1" matches the null values, it has no opposite operator to lead to a range.
 		if s, ok := scalar.Args[0].(*expression.ScalarFunction); ok {
-			if s.FuncName.L == ast.In || s.FuncName.L == ast.Like {
+			if s.FuncName.L == ast.In || s.FuncName.L == ast.Like || s.FuncName.L == ast.NullEQ {
 				return false
 			}
 		} else {
@@ -360,7 +397,7 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 		}
 		return c.check(scalar.Args[0])
 	case ast.In:
-		if !c.checkColumn(scalar.Args[0]) {
+		if !c.checkColumn(scalar.Args[0]) || isBinaryCompareOnFoldedKeys(scalar.Args) {
 			return false
 		}
 		for _, v := range scalar.Args[1:] {
@@ -376,7 +413,7 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 }
 
 func (c *conditionChecker) checkLikeFunc(scalar *expression.ScalarFunction) bool {
-	if !c.checkColumn(scalar.Args[0]) {
+	if !c.checkColumn(scalar.Args[0]) || isBinaryCompareOnFoldedKeys(scalar.Args[:2]) {
 		return false
 	}
 	pattern, ok := scalar.Args[1].(*expression.Constant)
@@ -75,6 +75,17 @@ func (p *Sort) ResolveIndicesAndCorCols() {
 	}
 }
 
+// ResolveIndicesAndCorCols implements LogicalPlan interface.
+func (p *Window) ResolveIndicesAndCorCols() {
+	p.baseLogicalPlan.ResolveIndicesAndCorCols()
+	for _, expr := range p.PartitionBy {
+		expr.ResolveIndices(p.GetChildByIndex(0).GetSchema())
+	}
+	for _, item := range p.OrderBy {
+		item.Expr.ResolveIndices(p.GetChildByIndex(0).GetSchema())
+	}
+}
+
 // ResolveIndicesAndCorCols implements LogicalPlan interface.
 func (p *Apply) ResolveIndicesAndCorCols() {
 	p.baseLogicalPlan.ResolveIndicesAndCorCols()
@@ -15,6 +15,7 @@ package plan
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/ast"
@@ -58,6 +59,10 @@ type nameResolver struct {
 	Err             error
 	useOuterContext bool
 
+	// coalescedFields are the result fields of join using columns that are merged into the other side,
+	// they don't make the column name ambiguous.
+	coalescedFields map[*ast.ResultField]bool
+
 	contextStack []*resolverContext
 }
 
@@ -102,7 +107,7 @@ type resolverContext struct {
 	useOuterContext bool
 	// When visiting multi-table delete stmt table list.
 	inDeleteTableList bool
-	// When visiting create/drop table statement.
+	// When visiting create/drop table statement, or the name of a view in create view statement.
 	inCreateOrDropTable bool
 	// When visiting show statement.
 	inShow bool
@@ -169,11 +174,23 @@ func (nr *nameResolver) Enter(inNode ast.Node) (outNode ast.Node, skipChildren b
 				return inNode, true
 			}
 		}
+	case *ast.ColumnOption:
+		// The columns in a check expression are resolved against the table definition by DDL.
+		if v.Tp == ast.ColumnOptionCheck {
+			return inNode, true
+		}
+	case *ast.Constraint:
+		if v.Tp == ast.ConstraintCheck {
+			return inNode, true
+		}
 	case *ast.CreateIndexStmt:
 		nr.pushContext()
 	case *ast.CreateTableStmt:
 		nr.pushContext()
 		nr.currentContext().inCreateOrDropTable = true
+	case *ast.CreateViewStmt:
+		nr.pushContext()
+		nr.currentContext().inCreateOrDropTable = true
 	case *ast.DeleteStmt:
 		nr.pushContext()
 	case *ast.DeleteTableList:
@@ -249,6 +266,8 @@ func (nr *nameResolver) Leave(inNode ast.Node) (node ast.Node, ok bool) {
 		nr.popContext()
 	case *ast.CreateTableStmt:
 		nr.popContext()
+	case *ast.CreateViewStmt:
+		nr.popContext()
 	case *ast.DeleteTableList:
 		nr.currentContext().inDeleteTableList = false
 	case *ast.DoStmt:
@@ -341,7 +360,7 @@ func (nr *nameResolver) handleTableName(tn *ast.TableName) {
 	if ctx.inDeleteTableList {
 		idx, ok := ctx.tableMap[nr.tableUniqueName(tn.Schema, tn.Name)]
 		if !ok {
-			nr.Err = errors.Errorf("Unknown table %s", tn.Name.O)
+			nr.Err = ErrUnknownTable.GenByArgs(tn.Name.O, "MULTI DELETE")
 			return
 		}
 		ts := ctx.tables[idx]
@@ -419,8 +438,12 @@ func (nr *nameResolver) handleTableSource(ts *ast.TableSource) {
 			return
 		}
 		ctx.tableMap[name] = len(ctx.tables)
-	case *ast.SelectStmt:
+	case *ast.SelectStmt, *ast.UnionStmt:
 		name := ts.AsName.L
+		if name == "" {
+			nr.Err = ErrDerivedMustHaveAlias.GenByArgs()
+			return
+		}
 		if _, ok := ctx.derivedTableMap[name]; ok {
 			nr.Err = errors.Errorf("duplicated table/alias name %s", name)
 			return
@@ -436,7 +459,7 @@ func (nr *nameResolver) handleTableSource(ts *ast.TableSource) {
 			name = f.Column.Name.L
 		}
 		if _, ok := dupNames[name]; ok {
-			nr.Err = errors.Errorf("Duplicate column name '%s'", name)
+			nr.Err = infoschema.ErrColumnExists.GenByArgs(name)
 			return
 		}
 		dupNames[name] = struct{}{}
@@ -457,6 +480,35 @@ func (nr *nameResolver) handleJoin(j *ast.Join) {
 	copy(rfs, j.Left.GetResultFields())
 	copy(rfs[leftLen:], j.Right.GetResultFields())
 	j.SetResultFields(rfs)
+	if j.Using != nil {
+		nr.coalesceUsingColumns(j)
+	}
+}
+
+// coalesceUsingColumns marks the using columns of the inner side of the join as coalesced.
+// For right join, the inner side is the left one.
+func (nr *nameResolver) coalesceUsingColumns(j *ast.Join) {
+	if nr.coalescedFields == nil {
+		nr.coalescedFields = make(map[*ast.ResultField]bool)
+	}
+	rfs := j.Right.GetResultFields()
+	if j.Tp == ast.RightJoin {
+		rfs = j.Left.GetResultFields()
+	}
+	for _, col := range j.Using {
+		for _, rf := range rfs {
+			if !nr.coalescedFields[rf] && matchResultFieldName(rf, col.Name.L) {
+				nr.coalescedFields[rf] = true
+				break
+			}
+		}
+	}
+}
+
+func matchResultFieldName(rf *ast.ResultField, columnNameL string) bool {
+	matchAsName := rf.ColumnAsName.L != "" && rf.ColumnAsName.L == columnNameL
+	matchColumnName := rf.ColumnAsName.L == "" && rf.Column.Name.L == columnNameL
+	return matchAsName || matchColumnName
 }
 
 // handleColumnName looks up and sets ResultField for
@@ -480,7 +532,36 @@ func (nr *nameResolver) handleColumnName(cn *ast.ColumnNameExpr) {
 			return
 		}
 	}
-	nr.Err = errors.Errorf("unknown column %s", cn.Name.Name.L)
+	nr.Err = ErrUnknownColumn.GenByArgs(columnNameString(cn.Name), ctx.clauseName())
+}
+
+// clauseName returns the name of the clause being visited, it is used in error messages.
+func (ctx *resolverContext) clauseName() string {
+	switch {
+	case ctx.inOnCondition:
+		return "on clause"
+	case ctx.inHaving:
+		return "having clause"
+	case ctx.inOrderBy:
+		return "order clause"
+	case ctx.inGroupBy:
+		return "group statement"
+	case ctx.inFieldList:
+		return "field list"
+	}
+	return "where clause"
+}
+
+// columnNameString returns the column name as it is written in the statement.
+func columnNameString(cn *ast.ColumnName) string {
+	name := cn.Name.O
+	if cn.Table.O != "" {
+		name = cn.Table.O + "." + name
+	}
+	if cn.Schema.O != "" {
+		name = cn.Schema.O + "." + name
+	}
+	return name
 }
 
 // resolveColumnNameInContext looks up and sets ResultField for a column with the ctx.
@@ -580,7 +661,7 @@ func (nr *nameResolver) resolveColumnNameInOnCondition(cn *ast.ColumnNameExpr) {
 	join := ctx.joinNodeStack[len(ctx.joinNodeStack)-1]
 	tableSources := appendTableSources(nil, join)
 	if !nr.resolveColumnInTableSources(cn, tableSources) {
-		nr.Err = errors.Errorf("unknown column name %s", cn.Name.Name.O)
+		nr.Err = ErrUnknownColumn.GenByArgs(columnNameString(cn.Name), ctx.clauseName())
 	}
 }
 
@@ -622,11 +703,12 @@ func (nr *nameResolver) resolveColumnInTableSources(cn *ast.ColumnNameExpr, tabl
 		for _, ts := range tableSources {
 			rfs := ts.GetResultFields()
 			for _, rf := range rfs {
-				matchAsName := rf.ColumnAsName.L != "" && rf.ColumnAsName.L == columnNameL
-				matchColumnName := rf.ColumnAsName.L == "" && rf.Column.Name.L == columnNameL
-				if matchAsName || matchColumnName {
+				if nr.coalescedFields[rf] {
+					continue
+				}
+				if matchResultFieldName(rf, columnNameL) {
 					if matchedResultField != nil {
-						nr.Err = errors.Errorf("column %s is ambiguous.", cn.Name.Name.O)
+						nr.Err = ErrAmbiguous.GenByArgs(cn.Name.Name.O, nr.currentContext().clauseName())
 						return true
 					}
 					matchedResultField = rf
@@ -675,7 +757,7 @@ func (nr *nameResolver) resolveColumnInResultFields(ctx *resolverContext, cn *as
 			} else {
 				sameColumn := matched.TableName == rf.TableName && matched.Column.Name.L == rf.Column.Name.L
 				if !sameColumn {
-					nr.Err = errors.Errorf("column %s is ambiguous.", cn.Name.Name.O)
+					nr.Err = ErrAmbiguous.GenByArgs(cn.Name.Name.O, nr.currentContext().clauseName())
 					return true
 				}
 			}
@@ -727,7 +809,11 @@ func (nr *nameResolver) createResultFields(field *ast.SelectField) (rfs []*ast.R
 		tableRfs := []*ast.ResultField{}
 		if field.WildCard.Table.L == "" {
 			for _, v := range ctx.tables {
-				tableRfs = append(tableRfs, v.GetResultFields()...)
+				for _, rf := range v.GetResultFields() {
+					if !nr.coalescedFields[rf] {
+						tableRfs = append(tableRfs, rf)
+					}
+				}
 			}
 		} else {
 			name := nr.tableUniqueName(field.WildCard.Schema, field.WildCard.Table)
@@ -820,7 +906,7 @@ func (nr *nameResolver) tableUniqueName(schema, table model.CIStr) string {
 func (nr *nameResolver) handlePosition(pos *ast.PositionExpr) {
 	ctx := nr.currentContext()
 	if pos.N < 1 || pos.N > len(ctx.fieldList) {
-		nr.Err = errors.Errorf("Unknown column '%d'", pos.N)
+		nr.Err = ErrUnknownColumn.GenByArgs(strconv.Itoa(pos.N), ctx.clauseName())
 		return
 	}
 	matched := ctx.fieldList[pos.N-1]
@@ -835,7 +921,7 @@ func (nr *nameResolver) handlePosition(pos *ast.PositionExpr) {
 	if nr.currentContext().inGroupBy {
 		// make sure item is not aggregate function
 		if ast.HasAggFlag(pos.Refer.Expr) {
-			nr.Err = errors.New("group by cannot contain aggregate function")
+			nr.Err = ErrWrongGroupField.GenByArgs(pos.Refer.ColumnAsName.O)
 		}
 	}
 }
@@ -893,7 +979,7 @@ func (nr *nameResolver) fillShowFields(s *ast.ShowStmt) {
 			mysql.TypeVarchar, mysql.TypeVarchar}
 	case ast.ShowColumns:
 		names = table.ColDescFieldNames(s.Full)
-	case ast.ShowWarnings:
+	case ast.ShowWarnings, ast.ShowErrors:
 		names = []string{"Level", "Code", "Message"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeLong, mysql.TypeVarchar}
 	case ast.ShowCharset:
@@ -911,6 +997,8 @@ func (nr *nameResolver) fillShowFields(s *ast.ShowStmt) {
 		names = []string{"Table", "Create Table"}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
+	case ast.ShowCreateView:
+		names = []string{"View", "Create View", "character_set_client", "collation_connection"}
 	case ast.ShowGrants:
 		names = []string{fmt.Sprintf("Grants for %s", s.User)}
 	case ast.ShowTriggers:
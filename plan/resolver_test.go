@@ -14,6 +14,7 @@
 package plan_test
 
 import (
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/ast"
@@ -21,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/plan"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -78,6 +80,48 @@ var resolverTestCases = []resolverTestCase{
 	{"select c1 from t1 group by c1 having c1 = 3", true},
 	{"select c1 from t1 group by c1 having c2 = 3", false},
 	{"select c1 from t1 where exists (select c2)", true},
+	{"select t.c1 as x from t1 as t where t.c1 > 1 group by x having x > 1 order by x", true},
+	{"select t1.c1 from t1 as t", false},
+	{"select x.c1, y.c1 from t1 x join t1 y on x.c1 = y.c1", true},
+	{"select c1 as x from t1 where x > 1", false},
+}
+
+func (ts *testNameResolverSuite) TestNameResolverError(c *C) {
+	store, err := tidb.NewStore(tidb.EngineGoLevelDBMemory)
+	c.Assert(err, IsNil)
+	defer store.Close()
+	testKit := testkit.NewTestKit(c, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t1 (c1 int, c2 int)")
+	testKit.MustExec("create table t2 (c1 int, c3 int)")
+	ctx := testKit.Se.(context.Context)
+	domain := sessionctx.GetDomain(ctx)
+	ctx.GetSessionVars().CurrentDB = "test"
+	cases := []struct {
+		src string
+		err *terror.Error
+		msg string
+	}{
+		{"select t1.c1 from t1 as t", plan.ErrUnknownColumn, "Unknown column 't1.c1' in 'field list'"},
+		{"select c1 as x from t1 where x > 1", plan.ErrUnknownColumn, "Unknown column 'x' in 'where clause'"},
+		{"select c1 from t1 group by c3", plan.ErrUnknownColumn, "Unknown column 'c3' in 'group statement'"},
+		{"select c1 from t1 order by c3", plan.ErrUnknownColumn, "Unknown column 'c3' in 'order clause'"},
+		{"select c1 from t1 having c3 > 1", plan.ErrUnknownColumn, "Unknown column 'c3' in 'having clause'"},
+		{"select * from t1 join t2 on t1.c1 = t3.c1", plan.ErrUnknownColumn, "Unknown column 't3.c1' in 'on clause'"},
+		{"select c1 from t1, t2", plan.ErrAmbiguous, "Column 'c1' in field list is ambiguous"},
+		{"select t1.c1 from t1, t2 where c1 > 1", plan.ErrAmbiguous, "Column 'c1' in where clause is ambiguous"},
+		{"select t1.c1, t2.c1 from t1, t2 order by c1", plan.ErrAmbiguous, "Column 'c1' in order clause is ambiguous"},
+		{"select c1, c2 from t1 order by 3", plan.ErrUnknownColumn, "Unknown column '3' in 'order clause'"},
+		{"select c1 from t1 group by 0", plan.ErrUnknownColumn, "Unknown column '0' in 'group statement'"},
+		{"select c1, count(*) from t1 group by 2", plan.ErrWrongGroupField, "Can't group on 'count(*)'"},
+	}
+	for _, ca := range cases {
+		node, err := ts.ParseOneStmt(ca.src, "", "")
+		c.Assert(err, IsNil)
+		err = plan.ResolveName(node, domain.InfoSchema(), ctx)
+		c.Assert(terror.ErrorEqual(err, ca.err), IsTrue, Commentf("%s", ca.src))
+		c.Assert(errors.Cause(err).(*terror.Error).ToSQLError().Message, Equals, ca.msg, Commentf("%s", ca.src))
+	}
 }
 
 func (ts *testNameResolverSuite) TestNameResolver(c *C) {
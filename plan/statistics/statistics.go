@@ -192,6 +192,7 @@ type Table struct {
 	TS      int64 // build timestamp.
 	Columns []*Column
 	Count   int64 // Total row count in a table.
+	Pseudo  bool  // Pseudo is true if the table has never been analyzed.
 }
 
 // String implements Stringer interface.
@@ -230,6 +231,12 @@ func (t *Table) ToPB() (*TablePB, error) {
 
 // buildColumn builds column statistics from samples.
 func (t *Table) buildColumn(sc *variable.StatementContext, offset int, samples []types.Datum, bucketCount int64) error {
+	ci := t.info.Columns[offset]
+	if len(samples) == 0 {
+		// The table is empty, the column has no distinct value and no bucket.
+		t.Columns[offset] = &Column{ID: ci.ID}
+		return nil
+	}
 	err := types.SortDatums(sc, samples)
 	if err != nil {
 		return errors.Trace(err)
@@ -238,7 +245,6 @@ func (t *Table) buildColumn(sc *variable.StatementContext, offset int, samples [
 	if err != nil {
 		return errors.Trace(err)
 	}
-	ci := t.info.Columns[offset]
 	col := &Column{
 		ID:      ci.ID,
 		NDV:     estimatedNDV,
@@ -311,8 +317,12 @@ func estimateNDV(sc *variable.StatementContext, count int64, samples []types.Dat
 	return int64(estimatedDistinct), nil
 }
 
-// NewTable creates a table statistics.
+// NewTable creates a table statistics. The column samples are in the order of the table columns,
+// they are nil for an empty table.
 func NewTable(sc *variable.StatementContext, ti *model.TableInfo, ts, count, numBuckets int64, columnSamples [][]types.Datum) (*Table, error) {
+	if columnSamples == nil {
+		columnSamples = make([][]types.Datum, len(ti.Columns))
+	}
 	t := &Table{
 		info:    ti,
 		TS:      ts,
@@ -329,27 +339,36 @@ func NewTable(sc *variable.StatementContext, ti *model.TableInfo, ts, count, num
 }
 
 // TableFromPB creates a table statistics from protobuffer.
+// The column statistics are matched by column ID, so the statistics stay usable after the table
+// schema has changed, a column added after the table was analyzed gets pseudo statistics.
 func TableFromPB(ti *model.TableInfo, tpb *TablePB) (*Table, error) {
 	if tpb.GetId() != ti.ID {
 		return nil, errors.Errorf("table id not match, expected %d, got %d", ti.ID, tpb.GetId())
 	}
-	if len(tpb.Columns) != len(ti.Columns) {
-		return nil, errors.Errorf("column count not match, expected %d, got %d", len(ti.Columns), len(tpb.Columns))
-	}
-	for i := range ti.Columns {
-		if ti.Columns[i].ID != tpb.Columns[i].GetId() {
-			return nil, errors.Errorf("column ID not match, expected %d, got %d", ti.Columns[i].ID, tpb.Columns[i].GetId())
-		}
+	colPBs := make(map[int64]*ColumnPB, len(tpb.GetColumns()))
+	for _, cpb := range tpb.GetColumns() {
+		colPBs[cpb.GetId()] = cpb
 	}
 	t := &Table{info: ti}
 	t.TS = tpb.GetTs()
 	t.Count = tpb.GetCount()
-	t.Columns = make([]*Column, len(tpb.GetColumns()))
+	t.Columns = make([]*Column, len(ti.Columns))
 	for i, cInfo := range t.info.Columns {
-		cpb := tpb.Columns[i]
-		values, err := codec.Decode(cpb.GetValue(), 1)
-		if err != nil {
-			return nil, errors.Trace(err)
+		cpb, ok := colPBs[cInfo.ID]
+		if !ok {
+			t.Columns[i] = pseudoColumn(cInfo)
+			continue
+		}
+		// The column of an empty table has no bucket value.
+		var (
+			values []types.Datum
+			err    error
+		)
+		if len(cpb.GetValue()) > 0 {
+			values, err = codec.Decode(cpb.GetValue(), 1)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
 		c := &Column{
 			ID:      cpb.GetId(),
@@ -374,13 +393,17 @@ func PseudoTable(ti *model.TableInfo) *Table {
 	t := &Table{info: ti}
 	t.TS = pseudoTimestamp
 	t.Count = pseudoRowCount
+	t.Pseudo = true
 	t.Columns = make([]*Column, len(ti.Columns))
 	for i, v := range ti.Columns {
-		c := &Column{
-			ID:  v.ID,
-			NDV: pseudoRowCount / 2,
-		}
-		t.Columns[i] = c
+		t.Columns[i] = pseudoColumn(v)
 	}
 	return t
 }
+
+func pseudoColumn(ci *model.ColumnInfo) *Column {
+	return &Column{
+		ID:  ci.ID,
+		NDV: pseudoRowCount / 2,
+	}
+}
@@ -111,6 +111,54 @@ func (s *testStatisticsSuite) TestTable(c *C) {
 	c.Check(nt.String(), Equals, str)
 }
 
+func (s *testStatisticsSuite) TestEmptyTable(c *C) {
+	tblInfo := &model.TableInfo{ID: 1}
+	tblInfo.Columns = []*model.ColumnInfo{
+		{ID: 2, FieldType: *types.NewFieldType(mysql.TypeLonglong)},
+	}
+	sc := new(variable.StatementContext)
+	t, err := NewTable(sc, tblInfo, 10, 0, 256, nil)
+	c.Check(err, IsNil)
+	c.Check(t.Columns, HasLen, 1)
+	c.Check(t.Columns[0].NDV, Equals, int64(0))
+
+	tpb, err := t.ToPB()
+	c.Check(err, IsNil)
+	nt, err := TableFromPB(tblInfo, tpb)
+	c.Check(err, IsNil)
+	c.Check(nt.Count, Equals, int64(0))
+	c.Check(nt.String(), Equals, t.String())
+}
+
+func (s *testStatisticsSuite) TestTableFromPBSchemaChanged(c *C) {
+	tblInfo := &model.TableInfo{ID: 1}
+	tblInfo.Columns = []*model.ColumnInfo{
+		{ID: 2, FieldType: *types.NewFieldType(mysql.TypeLonglong)},
+	}
+	sc := new(variable.StatementContext)
+	t, err := NewTable(sc, tblInfo, 10, s.count, 256, [][]types.Datum{s.samples})
+	c.Check(err, IsNil)
+	tpb, err := t.ToPB()
+	c.Check(err, IsNil)
+
+	// A column is added after the table is analyzed.
+	newInfo := &model.TableInfo{ID: 1}
+	newInfo.Columns = []*model.ColumnInfo{
+		{ID: 3, FieldType: *types.NewFieldType(mysql.TypeLonglong)},
+		tblInfo.Columns[0],
+	}
+	nt, err := TableFromPB(newInfo, tpb)
+	c.Check(err, IsNil)
+	c.Check(nt.Columns, HasLen, 2)
+	c.Check(nt.Columns[0].ID, Equals, int64(3))
+	c.Check(nt.Columns[0].Numbers, HasLen, 0)
+	c.Check(nt.Columns[1].String(), Equals, t.Columns[0].String())
+
+	newInfo.ID = 2
+	_, err = TableFromPB(newInfo, tpb)
+	c.Check(err, NotNil)
+}
+
 func (s *testStatisticsSuite) TestPseudoTable(c *C) {
 	ti := &model.TableInfo{}
 	ti.Columns = append(ti.Columns, &model.ColumnInfo{
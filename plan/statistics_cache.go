@@ -0,0 +1,125 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/ngaut/log"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/plan/statistics"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+type statsCacheItem struct {
+	tableInfo *model.TableInfo
+	statsTbl  *statistics.Table
+}
+
+// statsCache caches the table statistics by table ID, so the statistics stored by ANALYZE TABLE
+// are only loaded from the KV store when their version changes.
+var statsCache = struct {
+	sync.RWMutex
+	items map[int64]statsCacheItem
+}{items: make(map[int64]statsCacheItem)}
+
+// SetStatisticsTableCache sets the statistics of the table in the cache, it is called after the
+// statistics are built.
+func SetStatisticsTableCache(tblInfo *model.TableInfo, statsTbl *statistics.Table) {
+	statsCache.Lock()
+	statsCache.items[tblInfo.ID] = statsCacheItem{tableInfo: tblInfo, statsTbl: statsTbl}
+	statsCache.Unlock()
+}
+
+// DropStatisticsTableCache removes the statistics of the table from the cache, it is called after the
+// table is dropped or truncated.
+func DropStatisticsTableCache(tableID int64) {
+	statsCache.Lock()
+	delete(statsCache.items, tableID)
+	statsCache.Unlock()
+}
+
+// GetStatisticsTable returns the statistics of the table, the pseudo statistics is returned if the
+// table has never been analyzed.
+func GetStatisticsTable(ctx context.Context, tblInfo *model.TableInfo) *statistics.Table {
+	m, err := statsMeta(ctx)
+	if err != nil {
+		log.Warnf("[plan] load statistics of table %s error: %v", tblInfo.Name.O, errors.ErrorStack(err))
+		return statistics.PseudoTable(tblInfo)
+	}
+	if m == nil {
+		return statistics.PseudoTable(tblInfo)
+	}
+	// The statistics may be updated by ANALYZE TABLE on another server, so the cached statistics
+	// is checked against the version in the KV store.
+	version, err := m.GetTableStatsVersion(tblInfo.ID)
+	if err != nil {
+		log.Warnf("[plan] load statistics version of table %s error: %v", tblInfo.Name.O, errors.ErrorStack(err))
+		return statistics.PseudoTable(tblInfo)
+	}
+	statsCache.RLock()
+	item, ok := statsCache.items[tblInfo.ID]
+	statsCache.RUnlock()
+	// The cached statistics is out of date if the table schema has changed or the table is analyzed again.
+	if ok && item.tableInfo == tblInfo && item.statsTbl.TS == version {
+		return item.statsTbl
+	}
+	statsTbl, err := loadStatisticsTable(m, tblInfo)
+	if err != nil {
+		log.Warnf("[plan] load statistics of table %s error: %v", tblInfo.Name.O, errors.ErrorStack(err))
+		return statistics.PseudoTable(tblInfo)
+	}
+	// The pseudo statistics isn't cached, or the statistics of a table analyzed later would be ignored.
+	if !statsTbl.Pseudo {
+		SetStatisticsTableCache(tblInfo, statsTbl)
+	}
+	return statsTbl
+}
+
+// statsMeta returns the Meta to read the statistics with, it's nil if there is no storage.
+func statsMeta(ctx context.Context) (*meta.Meta, error) {
+	if txn := ctx.Txn(); txn != nil {
+		return meta.NewMeta(txn), nil
+	}
+	// The rows of a SHOW statement are fetched after its transaction is committed, so the latest
+	// snapshot is used.
+	do := sessionctx.GetDomain(ctx)
+	if do == nil {
+		return nil, nil
+	}
+	ver, err := do.Store().CurrentVersion()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshot, err := do.Store().GetSnapshot(ver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta.NewSnapshotMeta(snapshot), nil
+}
+
+func loadStatisticsTable(m *meta.Meta, tblInfo *model.TableInfo) (*statistics.Table, error) {
+	tpb, err := m.GetTableStats(tblInfo.ID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tpb == nil {
+		return statistics.PseudoTable(tblInfo), nil
+	}
+	statsTbl, err := statistics.TableFromPB(tblInfo, tpb)
+	return statsTbl, errors.Trace(err)
+}
@@ -142,6 +142,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = "Distinct"
 	case *Trim:
 		str = "Trim"
+	case *Window:
+		str = "Window(" + x.FuncName + ")"
 	case *Cache:
 		str = "Cache"
 	default:
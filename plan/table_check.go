@@ -0,0 +1,117 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/juju/errors"
+	"github.com/pingcap/tidb/ast"
+	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/model"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// checkColumnResolver resolves the column names in a check expression to the columns of the table.
+type checkColumnResolver struct {
+	tblInfo *model.TableInfo
+	cols    []*model.ColumnInfo
+	err     error
+}
+
+// Enter implements ast.Visitor interface.
+func (r *checkColumnResolver) Enter(inNode ast.Node) (ast.Node, bool) {
+	switch v := inNode.(type) {
+	case *ast.ColumnNameExpr:
+		for _, col := range r.cols {
+			if col.Name.L == v.Name.Name.L {
+				v.Refer = &ast.ResultField{Column: col, Table: r.tblInfo}
+				return inNode, true
+			}
+		}
+		r.err = ErrUnknownColumn.GenByArgs(v.Name.Name.O, "check constraint")
+		return inNode, true
+	case *ast.SubqueryExpr:
+		r.err = ErrUnsupportedType.Gen("Unsupported subquery in check constraint")
+		return inNode, true
+	}
+	return inNode, false
+}
+
+// Leave implements ast.Visitor interface.
+func (r *checkColumnResolver) Leave(inNode ast.Node) (ast.Node, bool) {
+	return inNode, r.err == nil
+}
+
+// BuildTableChecks builds the expressions of the check constraints of the table, they are
+// evaluated on the rows of the public columns, in the same order as tblInfo.Checks.
+func BuildTableChecks(ctx context.Context, tblInfo *model.TableInfo) ([]expression.Expression, error) {
+	if len(tblInfo.Checks) == 0 {
+		return nil, nil
+	}
+	cols := make([]*model.ColumnInfo, 0, len(tblInfo.Columns))
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(tblInfo.Columns)))
+	for _, col := range tblInfo.Columns {
+		if col.State != model.StatePublic {
+			continue
+		}
+		schema.Append(&expression.Column{
+			ColName:  col.Name,
+			TblName:  tblInfo.Name,
+			RetType:  &col.FieldType,
+			Position: len(cols),
+			Index:    len(cols),
+		})
+		cols = append(cols, col)
+	}
+	mockTablePlan := &TableDual{}
+	mockTablePlan.SetSchema(schema)
+	b := &planBuilder{
+		ctx:       ctx,
+		colMapper: make(map[*ast.ColumnNameExpr]int),
+		allocator: new(idAllocator),
+	}
+
+	charset, collation := ctx.GetSessionVars().GetCharsetInfo()
+	exprs := make([]expression.Expression, 0, len(tblInfo.Checks))
+	for _, check := range tblInfo.Checks {
+		// The check expression is stored as text, it is parsed as a select field.
+		sql := "SELECT " + check.Expr
+		var stmts []ast.StmtNode
+		var err error
+		if sqlParser, ok := ctx.(sqlexec.SQLParser); ok {
+			stmts, err = sqlParser.ParseSQL(sql, charset, collation)
+		} else {
+			stmts, err = parser.New().Parse(sql, charset, collation)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		expr := stmts[0].(*ast.SelectStmt).Fields.Fields[0].Expr
+		resolver := &checkColumnResolver{tblInfo: tblInfo, cols: cols}
+		expr.Accept(resolver)
+		if resolver.err != nil {
+			return nil, errors.Trace(resolver.err)
+		}
+		if err = InferType(ctx.GetSessionVars().StmtCtx, expr); err != nil {
+			return nil, errors.Trace(err)
+		}
+		newExpr, _, err := b.rewrite(expr, mockTablePlan, nil, true)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		exprs = append(exprs, newExpr)
+	}
+	return exprs, nil
+}
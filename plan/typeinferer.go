@@ -43,6 +43,13 @@ type typeInferrer struct {
 }
 
 func (v *typeInferrer) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch x := in.(type) {
+	case *ast.ColumnOption:
+		// The columns in a check expression are not resolved in the CREATE TABLE statement.
+		return in, x.Tp == ast.ColumnOptionCheck
+	case *ast.Constraint:
+		return in, x.Tp == ast.ConstraintCheck
+	}
 	return in, false
 }
 
@@ -114,6 +121,12 @@ func (v *typeInferrer) Leave(in ast.Node) (out ast.Node, ok bool) {
 			v.err = err
 		}
 		x.Type.Collate = cln
+	case *ast.WindowFuncExpr:
+		ft := types.NewFieldType(mysql.TypeLonglong)
+		ft.Flen = 21
+		ft.Charset = charset.CharsetBin
+		ft.Collate = charset.CollationBin
+		x.SetType(ft)
 		// TODO: handle all expression types.
 	}
 	return in, true
@@ -147,6 +160,11 @@ func (v *typeInferrer) aggregateFunc(x *ast.AggregateFuncExpr) {
 		ft.Collate = charset.CollationBin
 		ft.Decimal = x.Args[0].GetType().Decimal
 		x.SetType(ft)
+	case ast.AggFuncStddevPop, ast.AggFuncStddevSamp, ast.AggFuncVarPop, ast.AggFuncVarSamp:
+		ft := types.NewFieldType(mysql.TypeDouble)
+		ft.Charset = charset.CharsetBin
+		ft.Collate = charset.CollationBin
+		x.SetType(ft)
 	case ast.AggFuncGroupConcat:
 		ft := types.NewFieldType(mysql.TypeVarString)
 		ft.Charset = v.defaultCharset
@@ -256,7 +274,9 @@ func (v *typeInferrer) handleFuncCallExpr(x *ast.FuncCallExpr) {
 		chs = charset.CharsetBin
 	)
 	switch x.FnName.L {
-	case "abs", "ifnull", "nullif":
+	case "ifnull", "coalesce":
+		tp = mergeResultType(x.Args)
+	case "abs", "nullif":
 		tp = x.Args[0].GetType()
 		// TODO: We should cover all types.
 		if x.FnName.L == "abs" && tp.Tp == mysql.TypeDatetime {
@@ -272,7 +292,7 @@ func (v *typeInferrer) handleFuncCallExpr(x *ast.FuncCallExpr) {
 				mergeArithType(tp.Tp, x.Args[i].GetType().Tp)
 			}
 		}
-	case "ceil", "ceiling":
+	case "ceil", "ceiling", "floor":
 		t := x.Args[0].GetType().Tp
 		if t == mysql.TypeNull || t == mysql.TypeFloat || t == mysql.TypeDouble || t == mysql.TypeVarchar ||
 			t == mysql.TypeTinyBlob || t == mysql.TypeMediumBlob || t == mysql.TypeLongBlob ||
@@ -281,6 +301,16 @@ func (v *typeInferrer) handleFuncCallExpr(x *ast.FuncCallExpr) {
 		} else {
 			tp = types.NewFieldType(mysql.TypeLonglong)
 		}
+	case "round":
+		t := x.Args[0].GetType().Tp
+		switch t {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+			tp = types.NewFieldType(mysql.TypeLonglong)
+		case mysql.TypeNewDecimal:
+			tp = types.NewFieldType(mysql.TypeNewDecimal)
+		default:
+			tp = types.NewFieldType(mysql.TypeDouble)
+		}
 	case "ln", "log", "log2", "log10":
 		tp = types.NewFieldType(mysql.TypeDouble)
 	case "pow", "power", "rand":
@@ -294,7 +324,7 @@ func (v *typeInferrer) handleFuncCallExpr(x *ast.FuncCallExpr) {
 		tp = types.NewFieldType(mysql.TypeDatetime)
 	case "microsecond", "second", "minute", "hour", "day", "week", "month", "year",
 		"dayofweek", "dayofmonth", "dayofyear", "weekday", "weekofyear", "yearweek",
-		"found_rows", "length", "extract", "locate":
+		"found_rows", "length", "extract", "locate", "datediff":
 		tp = types.NewFieldType(mysql.TypeLonglong)
 	case "now", "sysdate":
 		tp = types.NewFieldType(mysql.TypeDatetime)
@@ -320,16 +350,22 @@ func (v *typeInferrer) handleFuncCallExpr(x *ast.FuncCallExpr) {
 		tp = types.NewFieldType(mysql.TypeLonglong)
 		tp.Flag |= mysql.UnsignedFlag
 	case "if":
-		// TODO: fix this
 		// See https://dev.mysql.com/doc/refman/5.5/en/control-flow-functions.html#function_if
 		// The default return type of IF() (which may matter when it is stored into a temporary table) is calculated as follows.
 		// Expression	Return Value
 		// expr2 or expr3 returns a string	string
 		// expr2 or expr3 returns a floating-point value	floating-point
 		// expr2 or expr3 returns an integer	integer
-		tp = x.Args[1].GetType()
+		tp = mergeResultType(x.Args[1:])
 	case "get_lock", "release_lock":
 		tp = types.NewFieldType(mysql.TypeLonglong)
+	case "json_extract":
+		tp = types.NewFieldType(mysql.TypeJSON)
+	case "json_unquote":
+		// The unquoted string is compared in binary like the JSON text.
+		tp = types.NewFieldType(mysql.TypeVarString)
+		tp.Charset = charset.CharsetUTF8MB4
+		tp.Collate = "utf8mb4_bin"
 	default:
 		tp = types.NewFieldType(mysql.TypeUnspecified)
 	}
@@ -354,9 +390,22 @@ func (v *typeInferrer) handleFuncCallExpr(x *ast.FuncCallExpr) {
 // If used in a string context, the result is returned as a string.
 // If used in a numeric context, the result is returned as a decimal, real, or integer value.
 func (v *typeInferrer) handleCaseExpr(x *ast.CaseExpr) {
-	var currType types.FieldType
+	results := make([]ast.ExprNode, 0, len(x.WhenClauses)+1)
 	for _, w := range x.WhenClauses {
-		t := w.Result.GetType()
+		results = append(results, w.Result)
+	}
+	if x.ElseClause != nil {
+		results = append(results, x.ElseClause)
+	}
+	x.SetType(mergeResultType(results))
+}
+
+// mergeResultType returns the compatible aggregated type of the exprs, it is used
+// for the functions which return one of their arguments, like CASE, IF and COALESCE.
+func mergeResultType(exprs []ast.ExprNode) *types.FieldType {
+	var currType types.FieldType
+	for _, expr := range exprs {
+		t := expr.GetType()
 		if currType.Tp == mysql.TypeUnspecified {
 			currType = *t
 			continue
@@ -367,24 +416,10 @@ func (v *typeInferrer) handleCaseExpr(x *ast.CaseExpr) {
 			currType.Collate = t.Collate
 		}
 		currType.Tp = mtp
-
 	}
-	if x.ElseClause != nil {
-		t := x.ElseClause.GetType()
-		if currType.Tp == mysql.TypeUnspecified {
-			currType = *t
-		} else {
-			mtp := types.MergeFieldType(currType.Tp, t.Tp)
-			if mtp == t.Tp && mtp != currType.Tp {
-				currType.Charset = t.Charset
-				currType.Collate = t.Collate
-			}
-			currType.Tp = mtp
-		}
-	}
-	x.SetType(&currType)
 	// TODO: We need a better way to set charset/collation
-	x.Type.Charset, x.Type.Collate = types.DefaultCharsetForType(x.Type.Tp)
+	currType.Charset, currType.Collate = types.DefaultCharsetForType(currType.Tp)
+	return &currType
 }
 
 // like expression expects the target expression and pattern to be a string, if it's not, we add a cast function.
@@ -397,12 +432,17 @@ func (v *typeInferrer) handleLikeExpr(x *ast.PatternLikeExpr) {
 }
 
 // regexp expression expects the target expression and pattern to be a string, if it's not, we add a cast function.
+// Binary strings are kept, so the pattern is matched case-sensitively with them.
 func (v *typeInferrer) handleRegexpExpr(x *ast.PatternRegexpExpr) {
 	x.SetType(types.NewFieldType(mysql.TypeLonglong))
 	x.Type.Charset = charset.CharsetBin
 	x.Type.Collate = charset.CollationBin
-	x.Expr = v.addCastToString(x.Expr)
-	x.Pattern = v.addCastToString(x.Pattern)
+	if !types.IsBinaryStr(x.Expr.GetType()) {
+		x.Expr = v.addCastToString(x.Expr)
+	}
+	if !types.IsBinaryStr(x.Pattern.GetType()) {
+		x.Pattern = v.addCastToString(x.Pattern)
+	}
 }
 
 // AddCastToString adds a cast function to string type if the expr charset is not UTF8.
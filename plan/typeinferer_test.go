@@ -142,6 +142,12 @@ func (ts *testTypeInferrerSuite) TestInferType(c *C) {
 		{"rtrim('TiDB ')", mysql.TypeVarString, "utf8"},
 		{"connection_id()", mysql.TypeLonglong, charset.CharsetBin},
 		{"if(1>2, 2, 3)", mysql.TypeLonglong, charset.CharsetBin},
+		{"if(1>2, 2, 'tidb')", mysql.TypeVarchar, "utf8"},
+		{"if(1>2, 2, 1.1)", mysql.TypeNewDecimal, charset.CharsetBin},
+		{"ifnull(null, 1)", mysql.TypeLonglong, charset.CharsetBin},
+		{"ifnull(c1, 1.1)", mysql.TypeNewDecimal, charset.CharsetBin},
+		{"coalesce(null, 1, 'tidb')", mysql.TypeVarchar, "utf8"},
+		{"nullif(1, 2)", mysql.TypeLonglong, charset.CharsetBin},
 		{"case c1 when null then 2 when 2 then 1.1 else 1 END", mysql.TypeNewDecimal, charset.CharsetBin},
 		{"case c1 when null then 2 when 2 then 'tidb' else 1.1 END", mysql.TypeVarchar, "utf8"},
 		{"greatest(1, 2, 3)", mysql.TypeLonglong, charset.CharsetBin},
@@ -28,6 +28,7 @@ func (k keyType) String() string {
 // Checker is the interface for check privileges.
 type Checker interface {
 	// Check checks privilege.
+	// If db is nil, only check global scope privileges.
 	// If tbl is nil, only check global/db scope privileges.
 	// If tbl is not nil, check global/db/table scope privileges.
 	Check(ctx context.Context, db *model.DBInfo, tbl *model.TableInfo, privilege mysql.PrivilegeType) (bool, error)
@@ -35,6 +36,13 @@ type Checker interface {
 	ShowGrants(ctx context.Context, user string) ([]string, error)
 }
 
+// Tracer is implemented by a Checker which can explain how its last Check was resolved.
+type Tracer interface {
+	// Trace returns the result of each privilege scope visited by the last Check.
+	// It returns nil if privilege trace is disabled for the session.
+	Trace() []string
+}
+
 const key keyType = 0
 
 // BindPrivilegeChecker binds Checker to context.
@@ -39,7 +39,10 @@ var (
 	errInvalidUserNameFormat = terror.ClassPrivilege.New(codeInvalidUserNameFormat, "wrong username format")
 )
 
-var _ privilege.Checker = (*UserPrivileges)(nil)
+var (
+	_ privilege.Checker = (*UserPrivileges)(nil)
+	_ privilege.Tracer  = (*UserPrivileges)(nil)
+)
 
 type privileges struct {
 	Level ast.GrantLevelType
@@ -169,10 +172,12 @@ func (ps *userPrivileges) ShowGrants() []string {
 type UserPrivileges struct {
 	User  string
 	privs *userPrivileges
+	trace []string
 }
 
 // Check implements Checker.Check interface.
 func (p *UserPrivileges) Check(ctx context.Context, db *model.DBInfo, tbl *model.TableInfo, privilege mysql.PrivilegeType) (bool, error) {
+	p.trace = nil
 	if p.privs == nil {
 		// Lazy load
 		if len(p.User) == 0 {
@@ -189,32 +194,65 @@ func (p *UserPrivileges) Check(ctx context.Context, db *model.DBInfo, tbl *model
 			return false, errors.Trace(err)
 		}
 	}
+	tracing := ctx.GetSessionVars().PrivilegeTrace
 	// Check global scope privileges.
 	ok := p.privs.GlobalPrivs.contain(privilege)
+	if tracing {
+		p.addTrace("global", checkResult(privilege, ok))
+	}
 	if ok {
 		return true, nil
 	}
+	if db == nil {
+		return false, nil
+	}
 	// Check db scope privileges.
 	dbp, ok := p.privs.DBPrivs[db.Name.O]
 	if ok {
 		ok = dbp.contain(privilege)
+		if tracing {
+			p.addTrace(fmt.Sprintf("db '%s'", db.Name.O), checkResult(privilege, ok))
+		}
 		if ok {
 			return true, nil
 		}
+	} else if tracing {
+		p.addTrace(fmt.Sprintf("db '%s'", db.Name.O), "no entry")
 	}
 	if tbl == nil {
 		return false, nil
 	}
 	// Check table scope privileges.
-	dbTbl, ok := p.privs.TablePrivs[db.Name.O]
-	if !ok {
-		return false, nil
+	var tblp *privileges
+	if dbTbl, ok := p.privs.TablePrivs[db.Name.O]; ok {
+		tblp = dbTbl[tbl.Name.O]
+	}
+	ok = tblp != nil && tblp.contain(privilege)
+	if tracing {
+		tblScope := fmt.Sprintf("table '%s'.'%s'", db.Name.O, tbl.Name.O)
+		if tblp == nil {
+			p.addTrace(tblScope, "no entry")
+		} else {
+			p.addTrace(tblScope, checkResult(privilege, ok))
+		}
 	}
-	tblp, ok := dbTbl[tbl.Name.O]
-	if !ok {
-		return false, nil
+	return ok, nil
+}
+
+func checkResult(priv mysql.PrivilegeType, granted bool) string {
+	if granted {
+		return mysql.Priv2Str[priv] + " granted"
 	}
-	return tblp.contain(privilege), nil
+	return mysql.Priv2Str[priv] + " not granted"
+}
+
+func (p *UserPrivileges) addTrace(scope, result string) {
+	p.trace = append(p.trace, fmt.Sprintf("%s: %s", scope, result))
+}
+
+// Trace implements Tracer.Trace interface.
+func (p *UserPrivileges) Trace() []string {
+	return p.trace
 }
 
 func (p *UserPrivileges) loadPrivileges(ctx context.Context) error {
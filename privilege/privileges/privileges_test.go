@@ -266,6 +266,52 @@ func (s *testPrivilegeSuite) TestDropTablePriv(c *C) {
 	mustExec(c, se1, `DROP TABLE todrop;`)
 }
 
+func (s *testPrivilegeSuite) TestPrivilegeTrace(c *C) {
+	defer testleak.AfterTest(c)()
+	se := newSession(c, s.store, s.dbName)
+	ctx, _ := se.(context.Context)
+	mustExec(c, se, `CREATE TABLE totrace(c int);`)
+	ctx.GetSessionVars().User = "root@localhost"
+	mustExec(c, se, `CREATE USER 'trace'@'localhost' identified by '123';`)
+	mustExec(c, se, `GRANT Select ON test.* TO  'trace'@'localhost';`)
+	mustExec(c, se, `GRANT Select ON test.totrace TO  'trace'@'localhost';`)
+
+	// Without trace mode, the error does not carry the trace.
+	se1 := newSession(c, s.store, s.dbName)
+	ctx1, _ := se1.(context.Context)
+	ctx1.GetSessionVars().User = "trace@localhost"
+	_, err := se1.Execute("DROP TABLE totrace;")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Not(Matches), ".*Privilege trace.*")
+
+	mustExec(c, se1, `SET tidb_privilege_trace = 1;`)
+	_, err = se1.Execute("DROP TABLE totrace;")
+	c.Assert(err, NotNil)
+	expected := "[executor:1142]DROP command denied to user 'trace'@'localhost' for table 'totrace' Privilege trace: [" +
+		"global: Drop not granted; db 'test': Drop not granted; table 'test'.'totrace': Drop not granted]"
+	c.Assert(err.Error(), Equals, expected)
+	// Every privilege denial carries the trace.
+	_, err = se1.Execute("SELECT 1 INTO OUTFILE '/tmp/totrace.txt';")
+	c.Assert(err, NotNil)
+	expected = "[executor:1227]Access denied; you need (at least one of) the FILE privilege(s) for this operation " +
+		"Privilege trace: [global: File not granted]"
+	c.Assert(err.Error(), Equals, expected)
+
+	// The trace stops at the scope which grants the privilege.
+	pc := &privileges.UserPrivileges{}
+	db := &model.DBInfo{Name: model.NewCIStr("test")}
+	tbl := &model.TableInfo{Name: model.NewCIStr("other")}
+	r, err := pc.Check(ctx1, db, tbl, mysql.SelectPriv)
+	c.Assert(err, IsNil)
+	c.Assert(r, IsTrue)
+	c.Assert(pc.Trace(), DeepEquals, []string{"global: Select not granted", "db 'test': Select granted"})
+	r, err = pc.Check(ctx1, db, tbl, mysql.IndexPriv)
+	c.Assert(err, IsNil)
+	c.Assert(r, IsFalse)
+	c.Assert(pc.Trace(), DeepEquals, []string{"global: Index not granted", "db 'test': Index not granted",
+		"table 'test'.'other': no entry"})
+}
+
 func mustExec(c *C, se tidb.Session, sql string) {
 	_, err := se.Execute(sql)
 	c.Assert(err, IsNil)
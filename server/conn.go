@@ -42,6 +42,7 @@ import (
 	"net"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -49,6 +50,7 @@ import (
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/hack"
 )
@@ -75,6 +77,15 @@ type clientConn struct {
 	lastCmd      string            // latest sql query string, currently used for logging error.
 	ctx          IContext          // an interface to execute sql statements.
 	attrs        map[string]string // attributes parsed from client handshake response, not used for now.
+
+	// mu protects the process info which is read by other connections for show processlist.
+	mu struct {
+		sync.RWMutex
+		command   byte      // the command being handled.
+		info      string    // the sql statement being executed.
+		db        string    // current database name.
+		startTime time.Time // the start time of the command.
+	}
 }
 
 func (cc *clientConn) String() string {
@@ -302,6 +313,8 @@ func (cc *clientConn) readHandshakeResponse() error {
 		cc.Close()
 		return errors.Trace(err)
 	}
+	cc.ctx.SetValue(executor.SessionManagerKey, cc.server)
+	cc.setProcessInfo(mysql.ComSleep, "")
 	if !cc.server.skipAuth() {
 		// Do Auth
 		addr := cc.conn.RemoteAddr().String()
@@ -365,9 +378,16 @@ func (cc *clientConn) dispatch(data []byte) error {
 
 	token := cc.server.getToken()
 
+	var info string
+	if cmd == mysql.ComQuery {
+		info = cc.lastCmd
+	}
+	cc.setProcessInfo(cmd, info)
+
 	startTS := time.Now()
 	defer func() {
 		cc.server.releaseToken(token)
+		cc.setProcessInfo(mysql.ComSleep, "")
 		log.Debugf("[TIME_CMD] %v %d", time.Since(startTS), cmd)
 	}()
 
@@ -410,11 +430,48 @@ func (cc *clientConn) dispatch(data []byte) error {
 		return cc.handleStmtReset(data)
 	case mysql.ComSetOption:
 		return cc.handleSetOption(data)
+	case mysql.ComResetConnection:
+		return cc.handleResetConnection()
 	default:
 		return mysql.NewErrf(mysql.ErrUnknown, "command %d not supported now", cmd)
 	}
 }
 
+// setProcessInfo records the command being handled, it's called by the goroutine of the connection.
+func (cc *clientConn) setProcessInfo(cmd byte, info string) {
+	db := cc.ctx.CurrentDB()
+	cc.mu.Lock()
+	cc.mu.command = cmd
+	cc.mu.info = info
+	cc.mu.db = db
+	cc.mu.startTime = time.Now()
+	cc.mu.Unlock()
+}
+
+// processInfo returns the process info of the connection for show processlist.
+func (cc *clientConn) processInfo() util.ProcessInfo {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return util.ProcessInfo{
+		ID:      uint64(cc.connectionID),
+		User:    cc.user,
+		Host:    cc.conn.RemoteAddr().String(),
+		DB:      cc.mu.db,
+		Command: mysql.Command2Str[cc.mu.command],
+		Time:    cc.mu.startTime,
+		Info:    cc.mu.info,
+	}
+}
+
+// handleResetConnection clears the session state without closing the connection, the user stays authenticated.
+func (cc *clientConn) handleResetConnection() error {
+	if err := cc.ctx.ResetSession(); err != nil {
+		return errors.Trace(err)
+	}
+	cc.dbname = ""
+	return cc.writeOK()
+}
+
 func (cc *clientConn) useDB(db string) (err error) {
 	// if input is "use `SELECT`", mysql client just send "SELECT"
 	// so we add `` around db.
@@ -493,7 +550,7 @@ func (cc *clientConn) writeEOF(more bool) error {
 		if more {
 			status |= mysql.ServerMoreResultsExists
 		}
-		data = append(data, dumpUint16(cc.ctx.Status())...)
+		data = append(data, dumpUint16(status)...)
 	}
 
 	err := cc.writePacket(data)
@@ -592,6 +649,12 @@ func (cc *clientConn) handleQuery(sql string) (err error) {
 
 	rs, err := cc.ctx.Execute(sql)
 	if err != nil {
+		// The statements before the failed one are executed, send their results ahead of the error.
+		for _, r := range rs {
+			if err1 := cc.writeResultset(r, false, true); err1 != nil {
+				return errors.Trace(err1)
+			}
+		}
 		return errors.Trace(err)
 	}
 	if rs != nil {
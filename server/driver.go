@@ -54,7 +54,7 @@ type IContext interface {
 	// CurrentDB returns current DB.
 	CurrentDB() string
 
-	// Execute executes a SQL statement.
+	// Execute executes the SQL statements, the result sets of the executed statements are returned even if one fails.
 	Execute(sql string) ([]ResultSet, error)
 
 	// SetClientCapability sets client capability flags
@@ -69,9 +69,15 @@ type IContext interface {
 	// FieldList returns columns of a table.
 	FieldList(tableName string) (columns []*ColumnInfo, err error)
 
+	// ResetSession clears the session state like a new connection, the authenticated user is kept.
+	ResetSession() error
+
 	// Close closes the IContext.
 	Close() error
 
+	// Cancel cancels the executing statement.
+	Cancel()
+
 	// Auth verifies user's authentication.
 	Auth(user string, auth []byte, salt []byte) bool
 }
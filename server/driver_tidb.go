@@ -15,12 +15,14 @@ package server
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb"
 	"github.com/pingcap/tidb/ast"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -39,10 +41,8 @@ func NewTiDBDriver(store kv.Storage) *TiDBDriver {
 
 // TiDBContext implements IContext.
 type TiDBContext struct {
-	session      tidb.Session
-	currentDB    string
-	warningCount uint16
-	stmts        map[int]*TiDBStatement
+	session tidb.Session
+	stmts   map[int]*TiDBStatement
 }
 
 // TiDBStatement implements IStatement.
@@ -118,6 +118,7 @@ func (qd *TiDBDriver) OpenCtx(connID uint64, capability uint32, collation uint8,
 	}
 	session.SetClientCapability(capability)
 	session.SetConnectionID(connID)
+	setSessionCollation(session, collation)
 	if dbname != "" {
 		_, err = session.Execute("use " + dbname)
 		if err != nil {
@@ -125,13 +126,27 @@ func (qd *TiDBDriver) OpenCtx(connID uint64, capability uint32, collation uint8,
 		}
 	}
 	tc := &TiDBContext{
-		session:   session,
-		currentDB: dbname,
-		stmts:     make(map[int]*TiDBStatement),
+		session: session,
+		stmts:   make(map[int]*TiDBStatement),
 	}
 	return tc, nil
 }
 
+// setSessionCollation sets the connection charset and collation to the collation sent by the client
+// in the handshake, the session defaults are kept if the collation is unknown.
+func setSessionCollation(session tidb.Session, collation uint8) {
+	name, ok := mysql.Collations[collation]
+	if !ok {
+		return
+	}
+	for _, c := range charset.GetCollations() {
+		if c.Name == name {
+			session.GetSessionVars().SetCharsetInfo(c.CharsetName, c.Name)
+			return
+		}
+	}
+}
+
 // Status implements IContext Status method.
 func (tc *TiDBContext) Status() uint16 {
 	return tc.session.Status()
@@ -169,20 +184,17 @@ func (tc *TiDBContext) AffectedRows() uint64 {
 
 // CurrentDB implements IContext CurrentDB method.
 func (tc *TiDBContext) CurrentDB() string {
-	return tc.currentDB
+	return tc.session.GetSessionVars().CurrentDB
 }
 
 // WarningCount implements IContext WarningCount method.
 func (tc *TiDBContext) WarningCount() uint16 {
-	return tc.warningCount
+	return uint16(len(tc.session.GetSessionVars().StmtCtx.GetWarnings()))
 }
 
 // Execute implements IContext Execute method.
 func (tc *TiDBContext) Execute(sql string) (rs []ResultSet, err error) {
 	rsList, err := tc.session.Execute(sql)
-	if err != nil {
-		return
-	}
 	if len(rsList) == 0 { // result ok
 		return
 	}
@@ -200,11 +212,22 @@ func (tc *TiDBContext) SetClientCapability(flags uint32) {
 	tc.session.SetClientCapability(flags)
 }
 
+// ResetSession implements IContext ResetSession method.
+func (tc *TiDBContext) ResetSession() error {
+	tc.stmts = make(map[int]*TiDBStatement)
+	return tc.session.ResetSession()
+}
+
 // Close implements IContext Close method.
 func (tc *TiDBContext) Close() (err error) {
 	return tc.session.Close()
 }
 
+// Cancel implements IContext Cancel method.
+func (tc *TiDBContext) Cancel() {
+	atomic.StoreUint32(&tc.session.GetSessionVars().Killed, 1)
+}
+
 // Auth implements IContext Auth method.
 func (tc *TiDBContext) Auth(user string, auth []byte, salt []byte) bool {
 	return tc.session.Auth(user, auth, salt)
@@ -43,6 +43,7 @@ import (
 	"github.com/ngaut/log"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/prometheus/client_golang/prometheus"
@@ -71,6 +72,34 @@ type Server struct {
 	clients           map[uint32]*clientConn
 }
 
+// ShowProcessList implements the SessionManager interface.
+func (s *Server) ShowProcessList() []util.ProcessInfo {
+	s.rwlock.RLock()
+	rs := make([]util.ProcessInfo, 0, len(s.clients))
+	for _, client := range s.clients {
+		rs = append(rs, client.processInfo())
+	}
+	s.rwlock.RUnlock()
+	return rs
+}
+
+// Kill implements the SessionManager interface.
+func (s *Server) Kill(connectionID uint64, query bool) bool {
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	conn, ok := s.clients[uint32(connectionID)]
+	if !ok {
+		return false
+	}
+	conn.ctx.Cancel()
+	if !query {
+		// Closing the network connection makes the blocked read in Run return,
+		// then Run cleans up the connection.
+		conn.conn.Close()
+	}
+	return true
+}
+
 // ConnectionCount gets current connection count.
 func (s *Server) ConnectionCount() int {
 	var cnt int
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	. "github.com/pingcap/check"
@@ -551,6 +552,15 @@ func runTestMultiStatements(c *C) {
 		} else {
 			dbt.Error("no data")
 		}
+
+		// An error aborts the remaining statements.
+		_, err = dbt.db.Exec("UPDATE test SET value = 6 WHERE id = 1; UPDATE unknown SET value = 7; UPDATE test SET value = 8 WHERE id = 1;")
+		c.Assert(err, NotNil)
+		rows = dbt.mustQuery("SELECT value FROM test WHERE id = 1 AND ';' = ';'")
+		c.Assert(rows.Next(), IsTrue)
+		rows.Scan(&out)
+		c.Assert(out, Equals, 6)
+		rows.Close()
 	})
 }
 
@@ -585,6 +595,114 @@ func runTestStmtCount(t *C) {
 	})
 }
 
+func runTestShowProcessList(c *C) {
+	runTests(c, dsn, func(dbt *DBTest) {
+		// findProcess returns the Command and db columns of the process whose Info is info.
+		findProcess := func(query, info string) (string, string, bool) {
+			rows := dbt.mustQuery(query)
+			defer rows.Close()
+			for rows.Next() {
+				var (
+					id                         uint64
+					user, host, command, state string
+					db, processInfo            sql.NullString
+					time                       int64
+				)
+				err := rows.Scan(&id, &user, &host, &db, &command, &time, &state, &processInfo)
+				dbt.Assert(err, IsNil)
+				if processInfo.String == info {
+					dbt.Assert(user, Equals, "root")
+					return command, db.String, true
+				}
+			}
+			return "", "", false
+		}
+
+		query := "show full processlist /* " + strings.Repeat("x", 100) + " */"
+		command, db, ok := findProcess(query, query)
+		dbt.Assert(ok, IsTrue)
+		dbt.Assert(command, Equals, "Query")
+		dbt.Assert(db, Equals, "test")
+
+		// The Info column is truncated to 100 characters without FULL.
+		query = "show processlist /* " + strings.Repeat("x", 100) + " */"
+		_, _, ok = findProcess(query, query[:100])
+		dbt.Assert(ok, IsTrue)
+	})
+}
+
+func runTestCharset(c *C) {
+	checkCharset := func(dbt *DBTest, cs, co string) {
+		rows := dbt.mustQuery("select @@character_set_client, @@character_set_connection, @@character_set_results, @@collation_connection")
+		defer rows.Close()
+		dbt.Assert(rows.Next(), IsTrue)
+		var client, conn, results, collation string
+		err := rows.Scan(&client, &conn, &results, &collation)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(client, Equals, cs)
+		dbt.Assert(conn, Equals, cs)
+		dbt.Assert(results, Equals, cs)
+		dbt.Assert(collation, Equals, co)
+	}
+	// The charset and collation are taken from the collation sent in the handshake.
+	runTests(c, dsn+"&collation=latin1_bin", func(dbt *DBTest) {
+		dbt.db.SetMaxOpenConns(1)
+		checkCharset(dbt, "latin1", "latin1_bin")
+		dbt.mustExec("set names utf8mb4")
+		checkCharset(dbt, "utf8mb4", "utf8mb4_general_ci")
+	})
+	runTests(c, dsn+"&collation=utf8_general_ci", func(dbt *DBTest) {
+		checkCharset(dbt, "utf8", "utf8_general_ci")
+	})
+}
+
+func runTestKill(c *C) {
+	runTests(c, dsn, func(dbt *DBTest) {
+		db, err := sql.Open("mysql", dsn)
+		dbt.Assert(err, IsNil)
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		var connID, id uint64
+		err = db.QueryRow("select connection_id()").Scan(&connID)
+		dbt.Assert(err, IsNil)
+
+		// KILL QUERY leaves the connection intact.
+		dbt.mustExec(fmt.Sprintf("kill query %d", connID))
+		err = db.QueryRow("select connection_id()").Scan(&id)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(id, Equals, connID)
+
+		// KILL CONNECTION closes the connection.
+		dbt.mustExec(fmt.Sprintf("kill connection %d", connID))
+		closed := false
+		for i := 0; i < 100 && !closed; i++ {
+			closed = true
+			rows := dbt.mustQuery("show processlist")
+			for rows.Next() {
+				var (
+					user, host, command, state string
+					dbName, info               sql.NullString
+					elapsed                    int64
+				)
+				err = rows.Scan(&id, &user, &host, &dbName, &command, &elapsed, &state, &info)
+				dbt.Assert(err, IsNil)
+				if id == connID {
+					closed = false
+				}
+			}
+			rows.Close()
+			if !closed {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		dbt.Assert(closed, IsTrue)
+
+		_, err = dbt.db.Exec(fmt.Sprintf("kill %d", connID))
+		dbt.Assert(err, NotNil)
+		dbt.Assert(err.Error(), Equals, fmt.Sprintf("Error 1094: Unknown thread id: %d", connID))
+	})
+}
+
 func getMetrics(t *C) []byte {
 	resp, err := http.Get("http://127.0.0.1:10090/metrics")
 	t.Assert(err, IsNil)
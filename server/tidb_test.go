@@ -127,6 +127,18 @@ func (ts *TidbTestSuite) TestMultiStatements(c *C) {
 	runTestMultiStatements(c)
 }
 
+func (ts *TidbTestSuite) TestShowProcessList(c *C) {
+	runTestShowProcessList(c)
+}
+
+func (ts *TidbTestSuite) TestCharset(c *C) {
+	runTestCharset(c)
+}
+
+func (ts *TidbTestSuite) TestKill(c *C) {
+	runTestKill(c)
+}
+
 func (ts *TidbTestSuite) TestSocket(c *C) {
 	c.Parallel()
 	cfg := &Config{
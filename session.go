@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/juju/errors"
@@ -31,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/mysql"
@@ -43,6 +45,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessionctx/varsutil"
 	"github.com/pingcap/tidb/store/localstore"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/types"
@@ -52,11 +55,13 @@ import (
 // Session context
 type Session interface {
 	context.Context
-	Status() uint16                              // Flag of current status, such as autocommit.
-	LastInsertID() uint64                        // Last inserted auto_increment id.
-	AffectedRows() uint64                        // Affected rows by latest executed stmt.
-	Execute(sql string) ([]ast.RecordSet, error) // Execute a sql statement.
-	String() string                              // For debug
+	Status() uint16       // Flag of current status, such as autocommit.
+	LastInsertID() uint64 // Last inserted auto_increment id.
+	AffectedRows() uint64 // Affected rows by latest executed stmt.
+	// Execute executes the sql statements one by one. If a statement fails, the following statements are skipped,
+	// and the record sets of the statements executed before it are returned with the error.
+	Execute(sql string) ([]ast.RecordSet, error)
+	String() string // For debug
 	CommitTxn() error
 	RollbackTxn() error
 	// For execute prepare statement in binary protocol.
@@ -66,6 +71,8 @@ type Session interface {
 	DropPreparedStmt(stmtID uint32) error
 	SetClientCapability(uint32) // Set client capability flags.
 	SetConnectionID(uint64)
+	// ResetSession clears the session state to the defaults like COM_RESET_CONNECTION, the authenticated user is kept.
+	ResetSession() error
 	Close() error
 	Retry() error
 	Auth(user string, auth []byte, salt []byte) bool
@@ -121,6 +128,7 @@ type session struct {
 	parser    *parser.Parser
 
 	sessionVars *variable.SessionVars
+	planCache   *executor.PlanCache
 }
 
 func (s *session) cleanRetryInfo() {
@@ -191,6 +199,26 @@ func (s *session) SetConnectionID(connectionID uint64) {
 	s.sessionVars.ConnectionID = connectionID
 }
 
+// ResetSession rolls back the current transaction, drops the temporary tables, and clears the user variables, the prepared statements
+// and the current database. The system variables are reloaded from the global values like a new session,
+// only the authenticated user, the connection ID and the client capability are kept.
+func (s *session) ResetSession() error {
+	err := s.RollbackTxn()
+	if err1 := s.dropTemporaryTables(); err == nil {
+		err = err1
+	}
+	old := s.sessionVars
+	vars := variable.NewSessionVars()
+	vars.User = old.User
+	vars.AuthHost = old.AuthHost
+	vars.ConnectionID = old.ConnectionID
+	vars.ClientCapability = old.ClientCapability
+	vars.GlobalVarsAccessor = s
+	s.sessionVars = vars
+	s.planCache = nil
+	return errors.Trace(err)
+}
+
 func (s *session) doCommit() error {
 	if s.txn == nil || !s.txn.Valid() {
 		return nil
@@ -435,13 +463,42 @@ func (s *session) Execute(sql string) ([]ast.RecordSet, error) {
 	if err := s.checkSchemaValidOrRollback(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	// A KILL QUERY issued before this query only affects the statement executing at that time.
+	atomic.StoreUint32(&s.sessionVars.Killed, 0)
 	startTS := time.Now()
 	charset, collation := s.sessionVars.GetCharsetInfo()
 	connID := s.sessionVars.ConnectionID
-	rawStmts, err := s.ParseSQL(sql, charset, collation)
-	if err != nil {
-		log.Warnf("[%d] parse error:\n%v\n%s", connID, err, sql)
-		return nil, errors.Trace(err)
+	var (
+		rawStmts []ast.StmtNode
+		cached   *executor.CachedPlan
+		cacheKey string
+		err      error
+	)
+	cache := s.getPlanCache()
+	if cache != nil {
+		cacheKey = cache.Key(s, sql)
+	}
+	if cacheKey != "" {
+		// The cached plan is only valid for the schema of the current transaction.
+		if err = PrepareTxnCtx(s); err != nil {
+			return nil, errors.Trace(err)
+		}
+		cached = cache.Get(s, cacheKey)
+	}
+	if cached != nil {
+		rawStmts = []ast.StmtNode{cached.Stmt}
+	} else {
+		rawStmts, err = s.ParseSQL(sql, charset, collation)
+		if err != nil {
+			// A statement which can't be parsed replaces the diagnostics of the previous statement.
+			s.sessionVars.StmtCtx = new(variable.StatementContext)
+			s.sessionVars.StmtCtx.AppendError(err)
+			log.Warnf("[%d] parse error:\n%v\n%s", connID, err, sql)
+			return nil, errors.Trace(err)
+		}
+		if len(rawStmts) != 1 || !executor.IsPlanCacheable(rawStmts[0]) {
+			cacheKey = ""
+		}
 	}
 	sessionExecuteParseDuration.Observe(time.Since(startTS).Seconds())
 
@@ -450,12 +507,24 @@ func (s *session) Execute(sql string) ([]ast.RecordSet, error) {
 	for i, rst := range rawStmts {
 		startTS := time.Now()
 		// Some execution is done in compile stage, so we reset it before compile.
-		resetStmtCtx(s, rawStmts[0])
-		st, err1 := Compile(s, rst)
+		resetStmtCtx(s, rst)
+		var (
+			st   ast.Statement
+			err1 error
+		)
+		if cached != nil {
+			st = cached.Statement(s, sql)
+		} else {
+			st, err1 = Compile(s, rst)
+		}
 		if err1 != nil {
+			s.sessionVars.StmtCtx.AppendError(err1)
 			log.Warnf("[%d] compile error:\n%v\n%s", connID, err1, sql)
 			s.RollbackTxn()
-			return nil, errors.Trace(err1)
+			return s.multiResults(rs), errors.Trace(err1)
+		}
+		if cached == nil && cacheKey != "" {
+			cache.Put(s, cacheKey, rst, st)
 		}
 		sessionExecuteCompileDuration.Observe(time.Since(startTS).Seconds())
 
@@ -467,7 +536,7 @@ func (s *session) Execute(sql string) ([]ast.RecordSet, error) {
 		ph.EndStatement(s.stmtState)
 		if err != nil {
 			log.Warnf("[%d] session error:\n%v\n%s", connID, err, s)
-			return nil, errors.Trace(err)
+			return s.multiResults(rs), errors.Trace(err)
 		}
 		sessionExecuteRunDuration.Observe(time.Since(startTS).Seconds())
 		if r != nil {
@@ -475,11 +544,32 @@ func (s *session) Execute(sql string) ([]ast.RecordSet, error) {
 		}
 	}
 
+	return s.multiResults(rs), nil
+}
+
+// multiResults returns the record sets sent to the client.
+func (s *session) multiResults(rs []ast.RecordSet) []ast.RecordSet {
 	if s.sessionVars.ClientCapability&mysql.ClientMultiResults == 0 && len(rs) > 1 {
 		// return the first recordset if client doesn't support ClientMultiResults.
 		rs = rs[:1]
 	}
-	return rs, nil
+	return rs
+}
+
+// getPlanCache returns the plan cache of the session, it is nil if the plan cache is disabled.
+// The plan cache is also disabled when the session has temporary tables, because creating or dropping
+// a temporary table doesn't change the schema version which the cached plans are checked against.
+func (s *session) getPlanCache() *executor.PlanCache {
+	size := s.sessionVars.PlanCacheSize
+	if temps, ok := s.sessionVars.TemporaryTables.(*infoschema.TemporaryTables); ok && temps.Len() > 0 {
+		size = 0
+	}
+	if size == 0 {
+		s.planCache = nil
+	} else if s.planCache == nil || s.planCache.Capacity() != size {
+		s.planCache = executor.NewPlanCache(size)
+	}
+	return s.planCache
 }
 
 // For execute prepare statement in binary protocol
@@ -554,6 +644,7 @@ func (s *session) ExecutePreparedStmt(stmtID uint32, args ...interface{}) (ast.R
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	atomic.StoreUint32(&s.sessionVars.Killed, 0)
 	err = PrepareTxnCtx(s)
 	if err != nil {
 		s.RollbackTxn()
@@ -644,7 +735,31 @@ func (s *session) ClearValue(key fmt.Stringer) {
 
 // Close function does some clean work when session end.
 func (s *session) Close() error {
-	return s.RollbackTxn()
+	err := s.RollbackTxn()
+	if err1 := s.dropTemporaryTables(); err == nil {
+		err = err1
+	}
+	return errors.Trace(err)
+}
+
+// dropTemporaryTables deletes the data of the temporary tables created by the session.
+func (s *session) dropTemporaryTables() error {
+	temps, ok := s.sessionVars.TemporaryTables.(*infoschema.TemporaryTables)
+	if !ok || temps.Len() == 0 {
+		return nil
+	}
+	tbls := temps.Tables()
+	s.sessionVars.TemporaryTables = nil
+	err := kv.RunInNewTxn(s.store, true, func(txn kv.Transaction) error {
+		for _, tbl := range tbls {
+			err := util.DelKeyWithPrefix(txn, tablecodec.EncodeTablePrefix(tbl.Meta().ID))
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	})
+	return errors.Trace(err)
 }
 
 // GetSessionVars implements the context.Context interface.
@@ -652,19 +767,20 @@ func (s *session) GetSessionVars() *variable.SessionVars {
 	return s.sessionVars
 }
 
-func (s *session) getPassword(name, host string) (string, error) {
+// getPassword gets the password of the user, and the host of the matched account.
+func (s *session) getPassword(name, host string) (string, string, error) {
 	// Get password for name and host.
 	authSQL := fmt.Sprintf("SELECT Password FROM %s.%s WHERE User='%s' and Host='%s';", mysql.SystemDB, mysql.UserTable, name, host)
 	pwd, err := s.getExecRet(s, authSQL)
 	if err == nil {
-		return pwd, nil
+		return pwd, host, nil
 	} else if !terror.ExecResultIsEmpty.Equal(err) {
-		return "", errors.Trace(err)
+		return "", "", errors.Trace(err)
 	}
 	//Try to get user password for name with any host(%).
 	authSQL = fmt.Sprintf("SELECT Password FROM %s.%s WHERE User='%s' and Host='%%';", mysql.SystemDB, mysql.UserTable, name)
 	pwd, err = s.getExecRet(s, authSQL)
-	return pwd, errors.Trace(err)
+	return pwd, "%", errors.Trace(err)
 }
 
 func (s *session) Auth(user string, auth []byte, salt []byte) bool {
@@ -676,7 +792,7 @@ func (s *session) Auth(user string, auth []byte, salt []byte) bool {
 	// Get user password.
 	name := strs[0]
 	host := strs[1]
-	pwd, err := s.getPassword(name, host)
+	pwd, authHost, err := s.getPassword(name, host)
 	if err != nil {
 		if terror.ExecResultIsEmpty.Equal(err) {
 			log.Errorf("User [%s] not exist %v", name, err)
@@ -699,6 +815,7 @@ func (s *session) Auth(user string, auth []byte, salt []byte) bool {
 		return false
 	}
 	s.sessionVars.User = user
+	s.sessionVars.AuthHost = authHost
 	return true
 }
 
@@ -776,7 +893,7 @@ func createSession(store kv.Storage) (*session, error) {
 
 const (
 	notBootstrapped         = 0
-	currentBootstrapVersion = 3
+	currentBootstrapVersion = 6
 )
 
 func getStoreBootstrapVersion(store kv.Storage) int64 {
@@ -823,6 +940,9 @@ func finishBootstrap(store kv.Storage) {
 const loadCommonGlobalVarsSQL = "select * from mysql.global_variables where variable_name in ('" +
 	variable.AutocommitVar + "', '" +
 	variable.SQLModeVar + "', '" +
+	variable.GroupConcatMaxLen + "', '" +
+	variable.DivPrecisionIncrement + "', '" +
+	variable.ForeignKeyChecks + "', '" +
 	variable.DistSQLJoinConcurrencyVar + "', '" +
 	variable.DistSQLScanConcurrencyVar + "')"
 
@@ -20,8 +20,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/errors"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/context"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/plan"
@@ -859,6 +862,39 @@ func (s *testSessionSuite) TestSelectForUpdate(c *C) {
 	_, err = exec(se1, "commit")
 	c.Assert(err, IsNil)
 
+	// conflict for lock in share mode
+	mustExecSQL(c, se1, "begin")
+	rs, err = exec(se1, "select * from t where c1=11 lock in share mode")
+	c.Assert(err, IsNil)
+	_, err = GetRows(rs)
+	c.Assert(err, IsNil)
+
+	mustExecSQL(c, se2, "begin")
+	mustExecSQL(c, se2, "update t set c2=21 where c1=11")
+	mustExecSQL(c, se2, "commit")
+
+	_, err = exec(se1, "commit")
+	c.Assert(err, NotNil)
+	err = se1.Retry()
+	c.Assert(err, NotNil)
+
+	// locks are released by rollback
+	mustExecSQL(c, se1, "begin")
+	rs, err = exec(se1, "select * from t where c1=13 for update")
+	c.Assert(err, IsNil)
+	_, err = GetRows(rs)
+	c.Assert(err, IsNil)
+	mustExecSQL(c, se1, "rollback")
+
+	mustExecSQL(c, se2, "begin")
+	mustExecSQL(c, se2, "update t set c2=23 where c1=13")
+	mustExecSQL(c, se2, "commit")
+
+	mustExecSQL(c, se1, "begin")
+	mustExecSQL(c, se1, "update t set c3=33 where c1=13")
+	mustExecSQL(c, se1, "commit")
+	mustExecMatch(c, se1, "select c2, c3 from t where c1=13", [][]interface{}{{23, 33}})
+
 	// not conflict
 	mustExecSQL(c, se1, "begin")
 	rs, err = exec(se1, "select * from t where c1=11 for update")
@@ -1713,7 +1749,7 @@ func (s *testSessionSuite) TestBuiltin(c *C) {
 	se := newSession(c, store, s.dbName)
 
 	// Testcase for https://github.com/pingcap/tidb/issues/382
-	mustExecFailed(c, se, `select cast("xxx 10:10:10" as datetime)`)
+	mustExecMatch(c, se, `select cast("xxx 10:10:10" as datetime)`, [][]interface{}{{nil}})
 	mustExecMatch(c, se, "select locate('bar', 'foobarbar')", [][]interface{}{{4}})
 
 	err := store.Close()
@@ -2070,7 +2106,16 @@ func (s *testSessionSuite) TestSessionAuth(c *C) {
 	defer se.Close()
 	c.Assert(se.Auth("Any not exist username with zero password! @anyhost", []byte(""), []byte("")), IsFalse)
 
-	err := store.Close()
+	c.Assert(se.Auth("root@anyhost", []byte(""), []byte("")), IsTrue)
+	mustExecMatch(c, se, "select user(), current_user(), database()", [][]interface{}{{"root@anyhost", "root@%", s.dbName}})
+
+	// DATABASE() returns NULL when no schema is selected.
+	se1, err := CreateSession(store)
+	c.Assert(err, IsNil)
+	defer se1.Close()
+	mustExecMatch(c, se1, "select database(), version()", [][]interface{}{{nil, mysql.ServerVersion}})
+
+	err = store.Close()
 	c.Assert(err, IsNil)
 }
 
@@ -2480,3 +2525,232 @@ func (s *testSessionSuite) TestISColumns(c *C) {
 	sql := "select ORDINAL_POSITION from INFORMATION_SCHEMA.COLUMNS;"
 	mustExecSQL(c, se, sql)
 }
+
+func (s *testSessionSuite) TestPlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	mustExecSQL(c, se, "drop table if exists t_cache")
+	mustExecSQL(c, se, "create table t_cache (a int primary key, b int)")
+	mustExecSQL(c, se, "insert into t_cache values (1, 10), (2, 20)")
+	c.Assert(se.(*session).planCache, IsNil)
+
+	mustExecSQL(c, se, "set @@tidb_plan_cache_size = 2")
+	mustExecMatch(c, se, "select a, b from t_cache where a = 1", [][]interface{}{{1, 10}})
+	cache := se.(*session).planCache
+	c.Assert(cache.Len(), Equals, 1)
+	mustExecMatch(c, se, "select a, b  from t_cache /* comment */ where a=1", [][]interface{}{{1, 10}})
+	c.Assert(cache.Len(), Equals, 1)
+	mustExecMatch(c, se, "select a, b from t_cache where a = 2", [][]interface{}{{2, 20}})
+	c.Assert(cache.Len(), Equals, 2)
+	mustExecMatch(c, se, "select a, b from t_cache where a = 3", [][]interface{}{})
+	c.Assert(cache.Len(), Equals, 2)
+
+	// The plans depending on the values evaluated when optimizing are not cached.
+	mustExecMatch(c, se, "select a from t_cache where b = (select max(b) from t_cache)", [][]interface{}{{2}})
+	mustExecMatch(c, se, "select a from t_cache where b > @@div_precision_increment and a < year(now())", [][]interface{}{{1}, {2}})
+	c.Assert(cache.Len(), Equals, 2)
+
+	// The cached plan does not read the rows written in the current transaction.
+	mustExecSQL(c, se, "begin")
+	mustExecSQL(c, se, "insert into t_cache values (3, 30)")
+	mustExecMatch(c, se, "select a, b from t_cache where a = 3", [][]interface{}{{3, 30}})
+	mustExecSQL(c, se, "rollback")
+	mustExecMatch(c, se, "select a, b from t_cache where a = 3", [][]interface{}{})
+
+	// The cached plan is invalidated after the schema changes.
+	mustExecMatch(c, se, "select * from t_cache where a = 1", [][]interface{}{{1, 10}})
+	mustExecSQL(c, se, "alter table t_cache add column c int")
+	mustExecMatch(c, se, "select * from t_cache where a = 1", [][]interface{}{{1, 10, nil}})
+	mustExecSQL(c, se, "alter table t_cache drop column b")
+	mustExecFailed(c, se, "select a, b from t_cache where a = 1")
+
+	mustExecSQL(c, se, "set @@tidb_plan_cache_size = 0")
+	mustExecMatch(c, se, "select * from t_cache where a = 2", [][]interface{}{{2, nil}})
+	c.Assert(se.(*session).planCache, IsNil)
+	mustExecSQL(c, se, "drop table t_cache")
+}
+
+func (s *testSessionSuite) TestMultiStatements(c *C) {
+	defer testleak.AfterTest(c)()
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	se.SetClientCapability(mysql.ClientMultiStatements | mysql.ClientMultiResults)
+	mustExecSQL(c, se, "drop table if exists t_multi")
+	mustExecSQL(c, se, "create table t_multi (a int primary key, b varchar(20))")
+
+	// The semicolons in the quoted strings don't split the statements.
+	rs, err := se.Execute("insert into t_multi values (1, 'x;y'); select b from t_multi where b = ';'; select a, b from t_multi;")
+	c.Assert(err, IsNil)
+	c.Assert(rs, HasLen, 2)
+	rows, err := GetRows(rs[0])
+	c.Assert(err, IsNil)
+	matches(c, rows, [][]interface{}{})
+	rows, err = GetRows(rs[1])
+	c.Assert(err, IsNil)
+	matches(c, rows, [][]interface{}{{1, []byte("x;y")}})
+
+	// An error aborts the remaining statements, the record sets before it are returned.
+	rs, err = se.Execute("insert into t_multi values (2, 'a'); select a from t_multi where a = 2; insert into t_multi values (2, 'b'); insert into t_multi values (3, 'c')")
+	c.Assert(err, NotNil)
+	c.Assert(rs, HasLen, 1)
+	rows, err = GetRows(rs[0])
+	c.Assert(err, IsNil)
+	matches(c, rows, [][]interface{}{{2}})
+	mustExecMatch(c, se, "select a, b from t_multi", [][]interface{}{{1, []byte("x;y")}, {2, []byte("a")}})
+
+	// A failed statement in a transaction doesn't roll back the transaction.
+	_, err = se.Execute("begin; insert into t_multi values (3, 'c'); insert into t_multi values (3, 'd'); commit")
+	c.Assert(err, NotNil)
+	mustExecMatch(c, se, "select a from t_multi where a = 3", [][]interface{}{{3}})
+	mustExecSQL(c, se, "rollback")
+	mustExecMatch(c, se, "select count(*) from t_multi", [][]interface{}{{2}})
+
+	// Only the first record set is returned if the client doesn't support multiple results.
+	se.SetClientCapability(mysql.ClientMultiStatements)
+	rs, err = se.Execute("select 1; select 2")
+	c.Assert(err, IsNil)
+	c.Assert(rs, HasLen, 1)
+	mustExecSQL(c, se, "drop table t_multi")
+}
+
+func (s *testSessionSuite) TestSelectLockInShareMode(c *C) {
+	defer testleak.AfterTest(c)()
+	store := newStore(c, s.dbName)
+	se1 := newSession(c, store, s.dbName)
+	se2 := newSession(c, store, s.dbName)
+	mustExecSQL(c, se1, "drop table if exists t_share")
+	mustExecSQL(c, se1, "create table t_share (a int primary key, b int)")
+	mustExecSQL(c, se1, "insert t_share values (1, 1), (2, 2)")
+
+	// The shared locks don't conflict with each other.
+	mustExecSQL(c, se1, "begin")
+	mustExecMatch(c, se1, "select b from t_share where a = 1 lock in share mode", [][]interface{}{{1}})
+	mustExecSQL(c, se2, "begin")
+	mustExecMatch(c, se2, "select b from t_share where a = 1 lock in share mode", [][]interface{}{{1}})
+	mustExecSQL(c, se1, "commit")
+	mustExecSQL(c, se2, "commit")
+
+	// The exclusive locks conflict.
+	mustExecSQL(c, se1, "begin")
+	mustExecMatch(c, se1, "select b from t_share where a = 1 for update", [][]interface{}{{1}})
+	mustExecSQL(c, se2, "begin")
+	mustExecMatch(c, se2, "select b from t_share where a = 1 for update", [][]interface{}{{1}})
+	mustExecSQL(c, se1, "commit")
+	_, err := exec(se2, "commit")
+	c.Assert(err, NotNil)
+
+	// The row locked in shared mode can't be modified by others before the commit.
+	mustExecSQL(c, se1, "begin")
+	mustExecMatch(c, se1, "select b from t_share where a = 1 lock in share mode", [][]interface{}{{1}})
+	mustExecSQL(c, se2, "update t_share set b = 10 where a = 1")
+	_, err = exec(se1, "commit")
+	c.Assert(err, NotNil)
+	c.Assert(se1.Retry(), NotNil)
+
+	// A row written after the shared lock is committed doesn't conflict.
+	mustExecSQL(c, se1, "begin")
+	mustExecMatch(c, se1, "select b from t_share where a = 2 lock in share mode", [][]interface{}{{2}})
+	mustExecSQL(c, se2, "begin")
+	mustExecSQL(c, se2, "update t_share set b = 20 where a = 2")
+	mustExecSQL(c, se1, "commit")
+	mustExecSQL(c, se2, "commit")
+
+	// The shared lock is upgraded to an exclusive one if the transaction writes the row.
+	mustExecSQL(c, se1, "begin")
+	mustExecMatch(c, se1, "select b from t_share where a = 2 lock in share mode", [][]interface{}{{20}})
+	mustExecSQL(c, se1, "update t_share set b = 21 where a = 2")
+	mustExecSQL(c, se2, "begin")
+	mustExecMatch(c, se2, "select b from t_share where a = 2 lock in share mode", [][]interface{}{{20}})
+	mustExecSQL(c, se1, "commit")
+	_, err = exec(se2, "commit")
+	c.Assert(err, NotNil)
+	mustExecMatch(c, se2, "select a, b from t_share", [][]interface{}{{1, 10}, {2, 21}})
+	mustExecSQL(c, se1, "drop table t_share")
+}
+
+func (s *testSessionSuite) TestResetSession(c *C) {
+	defer testleak.AfterTest(c)()
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	defer se.Close()
+	c.Assert(se.Auth("root@anyhost", []byte(""), []byte("")), IsTrue)
+	se.SetConnectionID(5)
+	mustExecSQL(c, se, "drop table if exists t_reset")
+	mustExecSQL(c, se, "create table t_reset (a int)")
+	mustExecSQL(c, se, "set @a = 1, sql_mode = '', autocommit = 0")
+	mustExecSQL(c, se, "prepare s from 'select 1'")
+	stmtID, _, _, err := se.PrepareStmt("select 1")
+	c.Assert(err, IsNil)
+	mustExecSQL(c, se, "insert t_reset values (1)")
+
+	c.Assert(se.ResetSession(), IsNil)
+	// The authenticated user and the connection are kept.
+	mustExecMatch(c, se, "select user(), connection_id(), database(), @a", [][]interface{}{{"root@anyhost", 5, nil, nil}})
+	mustExecMatch(c, se, "select @@sql_mode, @@autocommit", [][]interface{}{{"STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION", "ON"}})
+	_, err = exec(se, "execute s")
+	c.Assert(terror.ErrorEqual(err, executor.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+	_, err = se.ExecutePreparedStmt(stmtID)
+	c.Assert(err, NotNil)
+	// The uncommitted transaction is rolled back.
+	mustExecSQL(c, se, "use "+s.dbName)
+	mustExecMatch(c, se, "select count(*) from t_reset", [][]interface{}{{0}})
+	mustExecSQL(c, se, "drop table t_reset")
+}
+
+func (s *testSessionSuite) TestTemporaryTable(c *C) {
+	defer testleak.AfterTest(c)()
+	store := newStore(c, s.dbName)
+	se := newSession(c, store, s.dbName)
+	se1 := newSession(c, store, s.dbName)
+	defer se1.Close()
+	mustExecSQL(c, se, "drop table if exists t_temp")
+	mustExecSQL(c, se, "create table t_temp (a int)")
+	mustExecSQL(c, se, "insert t_temp values (1)")
+
+	// The temporary table shadows the base table with the same name.
+	mustExecSQL(c, se, "create temporary table t_temp (id int auto_increment primary key, b int, index idx_b(b)) auto_increment = 10")
+	mustExecSQL(c, se, "create temporary table if not exists t_temp (a int)")
+	_, err := exec(se, "create temporary table t_temp (a int)")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableExists), IsTrue, Commentf("err %v", err))
+	mustExecSQL(c, se, "insert t_temp (b) values (1), (2), (3)")
+	mustExecMatch(c, se, "select * from t_temp where b > 1", [][]interface{}{{11, 2}, {12, 3}})
+	mustExecMatch(c, se, "select b from t_temp use index (idx_b) where b < 3", [][]interface{}{{1}, {2}})
+	mustExecSQL(c, se, "update t_temp set b = 5 where id = 10")
+	mustExecSQL(c, se, "delete from t_temp where id = 11")
+	mustExecSQL(c, se, "begin")
+	mustExecSQL(c, se, "insert t_temp (b) values (6)")
+	mustExecSQL(c, se, "rollback")
+	mustExecMatch(c, se, "select * from t_temp", [][]interface{}{{10, 5}, {12, 3}})
+	_, err = exec(se, "alter table t_temp add column c int")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotSupportedYet), IsTrue, Commentf("err %v", err))
+	c.Assert(errors.Cause(err).(*terror.Error).ToSQLError().Code, Equals, uint16(mysql.ErrNotSupportedYet))
+
+	// The other sessions see the base table.
+	mustExecMatch(c, se1, "select * from t_temp", [][]interface{}{{1}})
+	mustExecSQL(c, se1, "insert t_temp values (2)")
+	mustExecMatch(c, se, "select count(*) from t_temp", [][]interface{}{{2}})
+
+	// Truncating the temporary table restarts the auto increment ID.
+	mustExecSQL(c, se, "truncate table t_temp")
+	mustExecSQL(c, se, "insert t_temp (b) values (7)")
+	mustExecMatch(c, se, "select * from t_temp", [][]interface{}{{1, 7}})
+
+	// DROP TEMPORARY TABLE only drops the temporary tables.
+	_, err = exec(se, "drop temporary table t_temp, t_temp")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableDropExists), IsTrue, Commentf("err %v", err))
+	mustExecMatch(c, se, "select * from t_temp", [][]interface{}{{1}, {2}})
+	mustExecSQL(c, se, "drop temporary table if exists t_temp")
+
+	// The temporary tables are dropped when the session is closed.
+	mustExecSQL(c, se, "create temporary table t_temp_closed (a int)")
+	mustExecSQL(c, se, "insert t_temp_closed values (1)")
+	_, err = exec(se1, "select * from t_temp_closed")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableNotExists), IsTrue, Commentf("err %v", err))
+	se.Close()
+	se = newSession(c, store, s.dbName)
+	_, err = exec(se, "select * from t_temp_closed")
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableNotExists), IsTrue, Commentf("err %v", err))
+	mustExecSQL(c, se, "drop table t_temp")
+	se.Close()
+}
@@ -42,6 +42,32 @@ func GetPrewriteValue(ctx context.Context, createIfNotExists bool) *binlog.Prewr
 	return v
 }
 
+// CopyPrewriteValue returns a copy of the binlog prewrite value, so the value can be restored when
+// the transaction is rolled back to a savepoint. The encoded rows are shared, but appending mutations
+// to either value doesn't change the other one.
+func CopyPrewriteValue(v *binlog.PrewriteValue) *binlog.PrewriteValue {
+	if v == nil {
+		return nil
+	}
+	nv := &binlog.PrewriteValue{
+		SchemaVersion: v.SchemaVersion,
+		Mutations:     make([]binlog.TableMutation, len(v.Mutations)),
+	}
+	for i, m := range v.Mutations {
+		// The capacity of the slices is limited, so the next append always allocates a new array.
+		nv.Mutations[i] = binlog.TableMutation{
+			TableId:      m.TableId,
+			InsertedRows: m.InsertedRows[:len(m.InsertedRows):len(m.InsertedRows)],
+			UpdatedRows:  m.UpdatedRows[:len(m.UpdatedRows):len(m.UpdatedRows)],
+			DeletedIds:   m.DeletedIds[:len(m.DeletedIds):len(m.DeletedIds)],
+			DeletedPks:   m.DeletedPks[:len(m.DeletedPks):len(m.DeletedPks)],
+			DeletedRows:  m.DeletedRows[:len(m.DeletedRows):len(m.DeletedRows)],
+			Sequence:     m.Sequence[:len(m.Sequence):len(m.Sequence)],
+		}
+	}
+	return nv
+}
+
 // WriteBinlog writes a binlog to Pump.
 func WriteBinlog(bin *binlog.Binlog, clusterID uint64) error {
 	commitData, _ := bin.Marshal()
@@ -194,6 +194,34 @@ func (s *testBinlogSuite) TestBinlog(c *C) {
 		binlog.MutationType_Update,
 	})
 
+	// Test rollback to savepoint, the mutations made after the savepoint are discarded.
+	tk.MustExec("create table local_binlog5 (c1 int primary key)")
+	tk.MustExec("begin")
+	tk.MustExec("insert local_binlog5 values (1)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert local_binlog5 values (2)")
+	tk.MustExec("delete from local_binlog5 where c1 = 1")
+	tk.MustExec("rollback to savepoint s1")
+	tk.MustExec("insert local_binlog5 values (3)")
+	tk.MustExec("commit")
+	prewriteVal = getLatestBinlogPrewriteValue(c, pump)
+	c.Assert(prewriteVal.Mutations[0].Sequence, DeepEquals, []binlog.MutationType{
+		binlog.MutationType_Insert,
+		binlog.MutationType_Insert,
+	})
+	c.Assert(prewriteVal.Mutations[0].InsertedRows, HasLen, 2)
+	c.Assert(prewriteVal.Mutations[0].DeletedIds, HasLen, 0)
+
+	tk.MustExec("begin")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert local_binlog5 values (4)")
+	tk.MustExec("rollback to savepoint s1")
+	tk.MustExec("insert local_binlog5 values (5)")
+	tk.MustExec("commit")
+	prewriteVal = getLatestBinlogPrewriteValue(c, pump)
+	c.Assert(prewriteVal.Mutations[0].Sequence, DeepEquals, []binlog.MutationType{binlog.MutationType_Insert})
+	c.Assert(prewriteVal.Mutations[0].InsertedRows, HasLen, 1)
+
 	checkBinlogCount(c, pump)
 
 	pump.mu.Lock()
@@ -16,6 +16,7 @@ package variable
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/pingcap/tidb/mysql"
@@ -76,6 +77,7 @@ type TransactionContext struct {
 	Binlog        interface{}
 	InfoSchema    interface{}
 	Histroy       interface{}
+	Savepoints    interface{}
 	SchemaVersion int64
 }
 
@@ -106,13 +108,24 @@ type SessionVars struct {
 	// Connection ID
 	ConnectionID uint64
 
+	// Killed is a flag to indicate that the executing statement of this session is killed,
+	// it is set by the KILL statement from another session and must be accessed atomically.
+	Killed uint32
+
 	// Current user
 	User string
 
+	// AuthHost is the host of the account the user is authenticated as, it may be a pattern like %.
+	// CURRENT_USER() returns the account, while USER() returns the host the client connects from.
+	AuthHost string
+
 	// Current DB
 	CurrentDB string
 
-	// Strict SQL mode
+	// SQLMode is the sql_mode of the session.
+	SQLMode mysql.SQLMode
+
+	// Strict SQL mode, it's true if SQLMode has STRICT_TRANS_TABLES or STRICT_ALL_TABLES.
 	StrictSQLMode bool
 
 	// CommonGlobalLoaded indicates if common global variable has been loaded for this session.
@@ -128,6 +141,9 @@ type SessionVars struct {
 	// version, we load an old version schema for query.
 	SnapshotInfoschema interface{}
 
+	// TemporaryTables holds the temporary tables created by the session.
+	TemporaryTables interface{}
+
 	// SkipConstraintCheck is true when importing data.
 	SkipConstraintCheck bool
 
@@ -135,6 +151,24 @@ type SessionVars struct {
 	// Then if there are multiple TiDB servers, the new table may not be available for other TiDB servers.
 	SkipDDLWait bool
 
+	// PrivilegeTrace can be set to true to record how each privilege check is resolved,
+	// the trace is attached to the access denied error.
+	PrivilegeTrace bool
+
+	// GroupConcatMaxLen is the maximum length in bytes of the group_concat result.
+	GroupConcatMaxLen uint64
+
+	// DivPrecisionIncrement is the number of digits by which to increase the scale of the result
+	// of division operations performed with the / operator.
+	DivPrecisionIncrement int
+
+	// ForeignKeyChecks is true when the foreign key constraints are enforced on the written rows.
+	ForeignKeyChecks bool
+
+	// PlanCacheSize is the maximum number of statements kept in the session plan cache,
+	// the plan cache is disabled if it is 0.
+	PlanCacheSize int
+
 	// GlobalAccessor is used to set and get global variables.
 	GlobalVarsAccessor GlobalVarAccessor
 
@@ -149,15 +183,19 @@ type SessionVars struct {
 // NewSessionVars creates a session vars object.
 func NewSessionVars() *SessionVars {
 	return &SessionVars{
-		Users:                make(map[string]string),
-		Systems:              make(map[string]string),
-		PreparedStmts:        make(map[uint32]interface{}),
-		PreparedStmtNameToID: make(map[string]uint32),
-		TxnCtx:               &TransactionContext{},
-		RetryInfo:            &RetryInfo{},
-		StrictSQLMode:        true,
-		Status:               mysql.ServerStatusAutocommit,
-		StmtCtx:              new(StatementContext),
+		Users:                 make(map[string]string),
+		Systems:               make(map[string]string),
+		PreparedStmts:         make(map[uint32]interface{}),
+		PreparedStmtNameToID:  make(map[string]uint32),
+		TxnCtx:                &TransactionContext{},
+		RetryInfo:             &RetryInfo{},
+		SQLMode:               mysql.ModeStrictTransTables | mysql.ModeNoEngineSubstitution,
+		StrictSQLMode:         true,
+		GroupConcatMaxLen:     1024,
+		DivPrecisionIncrement: 4,
+		ForeignKeyChecks:      true,
+		Status:                mysql.ServerStatusAutocommit,
+		StmtCtx:               new(StatementContext),
 	}
 }
 
@@ -181,6 +219,15 @@ func (s *SessionVars) GetCharsetInfo() (charset, collation string) {
 	return
 }
 
+// SetCharsetInfo sets the charset and collation of the connection, like SET NAMES does.
+// The charset is used for character_set_client, character_set_connection and character_set_results.
+func (s *SessionVars) SetCharsetInfo(charset, collation string) {
+	for _, v := range SetNamesVariables {
+		s.Systems[v] = charset
+	}
+	s.Systems[collationConnection] = collation
+}
+
 // SetLastInsertID saves the last insert id to the session context.
 // TODO: we may store the result for last_insert_id sys var later.
 func (s *SessionVars) SetLastInsertID(insertID uint64) {
@@ -250,6 +297,8 @@ type StatementContext struct {
 	InUpdateStmt      bool
 	IgnoreTruncate    bool
 	TruncateAsWarning bool
+	// DividedByZeroAsError is true if division by zero is an error rather than a warning.
+	DividedByZeroAsError bool
 
 	/* Variables that changes during execution. */
 	mu struct {
@@ -257,7 +306,41 @@ type StatementContext struct {
 		affectedRows uint64
 		foundRows    uint64
 		warnings     []error
+		errors       []error
+		nowTs        time.Time
+		currentRow   uint64
+	}
+}
+
+// SetCurrentRow sets the 1-based number of the row which is being written by the statement.
+func (sc *StatementContext) SetCurrentRow(row uint64) {
+	sc.mu.Lock()
+	sc.mu.currentRow = row
+	sc.mu.Unlock()
+}
+
+// CurrentRow gets the number of the row which is being written by the statement, the warnings
+// of a row refer to it. It's 1 before the first row is set, like MySQL.
+func (sc *StatementContext) CurrentRow() uint64 {
+	sc.mu.Lock()
+	row := sc.mu.currentRow
+	sc.mu.Unlock()
+	if row == 0 {
+		return 1
+	}
+	return row
+}
+
+// NowTs gets the current timestamp of the statement. It is fixed at the first call,
+// so the time functions return the same value during the execution of a statement.
+func (sc *StatementContext) NowTs() time.Time {
+	sc.mu.Lock()
+	if sc.mu.nowTs.IsZero() {
+		sc.mu.nowTs = time.Now()
 	}
+	ts := sc.mu.nowTs
+	sc.mu.Unlock()
+	return ts
 }
 
 // AddAffectedRows adds affected rows.
@@ -312,3 +395,26 @@ func (sc *StatementContext) AppendWarning(warn error) {
 	sc.mu.warnings = append(sc.mu.warnings, warn)
 	sc.mu.Unlock()
 }
+
+// GetErrors gets the errors that failed the statement.
+func (sc *StatementContext) GetErrors() []error {
+	sc.mu.Lock()
+	errs := make([]error, len(sc.mu.errors))
+	copy(errs, sc.mu.errors)
+	sc.mu.Unlock()
+	return errs
+}
+
+// SetErrors sets errors.
+func (sc *StatementContext) SetErrors(errs []error) {
+	sc.mu.Lock()
+	sc.mu.errors = errs
+	sc.mu.Unlock()
+}
+
+// AppendError appends an error that failed the statement, it is shown by SHOW ERRORS and SHOW WARNINGS.
+func (sc *StatementContext) AppendError(err error) {
+	sc.mu.Lock()
+	sc.mu.errors = append(sc.mu.errors, err)
+	sc.mu.Unlock()
+}
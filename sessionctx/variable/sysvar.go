@@ -57,6 +57,7 @@ func GetSysVar(name string) *SysVar {
 const (
 	CodeUnknownStatusVar terror.ErrCode = 1
 	CodeUnknownSystemVar terror.ErrCode = 1193
+	CodeWrongValueForVar terror.ErrCode = 1231
 )
 
 var tidbSysVars map[string]bool
@@ -65,6 +66,8 @@ var tidbSysVars map[string]bool
 var (
 	UnknownStatusVar = terror.ClassVariable.New(CodeUnknownStatusVar, "unknown status variable")
 	UnknownSystemVar = terror.ClassVariable.New(CodeUnknownSystemVar, "unknown system variable '%s'")
+	// ErrWrongValueForVar is returned when a system variable is set to an invalid value.
+	ErrWrongValueForVar = terror.ClassVariable.New(CodeWrongValueForVar, mysql.MySQLErrName[mysql.ErrWrongValueForVar])
 )
 
 func init() {
@@ -76,6 +79,7 @@ func init() {
 	// Register terror to mysql error map.
 	mySQLErrCodes := map[terror.ErrCode]uint16{
 		CodeUnknownSystemVar: mysql.ErrUnknownSystemVariable,
+		CodeWrongValueForVar: mysql.ErrWrongValueForVar,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassVariable] = mySQLErrCodes
 
@@ -85,6 +89,8 @@ func init() {
 	tidbSysVars[TiDBSnapshot] = true
 	tidbSysVars[TiDBSkipConstraintCheck] = true
 	tidbSysVars[TiDBSkipDDLWait] = true
+	tidbSysVars[TiDBPrivilegeTrace] = true
+	tidbSysVars[TiDBPlanCacheSize] = true
 }
 
 // we only support MySQL now
@@ -272,7 +278,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, "collation_database", "latin1_swedish_ci"},
 	{ScopeGlobal | ScopeSession, "auto_increment_increment", "1"},
 	{ScopeGlobal | ScopeSession, "max_heap_table_size", "16777216"},
-	{ScopeGlobal | ScopeSession, "div_precision_increment", "4"},
+	{ScopeGlobal | ScopeSession, DivPrecisionIncrement, "4"},
 	{ScopeGlobal, "innodb_lru_scan_depth", "1024"},
 	{ScopeGlobal, "innodb_purge_rseg_truncate_frequency", ""},
 	{ScopeGlobal | ScopeSession, "sql_auto_is_null", "OFF"},
@@ -540,7 +546,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeNone, "back_log", "80"},
 	{ScopeNone, "lower_case_file_system", "ON"},
 	{ScopeGlobal, "rpl_semi_sync_master_wait_no_slave", ""},
-	{ScopeGlobal | ScopeSession, "group_concat_max_len", "1024"},
+	{ScopeGlobal | ScopeSession, GroupConcatMaxLen, "1024"},
 	{ScopeSession, "pseudo_thread_id", ""},
 	{ScopeNone, "socket", "/tmp/myssock"},
 	{ScopeNone, "have_dynamic_loading", "YES"},
@@ -593,6 +599,8 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, DistSQLJoinConcurrencyVar, "5"},
 	{ScopeSession, TiDBSkipConstraintCheck, "0"},
 	{ScopeSession, TiDBSkipDDLWait, "0"},
+	{ScopeSession, TiDBPrivilegeTrace, "0"},
+	{ScopeSession, TiDBPlanCacheSize, "0"},
 }
 
 // TiDB system variables
@@ -602,6 +610,8 @@ const (
 	DistSQLJoinConcurrencyVar = "tidb_distsql_join_concurrency"
 	TiDBSkipConstraintCheck   = "tidb_skip_constraint_check"
 	TiDBSkipDDLWait           = "tidb_skip_ddl_wait"
+	TiDBPrivilegeTrace        = "tidb_privilege_trace"
+	TiDBPlanCacheSize         = "tidb_plan_cache_size"
 )
 
 // SetNamesVariables is the system variable names related to set names statements.
@@ -618,6 +628,12 @@ const (
 	CharsetDatabase = "character_set_database"
 	// CollationDatabase is the name for collation_database system variable.
 	CollationDatabase = "collation_database"
+	// GroupConcatMaxLen is the name for group_concat_max_len system variable.
+	GroupConcatMaxLen = "group_concat_max_len"
+	// DivPrecisionIncrement is the name for div_precision_increment system variable.
+	DivPrecisionIncrement = "div_precision_increment"
+	// ForeignKeyChecks is the name for foreign_key_checks system variable.
+	ForeignKeyChecks = "foreign_key_checks"
 )
 
 // GlobalVarAccessor is the interface for accessing global scope system and status variables.
@@ -14,6 +14,7 @@
 package varsutil
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +37,10 @@ func GetSystemVar(s *variable.SessionVars, key string) types.Datum {
 			d.SetString(variable.SysVars[variable.TiDBSkipConstraintCheck].Value)
 		} else if key == variable.TiDBSkipDDLWait {
 			d.SetString(variable.SysVars[variable.TiDBSkipDDLWait].Value)
+		} else if key == variable.TiDBPrivilegeTrace {
+			d.SetString(variable.SysVars[variable.TiDBPrivilegeTrace].Value)
+		} else if key == variable.TiDBPlanCacheSize {
+			d.SetString(variable.SysVars[variable.TiDBPlanCacheSize].Value)
 		}
 	}
 	return d
@@ -60,12 +65,13 @@ func SetSystemVar(vars *variable.SessionVars, name string, value types.Datum) er
 	}
 	switch name {
 	case variable.SQLModeVar:
-		sVal = strings.ToUpper(sVal)
-		if strings.Contains(sVal, "STRICT_TRANS_TABLES") || strings.Contains(sVal, "STRICT_ALL_TABLES") {
-			vars.StrictSQLMode = true
-		} else {
-			vars.StrictSQLMode = false
+		mode, err := ParseSQLMode(sVal)
+		if err != nil {
+			return errors.Trace(err)
 		}
+		vars.SQLMode = mode
+		vars.StrictSQLMode = mode.HasStrictMode()
+		sVal = mode.String()
 	case variable.TiDBSnapshot:
 		err = setSnapshotTS(vars, sVal)
 		if err != nil {
@@ -81,11 +87,61 @@ func SetSystemVar(vars *variable.SessionVars, name string, value types.Datum) er
 		vars.SkipConstraintCheck = (sVal == "1")
 	case variable.TiDBSkipDDLWait:
 		vars.SkipDDLWait = (sVal == "1")
+	case variable.TiDBPrivilegeTrace:
+		vars.PrivilegeTrace = (sVal == "1")
+	case variable.GroupConcatMaxLen:
+		maxLen, err := strconv.ParseUint(sVal, 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		vars.GroupConcatMaxLen = maxLen
+	case variable.DivPrecisionIncrement:
+		incr, err := strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Like MySQL, the value is clipped to the range [0, 30].
+		if incr < 0 {
+			incr = 0
+		} else if incr > types.MaxFraction {
+			incr = types.MaxFraction
+		}
+		vars.DivPrecisionIncrement = int(incr)
+		sVal = strconv.FormatInt(incr, 10)
+	case variable.ForeignKeyChecks:
+		vars.ForeignKeyChecks = strings.EqualFold(sVal, "ON") || sVal == "1"
+	case variable.TiDBPlanCacheSize:
+		size, err := strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if size < 0 {
+			size = 0
+		}
+		vars.PlanCacheSize = int(size)
+		sVal = strconv.FormatInt(size, 10)
 	}
 	vars.Systems[name] = sVal
 	return nil
 }
 
+// ParseSQLMode parses the comma separated sql mode names, the names are case insensitive.
+func ParseSQLMode(sVal string) (mysql.SQLMode, error) {
+	mode := mysql.ModeNone
+	for _, name := range strings.Split(sVal, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		m, ok := mysql.Str2SQLMode[name]
+		if !ok {
+			return mode, variable.ErrWrongValueForVar.GenByArgs(variable.SQLModeVar, name)
+		}
+		mode |= m
+	}
+	return mode, nil
+}
+
 func setSnapshotTS(s *variable.SessionVars, sVal string) error {
 	if sVal == "" {
 		s.SnapshotTS = 0
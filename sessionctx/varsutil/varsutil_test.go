@@ -17,7 +17,9 @@ import (
 	"testing"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/types"
 )
@@ -46,6 +48,18 @@ func (s *testVarsutilSuite) TestVarsutil(c *C) {
 	c.Assert(v.StrictSQLMode, IsTrue)
 	SetSystemVar(v, "sql_mode", types.NewStringDatum(""))
 	c.Assert(v.StrictSQLMode, IsFalse)
+	c.Assert(v.SQLMode, Equals, mysql.ModeNone)
+	err := SetSystemVar(v, "sql_mode", types.NewStringDatum(" no_zero_date, Strict_All_Tables,"))
+	c.Assert(err, IsNil)
+	c.Assert(v.SQLMode, Equals, mysql.ModeNoZeroDate|mysql.ModeStrictAllTables)
+	c.Assert(v.StrictSQLMode, IsTrue)
+	val = GetSystemVar(v, "sql_mode")
+	c.Assert(val.GetString(), Equals, "STRICT_ALL_TABLES,NO_ZERO_DATE")
+	err = SetSystemVar(v, "sql_mode", types.NewStringDatum("ansi,bad_mode"))
+	c.Assert(terror.ErrorEqual(err, variable.ErrWrongValueForVar), IsTrue)
+	c.Assert(v.SQLMode, Equals, mysql.ModeNoZeroDate|mysql.ModeStrictAllTables)
+	val = GetSystemVar(v, "sql_mode")
+	c.Assert(val.GetString(), Equals, "STRICT_ALL_TABLES,NO_ZERO_DATE")
 
 	SetSystemVar(v, "character_set_connection", types.NewStringDatum("utf8"))
 	SetSystemVar(v, "collation_connection", types.NewStringDatum("utf8_general_ci"))
@@ -83,4 +97,58 @@ func (s *testVarsutilSuite) TestVarsutil(c *C) {
 	c.Assert(v.SkipDDLWait, IsTrue)
 	d = GetSystemVar(v, variable.TiDBSkipDDLWait)
 	c.Assert(d.GetString(), Equals, "1")
+
+	// Test case for tidb_privilege_trace session variable.
+	d = GetSystemVar(v, variable.TiDBPrivilegeTrace)
+	c.Assert(d.GetString(), Equals, "0")
+	c.Assert(v.PrivilegeTrace, IsFalse)
+	SetSystemVar(v, variable.TiDBPrivilegeTrace, types.NewStringDatum("1"))
+	c.Assert(v.PrivilegeTrace, IsTrue)
+	d = GetSystemVar(v, variable.TiDBPrivilegeTrace)
+	c.Assert(d.GetString(), Equals, "1")
+
+	// Test case for tidb_plan_cache_size session variable.
+	d = GetSystemVar(v, variable.TiDBPlanCacheSize)
+	c.Assert(d.GetString(), Equals, "0")
+	c.Assert(v.PlanCacheSize, Equals, 0)
+	SetSystemVar(v, variable.TiDBPlanCacheSize, types.NewStringDatum("100"))
+	c.Assert(v.PlanCacheSize, Equals, 100)
+	SetSystemVar(v, variable.TiDBPlanCacheSize, types.NewStringDatum("-1"))
+	c.Assert(v.PlanCacheSize, Equals, 0)
+	d = GetSystemVar(v, variable.TiDBPlanCacheSize)
+	c.Assert(d.GetString(), Equals, "0")
+	c.Assert(SetSystemVar(v, variable.TiDBPlanCacheSize, types.NewStringDatum("abc")), NotNil)
+
+	// Test case for group_concat_max_len variable.
+	c.Assert(v.GroupConcatMaxLen, Equals, uint64(1024))
+	err = SetSystemVar(v, variable.GroupConcatMaxLen, types.NewStringDatum("10"))
+	c.Assert(err, IsNil)
+	c.Assert(v.GroupConcatMaxLen, Equals, uint64(10))
+	err = SetSystemVar(v, variable.GroupConcatMaxLen, types.NewStringDatum("abc"))
+	c.Assert(err, NotNil)
+	c.Assert(v.GroupConcatMaxLen, Equals, uint64(10))
+
+	// Test case for div_precision_increment variable.
+	c.Assert(v.DivPrecisionIncrement, Equals, 4)
+	err = SetSystemVar(v, variable.DivPrecisionIncrement, types.NewIntDatum(8))
+	c.Assert(err, IsNil)
+	c.Assert(v.DivPrecisionIncrement, Equals, 8)
+	err = SetSystemVar(v, variable.DivPrecisionIncrement, types.NewIntDatum(40))
+	c.Assert(err, IsNil)
+	c.Assert(v.DivPrecisionIncrement, Equals, 30)
+	d = GetSystemVar(v, variable.DivPrecisionIncrement)
+	c.Assert(d.GetString(), Equals, "30")
+	err = SetSystemVar(v, variable.DivPrecisionIncrement, types.NewIntDatum(-1))
+	c.Assert(err, IsNil)
+	c.Assert(v.DivPrecisionIncrement, Equals, 0)
+	err = SetSystemVar(v, variable.DivPrecisionIncrement, types.NewStringDatum("abc"))
+	c.Assert(err, NotNil)
+	c.Assert(v.DivPrecisionIncrement, Equals, 0)
+
+	// Test case for foreign_key_checks variable.
+	c.Assert(v.ForeignKeyChecks, IsTrue)
+	SetSystemVar(v, variable.ForeignKeyChecks, types.NewIntDatum(0))
+	c.Assert(v.ForeignKeyChecks, IsFalse)
+	SetSystemVar(v, variable.ForeignKeyChecks, types.NewStringDatum("on"))
+	c.Assert(v.ForeignKeyChecks, IsTrue)
 }
@@ -86,7 +86,7 @@ func (s *dbStore) SeekReverse(key []byte, startTS uint64) ([]byte, []byte, error
 
 // Commit writes the changed data in Batch.
 func (s *dbStore) CommitTxn(txn *dbTxn) error {
-	if len(txn.lockedKeys) == 0 {
+	if len(txn.lockedKeys) == 0 && len(txn.sharedKeys) == 0 {
 		return nil
 	}
 	return s.doCommit(txn)
@@ -111,17 +111,18 @@ func (s *dbStore) cleanRecentUpdates(segmentIndex int64) {
 func (s *dbStore) tryLock(txn *dbTxn) (err error) {
 	// check conflict
 	for k := range txn.lockedKeys {
-		if _, ok := s.keysLocked[k]; ok {
-			return errors.Trace(kv.ErrLockConflict)
+		if err = s.checkConflict(txn, k); err != nil {
+			return errors.Trace(err)
 		}
-
-		lastVer, ok := s.recentUpdates.Get([]byte(k))
-		if !ok {
+	}
+	// The keys locked in shared mode are only checked, they are not locked or updated by the commit,
+	// so the transactions locking the same keys in shared mode don't conflict.
+	for k := range txn.sharedKeys {
+		if _, ok := txn.lockedKeys[k]; ok {
 			continue
 		}
-		// If there's newer version of this key, returns error.
-		if lastVer.(kv.Version).Cmp(kv.Version{Ver: txn.tid}) > 0 {
-			return errors.Trace(kv.ErrConditionNotMatch)
+		if err = s.checkConflict(txn, k); err != nil {
+			return errors.Trace(err)
 		}
 	}
 
@@ -133,6 +134,22 @@ func (s *dbStore) tryLock(txn *dbTxn) (err error) {
 	return nil
 }
 
+// checkConflict checks the key is neither locked nor updated by other transactions after txn starts.
+func (s *dbStore) checkConflict(txn *dbTxn, k string) error {
+	if _, ok := s.keysLocked[k]; ok {
+		return kv.ErrLockConflict
+	}
+	lastVer, ok := s.recentUpdates.Get([]byte(k))
+	if !ok {
+		return nil
+	}
+	// If there's newer version of this key, returns error.
+	if lastVer.(kv.Version).Cmp(kv.Version{Ver: txn.tid}) > 0 {
+		return kv.ErrConditionNotMatch
+	}
+	return nil
+}
+
 func (s *dbStore) doCommit(txn *dbTxn) error {
 	var commitVer kv.Version
 	var err error
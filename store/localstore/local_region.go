@@ -273,6 +273,7 @@ func (rs *localRegion) Handle(req *regionRequest) (*regionResponse, error) {
 		selResp := new(tipb.SelectResponse)
 		selResp.Error = toPBError(err)
 		selResp.Chunks = ctx.chunks
+		selResp.Warnings = xeval.ToPBWarnings(ctx.sc.GetWarnings())
 		resp.err = err
 		data, err := proto.Marshal(selResp)
 		if err != nil {
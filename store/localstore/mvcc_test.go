@@ -134,6 +134,39 @@ func (t *testMvccSuite) TestMvccPutAndDel(c *C) {
 	c.Assert(cnt1, Greater, cnt)
 }
 
+func (t *testMvccSuite) TestSavepoint(c *C) {
+	txn, err := t.s.Begin()
+	c.Assert(err, IsNil)
+	err = txn.Set(encodeInt(10), encodeInt(10))
+	c.Assert(err, IsNil)
+	sp, err := txn.Savepoint()
+	c.Assert(err, IsNil)
+	err = txn.Set(encodeInt(11), encodeInt(11))
+	c.Assert(err, IsNil)
+	err = txn.Delete(encodeInt(0))
+	c.Assert(err, IsNil)
+	err = txn.RollbackToSavepoint(sp)
+	c.Assert(err, IsNil)
+	_, err = txn.Get(encodeInt(11))
+	c.Assert(kv.IsErrNotFound(err), IsTrue)
+	err = txn.Set(encodeInt(12), encodeInt(12))
+	c.Assert(err, IsNil)
+	err = txn.Commit()
+	c.Assert(err, IsNil)
+
+	// Only the writes made before the savepoint and after the rollback are committed.
+	txn, err = t.s.Begin()
+	c.Assert(err, IsNil)
+	for _, i := range []int{0, 10, 12} {
+		v, err := txn.Get(encodeInt(i))
+		c.Assert(err, IsNil)
+		c.Assert(v, BytesEquals, encodeInt(i))
+	}
+	_, err = txn.Get(encodeInt(11))
+	c.Assert(kv.IsErrNotFound(err), IsTrue)
+	txn.Commit()
+}
+
 func (t *testMvccSuite) TestMvccNext(c *C) {
 	txn, _ := t.s.Begin()
 	it, err := txn.Seek(encodeInt(2))
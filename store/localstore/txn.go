@@ -33,6 +33,7 @@ type dbTxn struct {
 	valid      bool
 	version    kv.Version          // commit version
 	lockedKeys map[string]struct{} // origin version in snapshot
+	sharedKeys map[string]struct{} // keys locked in shared mode, they are checked but not locked on commit
 	dirty      bool
 }
 
@@ -44,6 +45,7 @@ func newTxn(s *dbStore, ver kv.Version) *dbTxn {
 		valid:      true,
 		version:    kv.MinVersion,
 		lockedKeys: make(map[string]struct{}),
+		sharedKeys: make(map[string]struct{}),
 	}
 	log.Debugf("[kv] Begin txn:%d", txn.tid)
 	return txn
@@ -115,6 +117,7 @@ func (txn *dbTxn) Commit() error {
 
 func (txn *dbTxn) close() error {
 	txn.lockedKeys = nil
+	txn.sharedKeys = nil
 	txn.valid = false
 	return nil
 }
@@ -134,6 +137,13 @@ func (txn *dbTxn) LockKeys(keys ...kv.Key) error {
 	return nil
 }
 
+func (txn *dbTxn) LockKeysShared(keys ...kv.Key) error {
+	for _, key := range keys {
+		txn.sharedKeys[string(key)] = struct{}{}
+	}
+	return nil
+}
+
 func (txn *dbTxn) IsReadOnly() bool {
 	return !txn.dirty
 }
@@ -145,3 +155,11 @@ func (txn *dbTxn) StartTS() uint64 {
 func (txn *dbTxn) Valid() bool {
 	return txn.valid
 }
+
+func (txn *dbTxn) Savepoint() (*kv.Savepoint, error) {
+	return txn.us.Savepoint()
+}
+
+func (txn *dbTxn) RollbackToSavepoint(sp *kv.Savepoint) error {
+	return txn.us.RollbackToSavepoint(sp)
+}
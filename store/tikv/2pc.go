@@ -459,6 +459,11 @@ func (c *twoPhaseCommitter) execute() error {
 	}
 	c.commitTS = commitTS
 
+	if err = c.txn.checkSharedKeys(commitTS, c.mutations); err != nil {
+		log.Debugf("2PC failed on checking shared lock keys: %v, tid: %d", err, c.startTS)
+		return errors.Trace(err)
+	}
+
 	if c.store.oracle.IsExpired(c.startTS, maxTxnTimeUse) {
 		err = errors.Errorf("txn takes too much time, start: %d, commit: %d", c.startTS, c.commitTS)
 		return errors.Annotate(err, txnRetryableMark)
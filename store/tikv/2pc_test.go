@@ -19,6 +19,7 @@ import (
 
 	"github.com/juju/errors"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/store/tikv/mock-tikv"
 	"golang.org/x/net/context"
 )
@@ -195,3 +196,81 @@ func (s *testCommitterSuite) TestContextCancelRetryable(c *C) {
 	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(err.Error(), txnRetryableMark), IsTrue)
 }
+
+func (s *testCommitterSuite) TestSharedLock(c *C) {
+	s.mustCommit(c, map[string]string{
+		"a": "a0",
+	})
+
+	// The transactions locking the same key in shared mode don't conflict.
+	txn1, txn2 := s.begin(c), s.begin(c)
+	c.Assert(txn1.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn1.Set([]byte("b"), []byte("b1")), IsNil)
+	c.Assert(txn2.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn2.Set([]byte("c"), []byte("c2")), IsNil)
+	c.Assert(txn1.Commit(), IsNil)
+	c.Assert(txn2.Commit(), IsNil)
+	s.checkValues(c, map[string]string{
+		"a": "a0",
+		"b": "b1",
+		"c": "c2",
+	})
+
+	// The commit fails if the key is modified by another transaction after the transaction starts.
+	txn1, txn2 = s.begin(c), s.begin(c)
+	c.Assert(txn1.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn1.Set([]byte("b"), []byte("b3")), IsNil)
+	c.Assert(txn2.Set([]byte("a"), []byte("a2")), IsNil)
+	c.Assert(txn2.Commit(), IsNil)
+	err := txn1.Commit()
+	c.Assert(kv.ErrConditionNotMatch.Equal(errors.Cause(err)), IsTrue, Commentf("err %v", err))
+	c.Assert(kv.IsRetryableError(err), IsTrue)
+	s.checkValues(c, map[string]string{
+		"a": "a2",
+		"b": "b1",
+	})
+
+	// The key is checked even if the transaction writes nothing.
+	txn1, txn2 = s.begin(c), s.begin(c)
+	c.Assert(txn1.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn2.Delete([]byte("a")), IsNil)
+	c.Assert(txn2.Commit(), IsNil)
+	err = txn1.Commit()
+	c.Assert(kv.ErrConditionNotMatch.Equal(errors.Cause(err)), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testCommitterSuite) TestSharedLockUpgrade(c *C) {
+	s.mustCommit(c, map[string]string{
+		"a": "a0",
+	})
+
+	// The key written by the transaction itself is locked exclusively, the write is not a conflict.
+	txn1 := s.begin(c)
+	c.Assert(txn1.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn1.Set([]byte("a"), []byte("a1")), IsNil)
+	c.Assert(txn1.Commit(), IsNil)
+	s.checkValues(c, map[string]string{
+		"a": "a1",
+	})
+
+	// The transactions locking the key in shared mode conflict with the one which upgrades the lock.
+	txn1, txn2 := s.begin(c), s.begin(c)
+	c.Assert(txn1.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn1.Set([]byte("a"), []byte("a2")), IsNil)
+	c.Assert(txn2.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn2.Set([]byte("b"), []byte("b2")), IsNil)
+	c.Assert(txn1.Commit(), IsNil)
+	c.Assert(txn2.Commit(), NotNil)
+
+	// The upgraded lock is checked by the prewrite like an exclusive lock.
+	txn1, txn2 = s.begin(c), s.begin(c)
+	c.Assert(txn1.LockKeysShared([]byte("a")), IsNil)
+	c.Assert(txn1.LockKeys([]byte("a")), IsNil)
+	c.Assert(txn1.Set([]byte("b"), []byte("b3")), IsNil)
+	c.Assert(txn2.Set([]byte("a"), []byte("a3")), IsNil)
+	c.Assert(txn2.Commit(), IsNil)
+	c.Assert(txn1.Commit(), NotNil)
+	s.checkValues(c, map[string]string{
+		"a": "a3",
+	})
+}
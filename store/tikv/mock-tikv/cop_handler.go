@@ -110,6 +110,7 @@ func (h *rpcHandler) handleCopRequest(req *coprocessor.Request) (*coprocessor.Re
 		selResp := new(tipb.SelectResponse)
 		selResp.Error = toPBError(err)
 		selResp.Chunks = chunks
+		selResp.Warnings = xeval.ToPBWarnings(ctx.sc.GetWarnings())
 		if err != nil {
 			if locked, ok := errors.Cause(err).(*ErrLocked); ok {
 				resp.Locked = &kvrpcpb.LockInfo{
@@ -14,11 +14,13 @@
 package tikv
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/ngaut/log"
+	pb "github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tipb/go-binlog"
 	"golang.org/x/net/context"
@@ -36,7 +38,9 @@ type tikvTxn struct {
 	commitTS uint64
 	valid    bool
 	lockKeys [][]byte
-	dirty    bool
+	// sharedKeys are the keys locked in shared mode, they are checked but not locked on commit.
+	sharedKeys [][]byte
+	dirty      bool
 }
 
 func newTiKVTxn(store *tikvStore) (*tikvTxn, error) {
@@ -129,7 +133,15 @@ func (txn *tikvTxn) Commit() error {
 		return errors.Trace(err)
 	}
 	if committer == nil {
-		return nil
+		// Nothing is written, only the keys locked in shared mode are checked.
+		if len(txn.sharedKeys) == 0 {
+			return nil
+		}
+		ts, err := txn.store.getTimestampWithRetry(NewBackoffer(tsoMaxBackoff, context.Background()))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(txn.checkSharedKeys(ts, nil))
 	}
 	err = committer.execute()
 	if err != nil {
@@ -165,6 +177,49 @@ func (txn *tikvTxn) LockKeys(keys ...kv.Key) error {
 	return nil
 }
 
+// LockKeysShared implements the kv.Transaction interface. TiKV has no shared locks, the keys are not
+// prewritten, they are checked by checkSharedKeys after the prewrite instead.
+func (txn *tikvTxn) LockKeysShared(keys ...kv.Key) error {
+	txnCmdCounter.WithLabelValues("lock_keys_shared").Inc()
+	for _, key := range keys {
+		txn.sharedKeys = append(txn.sharedKeys, key)
+	}
+	return nil
+}
+
+// checkSharedKeys checks that the keys locked in shared mode are not modified by other transactions
+// between the start of txn and ts, which is the commit timestamp of txn. The keys in mutations are
+// written or locked exclusively by txn, they are skipped because the prewrite has locked them.
+func (txn *tikvTxn) checkSharedKeys(ts uint64, mutations map[string]*pb.Mutation) error {
+	var keys []kv.Key
+	for _, key := range txn.sharedKeys {
+		if _, ok := mutations[string(key)]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	startValues, err := newTiKVSnapshot(txn.store, kv.NewVersion(txn.startTS)).BatchGet(keys)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The locks of the transactions committing before ts are resolved by the read.
+	values, err := newTiKVSnapshot(txn.store, kv.NewVersion(ts)).BatchGet(keys)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, key := range keys {
+		startValue, ok1 := startValues[string(key)]
+		value, ok2 := values[string(key)]
+		if ok1 != ok2 || !bytes.Equal(startValue, value) {
+			log.Debugf("[kv] shared lock key %q is modified, tid: %d", key, txn.startTS)
+			return errors.Trace(kv.ErrConditionNotMatch)
+		}
+	}
+	return nil
+}
+
 func (txn *tikvTxn) IsReadOnly() bool {
 	return !txn.dirty
 }
@@ -176,3 +231,11 @@ func (txn *tikvTxn) StartTS() uint64 {
 func (txn *tikvTxn) Valid() bool {
 	return txn.valid
 }
+
+func (txn *tikvTxn) Savepoint() (*kv.Savepoint, error) {
+	return txn.us.Savepoint()
+}
+
+func (txn *tikvTxn) RollbackToSavepoint(sp *kv.Savepoint) error {
+	return txn.us.RollbackToSavepoint(sp)
+}
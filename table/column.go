@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/model"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/util/charset"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -111,15 +112,22 @@ func CastValues(ctx context.Context, rec []types.Datum, cols []*Column, ignoreEr
 	return nil
 }
 
-// CastValue casts a value based on column type.
+// CastValue casts a value based on column type. In strict sql mode, a value that can't be converted
+// exactly is an error, otherwise the value is clipped or truncated with a warning.
 func CastValue(ctx context.Context, val types.Datum, col *model.ColumnInfo) (casted types.Datum, err error) {
-	casted, err = val.ConvertTo(ctx.GetSessionVars().StmtCtx, &col.FieldType)
+	sessVars := ctx.GetSessionVars()
+	casted, err = val.ConvertTo(sessVars.StmtCtx, &col.FieldType)
+	if err == nil && casted.Kind() == types.KindMysqlTime && casted.GetMysqlTime().IsZero() &&
+		sessVars.SQLMode.HasNoZeroDateMode() {
+		err = errIncorrectValue.GenByArgs(types.TypeStr(col.Tp), casted.GetMysqlTime(), col.Name)
+	} else if types.ErrOverflow.Equal(err) {
+		err = errOutOfRange.GenByArgs(col.Name, sessVars.StmtCtx.CurrentRow())
+	}
 	if err != nil {
-		if ctx.GetSessionVars().StrictSQLMode {
+		if sessVars.StrictSQLMode {
 			return casted, errors.Trace(err)
 		}
-		// TODO: add warnings.
-		log.Warnf("cast value error %v", err)
+		sessVars.StmtCtx.AppendWarning(err)
 	}
 	return casted, nil
 }
@@ -143,7 +151,10 @@ const defaultPrivileges string = "select,insert,update,references"
 func (c *Column) GetTypeDesc() string {
 	desc := c.FieldType.CompactStr()
 	if mysql.HasUnsignedFlag(c.Flag) {
-		desc += " UNSIGNED"
+		desc += " unsigned"
+	}
+	if mysql.HasZerofillFlag(c.Flag) {
+		desc += " zerofill"
 	}
 	return desc
 }
@@ -229,6 +240,21 @@ func (c *Column) IsPKHandleColumn(tbInfo *model.TableInfo) bool {
 	return mysql.HasPriKeyFlag(c.Flag) && tbInfo.PKIsHandle
 }
 
+// FoldIndexValue folds the string value of a column with a case-insensitive collation,
+// the index keys of the column are encoded from the folded strings so that they are
+// compared case-insensitively.
+func FoldIndexValue(col *model.ColumnInfo, v *types.Datum) {
+	if !types.IsCaseInsensitiveStr(&col.FieldType) {
+		return
+	}
+	switch v.Kind() {
+	case types.KindString:
+		v.SetString(charset.FoldCase(v.GetString()))
+	case types.KindBytes:
+		v.SetBytes([]byte(charset.FoldCase(v.GetString())))
+	}
+}
+
 // CheckNotNull checks if row has nil value set to a column with NotNull flag set.
 func CheckNotNull(cols []*Column, row []types.Datum) error {
 	for _, c := range cols {
@@ -247,7 +273,7 @@ func GetColDefaultValue(ctx context.Context, col *model.ColumnInfo) (types.Datum
 		if ctx != nil {
 			sessVars := ctx.GetSessionVars()
 			if !sessVars.StrictSQLMode {
-				// TODO: add warning.
+				sessVars.StmtCtx.AppendWarning(err)
 				return GetZeroValue(col), true, nil
 			}
 		}
@@ -75,6 +75,14 @@ func (s *testColumnSuite) TestString(c *C) {
 
 	col.Decimal = -1
 	c.Assert(col.GetTypeDesc(), Equals, "datetime")
+
+	col.Tp = mysql.TypeLong
+	col.Flen = 10
+	col.Flag = mysql.UnsignedFlag
+	c.Assert(col.GetTypeDesc(), Equals, "int(10) unsigned")
+
+	col.Flag |= mysql.ZerofillFlag
+	c.Assert(col.GetTypeDesc(), Equals, "int(10) unsigned zerofill")
 }
 
 func (s *testColumnSuite) TestFind(c *C) {
@@ -34,6 +34,8 @@ var (
 	errColumnCantNull  = terror.ClassTable.New(codeColumnCantNull, "column can not be null")
 	errUnknownColumn   = terror.ClassTable.New(codeUnknownColumn, "unknown column")
 	errDuplicateColumn = terror.ClassTable.New(codeDuplicateColumn, "duplicate column")
+	errIncorrectValue  = terror.ClassTable.New(codeIncorrectValue, "Incorrect %s value: '%s' for column '%s'")
+	errOutOfRange      = terror.ClassTable.New(codeOutOfRange, "Out of range value for column '%s' at row %d")
 
 	errGetDefaultFailed = terror.ClassTable.New(codeGetDefaultFailed, "get default value fail")
 
@@ -140,6 +142,8 @@ const (
 	codeColumnCantNull  = 1048
 	codeUnknownColumn   = 1054
 	codeDuplicateColumn = 1110
+	codeOutOfRange      = 1264
+	codeIncorrectValue  = 1292
 	codeNoDefaultValue  = 1364
 )
 
@@ -159,6 +163,8 @@ func init() {
 		codeColumnCantNull:  mysql.ErrBadNull,
 		codeUnknownColumn:   mysql.ErrBadField,
 		codeDuplicateColumn: mysql.ErrFieldSpecifiedTwice,
+		codeOutOfRange:      mysql.ErrWarnDataOutOfRange,
+		codeIncorrectValue:  mysql.ErrTruncatedWrongValue,
 		codeNoDefaultValue:  mysql.ErrNoDefaultForField,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassTable] = tableMySQLErrCodes
@@ -137,6 +137,7 @@ func (c *index) GenIndexKey(indexedValues []types.Datum, h int64) (key []byte, d
 		v := &indexedValues[i]
 		if v.Kind() == types.KindString || v.Kind() == types.KindBytes {
 			ic := c.idxInfo.Columns[i]
+			table.FoldIndexValue(c.tblInfo.Columns[ic.Offset], v)
 			if ic.Length != types.UnspecifiedLength && len(v.GetBytes()) > ic.Length {
 				// truncate value and limit its length
 				v.SetBytes(v.GetBytes()[:ic.Length])
@@ -178,13 +178,17 @@ func (s *testIndexSuite) TestCombineIndexSeek(c *C) {
 	defer testleak.AfterTest(c)()
 	tblInfo := &model.TableInfo{
 		ID: 1,
+		Columns: []*model.ColumnInfo{
+			{Offset: 0},
+			{Offset: 1},
+		},
 		Indices: []*model.IndexInfo{
 			{
 				ID:   2,
 				Name: model.NewCIStr("test"),
 				Columns: []*model.IndexColumn{
-					{},
-					{},
+					{Offset: 0},
+					{Offset: 1},
 				},
 			},
 		},
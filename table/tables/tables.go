@@ -196,13 +196,11 @@ func (t *Table) UpdateRecord(ctx context.Context, h int64, oldData []types.Datum
 		}
 		colIDs = append(colIDs, col.ID)
 	}
-	// Set new row data into KV.
+	// Set new row data into KV, it is written to the transaction with the indices,
+	// so nothing is changed if the new row has a duplicate key.
 	key := t.RecordKey(h)
 	value, err := tablecodec.EncodeRow(currentData, colIDs)
-	if err = txn.Set(key, value); err != nil {
-		return errors.Trace(err)
-	}
-	if err = bs.SaveTo(txn); err != nil {
+	if err = bs.Set(key, value); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -276,6 +274,14 @@ func (t *Table) rebuildIndices(rm kv.RetrieverMutator, h int64, touched map[int]
 		}
 
 		if err := t.buildIndexForRow(rm, h, newVs, idx); err != nil {
+			if terror.ErrorEqual(err, kv.ErrKeyExists) {
+				// Report the key and the values like adding a record does.
+				entryKey, err1 := t.genIndexKeyStr(newVs)
+				if err1 != nil {
+					return errors.Trace(err1)
+				}
+				return kv.ErrKeyExists.FastGen("Duplicate entry '%s' for key '%s'", entryKey, idx.Meta().Name)
+			}
 			return errors.Trace(err)
 		}
 	}
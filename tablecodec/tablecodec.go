@@ -316,6 +316,11 @@ func Unflatten(datum types.Datum, ft *types.FieldType, inIndex bool) (types.Datu
 		datum.SetValue(dur)
 		return datum, nil
 	case mysql.TypeEnum:
+		// 0 is the index of the empty string, which is stored for an invalid value in non-strict sql mode.
+		if datum.GetUint64() == 0 {
+			datum.SetValue(types.Enum{})
+			return datum, nil
+		}
 		enum, err := types.ParseEnumValue(ft.Elems, datum.GetUint64())
 		if err != nil {
 			return datum, errors.Trace(err)
@@ -27,6 +27,7 @@ import (
 	"github.com/ngaut/log"
 	"github.com/ngaut/systimemon"
 	"github.com/pingcap/tidb"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/perfschema"
 	"github.com/pingcap/tidb/plan"
@@ -59,6 +60,7 @@ var (
 	metricsAddr     = flag.String("metrics-addr", "", "prometheus pushgateway address, leaves it empty will disable prometheus push.")
 	metricsInterval = flag.Int("metrics-interval", 15, "prometheus client push interval in second, set \"0\" to disable prometheus push.")
 	binlogSocket    = flag.String("binlog-socket", "", "socket file to write binlog")
+	secureFilePriv  = flag.String("secure-file-priv", "", "the directory that SELECT INTO OUTFILE and LOAD DATA INFILE can access, leaves it empty will disable both statements.")
 )
 
 func main() {
@@ -98,6 +100,7 @@ func main() {
 		plan.JoinConcurrency = *joinCon
 	}
 	plan.AllowCartesianProduct = *crossJoin
+	executor.SecureFilePriv = *secureFilePriv
 	// Call this before setting log level to make sure that TiDB info could be printed.
 	printer.PrintTiDBInfo()
 	log.SetLevelByString(cfg.LogLevel)
@@ -128,14 +128,22 @@ func resetStmtCtx(ctx context.Context, s ast.StmtNode) {
 	case *ast.UpdateStmt, *ast.InsertStmt, *ast.DeleteStmt:
 		sc.IgnoreTruncate = false
 		sc.TruncateAsWarning = !sessVars.StrictSQLMode
+		sc.DividedByZeroAsError = sessVars.StrictSQLMode && sessVars.SQLMode.HasErrorForDivisionByZeroMode()
 		if _, ok := s.(*ast.UpdateStmt); ok {
 			sc.InUpdateStmt = true
 		}
+	case *ast.SelectStmt, *ast.UnionStmt:
+		// Data truncation in select statements, like comparing a number with '123abc', is always
+		// reported as a warning, no matter whether it is in strict sql mode.
+		sc.TruncateAsWarning = true
 	default:
 		sc.IgnoreTruncate = true
 		if show, ok := s.(*ast.ShowStmt); ok {
-			if show.Tp == ast.ShowWarnings {
+			// SHOW WARNINGS and SHOW ERRORS report the diagnostics of the previous statement,
+			// so they are kept instead of being cleared.
+			if show.Tp == ast.ShowWarnings || show.Tp == ast.ShowErrors {
 				sc.SetWarnings(sessVars.StmtCtx.GetWarnings())
+				sc.SetErrors(sessVars.StmtCtx.GetErrors())
 			}
 		}
 	}
@@ -187,6 +195,9 @@ func runStmt(ctx context.Context, s ast.Statement) (ast.RecordSet, error) {
 	var rs ast.RecordSet
 	se := ctx.(*session)
 	rs, err = s.Exec(ctx)
+	if err != nil {
+		se.sessionVars.StmtCtx.AppendError(err)
+	}
 	// All the history should be added here.
 	getHistory(ctx).add(0, s)
 	if !se.sessionVars.InTxn() {
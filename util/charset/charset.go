@@ -143,6 +143,18 @@ func GetCollations() []*Collation {
 	return collations
 }
 
+// IsCaseInsensitive returns true if the strings of the collation are compared case-insensitively.
+// Only the general_ci collations of utf8 and utf8mb4 are supported for now, the strings of the
+// other collations are compared as binary strings.
+func IsCaseInsensitive(collation string) bool {
+	return collation == CollationUTF8 || collation == CollationUTF8MB4
+}
+
+// FoldCase returns the string that s is compared as with a case-insensitive collation.
+func FoldCase(s string) string {
+	return strings.ToUpper(s)
+}
+
 const (
 	// CharsetBin is used for marking binary charset.
 	CharsetBin = "binary"
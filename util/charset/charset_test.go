@@ -87,3 +87,14 @@ func (s *testCharsetSuite) TestGetDefaultCollation(c *C) {
 		testGetDefaultCollation(c, t.cs, t.co, t.succ)
 	}
 }
+
+func (s *testCharsetSuite) TestCaseInsensitive(c *C) {
+	defer testleak.AfterTest(c)()
+	c.Assert(IsCaseInsensitive("utf8_general_ci"), IsTrue)
+	c.Assert(IsCaseInsensitive("utf8mb4_general_ci"), IsTrue)
+	c.Assert(IsCaseInsensitive("utf8_bin"), IsFalse)
+	c.Assert(IsCaseInsensitive("binary"), IsFalse)
+	c.Assert(FoldCase("aBc"), Equals, FoldCase("ABC"))
+	c.Assert(FoldCase("ä"), Equals, FoldCase("Ä"))
+	c.Assert(FoldCase("abc"), Not(Equals), FoldCase("abd"))
+}
@@ -671,6 +671,8 @@ func (d *Datum) ConvertTo(sc *variable.StatementContext, target *FieldType) (Dat
 		return d.convertToMysqlEnum(sc, target)
 	case mysql.TypeSet:
 		return d.convertToMysqlSet(sc, target)
+	case mysql.TypeJSON:
+		return d.convertToJSON(sc, target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -678,6 +680,22 @@ func (d *Datum) ConvertTo(sc *variable.StatementContext, target *FieldType) (Dat
 	}
 }
 
+// convertToJSON validates the string value as a JSON document and returns its normalized text.
+func (d *Datum) convertToJSON(sc *variable.StatementContext, target *FieldType) (Datum, error) {
+	var ret Datum
+	switch d.k {
+	case KindString, KindBytes:
+		s, err := NormalizeJSON(d.GetString())
+		if err != nil {
+			return ret, ErrInvalidJSONText.GenByArgs(err)
+		}
+		ret.SetString(s)
+		return ret, nil
+	default:
+		return ret, ErrInvalidJSONText.GenByArgs("not a JSON text, may need CAST")
+	}
+}
+
 func (d *Datum) convertToFloat(sc *variable.StatementContext, target *FieldType) (Datum, error) {
 	var (
 		f   float64
@@ -1065,7 +1083,9 @@ func (d *Datum) convertToMysqlEnum(sc *variable.StatementContext, target *FieldT
 		e, err = ParseEnumValue(target.Elems, uintDatum.GetUint64())
 	}
 	if err != nil {
-		return invalidConv(d, target.Tp)
+		// Like MySQL, an invalid value is converted to the empty string, whose index is 0.
+		ret.SetValue(Enum{})
+		return ret, errors.Trace(ErrTruncated)
 	}
 	ret.SetValue(e)
 	return ret, nil
@@ -1090,7 +1110,9 @@ func (d *Datum) convertToMysqlSet(sc *variable.StatementContext, target *FieldTy
 	}
 
 	if err != nil {
-		return invalidConv(d, target.Tp)
+		// Like MySQL, the invalid members are dropped, s holds the valid members.
+		ret.SetValue(s)
+		return ret, errors.Trace(ErrTruncated)
 	}
 	ret.SetValue(s)
 	return ret, nil
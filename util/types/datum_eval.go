@@ -207,8 +207,8 @@ func ComputeMul(a, b Datum) (d Datum, err error) {
 	return d, errors.Trace(err)
 }
 
-// ComputeDiv computes the result of a/b.
-func ComputeDiv(sc *variable.StatementContext, a, b Datum) (d Datum, err error) {
+// ComputeDiv computes the result of a/b, the scale of a decimal result is the scale of a plus fracIncr.
+func ComputeDiv(sc *variable.StatementContext, a, b Datum, fracIncr int) (d Datum, err error) {
 	// MySQL support integer division Div and division operator /
 	// we use opcode.Div for division operator and will use another for integer division later.
 	// for division operator, we will use float64 for calculation.
@@ -229,7 +229,6 @@ func ComputeDiv(sc *variable.StatementContext, a, b Datum) (d Datum, err error)
 	default:
 		// the scale of the result is the scale of the first operand plus
 		// the value of the div_precision_increment system variable (which is 4 by default)
-		// we will use 4 here
 		xa, err1 := a.ToDecimal(sc)
 		if err != nil {
 			return d, errors.Trace(err1)
@@ -241,7 +240,7 @@ func ComputeDiv(sc *variable.StatementContext, a, b Datum) (d Datum, err error)
 		}
 		// division by zero return null
 		to := new(MyDecimal)
-		err = DecimalDiv(xa, xb, to, DivFracIncr)
+		err = DecimalDiv(xa, xb, to, fracIncr)
 		if err != ErrDivByZero {
 			d.SetMysqlDecimal(to)
 		} else {
@@ -29,6 +29,10 @@ var (
 	ErrDivByZero = terror.ClassTypes.New(codeDivByZero, "Division by 0")
 	// ErrBadNumber is return when parsing an invalid binary decimal number.
 	ErrBadNumber = terror.ClassTypes.New(codeBadNumber, "Bad Number")
+	// ErrInvalidJSONText is returned when a value stored into a JSON column is not a valid JSON document.
+	ErrInvalidJSONText = terror.ClassTypes.New(codeInvalidJSONText, "Invalid JSON text: %s")
+	// ErrInvalidJSONPath is returned when a JSON path expression can not be parsed.
+	ErrInvalidJSONPath = terror.ClassTypes.New(codeInvalidJSONPath, "Invalid JSON path expression %s")
 )
 
 const (
@@ -38,6 +42,9 @@ const (
 	codeTruncated   terror.ErrCode = terror.ErrCode(mysql.WarnDataTruncated)
 	codeOverflow    terror.ErrCode = terror.ErrCode(mysql.ErrWarnDataOutOfRange)
 	codeDivByZero   terror.ErrCode = terror.ErrCode(mysql.ErrDivisionByZero)
+
+	codeInvalidJSONText terror.ErrCode = terror.ErrCode(mysql.ErrInvalidJSONText)
+	codeInvalidJSONPath terror.ErrCode = terror.ErrCode(mysql.ErrInvalidJSONPath)
 )
 
 func init() {
@@ -46,6 +53,9 @@ func init() {
 		codeTruncated:   mysql.WarnDataTruncated,
 		codeOverflow:    mysql.ErrWarnDataOutOfRange,
 		codeDivByZero:   mysql.ErrDivisionByZero,
+
+		codeInvalidJSONText: mysql.ErrInvalidJSONText,
+		codeInvalidJSONPath: mysql.ErrInvalidJSONPath,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassTypes] = typesMySQLErrCodes
 }
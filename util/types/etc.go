@@ -49,6 +49,24 @@ func IsTypeChar(tp byte) bool {
 	}
 }
 
+// IsCaseInsensitiveStr returns a boolean indicating
+// whether the field type is a string type whose collation compares strings case-insensitively.
+func IsCaseInsensitiveStr(ft *FieldType) bool {
+	if !charset.IsCaseInsensitive(ft.Collate) {
+		return false
+	}
+	return IsTypeChar(ft.Tp) || IsTypeBlob(ft.Tp) || ft.Tp == mysql.TypeVarString
+}
+
+// IsBinaryStr returns a boolean indicating
+// whether the field type is a binary string type.
+func IsBinaryStr(ft *FieldType) bool {
+	if ft.Collate != charset.CollationBin {
+		return false
+	}
+	return IsTypeChar(ft.Tp) || IsTypeBlob(ft.Tp) || ft.Tp == mysql.TypeVarString
+}
+
 var type2Str = map[byte]string{
 	mysql.TypeBit:        "bit",
 	mysql.TypeBlob:       "text",
@@ -61,6 +79,7 @@ var type2Str = map[byte]string{
 	mysql.TypeFloat:      "float",
 	mysql.TypeGeometry:   "geometry",
 	mysql.TypeInt24:      "mediumint",
+	mysql.TypeJSON:       "json",
 	mysql.TypeLong:       "int",
 	mysql.TypeLonglong:   "bigint",
 	mysql.TypeLongBlob:   "longtext",
@@ -118,5 +137,5 @@ func InvOp2(x, y interface{}, o opcode.Op) (interface{}, error) {
 
 // Overflow returns an overflowed error.
 func overflow(v interface{}, tp byte) error {
-	return errors.Errorf("constant %v overflows %s", v, TypeStr(tp))
+	return ErrOverflow.Gen("constant %v overflows %s", v, TypeStr(tp))
 }
@@ -192,6 +192,13 @@ func DefaultCharsetForType(tp byte) (string, string) {
 // The result field type of the case expression is the merged type of the two when clause.
 // See https://github.com/mysql/mysql-server/blob/5.7/sql/field.cc#L1042
 func MergeFieldType(a byte, b byte) byte {
+	// TypeJSON is not in the merge rules, it merges to itself with JSON or NULL, and to a string otherwise.
+	if a == mysql.TypeJSON || b == mysql.TypeJSON {
+		if (a == mysql.TypeJSON || a == mysql.TypeNull) && (b == mysql.TypeJSON || b == mysql.TypeNull) {
+			return mysql.TypeJSON
+		}
+		return mysql.TypeLongBlob
+	}
 	ia := getFieldTypeIndex(a)
 	ib := getFieldTypeIndex(b)
 	return fieldTypeMergeRules[ia][ib]
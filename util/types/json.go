@@ -0,0 +1,317 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+// A JSON value is stored as its normalized text, which is the text MySQL returns for the value:
+// object members are sorted by key length and then by key, and ", " and ": " are used as separators.
+// See https://dev.mysql.com/doc/refman/5.7/en/json.html
+
+// ParseJSON parses s as a JSON document and returns the decoded value. Objects are decoded as
+// map[string]interface{}, arrays as []interface{} and numbers as json.Number.
+func ParseJSON(s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("The document is empty.")
+		}
+		return nil, errors.Trace(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("The document root must not be followed by other values.")
+	}
+	return v, nil
+}
+
+// NormalizeJSON validates s as a JSON document and returns its normalized text.
+func NormalizeJSON(s string) (string, error) {
+	v, err := ParseJSON(s)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return JSONToString(v), nil
+}
+
+// JSONToString returns the normalized text of a decoded JSON value.
+func JSONToString(v interface{}) string {
+	var buf bytes.Buffer
+	writeJSON(&buf, v)
+	return buf.String()
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	switch x := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(x))
+	case json.Number:
+		buf.WriteString(x.String())
+	case string:
+		writeJSONString(buf, x)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSON(buf, e)
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Sort(jsonKeys(keys))
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSONString(buf, k)
+			buf.WriteString(": ")
+			writeJSON(buf, x[k])
+		}
+		buf.WriteByte('}')
+	default:
+		writeJSONString(buf, fmt.Sprintf("%v", x))
+	}
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// jsonKeys sorts object keys like MySQL, shorter keys come first.
+type jsonKeys []string
+
+func (k jsonKeys) Len() int      { return len(k) }
+func (k jsonKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k jsonKeys) Less(i, j int) bool {
+	if len(k[i]) != len(k[j]) {
+		return len(k[i]) < len(k[j])
+	}
+	return k[i] < k[j]
+}
+
+// JSONUnquote returns the unquoted string if s is the text of a JSON string, otherwise s itself.
+func JSONUnquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s, nil
+	}
+	var str string
+	if err := json.Unmarshal([]byte(s), &str); err != nil {
+		return "", errors.Trace(err)
+	}
+	return str, nil
+}
+
+type jsonPathLegType byte
+
+const (
+	jsonPathLegKey jsonPathLegType = iota
+	jsonPathLegIndex
+)
+
+// jsonPathLeg is a member or an array cell in a JSON path, the wildcard is true for '.*' and '[*]'.
+type jsonPathLeg struct {
+	tp       jsonPathLegType
+	key      string
+	index    int
+	wildcard bool
+}
+
+// JSONPath is a parsed JSON path expression like `$.a[0]."b c"`.
+type JSONPath struct {
+	legs []jsonPathLeg
+}
+
+// HasWildcard returns whether the path may match more than one value.
+func (p JSONPath) HasWildcard() bool {
+	for _, leg := range p.legs {
+		if leg.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseJSONPath parses a JSON path expression, which starts with '$' and is followed by
+// '.key', '."quoted key"', '.*', '[N]' or '[*]' legs.
+func ParseJSONPath(s string) (JSONPath, error) {
+	var p JSONPath
+	invalid := ErrInvalidJSONPath.GenByArgs(s)
+	str := strings.TrimSpace(s)
+	if len(str) == 0 || str[0] != '$' {
+		return p, invalid
+	}
+	str = strings.TrimLeftFunc(str[1:], unicode.IsSpace)
+	for len(str) > 0 {
+		switch str[0] {
+		case '.':
+			str = strings.TrimLeftFunc(str[1:], unicode.IsSpace)
+			switch {
+			case strings.HasPrefix(str, "*"):
+				p.legs = append(p.legs, jsonPathLeg{tp: jsonPathLegKey, wildcard: true})
+				str = str[1:]
+			case strings.HasPrefix(str, `"`):
+				end := jsonStringEnd(str)
+				if end < 0 {
+					return p, invalid
+				}
+				var key string
+				if err := json.Unmarshal([]byte(str[:end]), &key); err != nil {
+					return p, invalid
+				}
+				p.legs = append(p.legs, jsonPathLeg{tp: jsonPathLegKey, key: key})
+				str = str[end:]
+			default:
+				end := 0
+				for end < len(str) {
+					r, size := utf8.DecodeRuneInString(str[end:])
+					if !(r == '_' || r == '$' || unicode.IsLetter(r) || (end > 0 && unicode.IsDigit(r))) {
+						break
+					}
+					end += size
+				}
+				if end == 0 {
+					return p, invalid
+				}
+				p.legs = append(p.legs, jsonPathLeg{tp: jsonPathLegKey, key: str[:end]})
+				str = str[end:]
+			}
+		case '[':
+			end := strings.IndexByte(str, ']')
+			if end < 0 {
+				return p, invalid
+			}
+			idx := strings.TrimSpace(str[1:end])
+			if idx == "*" {
+				p.legs = append(p.legs, jsonPathLeg{tp: jsonPathLegIndex, wildcard: true})
+			} else {
+				n, err := strconv.ParseUint(idx, 10, 31)
+				if err != nil {
+					return p, invalid
+				}
+				p.legs = append(p.legs, jsonPathLeg{tp: jsonPathLegIndex, index: int(n)})
+			}
+			str = str[end+1:]
+		default:
+			return p, invalid
+		}
+		str = strings.TrimLeftFunc(str, unicode.IsSpace)
+	}
+	return p, nil
+}
+
+// jsonStringEnd returns the length of the quoted string at the beginning of s, or -1 if it is not closed.
+func jsonStringEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// Extract returns all the values in v matched by the path, in document order.
+func (p JSONPath) Extract(v interface{}) []interface{} {
+	return extractJSON(v, p.legs, nil)
+}
+
+func extractJSON(v interface{}, legs []jsonPathLeg, ret []interface{}) []interface{} {
+	if len(legs) == 0 {
+		return append(ret, v)
+	}
+	leg, rest := legs[0], legs[1:]
+	switch leg.tp {
+	case jsonPathLegIndex:
+		arr, ok := v.([]interface{})
+		if !ok {
+			// Like MySQL, a scalar or an object is treated as an array of one element.
+			if leg.wildcard || leg.index == 0 {
+				return extractJSON(v, rest, ret)
+			}
+			return ret
+		}
+		if leg.wildcard {
+			for _, e := range arr {
+				ret = extractJSON(e, rest, ret)
+			}
+		} else if leg.index < len(arr) {
+			ret = extractJSON(arr[leg.index], rest, ret)
+		}
+	case jsonPathLegKey:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return ret
+		}
+		if leg.wildcard {
+			keys := make([]string, 0, len(obj))
+			for k := range obj {
+				keys = append(keys, k)
+			}
+			sort.Sort(jsonKeys(keys))
+			for _, k := range keys {
+				ret = extractJSON(obj[k], rest, ret)
+			}
+		} else if e, ok := obj[leg.key]; ok {
+			ret = extractJSON(e, rest, ret)
+		}
+	}
+	return ret
+}
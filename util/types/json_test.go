@@ -0,0 +1,130 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/terror"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = Suite(&testJSONSuite{})
+
+type testJSONSuite struct {
+}
+
+func (s *testJSONSuite) TestNormalizeJSON(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		Input    string
+		Expected string
+	}{
+		{`null`, `null`},
+		{` true `, `true`},
+		{`-1.50e3`, `-1.50e3`},
+		{`"a\"b\u0001中"`, `"a\"b\u0001中"`},
+		{`[1,"a",[],{}]`, `[1, "a", [], {}]`},
+		{`{"bb":1,"a":{"c":2,"b":null},"ab":[true]}`, `{"a": {"b": null, "c": 2}, "ab": [true], "bb": 1}`},
+	}
+	for _, t := range tbl {
+		str, err := NormalizeJSON(t.Input)
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Expected)
+	}
+
+	tblErr := []string{"", "abc", `{"a":}`, `[1, 2`, `1 2`, `{a: 1}`}
+	for _, t := range tblErr {
+		_, err := NormalizeJSON(t)
+		c.Assert(err, NotNil, Commentf("input %s", t))
+	}
+}
+
+func (s *testJSONSuite) TestJSONUnquote(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		Input    string
+		Expected string
+	}{
+		{`"abc"`, `abc`},
+		{`"a\tb中"`, "a\tb中"},
+		{`abc`, `abc`},
+		{`[1, 2]`, `[1, 2]`},
+		{`"`, `"`},
+	}
+	for _, t := range tbl {
+		str, err := JSONUnquote(t.Input)
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Expected)
+	}
+	_, err := JSONUnquote(`"a\xb"`)
+	c.Assert(err, NotNil)
+}
+
+func (s *testJSONSuite) TestJSONPath(c *C) {
+	defer testleak.AfterTest(c)()
+	doc, err := ParseJSON(`{"a": [1, {"b": 2}, [3]], "c d": "x", "e": 4}`)
+	c.Assert(err, IsNil)
+	tbl := []struct {
+		Path     string
+		Wildcard bool
+		Expected string
+	}{
+		{`$`, false, `[{"a": [1, {"b": 2}, [3]], "e": 4, "c d": "x"}]`},
+		{`$.a`, false, `[[1, {"b": 2}, [3]]]`},
+		{` $ . a [ 1 ] . b `, false, `[2]`},
+		{`$."c d"`, false, `["x"]`},
+		{`$.a[2][0]`, false, `[3]`},
+		{`$.a[0][0]`, false, `[1]`},
+		{`$.a[0][1]`, false, `[]`},
+		{`$.a[3]`, false, `[]`},
+		{`$.f`, false, `[]`},
+		{`$.e.f`, false, `[]`},
+		{`$.a[*]`, true, `[1, {"b": 2}, [3]]`},
+		{`$.*`, true, `[[1, {"b": 2}, [3]], 4, "x"]`},
+		{`$.a[*].b`, true, `[2]`},
+	}
+	for _, t := range tbl {
+		path, err := ParseJSONPath(t.Path)
+		c.Assert(err, IsNil, Commentf("path %s", t.Path))
+		c.Assert(path.HasWildcard(), Equals, t.Wildcard)
+		found := path.Extract(doc)
+		if found == nil {
+			found = []interface{}{}
+		}
+		c.Assert(JSONToString(found), Equals, t.Expected, Commentf("path %s", t.Path))
+	}
+
+	tblErr := []string{"", "a", "$.", "$a", "$[", "$[-1]", "$[a]", `$."a`, "$.1a", "$**.a"}
+	for _, t := range tblErr {
+		_, err := ParseJSONPath(t)
+		c.Assert(terror.ErrorEqual(err, ErrInvalidJSONPath), IsTrue, Commentf("path %s", t))
+	}
+}
+
+func (s *testJSONSuite) TestConvertToJSON(c *C) {
+	defer testleak.AfterTest(c)()
+	ft := NewFieldType(mysql.TypeJSON)
+	d := NewStringDatum(`{"b": 1, "a": [true]}`)
+	v, err := d.ConvertTo(nil, ft)
+	c.Assert(err, IsNil)
+	c.Assert(v.GetString(), Equals, `{"a": [true], "b": 1}`)
+
+	d = NewStringDatum(`{"b": 1`)
+	_, err = d.ConvertTo(nil, ft)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidJSONText), IsTrue)
+	d = NewIntDatum(1)
+	_, err = d.ConvertTo(nil, ft)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidJSONText), IsTrue)
+}
@@ -166,6 +166,11 @@ func timeToUint64(t TimeInternal) uint64 {
 		uint64(t.Second()))
 }
 
+// DateDiff calculates number of days between two days.
+func DateDiff(startTime, endTime TimeInternal) int {
+	return calcDaynr(startTime.Year(), startTime.Month(), startTime.Day()) - calcDaynr(endTime.Year(), endTime.Month(), endTime.Day())
+}
+
 // calcDaynr calculates days since 0000-00-00.
 func calcDaynr(year, month, day int) int {
 	if year == 0 && month == 0 {
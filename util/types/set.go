@@ -38,7 +38,8 @@ func (e Set) ToNumber() float64 {
 	return float64(e.Value)
 }
 
-// ParseSetName creates a Set with name.
+// ParseSetName creates a Set with name. If some items of name are not in the set, an error
+// is returned along with the Set of the other items.
 func ParseSetName(elems []string, name string) (Set, error) {
 	if len(name) == 0 {
 		return zeroSet, nil
@@ -70,7 +71,7 @@ func ParseSetName(elems []string, name string) (Set, error) {
 		return ParseSetValue(elems, num)
 	}
 
-	return Set{}, errors.Errorf("item %s is not in Set %v", name, elems)
+	return Set{Name: strings.Join(items, ","), Value: value}, errors.Errorf("item %s is not in Set %v", name, elems)
 }
 
 var (
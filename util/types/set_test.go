@@ -72,6 +72,11 @@ func (s *testSetSuite) TestSet(c *C) {
 		_, err := ParseSetName(elems, t)
 		c.Assert(err, NotNil)
 	}
+	// The valid items are returned along with the error.
+	e, err := ParseSetName(elems, "b,e,a")
+	c.Assert(err, NotNil)
+	c.Assert(e.String(), Equals, "a,b")
+	c.Assert(e.ToNumber(), Equals, float64(3))
 
 	tblNumberErr := []uint64{
 		100, 16, 64,